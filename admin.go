@@ -0,0 +1,185 @@
+package jackett
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DeleteIndexer removes a configured indexer from Jackett
+func (c *Client) DeleteIndexer(indexerID string) error {
+	ctx := context.Background()
+	params := url.Values{}
+	params.Set("apikey", c.apiKeyFor(ctx))
+
+	endpoint := fmt.Sprintf("/api/v2.0/indexers/%s", url.PathEscape(indexerID))
+	if _, err := c.doAdmin(ctx, "DELETE", endpoint, params, nil); err != nil {
+		return fmt.Errorf("delete indexer %s error: %w", indexerID, err)
+	}
+
+	return nil
+}
+
+// DeleteIndexers removes every configured indexer for which filter returns true.
+// It returns the IDs of the indexers that were deleted. Deletion continues past
+// individual failures; all failures are returned joined in the error.
+func (c *Client) DeleteIndexers(filter func(Indexer) bool) ([]string, error) {
+	indexers, err := c.GetIndexers()
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+	var errs []error
+	for _, indexer := range indexers {
+		if !filter(indexer) {
+			continue
+		}
+		if err := c.DeleteIndexer(indexer.ID); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		deleted = append(deleted, indexer.ID)
+	}
+
+	return deleted, errors.Join(errs...)
+}
+
+// IndexerHealth reports the outcome of testing a single indexer
+type IndexerHealth struct {
+	ID      string
+	Name    string
+	OK      bool
+	Error   string
+	Latency time.Duration
+}
+
+// TestIndexer triggers Jackett's connectivity test for a configured indexer
+func (c *Client) TestIndexer(indexerID string) error {
+	return c.TestIndexerContext(context.Background(), indexerID)
+}
+
+// TestIndexerContext is like TestIndexer but honors ctx for cancellation
+func (c *Client) TestIndexerContext(ctx context.Context, indexerID string) error {
+	params := url.Values{}
+	params.Set("apikey", c.apiKeyFor(ctx))
+
+	endpoint := fmt.Sprintf("/api/v2.0/indexers/%s/test", url.PathEscape(indexerID))
+	if _, err := c.doAdmin(ctx, "POST", endpoint, params, nil); err != nil {
+		return fmt.Errorf("test indexer %s error: %w", indexerID, err)
+	}
+
+	return nil
+}
+
+// TestAllIndexers tests every configured indexer, running at most concurrency
+// tests at a time (concurrency < 1 means as many as WithMaxConcurrentRequests
+// allows, or one at a time without it). The report is in the same order as
+// GetIndexers. Individual test failures are recorded in the report; the error
+// is non-nil only if the indexers could not be listed or ctx was cancelled.
+func (c *Client) TestAllIndexers(ctx context.Context, concurrency int) ([]IndexerHealth, error) {
+	indexers, err := c.GetIndexersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency = c.fanOut(concurrency)
+
+	report := make([]IndexerHealth, len(indexers))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, indexer := range indexers {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return report, ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(i int, indexer Indexer) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			start := c.clock.Now()
+			err := c.TestIndexerContext(ctx, indexer.ID)
+			report[i] = IndexerHealth{
+				ID:      indexer.ID,
+				Name:    indexer.Name,
+				OK:      err == nil,
+				Latency: c.clock.Now().Sub(start),
+			}
+			if err != nil {
+				report[i].Error = err.Error()
+			}
+		}(i, indexer)
+	}
+	wg.Wait()
+
+	return report, ctx.Err()
+}
+
+// IndexerConfigField is one setting of an indexer's configuration form, such
+// as the site link, username, or a category mapping. Value holds the raw JSON
+// value since its type depends on Type.
+type IndexerConfigField struct {
+	ID    string          `json:"id"`
+	Type  string          `json:"type"`
+	Name  string          `json:"name,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// GetIndexerConfig retrieves the configuration form of an indexer, including
+// its current values
+func (c *Client) GetIndexerConfig(indexerID string) ([]IndexerConfigField, error) {
+	return c.GetIndexerConfigContext(context.Background(), indexerID)
+}
+
+// GetIndexerConfigContext is like GetIndexerConfig but honors ctx for cancellation
+func (c *Client) GetIndexerConfigContext(ctx context.Context, indexerID string) ([]IndexerConfigField, error) {
+	params := url.Values{}
+	params.Set("apikey", c.apiKeyFor(ctx))
+
+	endpoint := fmt.Sprintf("/api/v2.0/indexers/%s/config", url.PathEscape(indexerID))
+	respData, err := c.doAdmin(ctx, "GET", endpoint, params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get indexer config %s error: %w", indexerID, err)
+	}
+
+	var fields []IndexerConfigField
+	if err := json.Unmarshal(respData, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode indexer config: %w", err)
+	}
+
+	return fields, nil
+}
+
+// SetIndexerConfig saves an indexer's configuration. Configuring an indexer
+// that isn't set up yet adds it to Jackett; Jackett tests the indexer before
+// accepting the configuration.
+func (c *Client) SetIndexerConfig(indexerID string, fields []IndexerConfigField) error {
+	return c.SetIndexerConfigContext(context.Background(), indexerID, fields)
+}
+
+// SetIndexerConfigContext is like SetIndexerConfig but honors ctx for cancellation
+func (c *Client) SetIndexerConfigContext(ctx context.Context, indexerID string, fields []IndexerConfigField) error {
+	params := url.Values{}
+	params.Set("apikey", c.apiKeyFor(ctx))
+
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to encode indexer config: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("/api/v2.0/indexers/%s/config", url.PathEscape(indexerID))
+	if _, err := c.doAdmin(ctx, "POST", endpoint, params, body); err != nil {
+		// Jackett may echo the submitted tracker credentials
+		return c.redactError(ctx, fmt.Errorf("set indexer config %s error: %w", indexerID, err), fieldSecrets(fields)...)
+	}
+
+	return nil
+}
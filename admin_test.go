@@ -0,0 +1,141 @@
+package jackett
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDeleteIndexer(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2.0/indexers/test-indexer": {statusCode: http.StatusNoContent},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "DELETE", url: "/api/v2.0/indexers/test-indexer", query: url.Values{"apikey": []string{"test-api-key"}}},
+	}
+
+	client, mockTransport, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := client.DeleteIndexer("test-indexer"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mockTransport.requestIndex != len(mockTransport.expectedRequests) {
+		t.Errorf("Not all expected requests were made")
+	}
+}
+
+func TestDeleteIndexer_Error(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2.0/indexers/missing": {statusCode: http.StatusNotFound, responseBody: "not found"},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "DELETE", url: "/api/v2.0/indexers/missing"},
+	}
+
+	client, _, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := client.DeleteIndexer("missing"); err == nil {
+		t.Fatal("Expected error, got none")
+	}
+}
+
+func TestDeleteIndexers(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2.0/indexers/all/results/torznab":  {statusCode: http.StatusOK, responseBody: allIndexersXML},
+		"/api/v2.0/indexers/unconfigured-indexer": {statusCode: http.StatusNoContent},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "GET", url: "/api/v2.0/indexers/all/results/torznab"},
+		{method: "DELETE", url: "/api/v2.0/indexers/unconfigured-indexer"},
+	}
+
+	client, mockTransport, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	deleted, err := client.DeleteIndexers(func(i Indexer) bool { return !i.Configured })
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(deleted, []string{"unconfigured-indexer"}) {
+		t.Errorf("Expected [unconfigured-indexer] deleted, got %v", deleted)
+	}
+
+	if mockTransport.requestIndex != len(mockTransport.expectedRequests) {
+		t.Errorf("Not all expected requests were made")
+	}
+}
+
+func TestTestIndexer(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2.0/indexers/test-indexer/test": {statusCode: http.StatusNoContent},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "POST", url: "/api/v2.0/indexers/test-indexer/test", query: url.Values{"apikey": []string{"test-api-key"}}},
+	}
+
+	client, mockTransport, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := client.TestIndexer("test-indexer"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mockTransport.requestIndex != len(mockTransport.expectedRequests) {
+		t.Errorf("Not all expected requests were made")
+	}
+}
+
+func TestTestAllIndexers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2.0/indexers/all/results/torznab":
+			w.Write([]byte(allIndexersXML))
+		case "/api/v2.0/indexers/configured-indexer/test":
+			w.WriteHeader(http.StatusNoContent)
+		case "/api/v2.0/indexers/unconfigured-indexer/test":
+			http.Error(w, "login failed", http.StatusInternalServerError)
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "test-api-key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	report, err := client.TestAllIndexers(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(report) != 2 {
+		t.Fatalf("Expected 2 health entries, got %d", len(report))
+	}
+	if report[0].ID != "configured-indexer" || !report[0].OK {
+		t.Errorf("Expected configured-indexer to be healthy, got %+v", report[0])
+	}
+	if report[1].ID != "unconfigured-indexer" || report[1].OK {
+		t.Errorf("Expected unconfigured-indexer to be unhealthy, got %+v", report[1])
+	}
+	if !strings.Contains(report[1].Error, "login failed") {
+		t.Errorf("Expected error to contain 'login failed', got '%s'", report[1].Error)
+	}
+}
@@ -0,0 +1,58 @@
+package jackett
+
+import "context"
+
+// apiKeyKey is the context key of an API key set by ContextWithAPIKey
+type apiKeyKey struct{}
+
+// ContextWithAPIKey returns a copy of ctx that makes the client use apiKey
+// instead of its own for calls using it. Calls made with such a context
+// bypass the search and metadata caches and aren't coalesced with others,
+// and the key is redacted from their errors.
+func ContextWithAPIKey(ctx context.Context, apiKey string) context.Context {
+	return context.WithValue(ctx, apiKeyKey{}, apiKey)
+}
+
+// hasAPIKeyOverride reports whether ctx carries a per-call API key
+func hasAPIKeyOverride(ctx context.Context) bool {
+	_, ok := ctx.Value(apiKeyKey{}).(string)
+	return ok
+}
+
+// SetAPIKey replaces the API key used by all subsequent calls, e.g. after
+// the key was regenerated in Jackett. Cached search results are discarded
+// since their download links carry the old key. Calls already in flight
+// keep the old key.
+func (c *Client) SetAPIKey(apiKey string) {
+	c.apiKeyMu.Lock()
+	c.apiKey = apiKey
+	c.apiKeyMu.Unlock()
+
+	c.searchCache.invalidate()
+}
+
+// apiKeyFor returns the API key for a call made with ctx
+func (c *Client) apiKeyFor(ctx context.Context) string {
+	if apiKey, ok := ctx.Value(apiKeyKey{}).(string); ok {
+		return apiKey
+	}
+	c.apiKeyMu.RLock()
+	defer c.apiKeyMu.RUnlock()
+	return c.apiKey
+}
+
+// Clone returns a new client for the same Jackett instance using apiKey,
+// configured by the same options and sharing the http.Client. The clone has
+// its own admin session, caches and lifecycle, so closing one doesn't affect
+// the other.
+func (c *Client) Clone(apiKey string) *Client {
+	// The base URL was validated when c was created
+	clone, _ := NewClient(c.baseURL, apiKey)
+	clone.basicAuth = c.basicAuth
+	for _, opt := range c.opts {
+		opt(clone)
+	}
+	clone.opts = c.opts
+	clone.client = c.client
+	return clone
+}
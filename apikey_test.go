@@ -0,0 +1,159 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// keyServer serves indexers and searches to requests carrying a valid key
+func keyServer(t *testing.T, valid ...string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("apikey")
+		for _, v := range valid {
+			if key == v {
+				switch r.URL.Path {
+				case "/api/v2.0/indexers/all/results":
+					w.Write([]byte(hookSearchJSON))
+				case "/api/v2.0/indexers/all/results/torznab/api":
+					w.Write([]byte(torznabFeedXML))
+				default:
+					w.Write([]byte(allIndexersXML))
+				}
+				return
+			}
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSetAPIKey(t *testing.T) {
+	server := keyServer(t, "new-key")
+	client, _ := NewClientWithOptions(server.URL, "old-key", WithSearchCache(time.Minute))
+	client.searchCache.put(searchCacheKey("all", "ubuntu"), &SearchResponse{}, client.clock.Now())
+
+	if _, err := client.GetIndexers(); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("Expected ErrInvalidAPIKey, got %v", err)
+	}
+
+	client.SetAPIKey("new-key")
+	if _, err := client.GetIndexers(); err != nil {
+		t.Errorf("Expected the new key to be used, got %v", err)
+	}
+	response, err := client.Search("ubuntu")
+	if err != nil || len(response.Results) != 3 {
+		t.Errorf("Expected the search cache to be discarded, got %v, %v", response, err)
+	}
+}
+
+func TestSetAPIKeyConcurrent(t *testing.T) {
+	server := keyServer(t, "a", "b")
+	client, _ := NewClient(server.URL, "a")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				client.SetAPIKey([]string{"a", "b"}[i%4/2])
+				return
+			}
+			if _, err := client.GetIndexers(); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestContextWithAPIKey(t *testing.T) {
+	server := keyServer(t, "other-key")
+	client, _ := NewClient(server.URL, "wrong-key")
+
+	ctx := ContextWithAPIKey(context.Background(), "other-key")
+	if _, err := client.GetIndexersContext(ctx); err != nil {
+		t.Errorf("Expected the per-call key to be used, got %v", err)
+	}
+	if _, err := client.GetIndexersContext(context.Background()); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected the client's own key otherwise, got %v", err)
+	}
+}
+
+func TestContextWithAPIKeyBypassesCaches(t *testing.T) {
+	server := keyServer(t, "good-key")
+	client, _ := NewClientWithOptions(server.URL, "good-key", WithCache(CacheConfig{Metadata: time.Minute, Search: time.Minute}))
+	ctx := ContextWithAPIKey(context.Background(), "bad-key")
+	params := url.Values{"q": {"ubuntu"}}
+
+	// Fill the caches with the client's own key
+	if _, err := client.GetIndexersContext(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.TorznabSearch(context.Background(), "all", params); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.GetIndexersContext(ctx); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected the cached indexers not to be served to another key, got %v", err)
+	}
+	if _, err := client.TorznabSearch(ctx, "all", params); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected the cached search not to be served to another key, got %v", err)
+	}
+	if _, err := client.GetServerConfigContext(ctx); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected the server config to be fetched with the per-call key, got %v", err)
+	}
+}
+
+func TestContextWithAPIKeyRedacted(t *testing.T) {
+	client, _ := NewClient("http://127.0.0.1:1", "client-key")
+	ctx := ContextWithAPIKey(context.Background(), "per-call-secret")
+	_, err := client.GetIndexersContext(ctx)
+	if err == nil || strings.Contains(err.Error(), "per-call-secret") {
+		t.Errorf("Expected the per-call key to be redacted, got %v", err)
+	}
+}
+
+func TestClone(t *testing.T) {
+	server := keyServer(t, "new-key")
+	var mu sync.Mutex
+	var events []HookEvent
+	client, _ := NewClientWithOptions(server.URL, "old-key",
+		WithTimeout(time.Minute),
+		WithHooks(Hooks{OnResponse: func(e HookEvent) {
+			mu.Lock()
+			events = append(events, e)
+			mu.Unlock()
+		}}),
+	)
+
+	clone := client.Clone("new-key")
+	if _, err := clone.GetIndexers(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.GetIndexers(); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected the original to keep its key, got %v", err)
+	}
+	if clone.client != client.client || clone.client.Timeout != time.Minute {
+		t.Error("Expected the clone to share the configured http.Client")
+	}
+	if len(events) != 2 {
+		t.Errorf("Expected the hooks to be carried over, got %d events", len(events))
+	}
+
+	if err := clone.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.GetIndexers(); errors.Is(err, ErrClientClosed) {
+		t.Error("Expected closing the clone to leave the original open")
+	}
+}
@@ -0,0 +1,195 @@
+package jackett
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Audit outcomes
+const (
+	AuditSuccess = "success"
+	// AuditPartial marks a search in which some indexers failed
+	AuditPartial = "partial"
+	AuditFailure = "failure"
+)
+
+// AuditRecord describes one call made through the client. Queries are only
+// recorded as a keyed hash, so the log shows how often the same thing was
+// searched for without revealing what, even to someone guessing queries who
+// lacks the key.
+type AuditRecord struct {
+	Time time.Time `json:"time"`
+	// Operation is what the call did, e.g. "search", "torznab", "download",
+	// "list indexers", "test indexer" or "set server config"
+	Operation string `json:"operation"`
+	// Indexer is the indexer concerned, if any ("all" for aggregate searches)
+	Indexer string `json:"indexer,omitempty"`
+	// QueryHash is an HMAC-SHA256 prefix of the normalized search query
+	// under the audit key; see WithAuditKey
+	QueryHash string        `json:"query_hash,omitempty"`
+	Results   int           `json:"results"`
+	Outcome   string        `json:"outcome"`
+	Error     string        `json:"error,omitempty"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// AuditSink receives audit records. Record may be called concurrently.
+type AuditSink interface {
+	Record(AuditRecord) error
+}
+
+// AuditSinkFunc adapts a function to an AuditSink
+type AuditSinkFunc func(AuditRecord) error
+
+// Record calls f(record)
+func (f AuditSinkFunc) Record(record AuditRecord) error {
+	return f(record)
+}
+
+// JSONAuditSink appends each record to w as a line of JSON
+type JSONAuditSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONAuditSink returns a JSONAuditSink writing to w, typically a file
+// opened with os.O_APPEND
+func NewJSONAuditSink(w io.Writer) *JSONAuditSink {
+	return &JSONAuditSink{w: w}
+}
+
+// Record writes record as a JSON line
+func (s *JSONAuditSink) Record(record AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(line)
+	return err
+}
+
+// WithAuditLog sends a record of every call to sink: searches, downloads,
+// logins and all other API requests. Searches answered from the search cache
+// are not recorded. Failures to record are logged if WithLogger is in effect
+// but don't fail the call. Query hashes are keyed with a random key unless
+// WithAuditKey sets one; if no random key can be generated, creating the
+// client fails.
+func WithAuditLog(sink AuditSink) Option {
+	return func(c *Client) {
+		c.auditSink = sink
+		if c.auditKey == nil {
+			key := make([]byte, 32)
+			if _, err := rand.Read(key); err != nil {
+				c.optionErr = fmt.Errorf("jackett: audit key: %w", err)
+				return
+			}
+			c.auditKey = key
+		}
+	}
+}
+
+// WithAuditKey sets the secret key of the audit log's query hashes. With the
+// random default key, the same query hashes alike only within one client's
+// lifetime; a fixed key makes hashes comparable across restarts. Keep it
+// secret, since with it common queries can be recovered by hashing guesses.
+func WithAuditKey(key []byte) Option {
+	return func(c *Client) {
+		c.auditKey = append([]byte(nil), key...)
+	}
+}
+
+// hashQuery returns the audit hash of a search query under key
+func hashQuery(key []byte, query string) string {
+	if query == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(query))
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// audit sends a record of a call that started at start to the audit sink
+func (c *Client) audit(operation, indexerID, query string, start time.Time, results int, err error) {
+	if c.auditSink == nil {
+		return
+	}
+
+	record := AuditRecord{
+		Time:      start,
+		Operation: operation,
+		Indexer:   indexerID,
+		QueryHash: hashQuery(c.auditKey, query),
+		Results:   results,
+		Outcome:   AuditSuccess,
+		Duration:  c.clock.Now().Sub(start),
+	}
+	if _, partial := err.(*PartialError); partial {
+		record.Outcome = AuditPartial
+	} else if err != nil {
+		record.Outcome = AuditFailure
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	if err := c.auditSink.Record(record); err != nil && c.logger != nil {
+		c.logger.Warn("jackett audit record failed", slog.Any("error", err))
+	}
+}
+
+// auditOperation names the API call made by req to the Jackett served under
+// basePath for the audit log. Searches return an empty operation since they
+// are audited with their results.
+func auditOperation(basePath string, req *http.Request) (operation, indexerID string) {
+	path := strings.TrimPrefix(req.URL.Path, basePath+"/api/v2.0/")
+	if path == req.URL.Path {
+		return strings.ToLower(req.Method) + " " + path, ""
+	}
+
+	parts := strings.Split(path, "/")
+	switch {
+	case path == "indexers/all/results/torznab":
+		return "list indexers", ""
+	case path == "server/config" && req.Method == "GET":
+		return "get server config", ""
+	case path == "server/config":
+		return "set server config", ""
+	case path == "server/update":
+		return "update server", ""
+	case parts[0] != "indexers" || len(parts) < 2:
+		return strings.ToLower(req.Method) + " " + path, ""
+	}
+
+	indexerID, _ = url.PathUnescape(parts[1])
+	rest := strings.Join(parts[2:], "/")
+	switch {
+	case rest == "results", rest == "results/torznab/api" && req.URL.Query().Get("t") != "caps":
+		return "", indexerID
+	case rest == "results/torznab/api":
+		return "get indexer caps", indexerID
+	case rest == "test":
+		return "test indexer", indexerID
+	case rest == "config" && req.Method == "GET":
+		return "get indexer config", indexerID
+	case rest == "config":
+		return "set indexer config", indexerID
+	case rest == "" && req.Method == "DELETE":
+		return "delete indexer", indexerID
+	}
+	return strings.ToLower(req.Method) + " " + path, indexerID
+}
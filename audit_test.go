@@ -0,0 +1,181 @@
+package jackett
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWithAuditLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2.0/indexers/all/results":
+			w.Write([]byte(hookSearchJSON))
+		case "/api/v2.0/indexers/all/results/torznab/api":
+			w.Write([]byte(torznabFeedXML))
+		case "/api/v2.0/indexers/dead/test":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/dl/a/":
+			w.Write([]byte(testTorrent))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var records []AuditRecord
+	sink := AuditSinkFunc(func(r AuditRecord) error {
+		mu.Lock()
+		defer mu.Unlock()
+		records = append(records, r)
+		return nil
+	})
+	client, err := NewClientWithOptions(server.URL, "test-api-key", WithAuditLog(sink))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	client.Search("The  Matrix")
+	client.TorznabSearch(context.Background(), "all", url.Values{"q": {"ubuntu"}})
+	client.TestIndexer("dead")
+	client.DownloadTorrent(server.URL + "/dl/a/?path=x")
+
+	if len(records) != 4 {
+		t.Fatalf("Expected 4 records, got %d: %+v", len(records), records)
+	}
+
+	search := records[0]
+	if search.Operation != "search" || search.Indexer != "all" || search.Results != 3 || search.Outcome != AuditSuccess {
+		t.Errorf("Unexpected search record %+v", search)
+	}
+	if search.QueryHash != hashQuery(client.auditKey, "the matrix") || search.QueryHash == "" {
+		t.Errorf("Expected the normalized query to be hashed, got %q", search.QueryHash)
+	}
+	if records[1].Operation != "torznab" || records[1].Results != 1 {
+		t.Errorf("Unexpected torznab record %+v", records[1])
+	}
+	if test := records[2]; test.Operation != "test indexer" || test.Indexer != "dead" || test.Outcome != AuditFailure || test.Error == "" {
+		t.Errorf("Unexpected test record %+v", test)
+	}
+	if download := records[3]; download.Operation != "download" || download.Indexer != "a" || download.Outcome != AuditSuccess {
+		t.Errorf("Unexpected download record %+v", download)
+	}
+}
+
+func TestWithAuditLog_Subpath(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jackett/api/v2.0/indexers/all/results", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(hookSearchJSON))
+	})
+	mux.HandleFunc("/jackett/api/v2.0/indexers/dead/test", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var mu sync.Mutex
+	var records []AuditRecord
+	sink := AuditSinkFunc(func(r AuditRecord) error {
+		mu.Lock()
+		defer mu.Unlock()
+		records = append(records, r)
+		return nil
+	})
+	client, err := NewClientWithOptions(server.URL+"/jackett/", "test-api-key", WithAuditLog(sink))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	client.Search("ubuntu")
+	client.TestIndexer("dead")
+
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d: %+v", len(records), records)
+	}
+	if search := records[0]; search.Operation != "search" || search.Indexer != "all" {
+		t.Errorf("Unexpected search record %+v", search)
+	}
+	if test := records[1]; test.Operation != "test indexer" || test.Indexer != "dead" {
+		t.Errorf("Unexpected test record %+v", test)
+	}
+}
+
+func TestAuditKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(hookSearchJSON))
+	}))
+	defer server.Close()
+
+	queryHash := func(opts ...Option) string {
+		var hash string
+		sink := AuditSinkFunc(func(r AuditRecord) error {
+			hash = r.QueryHash
+			return nil
+		})
+		client, err := NewClientWithOptions(server.URL, "test-api-key", append(opts, WithAuditLog(sink))...)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		client.Search("ubuntu")
+		return hash
+	}
+
+	key := []byte("audit secret")
+	if a, b := queryHash(WithAuditKey(key)), queryHash(WithAuditKey(key)); a == "" || a != b {
+		t.Errorf("Expected equal hashes under the same key, got %q and %q", a, b)
+	}
+	if a, b := queryHash(), queryHash(); a == b {
+		t.Errorf("Expected the random default keys to hash differently, got %q twice", a)
+	}
+	if hash := queryHash(WithAuditKey(key)); hash == hashQuery(nil, "ubuntu") {
+		t.Error("Expected the hash to depend on the key")
+	}
+}
+
+func TestJSONAuditSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewJSONAuditSink(&buf)
+	for _, op := range []string{"search", "download"} {
+		if err := sink.Record(AuditRecord{Operation: op, Outcome: AuditSuccess}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+	var record AuditRecord
+	if err := json.Unmarshal([]byte(lines[1]), &record); err != nil || record.Operation != "download" {
+		t.Errorf("Expected a download record, got %+v (%v)", record, err)
+	}
+}
+
+func TestAuditOperation(t *testing.T) {
+	tests := []struct {
+		method, target string
+		operation      string
+		indexer        string
+	}{
+		{"GET", "/api/v2.0/indexers/all/results/torznab?t=indexers", "list indexers", ""},
+		{"GET", "/api/v2.0/indexers/rarbg/results?Query=x", "", "rarbg"},
+		{"GET", "/api/v2.0/indexers/rarbg/results/torznab/api?t=caps", "get indexer caps", "rarbg"},
+		{"POST", "/api/v2.0/indexers/rarbg/config", "set indexer config", "rarbg"},
+		{"DELETE", "/api/v2.0/indexers/rarbg", "delete indexer", "rarbg"},
+		{"POST", "/api/v2.0/server/config", "set server config", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, nil)
+		operation, indexer := auditOperation("", req)
+		if operation != tt.operation || indexer != tt.indexer {
+			t.Errorf("%s %s: expected %q/%q, got %q/%q", tt.method, tt.target, tt.operation, tt.indexer, operation, indexer)
+		}
+	}
+}
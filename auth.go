@@ -0,0 +1,135 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// session holds the dashboard login state shared by a Client
+type session struct {
+	mu       sync.Mutex
+	jar      http.CookieJar
+	password string
+}
+
+func newSession() *session {
+	// cookiejar.New only fails for a misconfigured public suffix list
+	jar, _ := cookiejar.New(nil)
+	return &session{jar: jar}
+}
+
+// Login authenticates against the Jackett dashboard with the admin password.
+// The session cookie is kept on the client and sent with every request, and
+// admin calls transparently log in again if the session expires.
+func (c *Client) Login(password string) error {
+	return c.LoginContext(context.Background(), password)
+}
+
+// LoginContext is like Login but honors ctx for cancellation
+func (c *Client) LoginContext(ctx context.Context, password string) error {
+	if err := c.login(ctx, password); err != nil {
+		return err
+	}
+
+	c.session.mu.Lock()
+	c.session.password = password
+	c.session.mu.Unlock()
+
+	return nil
+}
+
+func (c *Client) login(ctx context.Context, password string) (err error) {
+	start := c.clock.Now()
+	defer func() {
+		err = c.redactError(ctx, err, password)
+		c.audit("login", "", "", start, 0, err)
+	}()
+
+	form := url.Values{}
+	form.Set("password", password)
+
+	req, err := c.newRequest(ctx, "POST", "/UI/Dashboard", nil, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("login error: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// Jackett answers a login with a redirect; stop there so the session
+	// cookie on the redirect response is captured.
+	noRedirect := *c.client
+	noRedirect.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	if err := c.authenticate(req); err != nil {
+		return fmt.Errorf("login error: %w", err)
+	}
+
+	done, err := c.lifecycle.begin()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	release, err := c.acquireSlot(ctx)
+	if err != nil {
+		return fmt.Errorf("login error: %w", err)
+	}
+	defer release()
+
+	req, cancel := c.withRequestTimeout(req)
+	defer cancel()
+	resp, err := c.dumping(&noRedirect).Do(req)
+	if err != nil {
+		return fmt.Errorf("login error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return fmt.Errorf("login error: %w", &statusError{code: resp.StatusCode, body: string(body)})
+	}
+
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == "Jackett" {
+			c.session.jar.SetCookies(req.URL, resp.Cookies())
+			return nil
+		}
+	}
+	return fmt.Errorf("login error: invalid admin password")
+}
+
+// doAdmin is like doRequest but logs in again and retries once when an admin
+// endpoint rejects an expired session
+func (c *Client) doAdmin(ctx context.Context, method, endpoint string, query url.Values, body []byte) ([]byte, error) {
+	if method != "GET" {
+		// Any change may affect the indexers, their caps or the server config
+		defer c.cache.invalidate()
+	}
+
+	data, err := c.doRequest(ctx, method, endpoint, query, body)
+
+	var statusErr *statusError
+	if err == nil || !errors.As(err, &statusErr) || statusErr.code != http.StatusUnauthorized {
+		return data, err
+	}
+
+	c.session.mu.Lock()
+	password := c.session.password
+	c.session.mu.Unlock()
+	if password == "" {
+		return nil, err
+	}
+
+	if loginErr := c.login(ctx, password); loginErr != nil {
+		return nil, loginErr
+	}
+	return c.doRequest(ctx, method, endpoint, query, body)
+}
@@ -0,0 +1,103 @@
+package jackett_test
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cehbz/jackett/jacketttest"
+)
+
+// newAuthFake returns a fake Jackett with an admin password, which only
+// serves the server config to requests carrying a valid session cookie
+func newAuthFake(t *testing.T, password string, logins *int32, validSession *atomic.Value) *jacketttest.Server {
+	server := jacketttest.NewServer()
+	t.Cleanup(server.Close)
+	server.Handle("/UI/Dashboard", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(logins, 1)
+		if r.PostFormValue("password") != password {
+			http.Redirect(w, r, "/UI/Login", http.StatusFound)
+			return
+		}
+		session := "session-" + string(rune('0'+atomic.LoadInt32(logins)))
+		validSession.Store(session)
+		http.SetCookie(w, &http.Cookie{Name: "Jackett", Value: session, Path: "/"})
+		http.Redirect(w, r, "/UI/Dashboard", http.StatusFound)
+	})
+	server.Handle("/api/v2.0/server/config", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("Jackett")
+		if err != nil || cookie.Value != validSession.Load() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"app_version": "0.22.0"}`))
+	})
+	return server
+}
+
+func TestLogin(t *testing.T) {
+	var logins int32
+	var validSession atomic.Value
+	validSession.Store("")
+	server := newAuthFake(t, "secret", &logins, &validSession)
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.GetServerConfig(); err == nil {
+		t.Fatal("Expected error before login, got none")
+	}
+
+	if err := client.Login("secret"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	config, err := client.GetServerConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if config["app_version"] != "0.22.0" {
+		t.Errorf("Expected app_version '0.22.0', got '%v'", config["app_version"])
+	}
+}
+
+func TestLogin_InvalidPassword(t *testing.T) {
+	var logins int32
+	var validSession atomic.Value
+	validSession.Store("")
+	server := newAuthFake(t, "secret", &logins, &validSession)
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := client.Login("wrong"); err == nil {
+		t.Fatal("Expected error, got none")
+	}
+}
+
+func TestLogin_ReauthenticatesOnExpiredSession(t *testing.T) {
+	var logins int32
+	var validSession atomic.Value
+	validSession.Store("")
+	server := newAuthFake(t, "secret", &logins, &validSession)
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := client.Login("secret"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Expire the session server-side
+	validSession.Store("expired")
+
+	if _, err := client.GetServerConfig(); err != nil {
+		t.Fatalf("Expected re-authentication to succeed, got %v", err)
+	}
+	if atomic.LoadInt32(&logins) != 2 {
+		t.Errorf("Expected 2 logins, got %d", logins)
+	}
+}
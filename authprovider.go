@@ -0,0 +1,178 @@
+package jackett
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuthProvider adds credentials to every request sent to the Jackett
+// instance, for deployments behind zero-trust proxies such as Cloudflare
+// Access or an OAuth2-protected ingress
+type AuthProvider interface {
+	Authenticate(req *http.Request) error
+}
+
+// AuthProviderFunc adapts a function to the AuthProvider interface
+type AuthProviderFunc func(req *http.Request) error
+
+// Authenticate calls f(req)
+func (f AuthProviderFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// WithAuthProvider sets an AuthProvider applied to every request sent to
+// the Jackett instance
+func WithAuthProvider(provider AuthProvider) Option {
+	return func(c *Client) {
+		c.auth = provider
+	}
+}
+
+// CloudflareAccessAuth authenticates with a Cloudflare Access service token
+func CloudflareAccessAuth(clientID, clientSecret string) AuthProvider {
+	return AuthProviderFunc(func(req *http.Request) error {
+		req.Header.Set("CF-Access-Client-Id", clientID)
+		req.Header.Set("CF-Access-Client-Secret", clientSecret)
+		return nil
+	})
+}
+
+// TokenSource fetches a fresh short-lived token and its expiry
+type TokenSource func(ctx context.Context) (token string, expiry time.Time, err error)
+
+// RefreshingAuth is an AuthProvider that caches a token from Source and
+// fetches a new one shortly before it expires
+type RefreshingAuth struct {
+	// Source fetches new tokens
+	Source TokenSource
+	// Header carries the token, "Authorization" by default
+	Header string
+	// Scheme prefixes the token, "Bearer" by default when Header is
+	// "Authorization"; empty otherwise
+	Scheme string
+	// Skew refreshes the token this long before it expires, 30s by default
+	Skew time.Duration
+	// Clock defaults to the clock of the client authenticating, see
+	// WithClock
+	Clock Clock
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// Authenticate sets the cached token on req, refreshing it first if needed
+func (a *RefreshingAuth) Authenticate(req *http.Request) error {
+	token, err := a.currentToken(req.Context())
+	if err != nil {
+		return fmt.Errorf("failed to refresh auth token: %w", err)
+	}
+
+	header, scheme := a.Header, a.Scheme
+	if header == "" {
+		header = "Authorization"
+		if scheme == "" {
+			scheme = "Bearer"
+		}
+	}
+	if scheme != "" {
+		token = scheme + " " + token
+	}
+	req.Header.Set(header, token)
+	return nil
+}
+
+func (a *RefreshingAuth) currentToken(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	clock := a.Clock
+	if clock == nil {
+		clock = clockFrom(ctx)
+	}
+	skew := a.Skew
+	if skew == 0 {
+		skew = 30 * time.Second
+	}
+
+	if a.token != "" && clock.Now().Add(skew).Before(a.expiry) {
+		return a.token, nil
+	}
+
+	token, expiry, err := a.Source(contextWithClock(ctx, clock))
+	if err != nil {
+		return "", err
+	}
+	a.token, a.expiry = token, expiry
+	return token, nil
+}
+
+// OAuth2ClientCredentials returns a TokenSource performing the OAuth2 client
+// credentials grant against tokenURL. A nil httpClient uses http.DefaultClient.
+// Expiries are computed with the clock of the RefreshingAuth calling it.
+func OAuth2ClientCredentials(httpClient *http.Client, tokenURL, clientID, clientSecret string, scopes ...string) TokenSource {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return func(ctx context.Context) (string, time.Time, error) {
+		form := url.Values{}
+		form.Set("grant_type", "client_credentials")
+		if len(scopes) > 0 {
+			form.Set("scope", strings.Join(scopes, " "))
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("failed to create token request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("token request failed: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return "", time.Time{}, fmt.Errorf("token request failed (%d): %s", resp.StatusCode, string(body))
+		}
+
+		var token struct {
+			AccessToken string `json:"access_token"`
+			ExpiresIn   int64  `json:"expires_in"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+			return "", time.Time{}, fmt.Errorf("failed to decode token response: %w", err)
+		}
+		if token.AccessToken == "" {
+			return "", time.Time{}, fmt.Errorf("token response has no access_token")
+		}
+
+		now := clockFrom(ctx).Now()
+		expiry := now.Add(time.Duration(token.ExpiresIn) * time.Second)
+		if token.ExpiresIn == 0 {
+			expiry = now.Add(time.Hour)
+		}
+		return token.AccessToken, expiry, nil
+	}
+}
+
+// authenticate applies the configured AuthProvider, if any, to req. The
+// provider sees the client's clock in the request's context; the headers it
+// sets are shared with req.
+func (c *Client) authenticate(req *http.Request) error {
+	if c.auth == nil {
+		return nil
+	}
+	return c.auth.Authenticate(req.WithContext(contextWithClock(req.Context(), c.clock)))
+}
@@ -0,0 +1,116 @@
+package jackett
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithAuthProvider_CloudflareAccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("CF-Access-Client-Id") != "id" || r.Header.Get("CF-Access-Client-Secret") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"app_version": "0.22.0"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithOptions(server.URL, "test-api-key", WithAuthProvider(CloudflareAccessAuth("id", "secret")))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.GetServerConfig(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestRefreshingAuth(t *testing.T) {
+	clock := &stepClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	var fetches int
+	auth := &RefreshingAuth{
+		Source: func(ctx context.Context) (string, time.Time, error) {
+			fetches++
+			return "token-" + string(rune('0'+fetches)), clock.now.Add(5 * time.Minute), nil
+		},
+		Clock: clock,
+	}
+
+	req, _ := http.NewRequest("GET", "http://localhost", nil)
+	auth.Authenticate(req)
+	auth.Authenticate(req)
+	if got := req.Header.Get("Authorization"); got != "Bearer token-1" {
+		t.Errorf("Expected 'Bearer token-1', got '%s'", got)
+	}
+
+	// Within the refresh skew of expiry
+	clock.now = clock.now.Add(4*time.Minute + 45*time.Second)
+	auth.Authenticate(req)
+	if got := req.Header.Get("Authorization"); got != "Bearer token-2" {
+		t.Errorf("Expected 'Bearer token-2', got '%s'", got)
+	}
+	if fetches != 2 {
+		t.Errorf("Expected 2 token fetches, got %d", fetches)
+	}
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		if r.PostFormValue("grant_type") != "client_credentials" || id != "client" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"access_token": "abc", "expires_in": 3600, "token_type": "bearer"}`))
+	}))
+	defer server.Close()
+
+	source := OAuth2ClientCredentials(nil, server.URL, "client", "s3cret", "jackett")
+	token, expiry, err := source(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if token != "abc" {
+		t.Errorf("Expected token 'abc', got '%s'", token)
+	}
+	if time.Until(expiry) < 59*time.Minute {
+		t.Errorf("Expected expiry about an hour out, got %v", expiry)
+	}
+}
+
+func TestOAuth2ClientCredentialsUsesClientClock(t *testing.T) {
+	var fetches int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		w.Write([]byte(`{"access_token": "abc", "expires_in": 60}`))
+	}))
+	defer tokenServer.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"app_version": "0.22.0"}`))
+	}))
+	defer server.Close()
+
+	clock := &stepClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	auth := &RefreshingAuth{Source: OAuth2ClientCredentials(nil, tokenServer.URL, "client", "s3cret")}
+	client, err := NewClientWithOptions(server.URL, "key", WithClock(clock), WithAuthProvider(auth))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetServerConfig(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	clock.now = clock.now.Add(2 * time.Minute)
+	if _, err := client.GetServerConfig(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if fetches != 2 {
+		t.Errorf("Expected the token to expire on the client's clock after 2 fetches, got %d", fetches)
+	}
+}
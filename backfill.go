@@ -0,0 +1,135 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WantedItem is a movie, episode or other release a Backfill searches for
+type WantedItem struct {
+	// Key identifies the item across runs, e.g. "tt0133093" or
+	// "tvdb:121361:S01E02"
+	Key     string        `json:"key"`
+	Request SearchRequest `json:"request"`
+}
+
+// BackfillProgress reports the outcome of one item of a Backfill
+type BackfillProgress struct {
+	Item WantedItem
+	// Response holds the results found, nil if the search failed entirely
+	Response *SearchResponse
+	// Err is the search error; a *PartialError still completes the item
+	Err error
+	// Done counts the items completed so far, including those completed by
+	// earlier runs; Total is the number of items
+	Done  int
+	Total int
+}
+
+// Backfill searches for a list of wanted items in the background: the batch
+// counterpart to a Watcher. Each item is planned against the indexers'
+// capabilities (see PlanSearch) and searched on every suitable indexer, within
+// the client's per-indexer rate limits (see WithIndexerRateLimit and
+// SeedRateLimits). Searches are spread evenly across Window, and completed
+// items are recorded in Store so that a restarted backfill resumes where it
+// left off. Items whose search failed on every indexer are not completed and
+// are searched again by the next run.
+type Backfill struct {
+	Client *Client
+	Items  []WantedItem
+	// Indexers to plan against; the configured indexers if nil
+	Indexers []Indexer
+	// Window is the time over which the remaining items are spread; zero
+	// searches them back to back
+	Window time.Duration
+	// Store records completed items, in memory by default. Use a persistent
+	// store such as a FileSeenStore to resume after a restart.
+	Store SeenStore
+	// Filter, if set, drops the results it returns false for from each
+	// item's Response
+	Filter Predicate
+	// OnProgress, if set, is called after each item
+	OnProgress func(BackfillProgress)
+}
+
+// backfillKey is the Store key of a completed item
+func backfillKey(item WantedItem) string {
+	return "backfill:" + item.Key
+}
+
+// Run searches every item not yet completed, returning when all of them
+// were searched or ctx is cancelled. Failed items don't stop the run.
+func (b *Backfill) Run(ctx context.Context) error {
+	store := b.Store
+	if store == nil {
+		store = NewMemorySeenStore()
+	}
+
+	var pending []WantedItem
+	for _, item := range b.Items {
+		if item.Key == "" {
+			return errors.New("backfill error: wanted item without a key")
+		}
+		done, err := store.Seen(backfillKey(item))
+		if err != nil {
+			return fmt.Errorf("backfill error: %w", err)
+		}
+		if !done {
+			pending = append(pending, item)
+		}
+	}
+	completed := len(b.Items) - len(pending)
+	if len(pending) == 0 {
+		return nil
+	}
+
+	indexers := b.Indexers
+	if indexers == nil {
+		all, err := b.Client.GetIndexersContext(ctx)
+		if err != nil {
+			return fmt.Errorf("backfill error: %w", err)
+		}
+		for _, indexer := range all {
+			if indexer.Configured {
+				indexers = append(indexers, indexer)
+			}
+		}
+	}
+
+	spacing := b.Window / time.Duration(len(pending))
+	for i, item := range pending {
+		if i > 0 && spacing > 0 {
+			select {
+			case <-b.Client.clock.After(spacing):
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-b.Client.Done():
+				return ErrClientClosed
+			}
+		}
+
+		response, err := PlanSearch(item.Request, indexers).Execute(ctx, b.Client)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var partial *PartialError
+		if err == nil || errors.As(err, &partial) {
+			if markErr := store.Mark(backfillKey(item)); markErr != nil {
+				return fmt.Errorf("backfill error: %w", markErr)
+			}
+			completed++
+			if b.Filter != nil {
+				response = response.Where(b.Filter)
+			}
+		} else {
+			response = nil
+		}
+
+		if b.OnProgress != nil {
+			b.OnProgress(BackfillProgress{Item: item, Response: response, Err: err, Done: completed, Total: len(b.Items)})
+		}
+	}
+	return nil
+}
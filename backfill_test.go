@@ -0,0 +1,124 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBackfill(t *testing.T) {
+	var mu sync.Mutex
+	var searched []string
+	failing := map[string]bool{"2": true}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ep := r.URL.Query().Get("ep")
+		mu.Lock()
+		searched = append(searched, ep)
+		fail := failing[ep]
+		mu.Unlock()
+		if fail {
+			w.Write([]byte(`<error code="900" description="Tracker down" />`))
+			return
+		}
+		w.Write([]byte(torznabFeedXML))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, "test-api-key")
+	var items []WantedItem
+	for _, ep := range []int{1, 2, 3} {
+		items = append(items, WantedItem{
+			Key:     fmt.Sprintf("tvdb:121361:S01E%02d", ep),
+			Request: SearchRequest{Mode: SearchModeTV, TVDBID: 121361, Season: 1, Episode: ep},
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "backfill")
+	run := func() []BackfillProgress {
+		store, err := OpenFileSeenStore(path)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer store.Close()
+
+		var progress []BackfillProgress
+		backfill := &Backfill{
+			Client:     client,
+			Items:      items,
+			Indexers:   plannerIndexers()[:1],
+			Store:      store,
+			OnProgress: func(p BackfillProgress) { progress = append(progress, p) },
+		}
+		if err := backfill.Run(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return progress
+	}
+
+	progress := run()
+	if len(progress) != 3 {
+		t.Fatalf("Expected progress for 3 items, got %+v", progress)
+	}
+	if p := progress[0]; p.Err != nil || p.Response == nil || len(p.Response.Results) != 1 || p.Done != 1 || p.Total != 3 {
+		t.Errorf("Unexpected progress %+v", p)
+	}
+	if p := progress[1]; !errors.Is(p.Err, ErrAllIndexersFailed) || p.Response != nil || p.Done != 1 {
+		t.Errorf("Expected the failed item not to complete, got %+v", p)
+	}
+	if p := progress[2]; p.Done != 2 {
+		t.Errorf("Unexpected progress %+v", p)
+	}
+
+	mu.Lock()
+	failing["2"] = false
+	searched = nil
+	mu.Unlock()
+
+	progress = run()
+	if len(searched) != 1 || searched[0] != "2" {
+		t.Errorf("Expected only the failed item to be searched again, got %v", searched)
+	}
+	if len(progress) != 1 || progress[0].Done != 3 || progress[0].Err != nil {
+		t.Errorf("Unexpected progress %+v", progress)
+	}
+
+	if progress = run(); len(progress) != 0 {
+		t.Errorf("Expected nothing left to do, got %+v", progress)
+	}
+}
+
+func TestBackfillWindow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(torznabFeedXML))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, "test-api-key")
+	items := []WantedItem{
+		{Key: "a", Request: SearchRequest{Query: "a"}},
+		{Key: "b", Request: SearchRequest{Query: "b"}},
+		{Key: "c", Request: SearchRequest{Query: "c"}},
+	}
+	backfill := &Backfill{Client: client, Items: items, Indexers: plannerIndexers()[:1], Window: 60 * time.Millisecond}
+
+	start := time.Now()
+	if err := backfill.Run(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Expected the searches to be spread across the window, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	backfill = &Backfill{Client: client, Items: items, Indexers: plannerIndexers()[:1]}
+	if err := backfill.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
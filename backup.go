@@ -0,0 +1,90 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// indexerExportVersion is the version of the IndexerExport format
+const indexerExportVersion = 1
+
+// IndexerExport is a portable backup of configured indexers. It contains
+// tracker credentials and should be stored as securely as Jackett's own
+// configuration.
+type IndexerExport struct {
+	Version        int             `json:"version"`
+	JackettVersion string          `json:"jackett_version,omitempty"`
+	ExportedAt     time.Time       `json:"exported_at"`
+	Indexers       []IndexerBackup `json:"indexers"`
+}
+
+// IndexerBackup holds a single indexer and its configuration values
+type IndexerBackup struct {
+	ID     string               `json:"id"`
+	Name   string               `json:"name"`
+	Config []IndexerConfigField `json:"config"`
+}
+
+// ExportIndexers captures every configured indexer and its configuration
+func (c *Client) ExportIndexers() (*IndexerExport, error) {
+	return c.ExportIndexersContext(context.Background())
+}
+
+// ExportIndexersContext is like ExportIndexers but honors ctx for cancellation
+func (c *Client) ExportIndexersContext(ctx context.Context) (*IndexerExport, error) {
+	indexers, err := c.GetIndexersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &IndexerExport{
+		Version:    indexerExportVersion,
+		ExportedAt: c.clock.Now().UTC(),
+		Indexers:   make([]IndexerBackup, 0, len(indexers)),
+	}
+	if config, err := c.GetServerConfigContext(ctx); err == nil {
+		export.JackettVersion, _ = config["app_version"].(string)
+	}
+
+	for _, indexer := range indexers {
+		fields, err := c.GetIndexerConfigContext(ctx, indexer.ID)
+		if err != nil {
+			return nil, fmt.Errorf("export indexers error: %w", err)
+		}
+		export.Indexers = append(export.Indexers, IndexerBackup{
+			ID:     indexer.ID,
+			Name:   indexer.Name,
+			Config: fields,
+		})
+	}
+
+	return export, nil
+}
+
+// ImportIndexers configures every indexer in export on this instance. Import
+// continues past individual failures (e.g. a tracker that is down and fails
+// Jackett's test); all failures are returned joined in the error.
+func (c *Client) ImportIndexers(export *IndexerExport) error {
+	return c.ImportIndexersContext(context.Background(), export)
+}
+
+// ImportIndexersContext is like ImportIndexers but honors ctx for cancellation
+func (c *Client) ImportIndexersContext(ctx context.Context, export *IndexerExport) error {
+	if export.Version != indexerExportVersion {
+		return fmt.Errorf("unsupported indexer export version %d", export.Version)
+	}
+
+	var errs []error
+	for _, backup := range export.Indexers {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := c.SetIndexerConfigContext(ctx, backup.ID, backup.Config); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
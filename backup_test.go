@@ -0,0 +1,88 @@
+package jackett
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const indexerConfigJSON = `[
+  {"id": "sitelink", "type": "inputstring", "name": "Site Link", "value": "https://configured.example.com/"},
+  {"id": "username", "type": "inputstring", "name": "Username", "value": "user"},
+  {"id": "freeleech", "type": "inputbool", "name": "Freeleech only", "value": true}
+]`
+
+func TestExportImportIndexers(t *testing.T) {
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v2.0/indexers/all/results/torznab":
+			w.Write([]byte(basicIndexerXML))
+		case r.URL.Path == "/api/v2.0/server/config":
+			w.Write([]byte(`{"app_version": "0.22.100"}`))
+		case r.URL.Path == "/api/v2.0/indexers/test-indexer/config" && r.Method == "GET":
+			w.Write([]byte(indexerConfigJSON))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer source.Close()
+
+	var mu sync.Mutex
+	imported := map[string][]IndexerConfigField{}
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || !strings.HasSuffix(r.URL.Path, "/config") {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			return
+		}
+		var fields []IndexerConfigField
+		json.NewDecoder(r.Body).Decode(&fields)
+		mu.Lock()
+		imported[r.URL.Path] = fields
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer target.Close()
+
+	sourceClient, _ := NewClient(source.URL, "source-key")
+	targetClient, _ := NewClient(target.URL, "target-key")
+
+	export, err := sourceClient.ExportIndexers()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if export.JackettVersion != "0.22.100" || len(export.Indexers) != 1 {
+		t.Fatalf("Expected one indexer exported from 0.22.100, got %+v", export)
+	}
+
+	// The export must survive a round trip through its JSON form
+	data, err := json.Marshal(export)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var restored IndexerExport
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := targetClient.ImportIndexers(&restored); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	fields := imported["/api/v2.0/indexers/test-indexer/config"]
+	if len(fields) != 3 {
+		t.Fatalf("Expected 3 config fields imported, got %d", len(fields))
+	}
+	if string(fields[2].Value) != "true" {
+		t.Errorf("Expected freeleech value 'true', got '%s'", fields[2].Value)
+	}
+}
+
+func TestImportIndexers_UnsupportedVersion(t *testing.T) {
+	client, _ := NewClient("http://localhost:9117", "test-api-key")
+	if err := client.ImportIndexers(&IndexerExport{Version: 99}); err == nil {
+		t.Fatal("Expected error, got none")
+	}
+}
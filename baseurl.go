@@ -0,0 +1,82 @@
+package jackett
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// userinfo matches credentials embedded in a URL
+var userinfo = regexp.MustCompile(`(//[^/@]*:)[^/@]*@`)
+
+// normalizeBaseURL validates the base URL of a Jackett instance and strips
+// trailing slashes from its path. Credentials embedded in it are removed and
+// returned separately.
+func normalizeBaseURL(raw string) (string, *url.Userinfo, error) {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w %q: %s", ErrInvalidBaseURL, userinfo.ReplaceAllString(raw, "${1}"+redactedPlaceholder+"@"), reason)
+	}
+
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return "", nil, invalid("empty")
+	}
+	u, err := url.Parse(trimmed)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return "", nil, invalid(err.Error())
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+	case "":
+		return "", nil, invalid(`missing scheme, e.g. "http://localhost:9117"`)
+	default:
+		if u.Opaque != "" {
+			// "localhost:9117" parses as scheme "localhost"
+			return "", nil, invalid(`missing scheme, e.g. "http://` + trimmed + `"`)
+		}
+		return "", nil, invalid(fmt.Sprintf("unsupported scheme %q, want http or https", u.Scheme))
+	}
+	if u.Host == "" {
+		return "", nil, invalid("missing host")
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", nil, invalid("must not have a query or fragment")
+	}
+
+	user := u.User
+	u.User = nil
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = strings.TrimRight(u.RawPath, "/")
+	u.ForceQuery = false
+	return u.String(), user, nil
+}
+
+// joinPath appends an escaped endpoint path such as
+// "/api/v2.0/indexers/a%2Fb/results" to the path of base, so that Jackett
+// served from a reverse-proxy subpath like https://host/jackett is reached at
+// https://host/jackett/api/v2.0/...
+func joinPath(base *url.URL, endpoint string) error {
+	escaped := strings.TrimRight(base.EscapedPath(), "/") + "/" + strings.TrimLeft(endpoint, "/")
+	path, err := url.PathUnescape(escaped)
+	if err != nil {
+		return err
+	}
+	base.Path = path
+	base.RawPath = escaped
+	return nil
+}
+
+// basePath returns the path of the base URL, e.g. "/jackett" for Jackett
+// behind a reverse-proxy subpath and "" for Jackett at the root
+func (c *Client) basePath() string {
+	u, _ := url.Parse(c.baseURL)
+	return u.Path
+}
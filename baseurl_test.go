@@ -0,0 +1,113 @@
+package jackett
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestNewClientBaseURL(t *testing.T) {
+	valid := []struct{ in, want string }{
+		{"http://localhost:9117", "http://localhost:9117"},
+		{"http://localhost:9117/", "http://localhost:9117"},
+		{" HTTPS://jackett.example.com//", "https://jackett.example.com"},
+		{"https://example.com/jackett/", "https://example.com/jackett"},
+		{"http://[::1]:9117", "http://[::1]:9117"},
+	}
+	for _, tt := range valid {
+		client, err := NewClient(tt.in, "key")
+		if err != nil {
+			t.Errorf("%q: expected no error, got %v", tt.in, err)
+			continue
+		}
+		if client.baseURL != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.in, tt.want, client.baseURL)
+		}
+	}
+
+	invalid := []struct{ in, reason string }{
+		{"", "empty"},
+		{"localhost:9117", `missing scheme, e.g. "http://localhost:9117"`},
+		{"/jackett", "missing scheme"},
+		{"ftp://example.com", "unsupported scheme"},
+		{"http://", "missing host"},
+		{"http://example.com/?apikey=x", "query"},
+		{"http://exa mple.com", "invalid character"},
+	}
+	for _, tt := range invalid {
+		_, err := NewClient(tt.in, "key")
+		if !errors.Is(err, ErrInvalidBaseURL) {
+			t.Errorf("%q: expected ErrInvalidBaseURL, got %v", tt.in, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.reason) {
+			t.Errorf("%q: expected the error to mention %q, got %v", tt.in, tt.reason, err)
+		}
+	}
+
+	if _, err := NewClientWithOptions("localhost", "key"); !errors.Is(err, ErrInvalidBaseURL) {
+		t.Errorf("Expected NewClientWithOptions to validate too, got %v", err)
+	}
+}
+
+func TestBaseURLSubpath(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/results"):
+			w.Write([]byte(hookSearchJSON))
+		case strings.HasSuffix(r.URL.Path, "/Dashboard"):
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.Write([]byte(allIndexersXML))
+		}
+	}))
+	defer server.Close()
+
+	for _, base := range []string{server.URL + "/jackett", server.URL + "/jackett/"} {
+		paths = nil
+		client, err := NewClient(base, "key")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := client.GetIndexers(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := client.SearchWithIndexer("a/b", "ubuntu"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		client.TestIndexer("a/b")
+		client.Login("secret")
+
+		want := []string{
+			"/jackett/api/v2.0/indexers/all/results/torznab",
+			"/jackett/api/v2.0/indexers/a%2Fb/results",
+			"/jackett/api/v2.0/indexers/a%2Fb/test",
+			"/jackett/UI/Dashboard",
+		}
+		if strings.Join(paths, " ") != strings.Join(want, " ") {
+			t.Errorf("%s: expected paths %v, got %v", base, want, paths)
+		}
+	}
+}
+
+func TestJoinPath(t *testing.T) {
+	tests := []struct{ base, endpoint, want string }{
+		{"http://h", "/api/v2.0/server/config", "http://h/api/v2.0/server/config"},
+		{"http://h/jackett", "/api/v2.0/server/config", "http://h/jackett/api/v2.0/server/config"},
+		{"http://h/my%20jackett", "/api/v2.0/indexers/a%2Fb", "http://h/my%20jackett/api/v2.0/indexers/a%2Fb"},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.base)
+		if err := joinPath(u, tt.endpoint); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if u.String() != tt.want {
+			t.Errorf("Expected %s, got %s", tt.want, u.String())
+		}
+	}
+}
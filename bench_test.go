@@ -0,0 +1,195 @@
+package jackett
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// Generators of large synthetic responses for benchmarks. They are
+// deterministic so results are comparable between runs.
+
+// largeTorznabFeed returns a torznab RSS feed with n items
+func largeTorznabFeed(n int) []byte {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:torznab="http://torznab.com/schemas/2015/feed"><channel><title>Bench</title>`)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, `<item><title>Release.%d.1080p.WEB.x264</title><guid>https://tracker.example/details/%d</guid>`+
+			`<jackettindexer id="tracker-%d">Tracker %d</jackettindexer><pubDate>Mon, 01 Jan 2024 12:00:00 +0000</pubDate>`+
+			`<size>%d</size><link>http://localhost:9117/dl/tracker-%d/?path=%d</link><category>5040</category>`+
+			`<torznab:attr name="seeders" value="%d" /><torznab:attr name="peers" value="%d" />`+
+			`<torznab:attr name="infohash" value="%040x" /><torznab:attr name="downloadvolumefactor" value="1" /></item>`,
+			i, i, i%20, i%20, i*1024, i%20, i, i%100, i%150, i)
+	}
+	b.WriteString(`</channel></rss>`)
+	return []byte(b.String())
+}
+
+// largeIndexersXML returns an indexer list with n configured indexers, each
+// with a realistic category tree
+func largeIndexersXML(n int) []byte {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?><indexers>`)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, `<indexer id="indexer-%d" configured="true"><title>Indexer %d</title><type>private</type><caps>`+
+			`<server title="Jackett" /><limits default="100" max="100" /><searching>`+
+			`<search available="yes" supportedParams="q" /><tv-search available="yes" supportedParams="q,season,ep,imdbid" />`+
+			`<movie-search available="yes" supportedParams="q,imdbid" /></searching><categories>`, i, i)
+		for cat := 1000; cat <= 8000; cat += 1000 {
+			fmt.Fprintf(&b, `<category id="%d" name="Category %d">`, cat, cat)
+			for sub := 10; sub <= 50; sub += 10 {
+				fmt.Fprintf(&b, `<subcat id="%d" name="Subcategory %d" />`, cat+sub, cat+sub)
+			}
+			b.WriteString(`</category>`)
+		}
+		b.WriteString(`</categories></caps></indexer>`)
+	}
+	b.WriteString(`</indexers>`)
+	return []byte(b.String())
+}
+
+// bodyTransport serves fixed bodies by path from memory, so benchmarks
+// measure the client rather than the network
+type bodyTransport map[string][]byte
+
+func (t bodyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, ok := t[req.URL.Path]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: http.NoBody, Header: make(http.Header), Request: req}, nil
+	}
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Body:          io.NopCloser(strings.NewReader(string(body))),
+		ContentLength: int64(len(body)),
+		Header:        make(http.Header),
+		Request:       req,
+	}, nil
+}
+
+func newBenchClient(b *testing.B, bodies bodyTransport) *Client {
+	client, err := NewClient("http://localhost:9117", "test-api-key", &http.Client{Transport: bodies})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return client
+}
+
+func BenchmarkParseTorznabFeed(b *testing.B) {
+	data := largeTorznabFeed(10000)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		results, err := parseTorznabFeed(data)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(results) != 10000 {
+			b.Fatalf("Expected 10000 results, got %d", len(results))
+		}
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	data := largeSearchJSON(10000)
+	client := newBenchClient(b, bodyTransport{"/api/v2.0/indexers/all/results": data})
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		response, err := client.Search("release")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(response.Results) != 10000 {
+			b.Fatalf("Expected 10000 results, got %d", len(response.Results))
+		}
+	}
+}
+
+func BenchmarkGetIndexers(b *testing.B) {
+	data := largeIndexersXML(500)
+	client := newBenchClient(b, bodyTransport{"/api/v2.0/indexers/all/results/torznab": data})
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		indexers, err := client.GetIndexers()
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(indexers) != 500 {
+			b.Fatalf("Expected 500 indexers, got %d", len(indexers))
+		}
+	}
+}
+
+// BenchmarkSearchPlanExecute measures the parallel search and merge path:
+// 20 indexers answering with 500 results each
+func BenchmarkSearchPlanExecute(b *testing.B) {
+	const indexers, perIndexer = 20, 500
+	feed := largeTorznabFeed(perIndexer)
+	bodies := bodyTransport{}
+	plan := &SearchPlan{Request: SearchRequest{Query: "release"}}
+	for i := 0; i < indexers; i++ {
+		id := fmt.Sprintf("indexer-%d", i)
+		bodies["/api/v2.0/indexers/"+id+"/results/torznab/api"] = feed
+		plan.Steps = append(plan.Steps, PlanStep{
+			IndexerID: id,
+			Mode:      SearchModeGeneral,
+			Params:    url.Values{"q": {"release"}},
+			Limit:     perIndexer + 1,
+			Pages:     1,
+		})
+	}
+	client := newBenchClient(b, bodies)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		response, err := plan.Execute(context.Background(), client)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(response.Results) != indexers*perIndexer {
+			b.Fatalf("Expected %d results, got %d", indexers*perIndexer, len(response.Results))
+		}
+	}
+}
+
+func TestBenchmarkGenerators(t *testing.T) {
+	results, err := parseTorznabFeed(largeTorznabFeed(3))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(results) != 3 || results[2].Seeders != 2 || results[2].InfoHash == "" {
+		t.Errorf("Expected 3 parsed results, got %+v", results)
+	}
+
+	response, err := decodeSearchResponse(strings.NewReader(string(largeSearchJSON(3))), nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(response.Results) != 3 {
+		t.Errorf("Expected 3 results, got %d", len(response.Results))
+	}
+
+	client, err := NewClient("http://localhost:9117", "test-api-key", &http.Client{Transport: bodyTransport{
+		"/api/v2.0/indexers/all/results/torznab": largeIndexersXML(2),
+	}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	indexers, err := client.GetIndexers()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(indexers) != 2 || len(indexers[1].Categories) != 8 || len(indexers[1].Categories[0].Subcats) != 5 {
+		t.Errorf("Expected 2 indexers with 8 categories of 5 subcategories, got %+v", indexers)
+	}
+}
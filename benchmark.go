@@ -0,0 +1,84 @@
+package jackett
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// IndexerBenchmark reports how one indexer performed over the rounds of
+// BenchmarkIndexers. Latency percentiles and result counts only cover the
+// successful rounds.
+type IndexerBenchmark struct {
+	ID             string
+	Name           string
+	Rounds         int
+	Errors         int
+	P50            time.Duration
+	P95            time.Duration
+	AverageResults float64
+	LastError      string
+}
+
+// BenchmarkIndexers runs query against each configured indexer rounds times
+// (rounds < 1 means once) and reports the latency and result counts of each.
+// Indexers are benchmarked one request at a time so they don't compete for
+// bandwidth. The report is in the same order as GetIndexers; the error is
+// non-nil only if the indexers could not be listed or ctx was cancelled.
+func (c *Client) BenchmarkIndexers(ctx context.Context, query string, rounds int) ([]IndexerBenchmark, error) {
+	indexers, err := c.GetIndexersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if rounds < 1 {
+		rounds = 1
+	}
+
+	params := url.Values{}
+	params.Set("t", "search")
+	params.Set("q", query)
+
+	report := make([]IndexerBenchmark, 0, len(indexers))
+	for _, indexer := range indexers {
+		bench := IndexerBenchmark{ID: indexer.ID, Name: indexer.Name, Rounds: rounds}
+
+		var latencies []time.Duration
+		var results int
+		for i := 0; i < rounds; i++ {
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+
+			start := c.clock.Now()
+			response, err := c.TorznabSearch(ctx, indexer.ID, params)
+			if err != nil {
+				bench.Errors++
+				bench.LastError = err.Error()
+				continue
+			}
+			latencies = append(latencies, c.clock.Now().Sub(start))
+			results += len(response.Results)
+		}
+
+		if len(latencies) > 0 {
+			sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+			bench.P50 = percentile(latencies, 50)
+			bench.P95 = percentile(latencies, 95)
+			bench.AverageResults = float64(results) / float64(len(latencies))
+		}
+		report = append(report, bench)
+	}
+
+	return report, ctx.Err()
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
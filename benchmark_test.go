@@ -0,0 +1,94 @@
+package jackett
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// latencyTransport serves canned responses, advancing clock by the latency
+// configured for the requested path
+type latencyTransport struct {
+	clock     *stepClock
+	latencies map[string][]time.Duration
+	bodies    map[string]string
+}
+
+func (l *latencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if delays := l.latencies[req.URL.Path]; len(delays) > 0 {
+		l.clock.now = l.clock.now.Add(delays[0])
+		l.latencies[req.URL.Path] = delays[1:]
+	}
+	body, ok := l.bodies[req.URL.Path]
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 20; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	if got := percentile(sorted, 50); got != 10*time.Millisecond {
+		t.Errorf("Expected p50 10ms, got %v", got)
+	}
+	if got := percentile(sorted, 95); got != 19*time.Millisecond {
+		t.Errorf("Expected p95 19ms, got %v", got)
+	}
+	if got := percentile(sorted[:1], 95); got != time.Millisecond {
+		t.Errorf("Expected single sample p95 1ms, got %v", got)
+	}
+}
+
+func TestBenchmarkIndexers(t *testing.T) {
+	clock := &stepClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	transport := &latencyTransport{
+		clock: clock,
+		latencies: map[string][]time.Duration{
+			"/api/v2.0/indexers/configured-indexer/results/torznab/api": {300 * time.Millisecond, 100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		bodies: map[string]string{
+			"/api/v2.0/indexers/all/results/torznab":                    allIndexersXML,
+			"/api/v2.0/indexers/configured-indexer/results/torznab/api": torznabFeedXML,
+		},
+	}
+
+	client, err := NewClientWithOptions("http://localhost:9117", "test-api-key",
+		WithHTTPClient(&http.Client{Transport: transport}), WithClock(clock))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	report, err := client.BenchmarkIndexers(context.Background(), "test", 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(report) != 2 {
+		t.Fatalf("Expected 2 benchmarks, got %d", len(report))
+	}
+
+	ok, failing := report[0], report[1]
+	if ok.ID != "configured-indexer" || ok.Errors != 0 {
+		t.Errorf("Expected error-free benchmark for 'configured-indexer', got %+v", ok)
+	}
+	if ok.P50 != 200*time.Millisecond || ok.P95 != 300*time.Millisecond {
+		t.Errorf("Expected p50 200ms and p95 300ms, got %v and %v", ok.P50, ok.P95)
+	}
+	if ok.AverageResults != 1 {
+		t.Errorf("Expected 1 result per round, got %f", ok.AverageResults)
+	}
+	if failing.Errors != 3 || failing.LastError == "" {
+		t.Errorf("Expected 3 errors for 'unconfigured-indexer', got %+v", failing)
+	}
+}
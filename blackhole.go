@@ -0,0 +1,106 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxFilenameBytes keeps saved names within common filesystem limits, leaving
+// room for a collision suffix and the extension
+const maxFilenameBytes = 200
+
+// SaveTorrent fetches result (see SearchResult.Fetch) and saves it in dir
+// under a sanitized name derived from its title, adding a numeric suffix
+// rather than overwriting an existing file. Torrents are saved as .torrent
+// files and magnet URIs as .magnet files, which most blackhole-watching
+// torrent clients accept; NZB files of usenet results are saved as .nzb.
+// An empty dir means Jackett's configured blackhole directory, which must
+// then be reachable from this machine. SaveTorrent returns the path of the
+// saved file.
+func (c *Client) SaveTorrent(ctx context.Context, result SearchResult, dir string) (string, error) {
+	if dir == "" {
+		config, err := c.GetServerConfigContext(ctx)
+		if err != nil {
+			return "", err
+		}
+		dir, _ = config["blackholedir"].(string)
+		if dir == "" {
+			return "", fmt.Errorf("save torrent error: no directory given and Jackett has no blackhole directory configured")
+		}
+	}
+
+	release, err := result.Fetch(ctx, c)
+	if err != nil {
+		return "", err
+	}
+	path, err := release.Save(dir, result.Title)
+	if err != nil {
+		return "", fmt.Errorf("save torrent error: %w", err)
+	}
+	return path, nil
+}
+
+// Save writes the release to dir under title, sanitized, adding a numeric
+// suffix rather than overwriting an existing file, and returns the path of
+// the saved file. Like SaveTorrent, it saves torrents as .torrent files,
+// magnet URIs as .magnet files and NZB files as .nzb.
+func (d *DownloadedRelease) Save(dir, title string) (string, error) {
+	data, ext := d.Torrent, ".torrent"
+	switch {
+	case d.IsMagnet():
+		data, ext = []byte(d.MagnetURI+"\n"), ".magnet"
+	case d.IsNZB():
+		data, ext = d.NZB, ".nzb"
+	}
+
+	base := sanitizeFilename(title)
+	for i := 1; ; i++ {
+		name := base
+		if i > 1 {
+			name += " (" + strconv.Itoa(i) + ")"
+		}
+		path := filepath.Join(dir, name+ext)
+
+		err := writeFileExclusive(path, data, 0644)
+		if err == nil {
+			return path, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return "", err
+		}
+	}
+}
+
+// sanitizeFilename turns a release title into a safe file name: path
+// separators, characters reserved on Windows and control characters become
+// underscores, leading and trailing dots and spaces are trimmed, and the name
+// is truncated on a rune boundary
+func sanitizeFilename(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, title)
+	name = strings.Trim(name, ". ")
+
+	if len(name) > maxFilenameBytes {
+		name = name[:maxFilenameBytes]
+		for !utf8.ValidString(name) {
+			name = name[:len(name)-1]
+		}
+		name = strings.TrimRight(name, ". ")
+	}
+
+	if name == "" {
+		return "release"
+	}
+	return name
+}
@@ -0,0 +1,99 @@
+package jackett
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Show.Name.S01E02.1080p", "Show.Name.S01E02.1080p"},
+		{"../../etc/passwd", "_.._etc_passwd"},
+		{`What? A "Movie": Part 1/2`, "What_ A _Movie__ Part 1_2"},
+		{"tab\there", "tab_here"},
+		{" .hidden. ", "hidden"},
+		{"...", "release"},
+		{strings.Repeat("é", 150), strings.Repeat("é", 100)},
+	}
+	for _, tt := range tests {
+		if got := sanitizeFilename(tt.in); got != tt.want {
+			t.Errorf("sanitizeFilename(%q) = %q, expected %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSaveTorrent(t *testing.T) {
+	blackhole := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2.0/server/config":
+			json.NewEncoder(w).Encode(map[string]interface{}{"blackholedir": blackhole})
+		case "/dl/torrent":
+			w.Write([]byte("torrent file data"))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "test-api-key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	result := SearchResult{Title: "Movie: The Sequel", Link: server.URL + "/dl/torrent"}
+	first, err := client.SaveTorrent(context.Background(), result, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	second, err := client.SaveTorrent(context.Background(), result, blackhole)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if first != filepath.Join(blackhole, "Movie_ The Sequel.torrent") {
+		t.Errorf("Expected file in blackhole dir, got %s", first)
+	}
+	if second != filepath.Join(blackhole, "Movie_ The Sequel (2).torrent") {
+		t.Errorf("Expected collision suffix, got %s", second)
+	}
+	if data, _ := os.ReadFile(second); string(data) != "torrent file data" {
+		t.Errorf("Expected torrent data in %s, got %q", second, data)
+	}
+
+	magnet, err := client.SaveTorrent(context.Background(), SearchResult{Title: "Magnet", MagnetURI: testMagnetURI}, blackhole)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if data, _ := os.ReadFile(magnet); filepath.Ext(magnet) != ".magnet" || strings.TrimSpace(string(data)) != testMagnetURI {
+		t.Errorf("Expected .magnet file with URI, got %s containing %q", magnet, data)
+	}
+
+	entries, _ := os.ReadDir(blackhole)
+	if len(entries) != 3 {
+		t.Errorf("Expected 3 files without temporaries, got %d", len(entries))
+	}
+}
+
+func TestDownloadedReleaseSave(t *testing.T) {
+	dir := t.TempDir()
+	release := &DownloadedRelease{NZB: []byte("<nzb/>")}
+
+	for _, want := range []string{"Show_S01.nzb", "Show_S01 (2).nzb"} {
+		path, err := release.Save(dir, "Show/S01")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if path != filepath.Join(dir, want) {
+			t.Errorf("Expected %s, got %s", want, path)
+		}
+	}
+}
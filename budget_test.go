@@ -0,0 +1,57 @@
+package jackett_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cehbz/jackett"
+	"github.com/cehbz/jackett/jacketttest"
+)
+
+func TestMaxConcurrentRequestsSharedBudget(t *testing.T) {
+	server := jacketttest.NewServer()
+	defer server.Close()
+	server.AddIndexer(jackett.Indexer{ID: "test"}, jackett.SearchResult{Title: "Ubuntu 22.04"})
+	link := server.AddTorrent("test", "ubuntu.torrent", []byte("d4:infod4:name6:ubuntuee"))
+	server.SetLatency(10 * time.Millisecond)
+
+	client, err := server.NewClient(jackett.WithMaxConcurrentRequests(2))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	links := make([]string, 8)
+	for i := range links {
+		links[i] = link
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		results, err := client.DownloadTorrents(context.Background(), links, 8)
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		for _, r := range results {
+			if r.Err != nil {
+				t.Errorf("Expected no download error, got %v", r.Err)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 4; i++ {
+			if _, err := client.Search("ubuntu"); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		}
+	}()
+	wg.Wait()
+
+	if peak := server.PeakConcurrency(); peak > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got %d", peak)
+	}
+}
@@ -0,0 +1,99 @@
+package jackett
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// responseCache memoizes raw responses of rarely changing endpoints. Raw
+// bytes are cached rather than decoded values so that every caller decodes
+// its own copy and can't mutate another's.
+type responseCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	data    []byte
+	expires time.Time
+}
+
+// WithMetadataCache caches the indexer list, indexer capabilities and server
+// config for ttl. These change rarely but are needed for every
+// capability-aware search. Changes made through this client's admin methods
+// invalidate the cache; call Invalidate after changing Jackett by other means.
+func WithMetadataCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cache = &responseCache{ttl: ttl, entries: make(map[string]cacheEntry)}
+	}
+}
+
+// CacheConfig sets the lifetimes of the client's caches. A zero duration
+// leaves that cache disabled.
+type CacheConfig struct {
+	// Metadata is the TTL of indexer lists, capabilities and server config
+	// (see WithMetadataCache)
+	Metadata time.Duration
+	// Search is the TTL of search responses (see WithSearchCache)
+	Search time.Duration
+}
+
+// WithCache enables the metadata and search caches according to cfg
+func WithCache(cfg CacheConfig) Option {
+	return func(c *Client) {
+		if cfg.Metadata > 0 {
+			WithMetadataCache(cfg.Metadata)(c)
+		}
+		if cfg.Search > 0 {
+			WithSearchCache(cfg.Search)(c)
+		}
+	}
+}
+
+// Invalidate discards everything cached by WithMetadataCache
+func (c *Client) Invalidate() {
+	c.cache.invalidate()
+}
+
+// cached returns the cached response for key, calling fetch and caching its
+// result on a miss. Errors are not cached. Concurrent misses share one fetch.
+// Calls with a per-call API key always fetch, so that they neither see nor
+// leave behind data fetched with another key.
+func (c *Client) cached(ctx context.Context, key string, fetch func() ([]byte, error)) ([]byte, error) {
+	if hasAPIKeyOverride(ctx) {
+		return fetch()
+	}
+	if c.cache == nil {
+		return c.coalesce(key, fetch)
+	}
+
+	now := c.clock.Now()
+	c.cache.mu.Lock()
+	entry, ok := c.cache.entries[key]
+	c.cache.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.data, nil
+	}
+
+	data, err := c.coalesce(key, fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache.mu.Lock()
+	c.cache.entries[key] = cacheEntry{data: data, expires: now.Add(c.cache.ttl)}
+	c.cache.mu.Unlock()
+	return data, nil
+}
+
+func (r *responseCache) invalidate() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = make(map[string]cacheEntry)
+}
@@ -0,0 +1,165 @@
+package jackett_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cehbz/jackett"
+	"github.com/cehbz/jackett/jacketttest"
+)
+
+// newCacheFake serves two indexers, the first with TV search caps
+func newCacheFake(t *testing.T) *jacketttest.Server {
+	server := jacketttest.NewServer()
+	t.Cleanup(server.Close)
+
+	server.AddIndexer(jackett.Indexer{
+		ID: "test-indexer",
+		Caps: &jackett.Caps{
+			Server: "Jackett",
+			Limits: jackett.Limits{Default: 50, Max: 100},
+			Searching: jackett.Searching{
+				Search:   &jackett.SearchType{Available: true, SupportedParams: []string{"q"}},
+				TVSearch: &jackett.SearchType{Available: true, SupportedParams: []string{"q", "season", "ep"}},
+			},
+		},
+		Categories: []jackett.Category{{ID: 5000, Name: "TV", Subcats: []jackett.Subcat{{ID: 5040, Name: "TV/HD"}}}},
+	})
+	server.AddIndexer(jackett.Indexer{ID: "other-indexer"})
+	server.SetServerConfig(map[string]interface{}{"app_version": "0.22.0", "port": 9117})
+	return server
+}
+
+func TestMetadataCacheHitsWithinTTL(t *testing.T) {
+	server := newCacheFake(t)
+	clock := jacketttest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	client, err := server.NewClient(jackett.WithClock(clock), jackett.WithMetadataCache(time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		indexers, err := client.GetIndexers()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(indexers) != 2 {
+			t.Fatalf("Expected 2 indexers, got %d", len(indexers))
+		}
+	}
+	if n := len(server.Requests()); n != 1 {
+		t.Errorf("Expected 1 request within the TTL, got %d", n)
+	}
+
+	clock.Advance(time.Minute)
+	if _, err := client.GetIndexers(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := len(server.Requests()); n != 2 {
+		t.Errorf("Expected a new request after the TTL, got %d requests", n)
+	}
+}
+
+func TestMetadataCacheReturnsIndependentCopies(t *testing.T) {
+	server := newCacheFake(t)
+	client, err := server.NewClient(jackett.WithMetadataCache(time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	config, err := client.GetServerConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	config["port"] = 1234
+
+	config, err = client.GetServerConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if config["port"] != float64(9117) {
+		t.Errorf("Expected cached config to be unaffected by mutation, got port %v", config["port"])
+	}
+	if n := len(server.Requests()); n != 1 {
+		t.Errorf("Expected 1 request, got %d", n)
+	}
+}
+
+func TestMetadataCacheInvalidation(t *testing.T) {
+	server := newCacheFake(t)
+	client, err := server.NewClient(jackett.WithMetadataCache(time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	config, err := client.GetServerConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := client.SetServerConfig(config); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.GetServerConfig(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// GET, POST, then GET again because the POST invalidated the cache
+	if n := len(server.Requests()); n != 3 {
+		t.Errorf("Expected 3 requests, got %d", n)
+	}
+
+	client.Invalidate()
+	if _, err := client.GetServerConfig(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := len(server.Requests()); n != 4 {
+		t.Errorf("Expected a new request after Invalidate, got %d requests", n)
+	}
+}
+
+func TestGetIndexerCaps(t *testing.T) {
+	server := newCacheFake(t)
+	client, err := server.NewClient(jackett.WithMetadataCache(time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		caps, categories, err := client.GetIndexerCaps(context.Background(), "test-indexer")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if caps.Limits.Default != 50 {
+			t.Errorf("Expected default limit 50, got %d", caps.Limits.Default)
+		}
+		if caps.Searching.TVSearch == nil || !caps.Searching.TVSearch.Available {
+			t.Error("Expected tv-search to be available")
+		}
+		if caps.Searching.MovieSearch != nil {
+			t.Error("Expected no movie-search")
+		}
+		if len(categories) != 1 || len(categories[0].Subcats) != 1 || categories[0].Subcats[0].ID != 5040 {
+			t.Errorf("Expected TV category with one subcategory, got %+v", categories)
+		}
+	}
+	if n := len(server.Requests()); n != 1 {
+		t.Errorf("Expected 1 request, got %d", n)
+	}
+}
+
+func TestMetadataCacheDisabledByDefault(t *testing.T) {
+	server := newCacheFake(t)
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetIndexers(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if n := len(server.Requests()); n != 2 {
+		t.Errorf("Expected 2 requests without a cache, got %d", n)
+	}
+}
@@ -0,0 +1,99 @@
+package jackett
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CallInfo receives metadata about the HTTP responses behind a call, such
+// as rate-limit and caching headers added by a proxy in front of Jackett.
+// Attach one to a call's context with ContextWithCallInfo and read it once
+// the call has returned.
+type CallInfo struct {
+	mu       sync.Mutex
+	received time.Time
+
+	// Requests counts the requests sent, including retries and failed
+	// attempts. It stays zero if the call was answered from a cache.
+	Requests int
+	// StatusCode and Header are those of the last response received
+	StatusCode int
+	Header     http.Header
+	// Duration is the time taken by the last request
+	Duration time.Duration
+}
+
+// callInfoKey is the context key of a CallInfo
+type callInfoKey struct{}
+
+// ContextWithCallInfo returns a copy of ctx that makes calls using it record
+// response metadata into info:
+//
+//	var info jackett.CallInfo
+//	indexers, err := client.GetIndexersContext(jackett.ContextWithCallInfo(ctx, &info))
+//	log.Printf("status %d, %s remaining", info.StatusCode, info.Header.Get("X-RateLimit-Remaining"))
+func ContextWithCallInfo(ctx context.Context, info *CallInfo) context.Context {
+	return context.WithValue(ctx, callInfoKey{}, info)
+}
+
+// RateLimit reads the limit, remaining requests and reset time from the
+// X-RateLimit-* or RateLimit-* headers of the last response. ok is false if
+// they are absent.
+func (info *CallInfo) RateLimit() (limit, remaining int, reset time.Time, ok bool) {
+	info.mu.Lock()
+	header, received := info.Header, info.received
+	info.mu.Unlock()
+	if header == nil {
+		return 0, 0, time.Time{}, false
+	}
+
+	get := func(name string) string {
+		if v := header.Get("X-RateLimit-" + name); v != "" {
+			return v
+		}
+		return header.Get("RateLimit-" + name)
+	}
+	limit, errLimit := strconv.Atoi(get("Limit"))
+	remaining, errRemaining := strconv.Atoi(get("Remaining"))
+	if errLimit != nil && errRemaining != nil {
+		return 0, 0, time.Time{}, false
+	}
+	if seconds, err := strconv.ParseInt(get("Reset"), 10, 64); err == nil {
+		// Large values are Unix times, small ones delays
+		if seconds > 1e9 {
+			reset = time.Unix(seconds, 0)
+		} else {
+			reset = received.Add(time.Duration(seconds) * time.Second)
+		}
+	}
+	return limit, remaining, reset, true
+}
+
+// ServerTiming returns the Server-Timing header of the last response
+func (info *CallInfo) ServerTiming() string {
+	info.mu.Lock()
+	defer info.mu.Unlock()
+	return info.Header.Get("Server-Timing")
+}
+
+// recordCall notes a request made for a call with ctx, sent at sent and
+// answered at received; resp is nil if no response was received
+func recordCall(ctx context.Context, resp *http.Response, sent, received time.Time) {
+	info, ok := ctx.Value(callInfoKey{}).(*CallInfo)
+	if !ok || info == nil {
+		return
+	}
+	info.mu.Lock()
+	defer info.mu.Unlock()
+
+	info.Requests++
+	info.Duration = received.Sub(sent)
+	info.received = received
+	if resp != nil {
+		info.StatusCode = resp.StatusCode
+		info.Header = resp.Header.Clone()
+	}
+}
@@ -0,0 +1,60 @@
+package jackett
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCallInfo(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.Header().Set("X-RateLimit-Reset", "30")
+		w.Header().Set("Server-Timing", "jackett;dur=12")
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("X-Cache", "HIT")
+		w.Write([]byte(torznabFeedXML))
+	}))
+	defer server.Close()
+
+	client, _ := NewClientWithOptions(server.URL, "key", WithRetry(RetryPolicy{MaxAttempts: 2}))
+	before := time.Now()
+
+	var info CallInfo
+	ctx := ContextWithCallInfo(context.Background(), &info)
+	if _, err := client.TorznabSearch(ctx, "all", url.Values{"q": {"ubuntu"}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if info.Requests != 2 || info.StatusCode != http.StatusOK || info.Header.Get("X-Cache") != "HIT" {
+		t.Errorf("Expected the retried call's last response, got %d requests, status %d", info.Requests, info.StatusCode)
+	}
+	limit, remaining, reset, ok := info.RateLimit()
+	if !ok || limit != 100 || remaining != 42 || reset.Before(before.Add(30*time.Second)) || reset.After(time.Now().Add(30*time.Second)) {
+		t.Errorf("Unexpected rate limit %d/%d reset %v (%v)", remaining, limit, reset, ok)
+	}
+	if info.ServerTiming() != "jackett;dur=12" {
+		t.Errorf("Unexpected Server-Timing %q", info.ServerTiming())
+	}
+}
+
+func TestCallInfoRateLimitAbsent(t *testing.T) {
+	var info CallInfo
+	if _, _, _, ok := info.RateLimit(); ok {
+		t.Error("Expected no rate limit without a response")
+	}
+	info.Header = http.Header{"Ratelimit-Remaining": {"5"}, "Ratelimit-Reset": {"1700000000"}}
+	_, remaining, reset, ok := info.RateLimit()
+	if !ok || remaining != 5 || reset.Unix() != 1700000000 {
+		t.Errorf("Expected the IETF headers to be read, got %d %v %v", remaining, reset, ok)
+	}
+}
@@ -0,0 +1,190 @@
+package jackett
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// IsAvailable reports whether the search function is available; false for
+// a nil SearchType
+func (st *SearchType) IsAvailable() bool {
+	return st != nil && st.Available
+}
+
+// Params returns the parameters the search function supports; nil for a
+// nil SearchType
+func (st *SearchType) Params() []string {
+	if st == nil {
+		return nil
+	}
+	return st.SupportedParams
+}
+
+// UnmarshalXML decodes a torznab caps search element, e.g.
+// <tv-search available="yes" supportedParams="q,season,ep"/>
+func (st *SearchType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var t TorznabSearchType
+	if err := d.DecodeElement(&t, &start); err != nil {
+		return err
+	}
+	*st = *convertSearchType(&t)
+	return nil
+}
+
+// MarshalXML encodes st as a torznab caps search element
+func (st SearchType) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	available := "no"
+	if st.Available {
+		available = "yes"
+	}
+	return e.EncodeElement(TorznabSearchType{Available: available, SupportedParams: strings.Join(st.SupportedParams, ",")}, start)
+}
+
+// parseAvailable parses a torznab available attribute, "yes" or "no"
+func parseAvailable(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.EqualFold(s, "yes") || strings.EqualFold(s, "true")
+}
+
+// parseParams splits a torznab supportedParams attribute
+func parseParams(s string) []string {
+	var params []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// Supports reports whether the search function is available and accepts
+// param, ignoring case
+func (st *SearchType) Supports(param string) bool {
+	if !st.IsAvailable() {
+		return false
+	}
+	for _, p := range st.Params() {
+		if strings.EqualFold(p, param) {
+			return true
+		}
+	}
+	return false
+}
+
+// searchModes lists every search mode, for predicates over all of them
+var searchModes = []SearchMode{SearchModeGeneral, SearchModeTV, SearchModeMovie, SearchModeMusic, SearchModeBook}
+
+// SearchType returns the search function of mode; music searches fall back
+// to the audio search function if there is no music one. It is nil if the
+// capabilities are unknown.
+func (c *Caps) SearchType(mode SearchMode) *SearchType {
+	if c == nil {
+		return nil
+	}
+	switch mode {
+	case SearchModeGeneral:
+		return c.Searching.Search
+	case SearchModeTV:
+		return c.Searching.TVSearch
+	case SearchModeMovie:
+		return c.Searching.MovieSearch
+	case SearchModeMusic:
+		if !c.Searching.MusicSearch.IsAvailable() && c.Searching.AudioSearch != nil {
+			return c.Searching.AudioSearch
+		}
+		return c.Searching.MusicSearch
+	case SearchModeBook:
+		return c.Searching.BookSearch
+	}
+	return nil
+}
+
+// Supports reports whether the search mode is available; false if the
+// capabilities are unknown
+func (c *Caps) Supports(mode SearchMode) bool {
+	return c.SearchType(mode).IsAvailable()
+}
+
+// SupportsParam reports whether any of modes (any mode at all if none are
+// given) accepts param, e.g. "imdbid"
+func (c *Caps) SupportsParam(param string, modes ...SearchMode) bool {
+	if len(modes) == 0 {
+		modes = searchModes
+	}
+	for _, mode := range modes {
+		if c.SearchType(mode).Supports(param) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultLimit returns the page size the indexer uses when none is
+// requested; zero if unknown
+func (c *Caps) DefaultLimit() int {
+	if c == nil {
+		return 0
+	}
+	return c.Limits.Default
+}
+
+// MaxLimit returns the largest page size the indexer accepts; zero if
+// unknown
+func (c *Caps) MaxLimit() int {
+	if c == nil {
+		return 0
+	}
+	return c.Limits.Max
+}
+
+// Clamp returns the page size to request for limit: the default if limit
+// isn't positive, and at most Max. It is zero if limit isn't positive and
+// the indexer has no default.
+func (l Limits) Clamp(limit int) int {
+	if limit <= 0 {
+		limit = l.Default
+	}
+	if l.Max > 0 && (limit <= 0 || limit > l.Max) {
+		limit = l.Max
+	}
+	return limit
+}
+
+// parseLimit parses a torznab limit attribute, zero if it is missing or
+// malformed
+func parseLimit(s string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// SupportsSearch reports whether the indexer offers free-text search
+func (i Indexer) SupportsSearch() bool { return i.Caps.Supports(SearchModeGeneral) }
+
+// SupportsTVSearch reports whether the indexer offers TV search
+func (i Indexer) SupportsTVSearch() bool { return i.Caps.Supports(SearchModeTV) }
+
+// SupportsMovieSearch reports whether the indexer offers movie search
+func (i Indexer) SupportsMovieSearch() bool { return i.Caps.Supports(SearchModeMovie) }
+
+// SupportsMusicSearch reports whether the indexer offers music (or audio)
+// search
+func (i Indexer) SupportsMusicSearch() bool { return i.Caps.Supports(SearchModeMusic) }
+
+// SupportsBookSearch reports whether the indexer offers book search
+func (i Indexer) SupportsBookSearch() bool { return i.Caps.Supports(SearchModeBook) }
+
+// SupportsParam reports whether any of the indexer's search modes (or just
+// the given ones) accepts param, e.g. indexer.SupportsParam("imdbid")
+func (i Indexer) SupportsParam(param string, modes ...SearchMode) bool {
+	return i.Caps.SupportsParam(param, modes...)
+}
+
+// DefaultLimit returns the indexer's default page size; zero if unknown
+func (i Indexer) DefaultLimit() int { return i.Caps.DefaultLimit() }
+
+// MaxLimit returns the indexer's largest page size; zero if unknown
+func (i Indexer) MaxLimit() int { return i.Caps.MaxLimit() }
@@ -0,0 +1,137 @@
+package jackett
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func capsIndexer() Indexer {
+	return Indexer{Caps: &Caps{
+		Limits: Limits{Default: 50, Max: 100},
+		Searching: Searching{
+			Search:      &SearchType{Available: true, SupportedParams: []string{"q"}},
+			TVSearch:    &SearchType{Available: true, SupportedParams: []string{"q", "season", "ep", "imdbid"}},
+			MovieSearch: &SearchType{Available: false, SupportedParams: []string{"q", "tmdbid"}},
+			AudioSearch: &SearchType{Available: true, SupportedParams: []string{"q", "artist"}},
+		},
+	}}
+}
+
+func TestIndexerCapabilities(t *testing.T) {
+	indexer := capsIndexer()
+
+	if !indexer.SupportsSearch() || !indexer.SupportsTVSearch() || indexer.SupportsMovieSearch() || indexer.SupportsBookSearch() {
+		t.Error("Unexpected search mode availability")
+	}
+	if !indexer.SupportsMusicSearch() {
+		t.Error("Expected music search to fall back to audio search")
+	}
+
+	if !indexer.SupportsParam("imdbid") || !indexer.SupportsParam("IMDBID", SearchModeTV) {
+		t.Error("Expected imdbid to be supported by TV search")
+	}
+	if indexer.SupportsParam("imdbid", SearchModeGeneral, SearchModeMovie) {
+		t.Error("Expected imdbid not to be supported by general or movie search")
+	}
+	if indexer.SupportsParam("tmdbid") {
+		t.Error("Expected the params of an unavailable mode to be ignored")
+	}
+	if !indexer.SupportsParam("artist", SearchModeMusic) {
+		t.Error("Expected the audio search params for music")
+	}
+
+	if indexer.DefaultLimit() != 50 || indexer.MaxLimit() != 100 {
+		t.Errorf("Expected limits 50 and 100, got %d and %d", indexer.DefaultLimit(), indexer.MaxLimit())
+	}
+	if got := indexer.Caps.SearchType(SearchModeTV).Params(); !reflect.DeepEqual(got, []string{"q", "season", "ep", "imdbid"}) {
+		t.Errorf("Unexpected params %v", got)
+	}
+}
+
+func TestIndexerCapabilitiesUnknown(t *testing.T) {
+	var indexer Indexer
+	if indexer.SupportsSearch() || indexer.SupportsParam("q") || indexer.DefaultLimit() != 0 || indexer.MaxLimit() != 0 {
+		t.Error("Expected nothing to be supported without capabilities")
+	}
+
+	indexer.Caps = &Caps{}
+	if indexer.DefaultLimit() != 0 || indexer.SupportsTVSearch() {
+		t.Error("Expected missing limits and search types to be unknown")
+	}
+	if (*SearchType)(nil).Params() != nil {
+		t.Error("Expected no params for a nil search type")
+	}
+}
+
+func TestLimitsClamp(t *testing.T) {
+	tests := []struct {
+		limits Limits
+		limit  int
+		want   int
+	}{
+		{Limits{Default: 50, Max: 100}, 0, 50},
+		{Limits{Default: 50, Max: 100}, 75, 75},
+		{Limits{Default: 50, Max: 100}, 500, 100},
+		{Limits{Default: 500, Max: 100}, 0, 100},
+		{Limits{Max: 100}, 0, 100},
+		{Limits{}, 0, 0},
+		{Limits{}, 1000, 1000},
+	}
+	for _, tt := range tests {
+		if got := tt.limits.Clamp(tt.limit); got != tt.want {
+			t.Errorf("%+v.Clamp(%d) = %d, want %d", tt.limits, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestLimitsParsing(t *testing.T) {
+	caps, _ := convertCaps(TorznabCaps{Limits: TorznabLimits{Default: " 50 ", Max: "lots"}})
+	if caps.Limits != (Limits{Default: 50}) {
+		t.Errorf("Expected malformed limits to parse as zero, got %+v", caps.Limits)
+	}
+
+	for _, data := range []string{`{"default": 50, "max": 100}`, `{"default": "50", "max": "100"}`} {
+		var limits Limits
+		if err := json.Unmarshal([]byte(data), &limits); err != nil || limits != (Limits{Default: 50, Max: 100}) {
+			t.Errorf("%s: expected 50 and 100, got %+v, %v", data, limits, err)
+		}
+	}
+	var limits Limits
+	if err := json.Unmarshal([]byte(`{"default": "", "max": null}`), &limits); err != nil || limits != (Limits{}) {
+		t.Errorf("Expected empty limits, got %+v, %v", limits, err)
+	}
+}
+
+func TestSearchTypeDecoding(t *testing.T) {
+	want := SearchType{Available: true, SupportedParams: []string{"q", "season", "ep"}}
+
+	var st SearchType
+	if err := xml.Unmarshal([]byte(`<tv-search available="Yes" supportedParams="q, season,ep,"/>`), &st); err != nil || !reflect.DeepEqual(st, want) {
+		t.Errorf("Expected %+v from XML, got %+v, %v", want, st, err)
+	}
+	data, err := xml.Marshal(st)
+	if err != nil || string(data) != `<SearchType available="yes" supportedParams="q,season,ep"></SearchType>` {
+		t.Errorf("Unexpected XML %s, %v", data, err)
+	}
+
+	for _, data := range []string{
+		`{"available": true, "supported_params": ["q", "season", "ep"]}`,
+		`{"available": "yes", "supported_params": "q,season,ep"}`,
+	} {
+		var st SearchType
+		if err := json.Unmarshal([]byte(data), &st); err != nil || !reflect.DeepEqual(st, want) {
+			t.Errorf("%s: expected %+v, got %+v, %v", data, want, st, err)
+		}
+	}
+	st = SearchType{}
+	if err := json.Unmarshal([]byte(`{"available": "no"}`), &st); err != nil || st.Available || st.SupportedParams != nil {
+		t.Errorf("Expected an unavailable search type, got %+v, %v", st, err)
+	}
+
+	caps, _ := convertCaps(TorznabCaps{Searching: TorznabSearching{MovieSearch: &TorznabSearchType{Available: "no", SupportedParams: "q"}}})
+	if caps.Searching.MovieSearch.Available || !reflect.DeepEqual(caps.Searching.MovieSearch.SupportedParams, []string{"q"}) {
+		t.Errorf("Unexpected movie search %+v", caps.Searching.MovieSearch)
+	}
+}
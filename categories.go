@@ -0,0 +1,59 @@
+package jackett
+
+import "strings"
+
+// FlatCategory is a category or subcategory of an indexer, as listed by
+// Indexer.FlattenedCategories
+type FlatCategory struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// ParentID is the ID of a subcategory's top-level category; zero for a
+	// top-level category
+	ParentID int `json:"parent_id,omitempty"`
+}
+
+// IsSubcategory reports whether c is a subcategory
+func (c FlatCategory) IsSubcategory() bool {
+	return c.ParentID != 0
+}
+
+// FlattenedCategories lists the indexer's categories, each followed by its
+// subcategories
+func (i Indexer) FlattenedCategories() []FlatCategory {
+	return flattenCategories(i.Categories)
+}
+
+// FindCategory looks up a category or subcategory of the indexer by ID
+func (i Indexer) FindCategory(id int) (FlatCategory, bool) {
+	for _, cat := range flattenCategories(i.Categories) {
+		if cat.ID == id {
+			return cat, true
+		}
+	}
+	return FlatCategory{}, false
+}
+
+// CategoriesMatching returns the categories and subcategories of the indexer
+// whose name contains name, ignoring case; e.g. "hd" finds "Movies/HD" and
+// "TV/HD"
+func (i Indexer) CategoriesMatching(name string) []FlatCategory {
+	name = strings.ToLower(name)
+	var matched []FlatCategory
+	for _, cat := range flattenCategories(i.Categories) {
+		if strings.Contains(strings.ToLower(cat.Name), name) {
+			matched = append(matched, cat)
+		}
+	}
+	return matched
+}
+
+func flattenCategories(categories []Category) []FlatCategory {
+	var flat []FlatCategory
+	for _, cat := range categories {
+		flat = append(flat, FlatCategory{ID: cat.ID, Name: cat.Name})
+		for _, sub := range cat.Subcats {
+			flat = append(flat, FlatCategory{ID: sub.ID, Name: sub.Name, ParentID: cat.ID})
+		}
+	}
+	return flat
+}
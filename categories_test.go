@@ -0,0 +1,62 @@
+package jackett
+
+import (
+	"reflect"
+	"testing"
+)
+
+func categoryIndexer() Indexer {
+	return Indexer{Categories: []Category{
+		{ID: 2000, Name: "Movies", Subcats: []Subcat{{ID: 2040, Name: "Movies/HD"}, {ID: 2045, Name: "Movies/UHD"}}},
+		{ID: 5000, Name: "TV", Subcats: []Subcat{{ID: 5040, Name: "TV/HD"}}},
+		{ID: 100001, Name: "Custom Category 1"},
+	}}
+}
+
+func TestFlattenedCategories(t *testing.T) {
+	want := []FlatCategory{
+		{ID: 2000, Name: "Movies"},
+		{ID: 2040, Name: "Movies/HD", ParentID: 2000},
+		{ID: 2045, Name: "Movies/UHD", ParentID: 2000},
+		{ID: 5000, Name: "TV"},
+		{ID: 5040, Name: "TV/HD", ParentID: 5000},
+		{ID: 100001, Name: "Custom Category 1"},
+	}
+	if got := categoryIndexer().FlattenedCategories(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+	if got := (Indexer{}).FlattenedCategories(); got != nil {
+		t.Errorf("Expected no categories, got %+v", got)
+	}
+}
+
+func TestFindCategory(t *testing.T) {
+	indexer := categoryIndexer()
+
+	cat, ok := indexer.FindCategory(5040)
+	if !ok || cat.Name != "TV/HD" || !cat.IsSubcategory() || cat.ParentID != 5000 {
+		t.Errorf("Expected the TV/HD subcategory, got %+v, %t", cat, ok)
+	}
+	cat, ok = indexer.FindCategory(100001)
+	if !ok || cat.Name != "Custom Category 1" || cat.IsSubcategory() {
+		t.Errorf("Expected the custom category, got %+v, %t", cat, ok)
+	}
+	if _, ok := indexer.FindCategory(3000); ok {
+		t.Error("Expected category 3000 not to be found")
+	}
+}
+
+func TestCategoriesMatching(t *testing.T) {
+	indexer := categoryIndexer()
+
+	var ids []int
+	for _, cat := range indexer.CategoriesMatching("hd") {
+		ids = append(ids, cat.ID)
+	}
+	if !reflect.DeepEqual(ids, []int{2040, 2045, 5040}) {
+		t.Errorf("Expected the HD subcategories, got %v", ids)
+	}
+	if got := indexer.CategoriesMatching("audio"); got != nil {
+		t.Errorf("Expected no matches, got %+v", got)
+	}
+}
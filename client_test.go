@@ -1,8 +1,11 @@
 package jackett
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -619,6 +622,55 @@ func TestDownloadTorrent_ExternalURL(t *testing.T) {
 	}
 }
 
+func TestDownloadTorrentContext(t *testing.T) {
+	expectedData := "torrent file data"
+
+	endpointResponses := map[string]mockResponse{
+		"/dl/test": {statusCode: http.StatusOK, responseBody: expectedData},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "GET", url: "/dl/test", query: url.Values{"apikey": []string{"test-api-key"}}},
+	}
+
+	client, mockTransport, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	n, err := client.DownloadTorrentContext(context.Background(), "http://localhost:9117/dl/test", &buf)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if n != int64(len(expectedData)) || buf.String() != expectedData {
+		t.Errorf("Expected %d bytes '%s', got %d bytes '%s'", len(expectedData), expectedData, n, buf.String())
+	}
+
+	if mockTransport.requestIndex != len(mockTransport.expectedRequests) {
+		t.Errorf("Not all expected requests were made")
+	}
+}
+
+func TestDownloadTorrent_TooLarge(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/dl/test": {statusCode: http.StatusOK, responseBody: strings.Repeat("x", 100)},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "GET", url: "/dl/test", query: url.Values{"apikey": []string{"test-api-key"}}},
+	}
+
+	client, _, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	WithMaxDownloadSize(64)(client)
+
+	if _, err := client.DownloadTorrent("http://localhost:9117/dl/test"); !errors.Is(err, ErrDownloadTooLarge) {
+		t.Fatalf("Expected ErrDownloadTooLarge, got %v", err)
+	}
+}
+
 func TestGetServerConfig(t *testing.T) {
 	mockConfig := map[string]interface{}{
 		"notices":          []string{},
@@ -903,8 +955,8 @@ func TestGetIndexers_AllIndexers(t *testing.T) {
 		caps := &Caps{
 			Server: tIdx.Caps.Server.Title,
 			Limits: Limits{
-				Default: tIdx.Caps.Limits.Default,
-				Max:     tIdx.Caps.Limits.Max,
+				Default: parseLimit(tIdx.Caps.Limits.Default),
+				Max:     parseLimit(tIdx.Caps.Limits.Max),
 			},
 			Searching: Searching{
 				Search:      convertSearchType(tIdx.Caps.Searching.Search),
@@ -0,0 +1,57 @@
+package jackett
+
+import (
+	"context"
+	"time"
+)
+
+// Clock abstracts time for every time-based part of the client (latency
+// measurement, scheduling, rate limiting, cache expiry) so behavior can be
+// tested deterministically. jacketttest.FakeClock is a controllable
+// implementation.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time
+	// on the returned channel
+	After(d time.Duration) <-chan time.Time
+	// NewTicker returns a Ticker delivering ticks every d
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks at intervals, like time.Ticker
+type Ticker interface {
+	// C returns the channel on which ticks are delivered
+	C() <-chan time.Time
+	// Stop turns off the ticker
+	Stop()
+}
+
+// systemClock is the Clock backed by the time package
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (systemClock) NewTicker(d time.Duration) Ticker       { return systemTicker{time.NewTicker(d)} }
+
+// clockKey is the context key of the clock passed to AuthProviders and
+// TokenSources
+type clockKey struct{}
+
+// contextWithClock returns a copy of ctx carrying clock
+func contextWithClock(ctx context.Context, clock Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, clock)
+}
+
+// clockFrom returns the clock carried by ctx, or the system clock
+func clockFrom(ctx context.Context) Clock {
+	if clock, ok := ctx.Value(clockKey{}).(Clock); ok {
+		return clock
+	}
+	return systemClock{}
+}
+
+type systemTicker struct{ t *time.Ticker }
+
+func (t systemTicker) C() <-chan time.Time { return t.t.C }
+func (t systemTicker) Stop()               { t.t.Stop() }
@@ -0,0 +1,439 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/cehbz/jackett"
+)
+
+// runSearch implements "search [flags] QUERY..."
+func runSearch(ctx context.Context, c *cli, args []string) error {
+	var asJSON bool
+	flags := c.newFlagSet("search", &asJSON)
+	indexer := flags.String("indexer", "all", "indexer ID to search")
+	minSeeders := flags.Int("min-seeders", 0, "drop results with fewer seeders")
+	var minSize, maxSize sizeFlag
+	flags.Var(&minSize, "min-size", "drop results smaller than this, e.g. 700MB")
+	flags.Var(&maxSize, "max-size", "drop results larger than this, e.g. 4.5GB")
+	categories := flags.String("cat", "", "comma-separated category IDs to keep")
+	sortBy := flags.String("sort", "", "sort by seeders, size, date or title")
+	limit := flags.Int("limit", 0, "print at most this many results")
+	format := flags.String("format", "table", "output format: table, json, ndjson, csv, markdown or template")
+	columns := flags.String("columns", "", "comma-separated result columns to print, e.g. title,size,link")
+	tmpl := flags.String("template", "", "Go template printed for each result, e.g. '{{.Title}} {{.Link}}' (implies -format template)")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return &usageError{"missing query"}
+	}
+	query := strings.Join(flags.Args(), " ")
+
+	var cats []int
+	for _, s := range strings.Split(*categories, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		id, err := strconv.Atoi(s)
+		if err != nil {
+			return &usageError{fmt.Sprintf("invalid category %q", s)}
+		}
+		cats = append(cats, id)
+	}
+	less, err := sortOrder(*sortBy)
+	if err != nil {
+		return err
+	}
+	encoder, err := resultEncoder(*format, *tmpl, asJSON)
+	if err != nil {
+		return err
+	}
+	if *columns != "" {
+		encoder.Columns = strings.Split(*columns, ",")
+		if err := encoder.Encode(io.Discard, nil); errors.Is(err, jackett.ErrUnknownColumn) {
+			return &usageError{fmt.Sprintf("%v; the columns are %s", err, strings.Join(jackett.ResultColumns(), ", "))}
+		}
+	}
+
+	response, err := c.client.SearchWithIndexerContext(ctx, *indexer, query)
+	var partial *jackett.PartialError
+	if errors.As(err, &partial) {
+		fmt.Fprintf(c.stderr, "warning: %v\n", err)
+	} else if err != nil {
+		return err
+	}
+
+	var results []jackett.SearchResult
+	for _, r := range response.Results {
+		switch {
+		case r.Seeders < *minSeeders && !r.IsUsenet():
+		case minSize > 0 && r.Size < int64(minSize):
+		case maxSize > 0 && r.Size > int64(maxSize):
+		case len(cats) > 0 && !hasCategory(r, cats):
+		default:
+			results = append(results, r)
+		}
+	}
+	if less != nil {
+		sort.SliceStable(results, func(i, j int) bool { return less(results[i], results[j]) })
+	}
+	if *limit > 0 && len(results) > *limit {
+		results = results[:*limit]
+	}
+
+	return encoder.Encode(c.stdout, results)
+}
+
+// resultEncoder returns the encoder selected by the -format, -template and
+// -json flags
+func resultEncoder(format, tmpl string, asJSON bool) (jackett.ResultEncoder, error) {
+	switch {
+	case tmpl != "":
+		if _, err := template.New("result").Parse(tmpl); err != nil {
+			return jackett.ResultEncoder{}, &usageError{fmt.Sprintf("invalid template: %v", err)}
+		}
+		return jackett.ResultEncoder{Format: jackett.FormatTemplate, Template: tmpl}, nil
+	case asJSON:
+		return jackett.ResultEncoder{Format: jackett.FormatJSON}, nil
+	}
+	f, err := jackett.ParseOutputFormat(format)
+	if err != nil || f == jackett.FormatTemplate {
+		return jackett.ResultEncoder{}, &usageError{fmt.Sprintf("invalid format %q", format)}
+	}
+	return jackett.ResultEncoder{Format: f}, nil
+}
+
+// sizeFlag is a flag taking a size like "1.5GB"
+type sizeFlag int64
+
+func (f *sizeFlag) String() string {
+	if *f == 0 {
+		return ""
+	}
+	return jackett.FormatSize(int64(*f))
+}
+
+func (f *sizeFlag) Set(s string) error {
+	size, err := jackett.ParseSize(s)
+	*f = sizeFlag(size)
+	return err
+}
+
+// sortOrder returns the ordering named by the -sort flag
+func sortOrder(name string) (func(a, b jackett.SearchResult) bool, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case "seeders":
+		return func(a, b jackett.SearchResult) bool { return a.Seeders > b.Seeders }, nil
+	case "size":
+		return func(a, b jackett.SearchResult) bool { return a.Size > b.Size }, nil
+	case "date":
+		return func(a, b jackett.SearchResult) bool { return a.PublishTime().After(b.PublishTime()) }, nil
+	case "title":
+		return func(a, b jackett.SearchResult) bool { return strings.ToLower(a.Title) < strings.ToLower(b.Title) }, nil
+	}
+	return nil, &usageError{fmt.Sprintf("invalid sort order %q", name)}
+}
+
+func hasCategory(r jackett.SearchResult, cats []int) bool {
+	for _, have := range r.Category {
+		for _, want := range cats {
+			if have == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// runIndexers implements "indexers [list]" and "indexers test [ID...]"
+func runIndexers(ctx context.Context, c *cli, args []string) error {
+	var asJSON bool
+	flags := c.newFlagSet("indexers", &asJSON)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	switch flags.Arg(0) {
+	case "", "list":
+		indexers, err := c.client.GetIndexersContext(ctx)
+		if err != nil {
+			return err
+		}
+		if asJSON {
+			return c.printJSON(indexers)
+		}
+		w := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tTYPE\tLANGUAGE")
+		for _, indexer := range indexers {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", indexer.ID, indexer.Name, indexer.Type, indexer.Language)
+		}
+		return w.Flush()
+	case "test":
+		return testIndexers(ctx, c, flags.Args()[1:], asJSON)
+	}
+	return &usageError{fmt.Sprintf("unknown indexers command %q", flags.Arg(0))}
+}
+
+// testIndexers tests the given indexers, or all configured ones. It fails if
+// any test failed.
+func testIndexers(ctx context.Context, c *cli, ids []string, asJSON bool) error {
+	var report []jackett.IndexerHealth
+	if len(ids) == 0 {
+		var err error
+		if report, err = c.client.TestAllIndexers(ctx, 4); err != nil {
+			return err
+		}
+	}
+	for _, id := range ids {
+		start := time.Now()
+		err := c.client.TestIndexerContext(ctx, id)
+		health := jackett.IndexerHealth{ID: id, Name: id, OK: err == nil, Latency: time.Since(start)}
+		if err != nil {
+			health.Error = err.Error()
+		}
+		report = append(report, health)
+	}
+
+	var failed int
+	for _, h := range report {
+		if !h.OK {
+			failed++
+		}
+	}
+	if asJSON {
+		if err := c.printJSON(report); err != nil {
+			return err
+		}
+	} else {
+		w := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tSTATUS\tLATENCY\tERROR")
+		for _, h := range report {
+			status := "ok"
+			if !h.OK {
+				status = "failed"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", h.ID, status, h.Latency.Round(time.Millisecond), h.Error)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d indexers failed", failed, len(report))
+	}
+	return nil
+}
+
+// runConfig implements "config get [KEY]"
+func runConfig(ctx context.Context, c *cli, args []string) error {
+	var asJSON bool
+	flags := c.newFlagSet("config", &asJSON)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.Arg(0) != "get" || flags.NArg() > 2 {
+		return &usageError{"usage: config get [KEY]"}
+	}
+
+	if password := os.Getenv("JACKETT_ADMIN_PASSWORD"); password != "" {
+		if err := c.client.LoginContext(ctx, password); err != nil {
+			return err
+		}
+	}
+	cfg, err := c.client.GetServerConfigContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if key := flags.Arg(1); key != "" {
+		value, ok := cfg[key]
+		if !ok {
+			return fmt.Errorf("no config setting %q", key)
+		}
+		if asJSON {
+			return c.printJSON(value)
+		}
+		_, err := fmt.Fprintln(c.stdout, value)
+		return err
+	}
+	if asJSON {
+		return c.printJSON(cfg)
+	}
+
+	keys := make([]string, 0, len(cfg))
+	for key := range cfg {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	w := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE")
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s\t%v\n", key, cfg[key])
+	}
+	return w.Flush()
+}
+
+// runDownload implements "download [-o PATH] LINK"
+func runDownload(ctx context.Context, c *cli, args []string) error {
+	var asJSON bool
+	flags := c.newFlagSet("download", &asJSON)
+	output := flags.String("o", "", "file or directory to save to; - for stdout (default: the current directory)")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return &usageError{"usage: download [-o PATH] LINK"}
+	}
+
+	release, err := c.client.DownloadRelease(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	return c.saveRelease(release, *output, downloadName(flags.Arg(0)), asJSON)
+}
+
+// saveRelease prints a magnet release's URI, or saves a .torrent or .nzb
+// release to output: a new file, a directory in which it is saved as name
+// (numbered if taken), or - for stdout. Existing files are never
+// overwritten.
+func (c *cli) saveRelease(release *jackett.DownloadedRelease, output, name string, asJSON bool) error {
+	if release.IsMagnet() {
+		if asJSON {
+			return c.printJSON(map[string]string{"magnet_uri": release.MagnetURI})
+		}
+		_, err := fmt.Fprintln(c.stdout, release.MagnetURI)
+		return err
+	}
+
+	data := release.Torrent
+	if release.IsNZB() {
+		data = release.NZB
+	}
+	if output == "-" {
+		_, err := c.stdout.Write(data)
+		return err
+	}
+
+	path := output
+	if info, err := os.Stat(path); path == "" || err == nil && info.IsDir() {
+		if path, err = release.Save(path, name); err != nil {
+			return err
+		}
+	} else if err := writeNewFile(path, data); err != nil {
+		return err
+	}
+	if asJSON {
+		return c.printJSON(map[string]interface{}{"path": path, "size": len(data)})
+	}
+	_, err := fmt.Fprintln(c.stdout, path)
+	return err
+}
+
+// writeNewFile writes data to path, failing if the file exists
+func writeNewFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+// downloadName derives a file name from a download link: the title Jackett
+// puts in the file parameter of its links, or "release"
+func downloadName(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "release"
+	}
+	return fileName(u.Query().Get("file"))
+}
+
+// fileName makes a release title safe to use as a file name, "release" if
+// nothing of it remains
+func fileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < ' ' || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, title)
+	if name = strings.Trim(name, ". "); name == "" {
+		return "release"
+	}
+	return name
+}
+
+// runPick implements "pick [flags] QUERY...": it searches, lets the user
+// choose one of the results ranked by relevance, and downloads it
+func runPick(ctx context.Context, c *cli, args []string) error {
+	var asJSON bool
+	flags := c.newFlagSet("pick", &asJSON)
+	indexer := flags.String("indexer", "all", "indexer ID to search")
+	output := flags.String("o", "", "file or directory to save to; - for stdout (default: the current directory)")
+	limit := flags.Int("limit", 100, "offer at most this many results")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return &usageError{"missing query"}
+	}
+	query := strings.Join(flags.Args(), " ")
+
+	response, err := c.client.SearchWithIndexerContext(ctx, *indexer, query)
+	var partial *jackett.PartialError
+	if errors.As(err, &partial) {
+		fmt.Fprintf(c.stderr, "warning: %v\n", err)
+	} else if err != nil {
+		return err
+	}
+	response.Rank(jackett.ParseRelevanceQuery(query), 0)
+	results := response.Results
+	if len(results) == 0 {
+		return fmt.Errorf("no results for %q", query)
+	}
+	if *limit > 0 && len(results) > *limit {
+		results = results[:*limit]
+	}
+
+	choice, err := c.choose(results)
+	if err != nil {
+		return err
+	}
+	release, err := results[choice].Fetch(ctx, c.client)
+	if err != nil {
+		return err
+	}
+	return c.saveRelease(release, *output, fileName(results[choice].Title), asJSON)
+}
+
+// choose asks the user to choose one of results, with the arrow-key picker
+// on a terminal and a numbered prompt otherwise. Either is drawn on stderr,
+// leaving stdout to the chosen release.
+func (c *cli) choose(results []jackett.SearchResult) (int, error) {
+	if f, ok := c.stdin.(*os.File); ok {
+		if restore, err := makeRaw(f.Fd()); err == nil {
+			choice, err := pickResult(f, c.stderr, results)
+			restore()
+			return choice, err
+		}
+	}
+	return promptResult(c.stdin, c.stderr, results)
+}
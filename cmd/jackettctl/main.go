@@ -0,0 +1,167 @@
+// Command jackettctl is a shell-scriptable Jackett client.
+//
+// Usage:
+//
+//	jackettctl [-url URL] [-api-key KEY] [-config FILE] <command> [arguments]
+//
+// The commands are:
+//
+//	search     search indexers, with filters and sorting
+//	indexers   list indexers, or test them with "indexers test [ID...]"
+//	config     print the server config with "config get [KEY]"
+//	download   download a release's .torrent or .nzb file
+//	pick       search, choose a result interactively, and download it
+//
+// The connection is configured by the flags, a config file, or the
+// JACKETT_URL and JACKETT_API_KEY environment variables (see package config).
+// The config command needs the admin password in JACKETT_ADMIN_PASSWORD if
+// Jackett has one. Every command prints a table, or JSON with -json; search
+// also prints NDJSON, CSV or a Go template per result with -format and
+// -template.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/cehbz/jackett"
+	"github.com/cehbz/jackett/config"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// usageError is a command line error, reported with the usage text
+type usageError struct{ msg string }
+
+func (e *usageError) Error() string { return e.msg }
+
+// errFlags is returned for invalid command flags, which the flag set has
+// already reported
+var errFlags = errors.New("invalid flags")
+
+// cli holds what every command needs
+type cli struct {
+	client *jackett.Client
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// commands maps command names to their implementations
+var commands = map[string]func(ctx context.Context, c *cli, args []string) error{
+	"search":   runSearch,
+	"indexers": runIndexers,
+	"config":   runConfig,
+	"download": runDownload,
+	"pick":     runPick,
+}
+
+// run executes the command line args, returning the exit code
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("jackettctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	baseURL := flags.String("url", "", "Jackett base URL (default $JACKETT_URL)")
+	apiKey := flags.String("api-key", "", "Jackett API key (default $JACKETT_API_KEY)")
+	configFile := flags.String("config", "", "config file (.json, .yaml or .toml)")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: jackettctl [flags] search|indexers|config|download|pick [arguments]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+	command, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "jackettctl: unknown command %q\n", flags.Arg(0))
+		flags.Usage()
+		return 2
+	}
+
+	client, err := newClient(*configFile, *baseURL, *apiKey)
+	if err != nil {
+		fmt.Fprintf(stderr, "jackettctl: %v\n", err)
+		return 1
+	}
+	defer client.Close(context.Background())
+
+	err = command(ctx, &cli{client: client, stdin: stdin, stdout: stdout, stderr: stderr}, flags.Args()[1:])
+	var usage *usageError
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errFlags):
+		return 2
+	case errors.As(err, &usage):
+		fmt.Fprintf(stderr, "jackettctl %s: %v\n", flags.Arg(0), err)
+		return 2
+	case err != nil:
+		fmt.Fprintf(stderr, "jackettctl %s: %v\n", flags.Arg(0), err)
+		return 1
+	}
+	return 0
+}
+
+// newClient creates the client from a config file or the environment, with
+// the flags taking precedence
+func newClient(configFile, baseURL, apiKey string) (*jackett.Client, error) {
+	var cfg config.Config
+	var err error
+	if configFile != "" {
+		cfg, err = config.ReadFile(configFile)
+	} else {
+		cfg, err = config.ReadEnv()
+		if err != nil && baseURL != "" && apiKey != "" {
+			cfg, err = config.Config{}, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if baseURL != "" {
+		cfg.URL = baseURL
+	}
+	if apiKey != "" {
+		cfg.APIKey = apiKey
+	}
+	return cfg.Client(jackett.WithUserAgent("jackettctl"))
+}
+
+// newFlagSet returns the flag set of a command, with the -json flag
+func (c *cli) newFlagSet(name string, asJSON *bool) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	flags.BoolVar(asJSON, "json", false, "print JSON instead of a table")
+	return flags
+}
+
+// parseFlags parses the flags of a command, returning errFlags if they are
+// invalid
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	err := flags.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return errFlags
+	}
+	return err
+}
+
+// printJSON writes v as indented JSON
+func (c *cli) printJSON(v interface{}) error {
+	enc := json.NewEncoder(c.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/jackett"
+	"github.com/cehbz/jackett/jacketttest"
+)
+
+const searchJSON = `{
+  "Results": [
+    {"Title": "Small", "Seeders": 3, "Size": 100, "Category": [2000], "PublishDate": "2024-01-02T00:00:00Z", "Link": "SERVER/dl/release"},
+    {"Title": "Big", "Seeders": 9, "Size": 900, "Category": [5000], "PublishDate": "2024-01-01T00:00:00Z", "MagnetUri": "magnet:?xt=urn:btih:big"},
+    {"Title": "Dead", "Seeders": 0, "Size": 500, "Category": [2000], "PublishDate": "2024-01-03T00:00:00Z"}
+  ],
+  "Indexers": [{"ID": "a", "Name": "A", "Status": 2, "Results": 3}]
+}`
+
+const testTorrent = "d4:infod6:lengthi1e4:name1:a12:piece lengthi1e6:pieces20:aaaaaaaaaaaaaaaaaaaaee"
+
+// newServer returns a fake Jackett with a good and a bad indexer. Searches
+// return searchJSON and /dl/release serves a torrent, or redirects to a
+// magnet URI for file=magnet.
+func newServer(t *testing.T) *jacketttest.Server {
+	server := jacketttest.NewServer()
+	t.Cleanup(server.Close)
+
+	server.AddIndexer(jackett.Indexer{ID: "good", Name: "Good", Type: "private", Language: "en-US"})
+	server.AddIndexer(jackett.Indexer{ID: "bad", Name: "Bad", Type: "public", Language: "en-US"})
+	server.FailRequests("/api/v2.0/indexers/bad/test", http.StatusInternalServerError, 0)
+	server.SetServerConfig(map[string]interface{}{"app_version": "0.22.0", "port": 9117})
+	server.Handle("GET /api/v2.0/indexers/all/results", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.ReplaceAll(searchJSON, "SERVER", "http://"+r.Host)))
+	})
+	server.Handle("/dl/release", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("file") == "magnet" {
+			http.Redirect(w, r, "magnet:?xt=urn:btih:abc", http.StatusFound)
+			return
+		}
+		w.Write([]byte(testTorrent))
+	})
+	return server
+}
+
+func runCommand(t *testing.T, server *jacketttest.Server, args ...string) (int, string, string) {
+	return runWithInput(t, server, "", args...)
+}
+
+func runWithInput(t *testing.T, server *jacketttest.Server, input string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	args = append([]string{"-url", server.URL, "-api-key", server.APIKey()}, args...)
+	code := run(context.Background(), args, strings.NewReader(input), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestSearch(t *testing.T) {
+	server := newServer(t)
+
+	code, stdout, stderr := runCommand(t, server, "search", "-json", "-min-seeders", "1", "-sort", "size", "test")
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr)
+	}
+	var results []jackett.SearchResult
+	if err := json.Unmarshal([]byte(stdout), &results); err != nil {
+		t.Fatalf("Expected JSON output, got %v: %s", err, stdout)
+	}
+	if len(results) != 2 || results[0].Title != "Big" || results[1].Title != "Small" {
+		t.Errorf("Expected Big then Small, got %+v", results)
+	}
+
+	code, stdout, _ = runCommand(t, server, "search", "-cat", "2000", "-sort", "date", "-limit", "1", "test")
+	if code != 0 || !strings.Contains(stdout, "Dead") || strings.Contains(stdout, "Small") || strings.Contains(stdout, "Big") {
+		t.Errorf("Expected only the newest 2000 result, got %d: %s", code, stdout)
+	}
+	if !strings.HasPrefix(stdout, "TITLE") {
+		t.Errorf("Expected a table header, got %s", stdout)
+	}
+
+	code, stdout, _ = runCommand(t, server, "search", "-format", "ndjson", "-min-size", "0.5KB", "-max-size", "1k", "test")
+	if code != 0 || strings.Count(stdout, "\n") != 1 || !strings.Contains(stdout, `"Big"`) {
+		t.Errorf("Expected only the result between 512 and 1024 bytes, got %d: %s", code, stdout)
+	}
+}
+
+func TestSearchFormats(t *testing.T) {
+	server := newServer(t)
+
+	code, stdout, _ := runCommand(t, server, "search", "-format", "csv", "-sort", "title", "test")
+	if code != 0 || !strings.HasPrefix(stdout, "title,") || !strings.Contains(stdout, "\nBig,") {
+		t.Errorf("Expected CSV output, got %d: %s", code, stdout)
+	}
+
+	code, stdout, _ = runCommand(t, server, "search", "-format", "ndjson", "test")
+	if code != 0 || strings.Count(stdout, "\n") != 3 {
+		t.Errorf("Expected 3 JSON lines, got %d: %s", code, stdout)
+	}
+
+	code, stdout, _ = runCommand(t, server, "search", "-template", "{{.Title}}={{.Seeders}}", "-sort", "seeders", "test")
+	if code != 0 || stdout != "Big=9\nSmall=3\nDead=0\n" {
+		t.Errorf("Expected the template output, got %d: %q", code, stdout)
+	}
+
+	code, stdout, _ = runCommand(t, server, "search", "-format", "markdown", "-columns", "title,seeders", "-limit", "1", "test")
+	if code != 0 || stdout != "| title | seeders |\n| --- | --- |\n| Small | 3 |\n" {
+		t.Errorf("Expected a Markdown table of the selected columns, got %d: %q", code, stdout)
+	}
+
+	for _, args := range [][]string{{"-columns", "title,color"}, {"-format", "xml"}, {"-format", "template"}, {"-template", "{{.Title"}} {
+		if code, _, _ := runCommand(t, server, append(append([]string{"search"}, args...), "test")...); code != 2 {
+			t.Errorf("%v: expected exit code 2, got %d", args, code)
+		}
+	}
+}
+
+func TestIndexers(t *testing.T) {
+	server := newServer(t)
+
+	code, stdout, _ := runCommand(t, server, "indexers")
+	if code != 0 || !strings.Contains(stdout, "good") || !strings.Contains(stdout, "Bad") {
+		t.Errorf("Expected both indexers listed, got %d: %s", code, stdout)
+	}
+
+	code, stdout, _ = runCommand(t, server, "indexers", "test", "good")
+	if code != 0 || !strings.Contains(stdout, "ok") {
+		t.Errorf("Expected the good indexer to pass, got %d: %s", code, stdout)
+	}
+
+	code, stdout, stderr := runCommand(t, server, "indexers", "-json", "test")
+	if code != 1 || !strings.Contains(stderr, "1 of 2 indexers failed") {
+		t.Errorf("Expected exit code 1 with a failure, got %d: %s", code, stderr)
+	}
+	var report []jackett.IndexerHealth
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil || len(report) != 2 {
+		t.Errorf("Expected a JSON report of 2 indexers, got %v: %s", err, stdout)
+	}
+}
+
+func TestConfigGet(t *testing.T) {
+	server := newServer(t)
+
+	code, stdout, _ := runCommand(t, server, "config", "get", "app_version")
+	if code != 0 || stdout != "0.22.0\n" {
+		t.Errorf("Expected the app version, got %d: %q", code, stdout)
+	}
+
+	code, stdout, _ = runCommand(t, server, "config", "get")
+	if code != 0 || !strings.Contains(stdout, "port") || !strings.Contains(stdout, "9117") {
+		t.Errorf("Expected every setting, got %d: %s", code, stdout)
+	}
+
+	if code, _, _ := runCommand(t, server, "config", "get", "missing"); code != 1 {
+		t.Errorf("Expected exit code 1 for a missing key, got %d", code)
+	}
+}
+
+func TestDownload(t *testing.T) {
+	server := newServer(t)
+	dir := t.TempDir()
+
+	code, stdout, stderr := runCommand(t, server, "download", "-o", dir, server.URL+"/dl/release?file=Some/Release")
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr)
+	}
+	path := filepath.Join(dir, "Some_Release.torrent")
+	if strings.TrimSpace(stdout) != path {
+		t.Errorf("Expected the path %s, got %s", path, stdout)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != testTorrent {
+		t.Errorf("Expected the torrent to be saved, got %q, %v", data, err)
+	}
+
+	code, stdout, stderr = runCommand(t, server, "download", "-o", dir, server.URL+"/dl/release?file=Some/Release")
+	if code != 0 || strings.TrimSpace(stdout) != filepath.Join(dir, "Some_Release (2).torrent") {
+		t.Errorf("Expected a numbered name next to the existing file, got %d: %s %s", code, stdout, stderr)
+	}
+	os.WriteFile(path, []byte("keep"), 0644)
+	if code, _, _ = runCommand(t, server, "download", "-o", path, server.URL+"/dl/release"); code == 0 {
+		t.Error("Expected saving over an existing file to fail")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "keep" {
+		t.Errorf("Expected the existing file to be kept, got %q", data)
+	}
+
+	code, stdout, _ = runCommand(t, server, "download", "-o", "-", server.URL+"/dl/release")
+	if code != 0 || stdout != testTorrent {
+		t.Errorf("Expected the torrent on stdout, got %d: %q", code, stdout)
+	}
+
+	code, stdout, _ = runCommand(t, server, "download", server.URL+"/dl/release?file=magnet")
+	if code != 0 || stdout != "magnet:?xt=urn:btih:abc\n" {
+		t.Errorf("Expected the magnet URI, got %d: %q", code, stdout)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	server := newServer(t)
+
+	for _, args := range [][]string{
+		{},
+		{"frobnicate"},
+		{"search"},
+		{"search", "-sort", "color", "test"},
+		{"search", "-cat", "movies", "test"},
+		{"search", "-min-size", "huge", "test"},
+		{"indexers", "remove"},
+		{"config", "set", "port"},
+		{"download"},
+	} {
+		if code, _, stderr := runCommand(t, server, args...); code != 2 || stderr == "" {
+			t.Errorf("%v: expected exit code 2 with a message, got %d: %q", args, code, stderr)
+		}
+	}
+}
+
+func TestMissingConnection(t *testing.T) {
+	t.Setenv("JACKETT_URL", "")
+	t.Setenv("JACKETT_API_KEY", "")
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"indexers"}, nil, &stdout, &stderr); code != 1 || stderr.Len() == 0 {
+		t.Errorf("Expected exit code 1 without a URL, got %d: %q", code, stderr.String())
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/cehbz/jackett"
+)
+
+// errCancelled is returned when the user quits a picker without choosing
+var errCancelled = errors.New("cancelled")
+
+// pickerHeight is how many results the arrow-key picker shows at once
+const pickerHeight = 15
+
+// pickResult lets the user choose one of results with the arrow keys (or j
+// and k) and Enter, drawing the list on out. in must deliver single key
+// presses, i.e. be a terminal in raw mode.
+func pickResult(in io.Reader, out io.Writer, results []jackett.SearchResult) (int, error) {
+	r := bufio.NewReader(in)
+	selected, drawn := 0, 0
+	for {
+		drawn = drawPicker(out, results, selected, drawn)
+
+		key, err := r.ReadByte()
+		if err != nil {
+			return 0, errCancelled
+		}
+		switch key {
+		case '\r', '\n':
+			return selected, nil
+		case 'q', 3, 4: // Ctrl-C and Ctrl-D arrive as bytes in raw mode
+			return 0, errCancelled
+		case 'k':
+			selected--
+		case 'j':
+			selected++
+		case 0x1b:
+			if prefix, _ := r.ReadByte(); prefix != '[' && prefix != 'O' {
+				continue
+			}
+			switch code, _ := r.ReadByte(); code {
+			case 'A':
+				selected--
+			case 'B':
+				selected++
+			case '5', '6': // Page Up and Page Down, followed by '~'
+				r.ReadByte()
+				if code == '5' {
+					selected -= pickerHeight
+				} else {
+					selected += pickerHeight
+				}
+			}
+		}
+		selected = max(0, min(selected, len(results)-1))
+	}
+}
+
+// drawPicker draws the visible part of the list over the previously drawn
+// lines, returning the number of lines drawn
+func drawPicker(out io.Writer, results []jackett.SearchResult, selected, drawn int) int {
+	if drawn > 0 {
+		fmt.Fprintf(out, "\x1b[%dA", drawn)
+	}
+	top := max(0, min(selected-pickerHeight/2, len(results)-pickerHeight))
+	bottom := min(top+pickerHeight, len(results))
+
+	fmt.Fprintf(out, "\r\x1b[2K%d results; ↑/↓ to move, Enter to download, q to quit\r\n", len(results))
+	for i := top; i < bottom; i++ {
+		line := resultLine(results[i])
+		if i == selected {
+			fmt.Fprintf(out, "\r\x1b[2K\x1b[7m> %s\x1b[0m\r\n", line)
+		} else {
+			fmt.Fprintf(out, "\r\x1b[2K  %s\r\n", line)
+		}
+	}
+	return bottom - top + 1
+}
+
+// promptResult asks for the number of one of results, for input that isn't a
+// terminal
+func promptResult(in io.Reader, out io.Writer, results []jackett.SearchResult) (int, error) {
+	for i, r := range results {
+		fmt.Fprintf(out, "%3d) %s\n", i+1, resultLine(r))
+	}
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprintf(out, "Select a result [1-%d, q to quit]: ", len(results))
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return 0, errCancelled
+		}
+		answer := strings.TrimSpace(scanner.Text())
+		if answer == "q" {
+			return 0, errCancelled
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(results) {
+			return n - 1, nil
+		}
+	}
+}
+
+// resultLine is the one-line summary of a result in the pickers
+func resultLine(r jackett.SearchResult) string {
+	return fmt.Sprintf("%-60.60s %10s %5d  %s", r.Title, jackett.FormatSize(r.Size), r.Seeders, r.Tracker)
+}
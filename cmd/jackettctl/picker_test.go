@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/jackett"
+)
+
+func pickerResults(n int) []jackett.SearchResult {
+	results := make([]jackett.SearchResult, n)
+	for i := range results {
+		results[i].Title = fmt.Sprintf("Result %d", i)
+	}
+	return results
+}
+
+func TestPickResult(t *testing.T) {
+	tests := []struct {
+		name  string
+		keys  string
+		want  int
+		error error
+	}{
+		{"enter", "\r", 0, nil},
+		{"arrows", "\x1b[B\x1b[B\x1b[A\r", 1, nil},
+		{"vi keys", "jjjk\n", 2, nil},
+		{"clamped", "kk\x1b[A\r", 0, nil},
+		{"page down", "\x1b[6~\x1b[6~\r", 19, nil},
+		{"application mode arrows", "\x1bOB\r", 1, nil},
+		{"quit", "jq", 0, errCancelled},
+		{"ctrl-c", "j\x03", 0, errCancelled},
+		{"end of input", "j", 0, errCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			got, err := pickResult(strings.NewReader(tt.keys), &out, pickerResults(20))
+			if !errors.Is(err, tt.error) || err == nil && got != tt.want {
+				t.Errorf("Expected %d, %v, got %d, %v", tt.want, tt.error, got, err)
+			}
+		})
+	}
+}
+
+func TestDrawPicker(t *testing.T) {
+	var out bytes.Buffer
+	if drawn := drawPicker(&out, pickerResults(40), 30, 0); drawn != pickerHeight+1 {
+		t.Errorf("Expected %d lines, got %d", pickerHeight+1, drawn)
+	}
+	if !strings.Contains(out.String(), "> Result 30") || strings.Contains(out.String(), "Result 20 ") || !strings.Contains(out.String(), "Result 37") {
+		t.Errorf("Expected a window around the selection, got %q", out.String())
+	}
+
+	out.Reset()
+	drawPicker(&out, pickerResults(3), 2, 4)
+	if !strings.HasPrefix(out.String(), "\x1b[4A") || strings.Count(out.String(), "\r\n") != 4 {
+		t.Errorf("Expected the list redrawn in place, got %q", out.String())
+	}
+}
+
+func TestPromptResult(t *testing.T) {
+	var out bytes.Buffer
+	got, err := promptResult(strings.NewReader("0\nfoo\n3\n"), &out, pickerResults(3))
+	if err != nil || got != 2 {
+		t.Errorf("Expected 2, got %d, %v", got, err)
+	}
+	if strings.Count(out.String(), "Select a result") != 3 {
+		t.Errorf("Expected invalid answers to be asked again, got %q", out.String())
+	}
+
+	if _, err := promptResult(strings.NewReader("q\n"), &out, pickerResults(3)); !errors.Is(err, errCancelled) {
+		t.Errorf("Expected errCancelled, got %v", err)
+	}
+	if _, err := promptResult(strings.NewReader(""), &out, pickerResults(3)); !errors.Is(err, errCancelled) {
+		t.Errorf("Expected errCancelled at end of input, got %v", err)
+	}
+}
+
+func TestPick(t *testing.T) {
+	server := newServer(t)
+	dir := t.TempDir()
+
+	code, stdout, stderr := runWithInput(t, server, "1\n", "pick", "-o", dir, "small")
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr)
+	}
+	if !strings.Contains(stderr, "  1) Small") {
+		t.Errorf("Expected the most relevant result first, got %s", stderr)
+	}
+	path := filepath.Join(dir, "Small.torrent")
+	if data, err := os.ReadFile(path); err != nil || string(data) != testTorrent || strings.TrimSpace(stdout) != path {
+		t.Errorf("Expected the torrent saved to %s, got %q, %v: %s", path, data, err, stdout)
+	}
+
+	code, stdout, _ = runWithInput(t, server, "1\n", "pick", "big")
+	if code != 0 || stdout != "magnet:?xt=urn:btih:big\n" {
+		t.Errorf("Expected the magnet URI, got %d: %q", code, stdout)
+	}
+
+	if code, stdout, _ := runWithInput(t, server, "q\n", "pick", "big"); code != 1 || stdout != "" {
+		t.Errorf("Expected exit code 1 when cancelled, got %d: %q", code, stdout)
+	}
+}
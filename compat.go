@@ -0,0 +1,178 @@
+package jackett
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// UnmarshalJSON decodes a search result tolerantly. Jackett versions differ
+// in how they encode list fields: Category, Languages, Subs and Genres may be
+// missing, null, empty, a single value instead of a list, or (for the string
+// lists) a comma-separated string. Empty lists always decode as nil. Imdb
+// may also be a string such as "tt0133093".
+func (r *SearchResult) UnmarshalJSON(data []byte) error {
+	type plain SearchResult
+	aux := struct {
+		*plain
+		Category  flexInts    `json:"Category"`
+		Languages flexStrings `json:"Languages"`
+		Subs      flexStrings `json:"Subs"`
+		Genres    flexStrings `json:"Genres"`
+		Imdb      flexIMDb    `json:"Imdb"`
+	}{plain: (*plain)(r)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.Imdb = aux.Imdb.id
+	r.Category = aux.Category
+	r.Languages = aux.Languages
+	r.Subs = aux.Subs
+	r.Genres = nil
+	if len(aux.Genres) > 0 {
+		genres := []string(aux.Genres)
+		r.Genres = &genres
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes limits encoded as numbers or, as in the torznab
+// caps and older versions of this package, strings. Malformed limits decode
+// as zero.
+func (l *Limits) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Default json.RawMessage `json:"default"`
+		Max     json.RawMessage `json:"max"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	l.Default, l.Max = flexLimit(aux.Default), flexLimit(aux.Max)
+	return nil
+}
+
+// UnmarshalJSON decodes a search type encoded with a boolean and a list, or
+// as in older versions of this package, with "yes"/"no" and a
+// comma-separated string
+func (st *SearchType) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Available       json.RawMessage `json:"available"`
+		SupportedParams flexStrings     `json:"supported_params"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	var available string
+	if json.Unmarshal(aux.Available, &available) != nil {
+		available = string(aux.Available)
+	}
+	st.Available = parseAvailable(available)
+	st.SupportedParams = aux.SupportedParams
+	return nil
+}
+
+func flexLimit(data json.RawMessage) int {
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		return parseLimit(s)
+	}
+	return parseLimit(string(data))
+}
+
+// flexIMDb decodes an IMDb ID encoded as a number or a string with or
+// without the "tt" prefix; id is nil if it is missing or malformed
+type flexIMDb struct{ id *int }
+
+func (f *flexIMDb) UnmarshalJSON(data []byte) error {
+	var s string
+	if json.Unmarshal(data, &s) != nil {
+		s = string(data)
+	}
+	if id, err := ParseIMDbID(s); err == nil {
+		f.id = &id
+	}
+	return nil
+}
+
+// flexInts decodes a list of integers that may also be encoded as null, a
+// single number, or numbers in strings
+type flexInts []int
+
+func (f *flexInts) UnmarshalJSON(data []byte) error {
+	*f = nil
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var raw []json.RawMessage
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+	} else {
+		raw = []json.RawMessage{data}
+	}
+
+	for _, item := range raw {
+		var n json.Number
+		if err := json.Unmarshal(item, &n); err != nil {
+			var s string
+			if json.Unmarshal(item, &s) != nil {
+				return fmt.Errorf("invalid integer %s", item)
+			}
+			n = json.Number(strings.TrimSpace(s))
+		}
+		if n == "" || n == "null" {
+			continue
+		}
+		v, err := strconv.Atoi(n.String())
+		if err != nil {
+			return fmt.Errorf("invalid integer %s", item)
+		}
+		*f = append(*f, v)
+	}
+	return nil
+}
+
+// flexStrings decodes a list of strings that may also be encoded as null or
+// a single, possibly comma-separated, string
+type flexStrings []string
+
+func (f *flexStrings) UnmarshalJSON(data []byte) error {
+	*f = nil
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var items []string
+	if data[0] == '[' {
+		var raw []*string
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		for _, s := range raw {
+			if s != nil {
+				items = append(items, *s)
+			}
+		}
+	} else {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		items = strings.Split(s, ",")
+	}
+
+	for _, s := range items {
+		if s = strings.TrimSpace(s); s != "" {
+			*f = append(*f, s)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package jackett
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeCapturedPayloads(t *testing.T) {
+	tests := []struct {
+		file       string
+		categories []int
+		languages  []string
+		subs       []string
+		genres     []string
+	}{
+		{"testdata/search-v0.11.json", []int{2000}, nil, nil, nil},
+		{"testdata/search-v0.16.json", []int{5040, 100005}, nil, nil, []string{"Drama", "Fantasy"}},
+		{"testdata/search-v0.22.json", []int{2045, 100041}, []string{"English", "French"}, nil, []string{"Science Fiction", "Adventure"}},
+	}
+
+	for _, tt := range tests {
+		data, err := os.ReadFile(tt.file)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Both the streaming decoder and plain json.Unmarshal must agree
+		var unmarshalled SearchResponse
+		if err := json.Unmarshal(data, &unmarshalled); err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.file, err)
+		}
+		streamed, err := decodeSearchResponse(bytes.NewReader(data), nil)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.file, err)
+		}
+		if !reflect.DeepEqual(&unmarshalled, streamed) {
+			t.Errorf("%s: expected streamed and unmarshalled responses to match", tt.file)
+		}
+
+		if len(streamed.Results) != 1 {
+			t.Fatalf("%s: expected 1 result, got %d", tt.file, len(streamed.Results))
+		}
+		r := streamed.Results[0]
+		if !reflect.DeepEqual(r.Category, tt.categories) {
+			t.Errorf("%s: expected categories %v, got %v", tt.file, tt.categories, r.Category)
+		}
+		if !reflect.DeepEqual(r.Languages, tt.languages) {
+			t.Errorf("%s: expected languages %#v, got %#v", tt.file, tt.languages, r.Languages)
+		}
+		if !reflect.DeepEqual(r.Subs, tt.subs) {
+			t.Errorf("%s: expected subs %#v, got %#v", tt.file, tt.subs, r.Subs)
+		}
+		var genres []string
+		if r.Genres != nil {
+			genres = *r.Genres
+		}
+		if !reflect.DeepEqual(genres, tt.genres) {
+			t.Errorf("%s: expected genres %#v, got %#v", tt.file, tt.genres, genres)
+		}
+		if r.Title == "" || r.Size == 0 || r.Seeders == 0 {
+			t.Errorf("%s: expected the remaining fields to decode, got %+v", tt.file, r)
+		}
+	}
+}
+
+func TestSearchResultUnmarshalEdgeCases(t *testing.T) {
+	tests := []struct {
+		input    string
+		category []int
+		subs     []string
+	}{
+		{`{}`, nil, nil},
+		{`{"Category": null, "Subs": null}`, nil, nil},
+		{`{"Category": [], "Subs": []}`, nil, nil},
+		{`{"Category": "2000", "Subs": "English"}`, []int{2000}, []string{"English"}},
+		{`{"Category": [2000, null], "Subs": ["English", null, " "]}`, []int{2000}, []string{"English"}},
+	}
+	for _, tt := range tests {
+		var r SearchResult
+		if err := json.Unmarshal([]byte(tt.input), &r); err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.input, err)
+		}
+		if !reflect.DeepEqual(r.Category, tt.category) || !reflect.DeepEqual(r.Subs, tt.subs) {
+			t.Errorf("%s: expected %v and %#v, got %v and %#v", tt.input, tt.category, tt.subs, r.Category, r.Subs)
+		}
+	}
+
+	var r SearchResult
+	if err := json.Unmarshal([]byte(`{"Category": {"id": 2000}}`), &r); err == nil {
+		t.Error("Expected an error for a category object")
+	}
+}
@@ -0,0 +1,26 @@
+package jackett
+
+import (
+	"io"
+	"net/http"
+	"strings"
+)
+
+// decompressBody returns a reader of resp's decoded body, decompressing it
+// if the server sent it gzip-encoded. Call release once done with the reader.
+func decompressBody(resp *http.Response) (body io.Reader, release func(), err error) {
+	noop := func() {}
+	if resp.Uncompressed || !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return resp.Body, noop, nil
+	}
+
+	gz, err := getGzipReader(resp.Body)
+	if err == io.EOF {
+		// An empty body, e.g. of a 204 response, has nothing to decompress
+		return strings.NewReader(""), noop, nil
+	}
+	if err != nil {
+		return nil, noop, err
+	}
+	return gz, func() { putGzipReader(gz) }, nil
+}
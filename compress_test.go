@@ -0,0 +1,84 @@
+package jackett
+
+import (
+	"compress/gzip"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// gzipHandler answers with body, gzip-encoded if the request accepts it
+func gzipHandler(status int, body string, sawGzip *bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+			return
+		}
+		*sawGzip = true
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(status)
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(body))
+		gz.Close()
+	}
+}
+
+func TestGzipResponses(t *testing.T) {
+	for _, disable := range []bool{false, true} {
+		var sawGzip bool
+		server := httptest.NewServer(gzipHandler(http.StatusOK, hookSearchJSON, &sawGzip))
+
+		httpClient := &http.Client{Transport: &http.Transport{DisableCompression: disable}}
+		client, err := NewClient(server.URL, "test-api-key", httpClient)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		response, err := client.Search("ubuntu")
+		server.Close()
+		if err != nil {
+			t.Fatalf("DisableCompression=%v: expected no error, got %v", disable, err)
+		}
+		if !sawGzip {
+			t.Errorf("DisableCompression=%v: expected the request to accept gzip", disable)
+		}
+		if len(response.Results) != 3 {
+			t.Errorf("DisableCompression=%v: expected 3 results, got %d", disable, len(response.Results))
+		}
+	}
+}
+
+func TestGzipErrorBody(t *testing.T) {
+	var sawGzip bool
+	server := httptest.NewServer(gzipHandler(http.StatusInternalServerError, "indexer exploded", &sawGzip))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "test-api-key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	_, err = client.Search("ubuntu")
+	if err == nil || !strings.Contains(err.Error(), "indexer exploded") {
+		t.Errorf("Expected the decompressed error body in the error, got %v", err)
+	}
+}
+
+func TestGzipResponseLimitAppliesToDecompressedSize(t *testing.T) {
+	var sawGzip bool
+	body := `{"Results": [], "Indexers": [], "Padding": "` + strings.Repeat("a", 64*1024) + `"}`
+	server := httptest.NewServer(gzipHandler(http.StatusOK, body, &sawGzip))
+	defer server.Close()
+
+	client, err := NewClientWithOptions(server.URL, "test-api-key", WithMaxResponseBytes(16*1024))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.Search("ubuntu"); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("Expected ErrResponseTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package jackett
+
+import "context"
+
+// WithMaxConcurrentRequests caps the number of HTTP requests the client has
+// in flight at once, across every operation: parallel and planned searches,
+// batch downloads, bulk indexer tests and link validation all draw from the
+// same budget, so a single client can't open hundreds of connections to one
+// Jackett instance. Requests over the budget wait for a free slot or for
+// their context to be cancelled. A limit of zero or less means no limit.
+func WithMaxConcurrentRequests(n int) Option {
+	return func(c *Client) {
+		if n <= 0 {
+			c.requestSlots = nil
+			return
+		}
+		c.requestSlots = make(chan struct{}, n)
+	}
+}
+
+// acquireSlot waits for a slot in the concurrency budget, returning a
+// function that frees it
+func (c *Client) acquireSlot(ctx context.Context) (func(), error) {
+	if c.requestSlots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case c.requestSlots <- struct{}{}:
+		return func() { <-c.requestSlots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// fanOut returns the number of workers for a fan-out operation asked to run
+// concurrency at a time: values below one mean the client's concurrency
+// budget, or one at a time without a budget
+func (c *Client) fanOut(concurrency int) int {
+	if concurrency >= 1 {
+		return concurrency
+	}
+	if c.requestSlots != nil {
+		return cap(c.requestSlots)
+	}
+	return 1
+}
@@ -0,0 +1,48 @@
+package jackett
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAcquireSlotCancelled(t *testing.T) {
+	client, err := NewClientWithOptions("http://localhost:9117", "test-api-key", WithMaxConcurrentRequests(1))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	release, err := client.acquireSlot(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.acquireSlot(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded while the budget is used up, got %v", err)
+	}
+
+	release()
+	if _, err := client.acquireSlot(context.Background()); err != nil {
+		t.Errorf("Expected a free slot after release, got %v", err)
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	client, err := NewClient("http://localhost:9117", "test-api-key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := client.fanOut(0); got != 1 {
+		t.Errorf("Expected 1 worker without a budget, got %d", got)
+	}
+	if got := client.fanOut(3); got != 3 {
+		t.Errorf("Expected 3 workers, got %d", got)
+	}
+
+	WithMaxConcurrentRequests(6)(client)
+	if got := client.fanOut(0); got != 6 {
+		t.Errorf("Expected the budget of 6 workers, got %d", got)
+	}
+}
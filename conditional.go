@@ -0,0 +1,103 @@
+package jackett
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// validators remembers the responses of endpoints polled with conditional
+// requests, together with the ETag and Last-Modified they were served with,
+// so an unchanged response costs Jackett (or a caching proxy in front of it)
+// nothing but a 304
+type validators struct {
+	mu      sync.Mutex
+	entries map[string]validatedResponse
+}
+
+type validatedResponse struct {
+	etag         string
+	lastModified string
+	data         []byte
+}
+
+// conditionalKey is the context key of a conditionalRequest
+type conditionalKey struct{}
+
+// conditionalRequest marks a request as conditional on the response stored
+// under key
+type conditionalRequest struct {
+	store *validators
+	key   string
+}
+
+func newValidators() *validators {
+	return &validators{entries: make(map[string]validatedResponse)}
+}
+
+// conditional returns a copy of ctx that makes its GET request conditional
+// on the last response stored under key. Requests with a per-call API key
+// are left unconditional, since the stored response may belong to another
+// key.
+func (c *Client) conditional(ctx context.Context, key string) context.Context {
+	if hasAPIKeyOverride(ctx) {
+		return ctx
+	}
+	return context.WithValue(ctx, conditionalKey{}, &conditionalRequest{store: c.validators, key: key})
+}
+
+// conditionalFrom returns the conditionalRequest of ctx, or nil
+func conditionalFrom(ctx context.Context) *conditionalRequest {
+	cond, _ := ctx.Value(conditionalKey{}).(*conditionalRequest)
+	return cond
+}
+
+// prepare adds the stored validators to req
+func (cond *conditionalRequest) prepare(req *http.Request) {
+	if cond == nil || req.Method != "GET" {
+		return
+	}
+	cond.store.mu.Lock()
+	entry, ok := cond.store.entries[cond.key]
+	cond.store.mu.Unlock()
+	if !ok {
+		return
+	}
+	if entry.etag != "" && req.Header.Get("If-None-Match") == "" {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+	if entry.lastModified != "" && req.Header.Get("If-Modified-Since") == "" {
+		req.Header.Set("If-Modified-Since", entry.lastModified)
+	}
+}
+
+// notModified returns the stored body if resp is a 304 for it
+func (cond *conditionalRequest) notModified(resp *http.Response) ([]byte, bool) {
+	if cond == nil || resp.StatusCode != http.StatusNotModified {
+		return nil, false
+	}
+	cond.store.mu.Lock()
+	defer cond.store.mu.Unlock()
+	entry, ok := cond.store.entries[cond.key]
+	return entry.data, ok
+}
+
+// capture hands body to fn, storing it if resp carries validators and fn
+// accepts it
+func (cond *conditionalRequest) capture(resp *http.Response, body io.Reader, fn func(*http.Response, io.Reader) error) error {
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if cond == nil || resp.StatusCode != http.StatusOK || (etag == "" && lastModified == "") {
+		return fn(resp, body)
+	}
+
+	var buf bytes.Buffer
+	if err := fn(resp, io.TeeReader(body, &buf)); err != nil {
+		return err
+	}
+	cond.store.mu.Lock()
+	cond.store.entries[cond.key] = validatedResponse{etag: etag, lastModified: lastModified, data: buf.Bytes()}
+	cond.store.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,78 @@
+package jackett
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConditionalRequests(t *testing.T) {
+	var conditions []string
+	full := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2.0/indexers/all/results/torznab":
+			conditions = append(conditions, r.Header.Get("If-None-Match"))
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			full++
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(allIndexersXML))
+		case "/api/v2.0/server/config":
+			conditions = append(conditions, r.Header.Get("If-Modified-Since"))
+			if r.Header.Get("If-Modified-Since") != "" {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+			w.Write([]byte(`{"app_version": "0.22.0"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, "key")
+	for i := 0; i < 3; i++ {
+		indexers, err := client.GetIndexers()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(indexers) != 2 || indexers[0].ID != "configured-indexer" {
+			t.Errorf("Expected the indexers on call %d, got %+v", i, indexers)
+		}
+	}
+	if full != 1 || conditions[1] != `"v1"` || conditions[2] != `"v1"` {
+		t.Errorf("Expected later calls to be answered by a 304, got %d full responses and conditions %q", full, conditions)
+	}
+
+	conditions = nil
+	for i := 0; i < 2; i++ {
+		config, err := client.GetServerConfig()
+		if err != nil || config["app_version"] != "0.22.0" {
+			t.Fatalf("Expected the server config, got %v, %v", config, err)
+		}
+	}
+	if conditions[0] != "" || conditions[1] != "Mon, 01 Jan 2024 00:00:00 GMT" {
+		t.Errorf("Expected If-Modified-Since on the second call, got %q", conditions)
+	}
+}
+
+func TestConditionalWithoutValidators(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
+			t.Error("Expected no conditional headers without validators")
+		}
+		w.Write([]byte(allIndexersXML))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, "key")
+	client.GetIndexers()
+	if _, err := client.GetIndexers(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(client.validators.entries) != 0 {
+		t.Errorf("Expected nothing to be stored, got %d entries", len(client.validators.entries))
+	}
+}
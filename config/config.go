@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cehbz/jackett"
+)
+
+// Config holds the settings needed to create a client
+type Config struct {
+	URL                string
+	APIKey             string
+	Timeout            time.Duration
+	UserAgent          string
+	InsecureSkipVerify bool
+}
+
+// envVars maps environment variables to config keys
+var envVars = map[string]string{
+	"JACKETT_URL":                  "url",
+	"JACKETT_API_KEY":              "api_key",
+	"JACKETT_API_KEY_FILE":         "api_key_file",
+	"JACKETT_TIMEOUT":              "timeout",
+	"JACKETT_USER_AGENT":           "user_agent",
+	"JACKETT_INSECURE_SKIP_VERIFY": "insecure_skip_verify",
+}
+
+// LoadFromEnv creates a client configured by the JACKETT_* environment
+// variables and opts
+func LoadFromEnv(opts ...jackett.Option) (*jackett.Client, error) {
+	cfg, err := ReadEnv()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Client(opts...)
+}
+
+// LoadFromFile creates a client configured by the file at path and opts
+func LoadFromFile(path string, opts ...jackett.Option) (*jackett.Client, error) {
+	cfg, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Client(opts...)
+}
+
+// ReadEnv reads a Config from the JACKETT_* environment variables
+func ReadEnv() (Config, error) {
+	values := map[string]string{}
+	for env, key := range envVars {
+		if v, ok := os.LookupEnv(env); ok {
+			values[key] = v
+		}
+	}
+	return fromValues(values, "environment")
+}
+
+// ReadFile reads a Config from a JSON (.json) file, or from a flat key-value
+// file of "key: value" (.yaml, .yml) or "key = value" (.toml) lines. A
+// relative api_key_file is resolved against the directory of path.
+func ReadFile(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("config: %w", err)
+	}
+
+	var values map[string]string
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		values, err = parseJSON(data)
+	case ".yaml", ".yml":
+		values, err = parseKeyValue(data, ':')
+	case ".toml":
+		values, err = parseKeyValue(data, '=')
+	default:
+		return Config{}, fmt.Errorf("config: unsupported file type %q", ext)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("config: %s: %w", path, err)
+	}
+
+	if keyFile := values["api_key_file"]; keyFile != "" && !filepath.IsAbs(keyFile) {
+		values["api_key_file"] = filepath.Join(filepath.Dir(path), keyFile)
+	}
+	return fromValues(values, path)
+}
+
+// Client creates a client from the config. opts are applied after the
+// config's own settings and so take precedence.
+func (cfg Config) Client(opts ...jackett.Option) (*jackett.Client, error) {
+	var all []jackett.Option
+	if cfg.Timeout > 0 {
+		all = append(all, jackett.WithTimeout(cfg.Timeout))
+	}
+	if cfg.UserAgent != "" {
+		all = append(all, jackett.WithUserAgent(cfg.UserAgent))
+	}
+	if cfg.InsecureSkipVerify {
+		all = append(all, jackett.WithInsecureSkipVerify())
+	}
+	return jackett.NewClientWithOptions(cfg.URL, cfg.APIKey, append(all, opts...)...)
+}
+
+// fromValues builds a Config from settings keyed by their file keys
+func fromValues(values map[string]string, source string) (Config, error) {
+	var cfg Config
+	for key, value := range values {
+		value = strings.TrimSpace(value)
+		switch key {
+		case "url":
+			cfg.URL = value
+		case "api_key":
+			cfg.APIKey = value
+		case "api_key_file":
+			if value == "" {
+				continue
+			}
+			data, err := os.ReadFile(value)
+			if err != nil {
+				return Config{}, fmt.Errorf("config: api_key_file: %w", err)
+			}
+			if values["api_key"] == "" {
+				cfg.APIKey = strings.TrimSpace(string(data))
+			}
+		case "timeout":
+			d, err := parseTimeout(value)
+			if err != nil {
+				return Config{}, fmt.Errorf("config: %s: timeout: %w", source, err)
+			}
+			cfg.Timeout = d
+		case "user_agent":
+			cfg.UserAgent = value
+		case "insecure_skip_verify":
+			if value == "" {
+				continue
+			}
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return Config{}, fmt.Errorf("config: %s: insecure_skip_verify: %w", source, err)
+			}
+			cfg.InsecureSkipVerify = b
+		default:
+			return Config{}, fmt.Errorf("config: %s: unknown setting %q", source, key)
+		}
+	}
+
+	if cfg.URL == "" {
+		return Config{}, fmt.Errorf("config: %s: url is not set", source)
+	}
+	if cfg.APIKey == "" {
+		return Config{}, fmt.Errorf("config: %s: api_key is not set", source)
+	}
+	return cfg, nil
+}
+
+// parseTimeout accepts Go durations ("1m30s") and plain seconds ("90")
+func parseTimeout(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	return time.ParseDuration(value)
+}
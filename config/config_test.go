@@ -0,0 +1,116 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cehbz/jackett"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return path
+}
+
+func TestReadEnv(t *testing.T) {
+	t.Setenv("JACKETT_URL", "http://localhost:9117")
+	t.Setenv("JACKETT_API_KEY", "env-key")
+	t.Setenv("JACKETT_TIMEOUT", "90")
+	t.Setenv("JACKETT_USER_AGENT", "tool/1.0")
+	t.Setenv("JACKETT_INSECURE_SKIP_VERIFY", "false")
+
+	cfg, err := ReadEnv()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := Config{URL: "http://localhost:9117", APIKey: "env-key", Timeout: 90 * time.Second, UserAgent: "tool/1.0"}
+	if cfg != want {
+		t.Errorf("Expected %+v, got %+v", want, cfg)
+	}
+}
+
+func TestReadEnvKeyFile(t *testing.T) {
+	t.Setenv("JACKETT_URL", "http://localhost:9117")
+	t.Setenv("JACKETT_API_KEY_FILE", writeFile(t, "key", "secret-key\n"))
+
+	cfg, err := ReadEnv()
+	if err != nil || cfg.APIKey != "secret-key" {
+		t.Errorf("Expected the key from the file, got %+v, %v", cfg, err)
+	}
+}
+
+func TestReadEnvMissing(t *testing.T) {
+	t.Setenv("JACKETT_URL", "http://localhost:9117")
+	t.Setenv("JACKETT_API_KEY", "")
+	if _, err := ReadEnv(); err == nil || !strings.Contains(err.Error(), "api_key is not set") {
+		t.Errorf("Expected a missing key error, got %v", err)
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	want := Config{URL: "http://jackett:9117/jackett", APIKey: "file-key", Timeout: time.Minute, InsecureSkipVerify: true}
+	files := map[string]string{
+		"jackett.json": `{"url": "http://jackett:9117/jackett", "api_key": "file-key", "timeout": 60, "insecure_skip_verify": true}`,
+		"jackett.yaml": "# Jackett\nurl: http://jackett:9117/jackett\napi_key: \"file-key\"\ntimeout: 1m # generous\ninsecure_skip_verify: true\n",
+		"jackett.toml": "url = \"http://jackett:9117/jackett\"\napi_key = 'file-key'\ntimeout = \"1m\"\ninsecure_skip_verify = true\n",
+	}
+	for name, content := range files {
+		cfg, err := ReadFile(writeFile(t, name, content))
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", name, err)
+			continue
+		}
+		if cfg != want {
+			t.Errorf("%s: expected %+v, got %+v", name, want, cfg)
+		}
+	}
+}
+
+func TestReadFileErrors(t *testing.T) {
+	tests := map[string]string{
+		"a.ini":  "url=x",
+		"b.yaml": "url: http://localhost\napi_key: k\nproxy:\n  host: x\n",
+		"c.toml": "url = \"http://localhost\"\n[auth]\n",
+		"d.json": `{"url": "http://localhost", "api_key": "k", "apikey": "k"}`,
+		"e.yaml": "url: http://localhost\napi_key: k\ntimeout: soon\n",
+	}
+	for name, content := range tests {
+		if _, err := ReadFile(writeFile(t, name, content)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoadFromFile(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		w.Write([]byte(`{"app_version": "0.22.0"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "api_key"), []byte("k"), 0600)
+	path := filepath.Join(dir, "jackett.yml")
+	os.WriteFile(path, []byte("url: "+server.URL+"\napi_key_file: api_key\nuser_agent: tool/1.0\n"), 0600)
+
+	client, err := LoadFromFile(path, jackett.WithUserAgent("override/2.0"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.GetServerConfig(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if userAgent != "override/2.0" {
+		t.Errorf("Expected the options to take precedence, got %q", userAgent)
+	}
+}
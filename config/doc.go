@@ -0,0 +1,20 @@
+// Package config builds jackett clients from environment variables or a
+// config file, so small tools built on the library don't each reinvent the
+// same flags and settings.
+//
+// The same settings are recognized everywhere:
+//
+//	environment                   file key               meaning
+//	JACKETT_URL                   url                    base URL of Jackett (required)
+//	JACKETT_API_KEY               api_key                API key (required, or api_key_file)
+//	JACKETT_API_KEY_FILE          api_key_file           file holding the API key, e.g. a Docker secret
+//	JACKETT_TIMEOUT               timeout                per-request timeout, "30s" or seconds
+//	JACKETT_USER_AGENT            user_agent             User-Agent of requests
+//	JACKETT_INSECURE_SKIP_VERIFY  insecure_skip_verify   disable TLS certificate verification
+//
+// Config files are chosen by extension: a flat JSON object (.json), or a
+// flat key-value file of "key: value" (.yaml, .yml) or "key = value" (.toml)
+// lines, with optional quotes and # comments. The latter are not parsed as
+// YAML or TOML: sections, nesting and lists are rejected, which keeps the
+// package free of dependencies.
+package config
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseJSON reads a flat JSON object of strings, numbers and booleans
+func parseJSON(data []byte) (map[string]string, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, v := range raw {
+		switch v := v.(type) {
+		case string:
+			values[key] = v
+		case float64:
+			values[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			values[key] = strconv.FormatBool(v)
+		case nil:
+		default:
+			return nil, fmt.Errorf("%s: nested values are not supported", key)
+		}
+	}
+	return values, nil
+}
+
+// parseKeyValue reads a flat key-value file: one "key: value" (sep ':') or
+// "key = value" (sep '=') pair per line, with optional quotes around values
+// and # comments. It is not a YAML or TOML parser; sections, nesting and
+// lists are rejected.
+func parseKeyValue(data []byte, sep byte) (map[string]string, error) {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		if line == "" || line[0] == '#' || line == "---" {
+			continue
+		}
+		if line[0] == '[' || line[0] == '-' || raw[0] == ' ' || raw[0] == '\t' {
+			return nil, fmt.Errorf("line %d: nested values are not supported", n)
+		}
+
+		i := strings.IndexByte(line, sep)
+		if i < 0 {
+			return nil, fmt.Errorf("line %d: expected key %c value", n, sep)
+		}
+		key := strings.Trim(strings.TrimSpace(line[:i]), `"'`)
+		value, err := keyValue(strings.TrimSpace(line[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
+
+// keyValue unquotes a value and strips a trailing comment
+func keyValue(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, `"`):
+		end := closingQuote(value)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string %s", value)
+		}
+		if err := checkTrailer(value[end+1:]); err != nil {
+			return "", err
+		}
+		return strconv.Unquote(value[:end+1])
+	case strings.HasPrefix(value, "'"):
+		end := strings.IndexByte(value[1:], '\'') + 1
+		if end == 0 {
+			return "", fmt.Errorf("unterminated string %s", value)
+		}
+		if err := checkTrailer(value[end+1:]); err != nil {
+			return "", err
+		}
+		return value[1:end], nil
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// closingQuote returns the index of the quote ending the double-quoted
+// string value starts with, or -1 if it is unterminated
+func closingQuote(value string) int {
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// checkTrailer rejects anything but a comment after a quoted value
+func checkTrailer(rest string) error {
+	rest = strings.TrimSpace(rest)
+	if rest != "" && rest[0] != '#' {
+		return fmt.Errorf("unexpected %s after quoted value", rest)
+	}
+	return nil
+}
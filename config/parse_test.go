@@ -0,0 +1,38 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKeyValue(t *testing.T) {
+	values, err := parseKeyValue([]byte("---\nurl: http://h:9117 # comment\nkey: \"a \\\"quoted\\\" # value\"\nother: 'single'\n"+
+		"agent: \"app\" # \"quoted\" comment\nsingle: 'it' # it's\n\n"), ':')
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := map[string]string{"url": "http://h:9117", "key": `a "quoted" # value`, "other": "single", "agent": "app", "single": "it"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("Expected %v, got %v", want, values)
+	}
+
+	for _, bad := range []string{"novalue\n", "key: \"open\n", "  indented: x\n", "- item\n", "key: \"a\" b\n"} {
+		if _, err := parseKeyValue([]byte(bad), ':'); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	values, err := parseJSON([]byte(`{"timeout": 2.5, "insecure_skip_verify": false, "user_agent": null}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := map[string]string{"timeout": "2.5", "insecure_skip_verify": "false"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("Expected %v, got %v", want, values)
+	}
+	if _, err := parseJSON([]byte(`{"url": {"host": "x"}}`)); err == nil {
+		t.Error("Expected nested values to be rejected")
+	}
+}
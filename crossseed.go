@@ -0,0 +1,112 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cehbz/jackett/torrent"
+)
+
+// CrossSeedTarget describes a torrent already being seeded
+type CrossSeedTarget struct {
+	Name     string
+	Size     int64
+	InfoHash string
+}
+
+// CrossSeedTargetFromTorrent describes the torrent in meta
+func CrossSeedTargetFromTorrent(meta *torrent.Metainfo) CrossSeedTarget {
+	return CrossSeedTarget{Name: meta.Name, Size: meta.TotalSize, InfoHash: meta.InfoHash}
+}
+
+// CrossSeedOptions tune FindCrossSeeds
+type CrossSeedOptions struct {
+	// IndexerID limits the search to one indexer; empty means "all"
+	IndexerID string
+	// SizeTolerance is the fraction by which a result's size may differ
+	// from the target's, as trackers round sizes; 0.02 if zero. Use a
+	// negative value to require identical sizes.
+	SizeTolerance float64
+}
+
+// CrossSeedCandidate is a release that is likely the same content as the
+// target, so the target's data can seed it
+type CrossSeedCandidate struct {
+	Result SearchResult
+	// SameInfoHash reports that the result is the target torrent itself,
+	// which may be added from another tracker as-is
+	SameInfoHash bool
+	// SizeDiff is the absolute difference between the sizes in bytes
+	SizeDiff int64
+}
+
+// FindCrossSeeds searches for releases matching target that could be
+// cross-seeded: results whose normalized title equals the target's name and
+// whose size is within the tolerance. Results advertising the target's info
+// hash always match. Candidates are ordered by size difference, exact info
+// hash matches first. If some indexers failed, the candidates from the others
+// are returned together with a *PartialError.
+func (c *Client) FindCrossSeeds(ctx context.Context, target CrossSeedTarget, opts CrossSeedOptions) ([]CrossSeedCandidate, error) {
+	name := crossSeedTitle(target.Name)
+	if name == "" {
+		return nil, errors.New("cross-seed error: target has no name")
+	}
+	indexerID := opts.IndexerID
+	if indexerID == "" {
+		indexerID = "all"
+	}
+	tolerance := opts.SizeTolerance
+	if tolerance == 0 {
+		tolerance = 0.02
+	} else if tolerance < 0 {
+		tolerance = 0
+	}
+
+	response, err := c.search(ctx, indexerID, name, nil)
+	if response == nil {
+		return nil, fmt.Errorf("cross-seed error: %w", err)
+	}
+
+	hash := normalizeInfoHash(target.InfoHash)
+	var candidates []CrossSeedCandidate
+	for _, r := range response.Results {
+		diff := r.Size - target.Size
+		if diff < 0 {
+			diff = -diff
+		}
+		candidate := CrossSeedCandidate{Result: r, SizeDiff: diff}
+		switch {
+		case hash != "" && normalizeInfoHash(r.InfoHash) == hash:
+			candidate.SameInfoHash = true
+		case crossSeedTitle(r.Title) != name:
+			continue
+		case float64(diff) > tolerance*float64(target.Size):
+			continue
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].SameInfoHash != candidates[j].SameInfoHash {
+			return candidates[i].SameInfoHash
+		}
+		return candidates[i].SizeDiff < candidates[j].SizeDiff
+	})
+	return candidates, err
+}
+
+// crossSeedTitle normalizes a torrent name or release title for comparison:
+// a media file extension is dropped, and the name is lowercased with
+// punctuation collapsed to single spaces, so "Show.Name.S01E02.mkv" and
+// "Show Name S01E02" compare equal
+func crossSeedTitle(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".mkv", ".mp4", ".avi", ".m4v", ".ts", ".iso", ".flac", ".mp3", ".epub", ".pdf":
+		name = strings.TrimSuffix(name, path.Ext(name))
+	}
+	return strings.Join(tokenize(name), " ")
+}
@@ -0,0 +1,58 @@
+package jackett
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cehbz/jackett/torrent"
+)
+
+const crossSeedSearchJSON = `{"Results":[
+	{"Title":"Show Name S01E02 1080p WEB x264-GRP","Size":1000000000,"Tracker":"A"},
+	{"Title":"Show.Name.S01E02.1080p.WEB.x264-GRP","Size":1010000000,"Tracker":"B"},
+	{"Title":"Show.Name.S01E02.1080p.WEB.x264-GRP","Size":1100000000,"Tracker":"C"},
+	{"Title":"Show.Name.S01E02.720p.WEB.x264-GRP","Size":1000000000,"Tracker":"D"},
+	{"Title":"Renamed upload","Size":5,"Tracker":"E","InfoHash":"0123456789ABCDEF0123456789ABCDEF01234567"}
+],"Indexers":[]}`
+
+func TestFindCrossSeeds(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("Query")
+		w.Write([]byte(crossSeedSearchJSON))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, "test-api-key")
+	target := CrossSeedTargetFromTorrent(&torrent.Metainfo{
+		Name:      "Show.Name.S01E02.1080p.WEB.x264-GRP.mkv",
+		TotalSize: 1000000000,
+		InfoHash:  "0123456789abcdef0123456789abcdef01234567",
+	})
+
+	candidates, err := client.FindCrossSeeds(context.Background(), target, CrossSeedOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if query != "show name s01e02 1080p web x264 grp" {
+		t.Errorf("Expected a normalized query, got %q", query)
+	}
+
+	var trackers []string
+	for _, c := range candidates {
+		trackers = append(trackers, c.Result.Tracker)
+	}
+	if len(candidates) != 3 || trackers[0] != "E" || trackers[1] != "A" || trackers[2] != "B" {
+		t.Fatalf("Expected the info hash match, then A and B by size, got %v", trackers)
+	}
+	if !candidates[0].SameInfoHash || candidates[1].SameInfoHash || candidates[2].SizeDiff != 10000000 {
+		t.Errorf("Unexpected candidates %+v", candidates)
+	}
+
+	candidates, _ = client.FindCrossSeeds(context.Background(), CrossSeedTarget{Name: target.Name, Size: target.Size}, CrossSeedOptions{SizeTolerance: -1})
+	if len(candidates) != 1 || candidates[0].Result.Tracker != "A" {
+		t.Errorf("Expected only the identical size to match, got %+v", candidates)
+	}
+}
@@ -0,0 +1,199 @@
+package jackett
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxDumpBody is how much of each request and response body is transcribed
+const maxDumpBody = 64 << 10
+
+// WithDebugDump writes a transcript of every HTTP exchange (API calls, logins
+// and downloads, including each redirect) to w. Secrets are removed: the API
+// key, the admin password, tracker credentials, and credential headers such
+// as Authorization and Cookie. Bodies are decompressed and cut off after
+// 64 KiB; binary bodies such as torrent files are only summarized. The
+// transcript is meant to be attached to Jackett bug reports.
+func WithDebugDump(w io.Writer) Option {
+	return func(c *Client) {
+		c.dump = &debugDump{w: w, c: c}
+	}
+}
+
+// debugDump serializes transcripts written by concurrent requests
+type debugDump struct {
+	mu sync.Mutex
+	w  io.Writer
+	c  *Client
+}
+
+// dumping returns httpClient with its transport wrapped to write transcripts
+// if WithDebugDump is in effect
+func (c *Client) dumping(httpClient *http.Client) *http.Client {
+	if c.dump == nil {
+		return httpClient
+	}
+	base := httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	hc := *httpClient
+	hc.Transport = &dumpTransport{base: base, dump: c.dump}
+	return &hc
+}
+
+// dumpTransport transcribes each round trip through base
+type dumpTransport struct {
+	base http.RoundTripper
+	dump *debugDump
+}
+
+func (t *dumpTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqBody, _ = io.ReadAll(io.LimitReader(body, maxDumpBody+1))
+			body.Close()
+		}
+	}
+
+	start := t.dump.c.clock.Now()
+	resp, err := t.base.RoundTrip(req)
+	duration := t.dump.c.clock.Now().Sub(start)
+
+	var respBody []byte
+	if err == nil {
+		// Transcribe the start of the body and hand the caller all of it
+		respBody, _ = io.ReadAll(io.LimitReader(resp.Body, maxDumpBody+1))
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(respBody), resp.Body), resp.Body}
+	}
+
+	t.dump.write(req, reqBody, resp, respBody, duration, err)
+	return resp, err
+}
+
+// write transcribes one exchange
+func (d *debugDump) write(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, duration time.Duration, err error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "=== %s %s (%s)\n", req.Method, d.c.redact(req.Context(), req.URL.Host), duration.Round(time.Millisecond))
+	fmt.Fprintf(&b, "> %s %s %s\n", req.Method, d.c.redact(req.Context(), req.URL.RequestURI()), req.Proto)
+	d.writeHeader(req.Context(), &b, "> ", req.Header)
+	d.writeBody(req.Context(), &b, "> ", req.Header, reqBody)
+
+	if err != nil {
+		fmt.Fprintf(&b, "! %s\n", d.c.redactError(req.Context(), err))
+	} else {
+		fmt.Fprintf(&b, "< %s %s\n", resp.Proto, resp.Status)
+		d.writeHeader(req.Context(), &b, "< ", resp.Header)
+		d.writeBody(req.Context(), &b, "< ", resp.Header, respBody)
+	}
+	b.WriteString("\n")
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	io.WriteString(d.w, b.String())
+}
+
+func (d *debugDump) writeHeader(ctx context.Context, b *strings.Builder, prefix string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range header[name] {
+			if sensitiveHeader(name) {
+				value = redactedPlaceholder
+			}
+			fmt.Fprintf(b, "%s%s: %s\n", prefix, name, d.c.redact(ctx, value))
+		}
+	}
+	b.WriteString(prefix + "\n")
+}
+
+func (d *debugDump) writeBody(ctx context.Context, b *strings.Builder, prefix string, header http.Header, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+
+	truncated := len(body) > maxDumpBody
+	if truncated {
+		body = body[:maxDumpBody]
+	}
+	if header.Get("Content-Encoding") == "gzip" {
+		// A cut off stream decompresses up to the cut
+		if zr, err := gzip.NewReader(bytes.NewReader(body)); err == nil {
+			body, _ = io.ReadAll(io.LimitReader(zr, maxDumpBody))
+		}
+	}
+
+	contentType := header.Get("Content-Type")
+	if !textual(contentType, body) {
+		fmt.Fprintf(b, "%s[%d bytes of %s]\n", prefix, len(body), contentType)
+		return
+	}
+
+	text := d.c.redact(ctx, string(body), bodySecrets(contentType, body)...)
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		b.WriteString(prefix + line + "\n")
+	}
+	if truncated {
+		b.WriteString(prefix + "[truncated]\n")
+	}
+}
+
+// sensitiveHeader reports whether a header carries credentials
+func sensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range []string{"authorization", "cookie", "secret", "token", "api-key", "apikey"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// textual reports whether a body is readable text rather than binary data
+// such as a torrent file
+func textual(contentType string, body []byte) bool {
+	if strings.Contains(contentType, "bittorrent") || strings.Contains(contentType, "octet-stream") {
+		return false
+	}
+	sniffed := http.DetectContentType(body)
+	return strings.HasPrefix(sniffed, "text/") || strings.Contains(sniffed, "json")
+}
+
+// bodySecrets finds credentials in a request or response body: passwords
+// in login forms and sensitive indexer config fields
+func bodySecrets(contentType string, body []byte) []string {
+	var secrets []string
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			for key, values := range form {
+				if strings.Contains(strings.ToLower(key), "password") {
+					secrets = append(secrets, values...)
+				}
+			}
+		}
+	}
+
+	var fields []IndexerConfigField
+	if json.Unmarshal(body, &fields) == nil {
+		secrets = append(secrets, fieldSecrets(fields)...)
+	}
+	return secrets
+}
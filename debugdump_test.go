@@ -0,0 +1,75 @@
+package jackett
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWithDebugDump(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2.0/indexers/all/results":
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Encoding", "gzip")
+			zw := gzip.NewWriter(w)
+			zw.Write([]byte(`{"Results": [{"Title": "Ubuntu", "Link": "http://jackett/dl/a/?jackett_apikey=` + secretKey + `"}], "Indexers": []}`))
+			zw.Close()
+		case "/UI/Dashboard":
+			http.SetCookie(w, &http.Cookie{Name: "Jackett", Value: "session-token"})
+			w.WriteHeader(http.StatusFound)
+		case "/dl/a/":
+			w.Header().Set("Content-Type", "application/x-bittorrent")
+			w.Write([]byte(testTorrent))
+		}
+	}))
+	defer server.Close()
+
+	var dump bytes.Buffer
+	client, err := NewClientWithOptions(server.URL, secretKey,
+		WithDebugDump(&dump),
+		WithAuthProvider(CloudflareAccessAuth("client-id", "client-secret")),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response, err := client.Search("ubuntu")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(response.Results) != 1 || response.Results[0].Title != "Ubuntu" {
+		t.Errorf("Expected the response to be unaffected by the dump, got %+v", response.Results)
+	}
+	if err := client.Login("admin-password"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.DownloadTorrent(server.URL + "/dl/a/?path=x"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	transcript := dump.String()
+	for _, want := range []string{
+		"> GET /api/v2.0/indexers/all/results?Query=ubuntu&apikey=REDACTED HTTP/1.1",
+		"> Cf-Access-Client-Secret: REDACTED",
+		"> Cf-Access-Client-Id: client-id",
+		"< HTTP/1.1 200 OK",
+		`< {"Results": [{"Title": "Ubuntu", "Link": "http://jackett/dl/a/?jackett_apikey=REDACTED"}], "Indexers": []}`,
+		"> password=REDACTED",
+		"< Set-Cookie: REDACTED",
+		"< [" + strconv.Itoa(len(testTorrent)) + " bytes of application/x-bittorrent]",
+	} {
+		if !strings.Contains(transcript, want) {
+			t.Errorf("Expected transcript to contain %q, got:\n%s", want, transcript)
+		}
+	}
+	for _, secret := range []string{secretKey, "client-secret", "admin-password", "session-token"} {
+		if strings.Contains(transcript, secret) {
+			t.Errorf("Expected %q to be redacted, got:\n%s", secret, transcript)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package jackett
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// ResultHook is invoked for each search result as it is decoded from the
+// response stream. The hook may modify the result in place; returning false
+// drops the result so it never accumulates in the returned SearchResponse.
+type ResultHook func(*SearchResult) bool
+
+// SearchWithHook performs a search query on the given indexer ("all" for every
+// configured indexer), invoking hook for each result as it is decoded. A hook
+// that consumes every result and returns false processes arbitrarily large
+// responses in constant memory.
+func (c *Client) SearchWithHook(indexerID, query string, hook ResultHook) (*SearchResponse, error) {
+	return c.search(context.Background(), indexerID, query, hook)
+}
+
+// search runs a query against the results endpoint. Searches without a hook
+// may be answered from the search cache or share a request with identical
+// concurrent searches.
+//
+// If some of the searched indexers failed, the response is returned together
+// with a *PartialError; if all of them failed, only an error wrapping
+// ErrAllIndexersFailed is returned.
+func (c *Client) search(ctx context.Context, indexerID, query string, hook ResultHook) (*SearchResponse, error) {
+	start := c.clock.Now()
+	response, err := c.searchResponse(ctx, indexerID, query, hook)
+	if err != nil {
+		c.recordHistory(ctx, "search", indexerID, query, nil, start, nil, err)
+		return nil, err
+	}
+	c.recordHistory(ctx, "search", indexerID, query, nil, start, response, indexerFailures(response.Indexers))
+
+	if err := indexerFailures(response.Indexers); err != nil {
+		if errors.Is(err, ErrAllIndexersFailed) {
+			return nil, fmt.Errorf("search error: %w", err)
+		}
+		return response, err
+	}
+	return response, nil
+}
+
+// searchResponse obtains the response to a search from the cache, a
+// concurrent identical search or Jackett
+func (c *Client) searchResponse(ctx context.Context, indexerID, query string, hook ResultHook) (*SearchResponse, error) {
+	if hook != nil || hasAPIKeyOverride(ctx) {
+		return c.fetchSearch(ctx, indexerID, query, hook)
+	}
+
+	key := searchCacheKey(indexerID, query)
+	if response, ok := c.searchCache.get(key, c.clock.Now()); ok {
+		return response, nil
+	}
+	return c.coalesceSearch(key, func() (*SearchResponse, error) {
+		response, err := c.fetchSearch(ctx, indexerID, query, nil)
+		if err == nil && !errors.Is(indexerFailures(response.Indexers), ErrAllIndexersFailed) {
+			c.searchCache.put(key, response, c.clock.Now())
+		}
+		return response, err
+	})
+}
+
+// fetchSearch requests a search, decoding the response as it streams in
+// rather than buffering the whole body, which matters for aggregate searches
+// returning tens of thousands of results
+func (c *Client) fetchSearch(ctx context.Context, indexerID, query string, hook ResultHook) (*SearchResponse, error) {
+	params := url.Values{}
+	params.Set("apikey", c.apiKeyFor(ctx))
+	params.Set("Query", query)
+
+	var truncated bool
+	if c.maxResults > 0 {
+		hook = limitResults(hook, c.maxResults, &truncated)
+	}
+
+	if err := c.waitForRateLimit(ctx, indexerID); err != nil {
+		return nil, fmt.Errorf("search error: %w", err)
+	}
+
+	var response *SearchResponse
+	start := c.clock.Now()
+	endpoint := fmt.Sprintf("/api/v2.0/indexers/%s/results", url.PathEscape(indexerID))
+	err := c.doStream(ctx, "GET", endpoint, params, nil, func(body io.Reader) error {
+		var err error
+		response, err = decodeSearchResponse(body, hook)
+		return err
+	})
+	if response != nil {
+		response.Truncated = truncated
+		c.logIndexerErrors(ctx, response.Indexers)
+	}
+	c.recordSearch(indexerID, start, response, err)
+	if err != nil {
+		err = fmt.Errorf("search error: %w", err)
+		c.audit("search", indexerID, normalizeQuery(query), start, 0, err)
+		return nil, err
+	}
+	c.audit("search", indexerID, normalizeQuery(query), start, len(response.Results), indexerFailures(response.Indexers))
+
+	c.postProcess(response)
+	return response, nil
+}
+
+// decodeSearchResponse decodes a SearchResponse one result at a time, passing
+// each through hook (if non-nil) before it is retained.
+func decodeSearchResponse(r io.Reader, hook ResultHook) (*SearchResponse, error) {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, fmt.Errorf("failed to decode search response: %w", err)
+	}
+
+	var response SearchResponse
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode search response: %w", err)
+		}
+		key, _ := tok.(string)
+
+		switch {
+		case strings.EqualFold(key, "Results"):
+			if err := decodeResults(dec, &response, hook); err != nil {
+				return nil, fmt.Errorf("failed to decode search response: %w", err)
+			}
+		case strings.EqualFold(key, "Indexers"):
+			if err := dec.Decode(&response.Indexers); err != nil {
+				return nil, fmt.Errorf("failed to decode search response: %w", err)
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, fmt.Errorf("failed to decode search response: %w", err)
+			}
+		}
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, fmt.Errorf("failed to decode search response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// decodeResults decodes the Results array element by element
+func decodeResults(dec *json.Decoder, response *SearchResponse, hook ResultHook) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected array for Results, got %v", tok)
+	}
+
+	for dec.More() {
+		var result SearchResult
+		if err := dec.Decode(&result); err != nil {
+			return err
+		}
+		if hook != nil && !hook(&result) {
+			continue
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("expected %v, got %v", want, tok)
+	}
+	return nil
+}
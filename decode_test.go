@@ -0,0 +1,122 @@
+package jackett
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const hookSearchJSON = `{
+  "Results": [
+    {"Title": "Keep 1080p", "Seeders": 10, "Tracker": "a"},
+    {"Title": "Drop 480p", "Seeders": 1, "Tracker": "b"},
+    {"Title": "Keep 720p", "Seeders": 5, "Tracker": "c"}
+  ],
+  "Indexers": [
+    {"ID": "a", "Name": "A", "Status": 2, "Results": 3, "Error": null}
+  ],
+  "Unknown": {"ignored": true}
+}`
+
+func TestSearchWithHook(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2.0/indexers/all/results": {statusCode: http.StatusOK, responseBody: hookSearchJSON},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "GET", url: "/api/v2.0/indexers/all/results", query: url.Values{"apikey": []string{"test-api-key"}, "Query": []string{"test"}}},
+	}
+
+	client, mockTransport, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var seen int
+	results, err := client.SearchWithHook("all", "test", func(r *SearchResult) bool {
+		seen++
+		r.Title = strings.ToUpper(r.Title)
+		return strings.HasPrefix(r.Title, "KEEP")
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if seen != 3 {
+		t.Errorf("Expected hook to see 3 results, got %d", seen)
+	}
+	if len(results.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results.Results))
+	}
+	if results.Results[1].Title != "KEEP 720P" {
+		t.Errorf("Expected transformed title 'KEEP 720P', got '%s'", results.Results[1].Title)
+	}
+	if len(results.Indexers) != 1 || results.Indexers[0].ID != "a" {
+		t.Errorf("Expected indexer status for 'a', got %v", results.Indexers)
+	}
+
+	if mockTransport.requestIndex != len(mockTransport.expectedRequests) {
+		t.Errorf("Not all expected requests were made")
+	}
+}
+
+func TestDecodeSearchResponse_NullResults(t *testing.T) {
+	response, err := decodeSearchResponse(strings.NewReader(`{"Results": null, "Indexers": []}`), nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(response.Results) != 0 {
+		t.Errorf("Expected 0 results, got %d", len(response.Results))
+	}
+}
+
+func TestDecodeSearchResponse_Malformed(t *testing.T) {
+	if _, err := decodeSearchResponse(strings.NewReader(`{"Results": [{"Title": 1}]}`), nil); err == nil {
+		t.Fatal("Expected error, got none")
+	}
+}
+
+// largeSearchJSON builds an aggregate search response with n results
+func largeSearchJSON(n int) []byte {
+	var b strings.Builder
+	b.WriteString(`{"Results": [`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `{"Title": "Release.%d.1080p.WEB.x264", "Size": %d, "Seeders": %d, "Tracker": "tracker-%d", "Link": "http://localhost:9117/dl/x/?path=%d", "CategoryDesc": "TV/HD"}`, i, i*1024, i%100, i%20, i)
+	}
+	b.WriteString(`], "Indexers": []}`)
+	return []byte(b.String())
+}
+
+func BenchmarkDecodeSearchResponse_Stream(b *testing.B) {
+	data := largeSearchJSON(10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := decodeSearchResponse(bytes.NewReader(data), nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeSearchResponse_Buffered(b *testing.B) {
+	data := largeSearchJSON(10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		body, err := io.ReadAll(bytes.NewReader(data))
+		if err != nil {
+			b.Fatal(err)
+		}
+		var response SearchResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
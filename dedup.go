@@ -0,0 +1,181 @@
+package jackett
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/cehbz/jackett/torrent"
+)
+
+// downloadCache remembers downloaded .torrent files by info hash and by link
+// so repeat downloads within a session are served from memory
+type downloadCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	byHash     map[string][]byte
+	byLink     map[string]string // link -> info hash
+	order      []string          // info hashes, oldest first
+}
+
+// WithDownloadDedup makes the client remember the .torrent files it
+// downloads, keyed by info hash and link, and serve repeat downloads of the
+// same torrent from memory. At most maxEntries torrents are kept (the oldest
+// are forgotten first); maxEntries < 1 means no limit.
+func WithDownloadDedup(maxEntries int) Option {
+	return func(c *Client) {
+		c.downloads = &downloadCache{
+			maxEntries: maxEntries,
+			byHash:     make(map[string][]byte),
+			byLink:     make(map[string]string),
+		}
+	}
+}
+
+// lookupHash returns a copy of the cached torrent with the given info hash
+func (d *downloadCache) lookupHash(infoHash string) ([]byte, bool) {
+	if d == nil || infoHash == "" {
+		return nil, false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	data, ok := d.byHash[infoHash]
+	if !ok {
+		return nil, false
+	}
+	return append([]byte(nil), data...), true
+}
+
+// lookupLink returns a copy of the cached torrent previously downloaded from link
+func (d *downloadCache) lookupLink(link string) ([]byte, bool) {
+	if d == nil {
+		return nil, false
+	}
+	d.mu.Lock()
+	infoHash, ok := d.byLink[link]
+	d.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	return d.lookupHash(infoHash)
+}
+
+// store caches a torrent downloaded from link. Data that doesn't decode as a
+// torrent is not cached.
+func (d *downloadCache) store(link string, data []byte) {
+	if d == nil {
+		return
+	}
+	meta, err := torrent.Parse(data)
+	if err != nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.byLink[link] = meta.InfoHash
+	if _, ok := d.byHash[meta.InfoHash]; ok {
+		return
+	}
+	d.byHash[meta.InfoHash] = append([]byte(nil), data...)
+	d.order = append(d.order, meta.InfoHash)
+
+	for d.maxEntries > 0 && len(d.order) > d.maxEntries {
+		oldest := d.order[0]
+		d.order = d.order[1:]
+		delete(d.byHash, oldest)
+		for link, infoHash := range d.byLink {
+			if infoHash == oldest {
+				delete(d.byLink, link)
+			}
+		}
+	}
+}
+
+// WithDownloadSeenStore records the info hash of every release the client
+// downloads in store, and fails later downloads of the same release with
+// ErrAlreadyDownloaded. Unlike WithDownloadDedup, a persistent store such as
+// FileSeenStore or SQLSeenStore remembers grabs across restarts and crashes,
+// and clients sharing it never grab a release twice between them.
+//
+// Releases whose info hash is known up front (magnet URIs and results with
+// an InfoHash fetched with SearchResult.Fetch) are refused without a
+// request. Other .torrent files are checked once downloaded and before
+// anything is written; downloads that aren't torrents, such as NZB files,
+// are not recorded.
+func WithDownloadSeenStore(store SeenStore) Option {
+	return func(c *Client) {
+		c.downloadSeen = store
+	}
+}
+
+// downloadSeenKey is the key of an info hash in the download seen store,
+// kept apart from the keys Watchers store
+func downloadSeenKey(infoHash string) string {
+	return "btih:" + infoHash
+}
+
+// checkDownloaded returns ErrAlreadyDownloaded if the release with the
+// normalized infoHash was downloaded before
+func (c *Client) checkDownloaded(infoHash string) error {
+	if c.downloadSeen == nil || infoHash == "" {
+		return nil
+	}
+	seen, err := c.downloadSeen.Seen(downloadSeenKey(infoHash))
+	if err != nil {
+		return fmt.Errorf("download seen store error: %w", err)
+	}
+	if seen {
+		return ErrAlreadyDownloaded
+	}
+	return nil
+}
+
+// markDownloaded records the release with the normalized infoHash as
+// downloaded
+func (c *Client) markDownloaded(infoHash string) error {
+	if c.downloadSeen == nil || infoHash == "" {
+		return nil
+	}
+	if err := c.downloadSeen.Mark(downloadSeenKey(infoHash)); err != nil {
+		return fmt.Errorf("download seen store error: %w", err)
+	}
+	return nil
+}
+
+// claimMagnet refuses a magnet URI downloaded before and records it otherwise
+func (c *Client) claimMagnet(magnetURI string) error {
+	infoHash := magnetInfoHash(magnetURI)
+	if err := c.checkDownloaded(infoHash); err != nil {
+		return err
+	}
+	return c.markDownloaded(infoHash)
+}
+
+// downloadUnseen downloads link like download, but holds the file back
+// until its info hash has been checked against the download seen store, and
+// records it once written to w
+func (c *Client) downloadUnseen(ctx context.Context, link string, w io.Writer, retry RetryPolicy) (int64, int, error) {
+	var buf bytes.Buffer
+	_, attempts, err := c.download(ctx, link, &buf, retry)
+	if err != nil {
+		return 0, attempts, err
+	}
+
+	var infoHash string
+	if meta, err := torrent.Parse(buf.Bytes()); err == nil {
+		infoHash = meta.InfoHash
+	}
+	if err := c.checkDownloaded(infoHash); err != nil {
+		return 0, attempts, err
+	}
+	n, err := w.Write(buf.Bytes())
+	if err != nil {
+		return int64(n), attempts, err
+	}
+	return int64(n), attempts, c.markDownloaded(infoHash)
+}
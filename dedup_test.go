@@ -0,0 +1,133 @@
+package jackett
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWithDownloadDedup(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(testTorrent))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithOptions(server.URL, "test-api-key", WithDownloadDedup(0))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		data, err := client.DownloadTorrent(server.URL + "/dl/a")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if string(data) != testTorrent {
+			t.Errorf("Expected torrent data, got %q", data)
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("Expected 1 request for repeated link, got %d", n)
+	}
+
+	// A different link to a torrent with a known info hash is served from memory
+	result := SearchResult{Title: "same torrent", Link: server.URL + "/dl/b", InfoHash: strings.ToUpper(testTorrentInfoHash)}
+	release, err := result.Fetch(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(release.Torrent) != testTorrent {
+		t.Errorf("Expected cached torrent data, got %q", release.Torrent)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("Expected no request for known info hash, got %d total", n)
+	}
+}
+
+func TestDownloadCache_Eviction(t *testing.T) {
+	cache := &downloadCache{maxEntries: 1, byHash: map[string][]byte{}, byLink: map[string]string{}}
+	other := strings.Replace(testTorrent, "5:x.iso", "5:y.iso", 1)
+
+	cache.store("a", []byte(testTorrent))
+	cache.store("b", []byte(other))
+	cache.store("c", []byte("not a torrent"))
+
+	if _, ok := cache.lookupLink("a"); ok {
+		t.Error("Expected oldest torrent to be evicted")
+	}
+	if _, ok := cache.lookupHash(testTorrentInfoHash); ok {
+		t.Error("Expected evicted info hash to be forgotten")
+	}
+	if data, ok := cache.lookupLink("b"); !ok || string(data) != other {
+		t.Errorf("Expected newest torrent to be cached, got %q", data)
+	}
+	if _, ok := cache.lookupLink("c"); ok {
+		t.Error("Expected invalid torrent not to be cached")
+	}
+}
+
+func TestWithDownloadSeenStore(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(testTorrent))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "downloads.seen")
+	store, err := OpenFileSeenStore(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	client, _ := NewClientWithOptions(server.URL, "test-api-key", WithDownloadSeenStore(store))
+	if _, err := client.DownloadTorrent(server.URL + "/dl/a"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.DownloadTorrent(server.URL + "/dl/b"); !errors.Is(err, ErrAlreadyDownloaded) {
+		t.Errorf("Expected ErrAlreadyDownloaded for another link to the same torrent, got %v", err)
+	}
+	store.Close()
+
+	// Another client, as after a restart, shares the grabs through the file
+	store, err = OpenFileSeenStore(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer store.Close()
+	other, _ := NewClientWithOptions(server.URL, "test-api-key", WithDownloadSeenStore(store))
+
+	var written bytes.Buffer
+	if _, err := other.DownloadTorrentContext(context.Background(), server.URL+"/dl/a", &written); !errors.Is(err, ErrAlreadyDownloaded) {
+		t.Errorf("Expected ErrAlreadyDownloaded after a restart, got %v", err)
+	}
+	if written.Len() != 0 {
+		t.Errorf("Expected nothing to be written, got %d bytes", written.Len())
+	}
+
+	before := atomic.LoadInt32(&requests)
+	result := SearchResult{Title: "same torrent", Link: server.URL + "/dl/c", InfoHash: strings.ToUpper(testTorrentInfoHash)}
+	if _, err := result.Fetch(context.Background(), other); !errors.Is(err, ErrAlreadyDownloaded) {
+		t.Errorf("Expected ErrAlreadyDownloaded for a known info hash, got %v", err)
+	}
+	if _, err := other.DownloadRelease(context.Background(), "magnet:?xt=urn:btih:"+testTorrentInfoHash); !errors.Is(err, ErrAlreadyDownloaded) {
+		t.Errorf("Expected ErrAlreadyDownloaded for a magnet URI of the torrent, got %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != before {
+		t.Errorf("Expected no requests for known info hashes, got %d", n-before)
+	}
+
+	if _, err := other.DownloadRelease(context.Background(), testMagnetURI); err != nil {
+		t.Errorf("Expected a new release to be downloaded, got %v", err)
+	}
+	if _, err := other.DownloadRelease(context.Background(), testMagnetURI); !errors.Is(err, ErrAlreadyDownloaded) {
+		t.Errorf("Expected ErrAlreadyDownloaded for a repeated magnet URI, got %v", err)
+	}
+}
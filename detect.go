@@ -0,0 +1,158 @@
+package jackett
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Backend is a kind of server that can answer searches
+type Backend int
+
+const (
+	// BackendUnknown is a server that answered none of the probes
+	BackendUnknown Backend = iota
+	// BackendJackett is Jackett, with its JSON search and admin endpoints
+	BackendJackett
+	// BackendProwlarr is Prowlarr's v1 API
+	BackendProwlarr
+	// BackendTorznab is a plain torznab XML endpoint, such as NZBHydra2's
+	BackendTorznab
+)
+
+func (b Backend) String() string {
+	switch b {
+	case BackendJackett:
+		return "Jackett"
+	case BackendProwlarr:
+		return "Prowlarr"
+	case BackendTorznab:
+		return "torznab"
+	}
+	return "unknown"
+}
+
+// ErrUnknownBackend is returned by DetectBackend when no supported API
+// answers at the URL
+var ErrUnknownBackend = errors.New("jackett: no supported search API found")
+
+// Detection is the outcome of DetectBackend
+type Detection struct {
+	Backend Backend
+	// URL is the base URL for NewClient or NewProwlarrClient, or the API URL
+	// for NewTorznabClient
+	URL string
+	// JSONSearch reports whether the server has Jackett's JSON search
+	// endpoint; otherwise searches go through torznab XML
+	JSONSearch bool
+}
+
+// torznabPaths are tried, in order, for a plain torznab endpoint below the
+// base URL; the base URL itself may be the endpoint, and NZBHydra2 serves it
+// at /torznab/api
+var torznabPaths = []string{"", "/api", "/torznab/api"}
+
+// DetectBackend probes baseURL for Jackett's API, Prowlarr's v1 API and
+// plain torznab endpoints, in that order. httpClient may be nil.
+func DetectBackend(ctx context.Context, baseURL, apiKey string, httpClient *http.Client) (*Detection, error) {
+	baseURL, _, err := normalizeBaseURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if httpClient == nil {
+		httpClient = newDefaultHTTPClient()
+	}
+
+	if isTorznabCaps(ctx, httpClient, baseURL+"/api/v2.0/indexers/all/results/torznab/api", apiKey) {
+		return &Detection{Backend: BackendJackett, URL: baseURL, JSONSearch: true}, nil
+	}
+	if isProwlarr(ctx, httpClient, baseURL, apiKey) {
+		return &Detection{Backend: BackendProwlarr, URL: baseURL}, nil
+	}
+	for _, path := range torznabPaths {
+		if isTorznabCaps(ctx, httpClient, baseURL+path, apiKey) {
+			return &Detection{Backend: BackendTorznab, URL: baseURL + path}, nil
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return nil, fmt.Errorf("%w at %s", ErrUnknownBackend, baseURL)
+}
+
+// NewSearcher detects the kind of server at baseURL and returns a client for
+// it: a *Client, *ProwlarrClient or *TorznabClient. httpClient may be nil.
+func NewSearcher(ctx context.Context, baseURL, apiKey string, httpClient *http.Client) (Searcher, error) {
+	detected, err := DetectBackend(ctx, baseURL, apiKey, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	var clients []*http.Client
+	if httpClient != nil {
+		clients = append(clients, httpClient)
+	}
+	switch detected.Backend {
+	case BackendJackett:
+		return NewClient(detected.URL, apiKey, clients...)
+	case BackendProwlarr:
+		return NewProwlarrClient(detected.URL, apiKey, clients...)
+	default:
+		return NewTorznabClient(detected.URL, apiKey, clients...)
+	}
+}
+
+// isTorznabCaps reports whether apiURL answers a torznab caps request.
+// A torznab error, such as a rejected API key, also identifies an endpoint.
+func isTorznabCaps(ctx context.Context, hc *http.Client, apiURL, apiKey string) bool {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL+"?"+url.Values{"t": {"caps"}, "apikey": {apiKey}}.Encode(), nil)
+	if err != nil {
+		return false
+	}
+	data, err := fetchXML(hc, req)
+	var torznabErr *TorznabError
+	if errors.As(err, &torznabErr) {
+		return true
+	}
+	if err != nil {
+		return false
+	}
+
+	if _, ok := parseTorznabError(data); ok {
+		return true
+	}
+
+	var root struct {
+		XMLName xml.Name
+	}
+	return xml.Unmarshal(data, &root) == nil && root.XMLName.Local == "caps"
+}
+
+// isProwlarr reports whether baseURL serves Prowlarr's system status
+func isProwlarr(ctx context.Context, hc *http.Client, baseURL, apiKey string) bool {
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/api/v1/system/status", nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("X-Api-Key", apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+
+	var status struct {
+		AppName string `json:"appName"`
+	}
+	return json.NewDecoder(resp.Body).Decode(&status) == nil && strings.EqualFold(status.AppName, "Prowlarr")
+}
@@ -0,0 +1,76 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDetectBackend(t *testing.T) {
+	jackett := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/jackett/api/v2.0/indexers/all/results/torznab/api" {
+			w.Write([]byte(testCapsXML))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer jackett.Close()
+
+	prowlarr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/system/status" && r.Header.Get("X-Api-Key") == "key" {
+			w.Write([]byte(`{"appName":"Prowlarr","version":"1.20.0"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer prowlarr.Close()
+
+	hydra := newHydraTestServer(t, nil)
+	defer hydra.Close()
+
+	unknown := httptest.NewServer(http.NotFoundHandler())
+	defer unknown.Close()
+
+	tests := []struct {
+		url     string
+		backend Backend
+		apiURL  string
+		json    bool
+	}{
+		{jackett.URL + "/jackett/", BackendJackett, jackett.URL + "/jackett", true},
+		{prowlarr.URL, BackendProwlarr, prowlarr.URL, false},
+		{hydra.URL, BackendTorznab, hydra.URL + "/torznab/api", false},
+		{hydra.URL + "/torznab/api", BackendTorznab, hydra.URL + "/torznab/api", false},
+	}
+	for _, tt := range tests {
+		detected, err := DetectBackend(context.Background(), tt.url, "key", nil)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.url, err)
+		}
+		if detected.Backend != tt.backend || detected.URL != tt.apiURL || detected.JSONSearch != tt.json {
+			t.Errorf("%s: expected %v at %s, got %+v", tt.url, tt.backend, tt.apiURL, detected)
+		}
+	}
+
+	// A rejected API key still identifies a torznab endpoint
+	if detected, err := DetectBackend(context.Background(), hydra.URL, "wrong", nil); err != nil || detected.Backend != BackendTorznab {
+		t.Errorf("Expected torznab for a rejected key, got %+v, %v", detected, err)
+	}
+
+	if _, err := DetectBackend(context.Background(), unknown.URL, "key", nil); !errors.Is(err, ErrUnknownBackend) {
+		t.Errorf("Expected ErrUnknownBackend, got %v", err)
+	}
+
+	searcher, err := NewSearcher(context.Background(), hydra.URL, "key", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := searcher.(*TorznabClient); !ok {
+		t.Errorf("Expected a *TorznabClient, got %T", searcher)
+	}
+	if response, err := searcher.Search("show"); err != nil || len(response.Results) != 1 {
+		t.Errorf("Unexpected search %+v, %v", response, err)
+	}
+}
@@ -0,0 +1,391 @@
+package jackett
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DownloadedRelease is the outcome of downloading a release: exactly one of
+// the contents of a .torrent file, a magnet URI or, for usenet results, the
+// contents of an NZB file
+type DownloadedRelease struct {
+	Torrent   []byte
+	MagnetURI string
+	NZB       []byte
+}
+
+// IsMagnet reports whether the release resolved to a magnet URI
+func (d *DownloadedRelease) IsMagnet() bool {
+	return d.MagnetURI != ""
+}
+
+// MagnetRedirectError is returned by DownloadTorrent and
+// DownloadTorrentContext when the link redirects to a magnet URI instead of
+// serving a .torrent file. Use DownloadRelease to handle both transparently.
+type MagnetRedirectError struct {
+	MagnetURI string
+}
+
+func (e *MagnetRedirectError) Error() string {
+	return "download redirected to magnet URI: " + e.MagnetURI
+}
+
+// DownloadRelease downloads the release at link, which Jackett frequently
+// answers with a redirect to a magnet URI rather than a .torrent file. The
+// redirect is not followed; the magnet URI is returned instead. A link that
+// is itself a magnet URI is returned as-is, and NZB files of usenet indexers
+// are returned in the NZB field.
+func (c *Client) DownloadRelease(ctx context.Context, link string) (*DownloadedRelease, error) {
+	if isMagnetURI(link) {
+		if err := c.claimMagnet(link); err != nil {
+			return nil, err
+		}
+		return &DownloadedRelease{MagnetURI: link}, nil
+	}
+
+	var buf bytes.Buffer
+	_, err := c.DownloadTorrentContext(ctx, link, &buf)
+	var magnet *MagnetRedirectError
+	if errors.As(err, &magnet) {
+		if err := c.claimMagnet(magnet.MagnetURI); err != nil {
+			return nil, err
+		}
+		return &DownloadedRelease{MagnetURI: magnet.MagnetURI}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if looksLikeNZB(buf.Bytes()) {
+		return &DownloadedRelease{NZB: buf.Bytes()}, nil
+	}
+	return &DownloadedRelease{Torrent: buf.Bytes()}, nil
+}
+
+// Fetch downloads the release using the best available mechanism: the
+// magnet URI if the result has one, otherwise the .torrent file behind Link
+// (which may itself turn out to be a magnet redirect). Clients created with
+// WithInfoHashVerification also verify the release.
+func (r SearchResult) Fetch(ctx context.Context, client *Client) (*DownloadedRelease, error) {
+	if err := client.checkDownloaded(normalizeInfoHash(r.InfoHash)); err != nil {
+		return nil, err
+	}
+
+	var release *DownloadedRelease
+	switch {
+	case r.MagnetURI != "":
+		if err := client.claimMagnet(r.MagnetURI); err != nil {
+			return nil, err
+		}
+		release = &DownloadedRelease{MagnetURI: r.MagnetURI}
+	case r.Link != "":
+		if data, ok := client.downloads.lookupHash(normalizeInfoHash(r.InfoHash)); ok {
+			if err := client.markDownloaded(normalizeInfoHash(r.InfoHash)); err != nil {
+				return nil, err
+			}
+			return &DownloadedRelease{Torrent: data}, nil
+		}
+		var err error
+		if release, err = client.DownloadRelease(ctx, r.Link); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("fetch %q error: result has neither a magnet URI nor a link", r.Title)
+	}
+
+	if client.verifyInfoHash {
+		if err := r.Verify(release); err != nil {
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+// RedirectPolicy controls which redirects downloads follow. Redirects to
+// magnet URIs are never followed; they are reported as the download's result.
+type RedirectPolicy struct {
+	// MaxRedirects is the most redirects a download follows; zero disables
+	// following redirects
+	MaxRedirects int
+	// AllowCrossHost permits redirects to a host other than the link's
+	AllowCrossHost bool
+}
+
+// ErrRedirectNotAllowed is returned when a download redirect violates the
+// client's RedirectPolicy
+var ErrRedirectNotAllowed = errors.New("jackett: redirect not allowed")
+
+// WithRedirectPolicy sets the redirect policy for downloads and link
+// validation. Without it, downloads follow the redirects the HTTP client's
+// own CheckRedirect allows (ten by default).
+func WithRedirectPolicy(policy RedirectPolicy) Option {
+	return func(c *Client) {
+		c.redirectPolicy = &policy
+	}
+}
+
+// check applies the policy to a redirect to req, given the requests so far
+func (p *RedirectPolicy) check(req *http.Request, via []*http.Request) error {
+	if len(via) > p.MaxRedirects {
+		return fmt.Errorf("%w: more than %d redirects", ErrRedirectNotAllowed, p.MaxRedirects)
+	}
+	if !p.AllowCrossHost && req.URL.Host != via[0].URL.Host {
+		return fmt.Errorf("%w: cross-host redirect from %s to %s", ErrRedirectNotAllowed, via[0].URL.Host, req.URL.Host)
+	}
+	return nil
+}
+
+// downloadClient returns a copy of the HTTP client that stops at redirects to
+// magnet URIs, which http.Client would otherwise fail to follow with an
+// "unsupported protocol scheme" error, removes the headers meant for Jackett
+// from redirects elsewhere, and applies the client's redirect policy
+func (c *Client) downloadClient() *http.Client {
+	hc := *c.client
+	checkRedirect := hc.CheckRedirect
+	policy := c.redirectPolicy
+	hc.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		c.stripHeaders(req)
+		if req.URL.Scheme == "magnet" {
+			return http.ErrUseLastResponse
+		}
+		if policy != nil {
+			return policy.check(req, via)
+		}
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+		// http.Client's default policy
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	return &hc
+}
+
+// magnetRedirect returns the magnet URI resp redirects to, if any
+func magnetRedirect(resp *http.Response) (string, bool) {
+	if resp.StatusCode < 300 || resp.StatusCode > 399 {
+		return "", false
+	}
+	location := resp.Header.Get("Location")
+	return location, isMagnetURI(location)
+}
+
+// stopAtMagnet returns a copy of hc that stops at redirects to magnet URIs,
+// for download clients of servers other than Jackett. onRedirect, if
+// non-nil, may adjust each redirected request, e.g. to drop credentials.
+func stopAtMagnet(hc *http.Client, onRedirect func(*http.Request)) *http.Client {
+	client := *hc
+	checkRedirect := hc.CheckRedirect
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if req.URL.Scheme == "magnet" {
+			return http.ErrUseLastResponse
+		}
+		if onRedirect != nil {
+			onRedirect(req)
+		}
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+		// http.Client's default policy
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	return &client
+}
+
+// copyTorrent streams the torrent file of a download response to w,
+// failing with ErrDownloadTooLarge beyond limit bytes (if positive)
+func copyTorrent(resp *http.Response, w io.Writer, limit int64) (int64, error) {
+	if magnetURI, ok := magnetRedirect(resp); ok {
+		return 0, &MagnetRedirectError{MagnetURI: magnetURI}
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		if torznabErr, ok := parseTorznabError(body); ok {
+			return 0, fmt.Errorf("download failed: %w", torznabErr)
+		}
+		return 0, fmt.Errorf("download failed: %w", &statusError{code: resp.StatusCode, body: string(body), path: resp.Request.URL.Path})
+	}
+	body, err := inspectTorznabError(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("download failed: %w", err)
+	}
+
+	if limit <= 0 {
+		return io.Copy(w, body)
+	}
+	n, err := io.Copy(w, io.LimitReader(body, limit+1))
+	if err != nil {
+		return n, fmt.Errorf("download error: %w", err)
+	}
+	if n > limit {
+		return n, fmt.Errorf("%w: exceeds limit of %d bytes", ErrDownloadTooLarge, limit)
+	}
+	return n, nil
+}
+
+func isMagnetURI(s string) bool {
+	return strings.HasPrefix(strings.ToLower(s), "magnet:")
+}
+
+// DownloadResult is the outcome of one download in a batch
+type DownloadResult struct {
+	Link     string
+	Torrent  []byte
+	Attempts int
+	Err      error
+}
+
+// DownloadTorrents downloads every link, running at most concurrency
+// downloads at a time (concurrency < 1 means as many as
+// WithMaxConcurrentRequests allows, or one at a time without it). The results
+// are in the same order as links; individual failures are recorded in them.
+// The error is non-nil only if ctx was cancelled.
+func (c *Client) DownloadTorrents(ctx context.Context, links []string, concurrency int) ([]DownloadResult, error) {
+	return c.DownloadTorrentsWithRetry(ctx, links, concurrency, c.downloadRetry)
+}
+
+// DownloadTorrentsWithRetry is like DownloadTorrents but retries failed
+// downloads according to retry instead of the client's download retry
+// policy.
+func (c *Client) DownloadTorrentsWithRetry(ctx context.Context, links []string, concurrency int, retry RetryPolicy) ([]DownloadResult, error) {
+	concurrency = c.fanOut(concurrency)
+
+	results := make([]DownloadResult, len(links))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, link := range links {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return results, ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(i int, link string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i] = c.downloadWithRetry(ctx, link, retry)
+		}(i, link)
+	}
+	wg.Wait()
+
+	return results, ctx.Err()
+}
+
+func (c *Client) downloadWithRetry(ctx context.Context, link string, retry RetryPolicy) DownloadResult {
+	var buf bytes.Buffer
+	_, attempts, err := c.downloadTorrent(ctx, link, &buf, retry)
+	result := DownloadResult{Link: link, Attempts: attempts, Err: err}
+	if err == nil {
+		result.Torrent = buf.Bytes()
+	}
+	return result
+}
+
+// LinkInfo describes a live download link
+type LinkInfo struct {
+	// ContentType is the media type served, e.g. application/x-bittorrent
+	ContentType string
+	// Size is the size of the download in bytes, or -1 if unknown
+	Size int64
+	// MagnetURI is set if the link is, or redirects to, a magnet URI
+	MagnetURI string
+}
+
+// ValidateLink checks that result can be downloaded without downloading it:
+// it sends a HEAD request to the result's link, falling back to a one-byte
+// ranged GET for servers that don't support HEAD. Results with a magnet URI
+// are valid without any request. A dead link returns an error.
+func (c *Client) ValidateLink(ctx context.Context, result SearchResult) (*LinkInfo, error) {
+	if result.MagnetURI != "" {
+		return &LinkInfo{Size: -1, MagnetURI: result.MagnetURI}, nil
+	}
+	if result.Link == "" {
+		return nil, fmt.Errorf("validate link %q error: result has neither a magnet URI nor a link", result.Title)
+	}
+	if isMagnetURI(result.Link) {
+		return &LinkInfo{Size: -1, MagnetURI: result.Link}, nil
+	}
+
+	done, err := c.lifecycle.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	release, err := c.acquireSlot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("validate link error: %w", err)
+	}
+	defer release()
+
+	resp, err := c.probeLink(ctx, "HEAD", result.Link)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp.Body.Close()
+		resp, err = c.probeLink(ctx, "GET", result.Link)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("validate link error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if magnetURI, ok := magnetRedirect(resp); ok {
+		return &LinkInfo{Size: -1, MagnetURI: magnetURI}, nil
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return nil, fmt.Errorf("validate link error: link is dead (%d)", resp.StatusCode)
+	}
+
+	info := &LinkInfo{ContentType: resp.Header.Get("Content-Type"), Size: resp.ContentLength}
+	if resp.StatusCode == http.StatusPartialContent {
+		info.Size = contentRangeSize(resp.Header.Get("Content-Range"))
+	}
+	return info, nil
+}
+
+// probeLink sends a HEAD request or a one-byte ranged GET to link
+func (c *Client) probeLink(ctx context.Context, method, link string) (*http.Response, error) {
+	req, err := c.newDownloadRequest(ctx, method, link)
+	if err != nil {
+		return nil, c.redactError(ctx, err)
+	}
+	if method == "GET" {
+		req.Header.Set("Range", "bytes=0-0")
+	}
+	req, cancel := c.withRequestTimeout(req)
+	resp, err := c.dumping(c.downloadClient()).Do(req)
+	if err != nil {
+		cancel()
+		return nil, c.redactError(ctx, err)
+	}
+	resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// contentRangeSize extracts the complete length from a Content-Range header
+// such as "bytes 0-0/12345", returning -1 if it is unknown
+func contentRangeSize(contentRange string) int64 {
+	i := strings.LastIndexByte(contentRange, '/')
+	if i < 0 {
+		return -1
+	}
+	size, err := strconv.ParseInt(contentRange[i+1:], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return size
+}
@@ -0,0 +1,203 @@
+package jackett
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testMagnetURI = "magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567&dn=test"
+
+func TestDownloadTorrentsWithRetry(t *testing.T) {
+	var flakyCalls, missingCalls, active, maxActive int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			m := atomic.LoadInt32(&maxActive)
+			if n <= m || atomic.CompareAndSwapInt32(&maxActive, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		switch r.URL.Path {
+		case "/dl/flaky":
+			if atomic.AddInt32(&flakyCalls, 1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Write([]byte("flaky data"))
+		case "/dl/missing":
+			atomic.AddInt32(&missingCalls, 1)
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.Write([]byte("data for " + r.URL.Path))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "test-api-key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	links := []string{server.URL + "/dl/a", server.URL + "/dl/flaky", server.URL + "/dl/missing", server.URL + "/dl/b"}
+	results, err := client.DownloadTorrentsWithRetry(context.Background(), links, 2, RetryPolicy{MaxAttempts: 3})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(results) != len(links) {
+		t.Fatalf("Expected %d results, got %d", len(links), len(results))
+	}
+	if string(results[0].Torrent) != "data for /dl/a" || results[0].Link != links[0] {
+		t.Errorf("Expected results in link order, got %+v", results[0])
+	}
+	if results[1].Err != nil || results[1].Attempts != 2 || string(results[1].Torrent) != "flaky data" {
+		t.Errorf("Expected flaky download to succeed on attempt 2, got %+v", results[1])
+	}
+	if results[2].Err == nil || results[2].Attempts != 1 || atomic.LoadInt32(&missingCalls) != 1 {
+		t.Errorf("Expected missing download to fail without retries, got %+v", results[2])
+	}
+	if m := atomic.LoadInt32(&maxActive); m > 2 {
+		t.Errorf("Expected at most 2 concurrent downloads, got %d", m)
+	}
+}
+
+func TestValidateLink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dl/ok":
+			if r.Method != "HEAD" {
+				t.Errorf("Expected HEAD, got %s", r.Method)
+			}
+			w.Header().Set("Content-Type", "application/x-bittorrent")
+			w.Header().Set("Content-Length", "12345")
+		case "/dl/nohead":
+			if r.Method == "HEAD" {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			if r.Header.Get("Range") != "bytes=0-0" {
+				t.Errorf("Expected one-byte range, got '%s'", r.Header.Get("Range"))
+			}
+			w.Header().Set("Content-Type", "application/x-bittorrent")
+			w.Header().Set("Content-Range", "bytes 0-0/6789")
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write([]byte("d"))
+		case "/dl/magnet":
+			http.Redirect(w, r, testMagnetURI, http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "test-api-key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	ctx := context.Background()
+
+	info, err := client.ValidateLink(ctx, SearchResult{Link: server.URL + "/dl/ok"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if info.ContentType != "application/x-bittorrent" || info.Size != 12345 {
+		t.Errorf("Expected torrent of 12345 bytes, got %+v", info)
+	}
+
+	info, err = client.ValidateLink(ctx, SearchResult{Link: server.URL + "/dl/nohead"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if info.Size != 6789 {
+		t.Errorf("Expected size 6789 from Content-Range, got %d", info.Size)
+	}
+
+	info, err = client.ValidateLink(ctx, SearchResult{Link: server.URL + "/dl/magnet"})
+	if err != nil || info.MagnetURI != testMagnetURI {
+		t.Errorf("Expected magnet URI, got %+v (%v)", info, err)
+	}
+
+	if _, err := client.ValidateLink(ctx, SearchResult{Link: server.URL + "/dl/dead"}); err == nil {
+		t.Error("Expected error for dead link, got none")
+	}
+}
+
+func TestWithRedirectPolicy(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("cdn data"))
+	}))
+	defer other.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/same":
+			http.Redirect(w, r, "/final", http.StatusFound)
+		case "/twice":
+			http.Redirect(w, r, "/same", http.StatusFound)
+		case "/cross":
+			http.Redirect(w, r, other.URL+"/file", http.StatusFound)
+		case "/magnet":
+			http.Redirect(w, r, testMagnetURI, http.StatusFound)
+		case "/final":
+			w.Write([]byte("torrent file data"))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithOptions(server.URL, "test-api-key", WithRedirectPolicy(RedirectPolicy{MaxRedirects: 1}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if data, err := client.DownloadTorrent(server.URL + "/same"); err != nil || string(data) != "torrent file data" {
+		t.Errorf("Expected same-host redirect to be followed, got %q (%v)", data, err)
+	}
+	if _, err := client.DownloadTorrent(server.URL + "/twice"); !errors.Is(err, ErrRedirectNotAllowed) {
+		t.Errorf("Expected ErrRedirectNotAllowed for too many redirects, got %v", err)
+	}
+	if _, err := client.DownloadTorrent(server.URL + "/cross"); !errors.Is(err, ErrRedirectNotAllowed) {
+		t.Errorf("Expected ErrRedirectNotAllowed for cross-host redirect, got %v", err)
+	}
+	if release, err := client.DownloadRelease(context.Background(), server.URL+"/magnet"); err != nil || release.MagnetURI != testMagnetURI {
+		t.Errorf("Expected magnet redirect as data, got %+v (%v)", release, err)
+	}
+
+	WithRedirectPolicy(RedirectPolicy{MaxRedirects: 1, AllowCrossHost: true})(client)
+	if data, err := client.DownloadTorrent(server.URL + "/cross"); err != nil || string(data) != "cdn data" {
+		t.Errorf("Expected cross-host redirect to be followed, got %q (%v)", data, err)
+	}
+}
+
+func TestDownloadTorrent_TorznabErrorWithOK(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><error code="900" description="Login failed"/>`))
+	}))
+	defer server.Close()
+
+	var written bytes.Buffer
+	client, _ := NewClientWithOptions(server.URL, "test-api-key",
+		WithDownloadRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}))
+	_, err := client.DownloadTorrentContext(context.Background(), server.URL+"/dl/test/?path=abc", &written)
+
+	var torznabErr *TorznabError
+	if !errors.As(err, &torznabErr) || torznabErr.Description != "Login failed" {
+		t.Fatalf("Expected the torznab error, got %v", err)
+	}
+	if written.Len() != 0 {
+		t.Errorf("Expected the error document not to be written, got %q", written.String())
+	}
+	if requests != 1 {
+		t.Errorf("Expected the error not to be retried, got %d requests", requests)
+	}
+}
@@ -0,0 +1,341 @@
+package jackett
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+)
+
+// OutputFormat selects how a ResultEncoder writes results
+type OutputFormat string
+
+const (
+	// FormatTable is an aligned, human-readable table; sizes are formatted
+	// by FormatSize
+	FormatTable OutputFormat = "table"
+	// FormatJSON is an indented JSON array
+	FormatJSON OutputFormat = "json"
+	// FormatNDJSON is one JSON object per line, for jq and streaming consumers
+	FormatNDJSON OutputFormat = "ndjson"
+	// FormatCSV is comma-separated values with a header row
+	FormatCSV OutputFormat = "csv"
+	// FormatMarkdown is a GitHub-flavored Markdown table
+	FormatMarkdown OutputFormat = "markdown"
+	// FormatTemplate executes ResultEncoder.Template once per result
+	FormatTemplate OutputFormat = "template"
+)
+
+// ErrUnknownFormat is returned for an output format ResultEncoder doesn't
+// support
+var ErrUnknownFormat = errors.New("jackett: unknown output format")
+
+// ErrUnknownColumn is returned for a ResultEncoder column that isn't one of
+// ResultColumns
+var ErrUnknownColumn = errors.New("jackett: unknown result column")
+
+// ParseOutputFormat returns the output format with the given name
+func ParseOutputFormat(name string) (OutputFormat, error) {
+	switch format := OutputFormat(strings.ToLower(name)); format {
+	case FormatTable, FormatJSON, FormatNDJSON, FormatCSV, FormatMarkdown, FormatTemplate:
+		return format, nil
+	}
+	return "", fmt.Errorf("%w %q", ErrUnknownFormat, name)
+}
+
+// ResultEncoder writes search results in one of the output formats. The
+// zero value writes a table.
+type ResultEncoder struct {
+	Format OutputFormat
+	// Columns selects and orders the fields written by every format but
+	// FormatTemplate; see ResultColumns for the names. Without it, tables
+	// show a summary, CSV and Markdown show DefaultColumns, and JSON and
+	// NDJSON show every field of SearchResult.
+	Columns []string
+	// Template is the text/template executed with each SearchResult for
+	// FormatTemplate, e.g. "{{.Title}}\t{{.Link}}". A newline is added
+	// after each result unless the template ends with one.
+	Template string
+}
+
+// DefaultColumns are the columns of CSV and Markdown output unless
+// ResultEncoder.Columns is set
+var DefaultColumns = []string{
+	"title", "tracker", "size", "seeders", "peers", "publish_date",
+	"category", "info_hash", "link", "magnet_uri", "details",
+}
+
+// tableColumns are the columns of a table unless ResultEncoder.Columns is
+// set
+var tableColumns = []string{"title", "size", "seeders", "peers", "tracker", "publish_date"}
+
+// Encode writes results to w
+func (e ResultEncoder) Encode(w io.Writer, results []SearchResult) error {
+	switch e.Format {
+	case "", FormatTable:
+		columns, err := selectColumns(e.Columns, tableColumns)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		header := make([]string, len(columns))
+		for i, col := range columns {
+			header[i] = strings.ToUpper(strings.ReplaceAll(col.name, "_", " "))
+		}
+		if e.Columns == nil {
+			header[len(header)-1] = "PUBLISHED"
+		}
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+		for _, r := range results {
+			cells := columnStrings(columns, r)
+			for i, col := range columns {
+				if col.name == "size" {
+					cells[i] = FormatSize(r.Size)
+				}
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+		return tw.Flush()
+	case FormatJSON:
+		values, err := e.jsonValues(results)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(values)
+	case FormatNDJSON:
+		values, err := e.jsonValues(results)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(w)
+		for _, v := range values {
+			if err := enc.Encode(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatCSV:
+		columns, err := selectColumns(e.Columns, DefaultColumns)
+		if err != nil {
+			return err
+		}
+		cw := csv.NewWriter(w)
+		cw.Write(columnNames(columns))
+		for _, r := range results {
+			cw.Write(columnStrings(columns, r))
+		}
+		cw.Flush()
+		return cw.Error()
+	case FormatMarkdown:
+		columns, err := selectColumns(e.Columns, DefaultColumns)
+		if err != nil {
+			return err
+		}
+		return encodeMarkdown(w, columns, results)
+	case FormatTemplate:
+		return e.encodeTemplate(w, results)
+	}
+	return fmt.Errorf("%w %q", ErrUnknownFormat, e.Format)
+}
+
+// jsonValues returns the values JSON output encodes: the results themselves,
+// or objects holding just the selected columns
+func (e ResultEncoder) jsonValues(results []SearchResult) ([]interface{}, error) {
+	values := make([]interface{}, len(results))
+	if e.Columns == nil {
+		for i, r := range results {
+			values[i] = r
+		}
+		return values, nil
+	}
+
+	columns, err := selectColumns(e.Columns, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i, r := range results {
+		object := make(map[string]interface{}, len(columns))
+		for _, col := range columns {
+			object[col.name] = col.value(r)
+		}
+		values[i] = object
+	}
+	return values, nil
+}
+
+func (e ResultEncoder) encodeTemplate(w io.Writer, results []SearchResult) error {
+	text := e.Template
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	tmpl, err := template.New("result").Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid result template: %w", err)
+	}
+	for _, r := range results {
+		if err := tmpl.Execute(w, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeMarkdown writes a GitHub-flavored Markdown table
+func encodeMarkdown(w io.Writer, columns []resultColumn, results []SearchResult) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("| " + strings.Join(columnNames(columns), " | ") + " |\n")
+	bw.WriteString("|" + strings.Repeat(" --- |", len(columns)) + "\n")
+	for _, r := range results {
+		cells := columnStrings(columns, r)
+		for i, cell := range cells {
+			cells[i] = markdownEscaper.Replace(cell)
+		}
+		bw.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	return bw.Flush()
+}
+
+// markdownEscaper keeps cell text from breaking out of its table cell
+var markdownEscaper = strings.NewReplacer("|", "\\|", "\r\n", " ", "\n", " ", "\r", " ")
+
+// resultColumn is a named field of SearchResult. value returns nil for
+// fields the result lacks.
+type resultColumn struct {
+	name  string
+	value func(SearchResult) interface{}
+}
+
+// resultColumns lists every column in the order of SearchResult's fields
+var resultColumns = []resultColumn{
+	{"title", func(r SearchResult) interface{} { return r.Title }},
+	{"size", func(r SearchResult) interface{} { return r.Size }},
+	{"seeders", func(r SearchResult) interface{} { return r.Seeders }},
+	{"peers", func(r SearchResult) interface{} { return r.Peers }},
+	{"link", func(r SearchResult) interface{} { return r.Link }},
+	{"magnet_uri", func(r SearchResult) interface{} { return r.MagnetURI }},
+	{"guid", func(r SearchResult) interface{} { return r.GUID }},
+	{"publish_date", func(r SearchResult) interface{} { return r.PublishDate }},
+	{"tracker", func(r SearchResult) interface{} { return r.Tracker }},
+	{"category", func(r SearchResult) interface{} { return r.Category }},
+	{"category_desc", func(r SearchResult) interface{} { return r.CategoryDesc }},
+	{"blackhole_link", func(r SearchResult) interface{} { return deref(r.BlackholeLink) }},
+	{"gain", func(r SearchResult) interface{} { return r.Gain }},
+	{"info_hash", func(r SearchResult) interface{} { return r.InfoHash }},
+	{"minimum_ratio", func(r SearchResult) interface{} { return deref(r.MinimumRatio) }},
+	{"minimum_seed_time", func(r SearchResult) interface{} { return deref(r.MinimumSeedTime) }},
+	{"download_volume_factor", func(r SearchResult) interface{} { return r.DownloadVolumeFactor }},
+	{"upload_volume_factor", func(r SearchResult) interface{} { return r.UploadVolumeFactor }},
+	{"first_seen", func(r SearchResult) interface{} { return r.FirstSeen }},
+	{"tracker_id", func(r SearchResult) interface{} { return r.TrackerId }},
+	{"tracker_type", func(r SearchResult) interface{} { return r.TrackerType }},
+	{"details", func(r SearchResult) interface{} { return r.Details }},
+	{"files", func(r SearchResult) interface{} { return deref(r.Files) }},
+	{"grabs", func(r SearchResult) interface{} { return deref(r.Grabs) }},
+	{"description", func(r SearchResult) interface{} { return deref(r.Description) }},
+	{"rage_id", func(r SearchResult) interface{} { return deref(r.RageID) }},
+	{"tvdb_id", func(r SearchResult) interface{} { return deref(r.TVDBId) }},
+	{"imdb", func(r SearchResult) interface{} { return deref(r.Imdb) }},
+	{"tmdb", func(r SearchResult) interface{} { return deref(r.TMDb) }},
+	{"tvmaze_id", func(r SearchResult) interface{} { return deref(r.TVMazeId) }},
+	{"trakt_id", func(r SearchResult) interface{} { return deref(r.TraktId) }},
+	{"douban_id", func(r SearchResult) interface{} { return deref(r.DoubanId) }},
+	{"genres", func(r SearchResult) interface{} { return deref(r.Genres) }},
+	{"languages", func(r SearchResult) interface{} { return r.Languages }},
+	{"subs", func(r SearchResult) interface{} { return r.Subs }},
+	{"year", func(r SearchResult) interface{} { return deref(r.Year) }},
+	{"author", func(r SearchResult) interface{} { return deref(r.Author) }},
+	{"book_title", func(r SearchResult) interface{} { return deref(r.BookTitle) }},
+	{"publisher", func(r SearchResult) interface{} { return deref(r.Publisher) }},
+	{"artist", func(r SearchResult) interface{} { return deref(r.Artist) }},
+	{"album", func(r SearchResult) interface{} { return deref(r.Album) }},
+	{"label", func(r SearchResult) interface{} { return deref(r.Label) }},
+	{"track", func(r SearchResult) interface{} { return deref(r.Track) }},
+	{"poster", func(r SearchResult) interface{} { return deref(r.Poster) }},
+	{"content_type", func(r SearchResult) interface{} { return r.ContentType }},
+	{"usenet_date", func(r SearchResult) interface{} { return r.UsenetDate }},
+	{"score", func(r SearchResult) interface{} { return r.Score }},
+}
+
+// ResultColumns returns the names of the columns ResultEncoder can write
+func ResultColumns() []string {
+	return columnNames(resultColumns)
+}
+
+// deref returns the value p points to, or nil
+func deref[T any](p *T) interface{} {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+// selectColumns looks up the named columns, or the defaults if names is nil
+// (every column if defaults is nil too)
+func selectColumns(names, defaults []string) ([]resultColumn, error) {
+	if names == nil {
+		if defaults == nil {
+			return resultColumns, nil
+		}
+		names = defaults
+	}
+	columns := make([]resultColumn, 0, len(names))
+	for _, name := range names {
+		col, ok := findColumn(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrUnknownColumn, name)
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+func findColumn(name string) (resultColumn, bool) {
+	for _, col := range resultColumns {
+		if strings.EqualFold(col.name, name) {
+			return col, true
+		}
+	}
+	return resultColumn{}, false
+}
+
+func columnNames(columns []resultColumn) []string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.name
+	}
+	return names
+}
+
+// columnStrings returns the text of r's columns: absent fields are empty and
+// lists are separated by semicolons
+func columnStrings(columns []resultColumn, r SearchResult) []string {
+	cells := make([]string, len(columns))
+	for i, col := range columns {
+		switch v := col.value(r).(type) {
+		case nil:
+		case string:
+			cells[i] = v
+		case []int:
+			ids := make([]string, len(v))
+			for j, id := range v {
+				ids[j] = strconv.Itoa(id)
+			}
+			cells[i] = strings.Join(ids, ";")
+		case []string:
+			cells[i] = strings.Join(v, ";")
+		case float64:
+			cells[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			cells[i] = fmt.Sprint(v)
+		}
+	}
+	return cells
+}
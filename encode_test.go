@@ -0,0 +1,124 @@
+package jackett
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var encodeResults = []SearchResult{
+	{Title: "Big, \"quoted\"", Size: 900, Seeders: 9, Peers: 12, Tracker: "a", Category: []int{2000, 2040}, Link: "http://a/dl"},
+	{Title: "Small", Size: 100, Seeders: 3, Tracker: "b", MagnetURI: "magnet:?xt=urn:btih:abc"},
+}
+
+func TestResultEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (ResultEncoder{}).Encode(&buf, encodeResults); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "TITLE") || !strings.HasPrefix(lines[2], "Small") {
+		t.Errorf("Expected a table of 2 results, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := (ResultEncoder{Format: FormatJSON}).Encode(&buf, nil); err != nil || strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("Expected an empty JSON array, got %q, %v", buf.String(), err)
+	}
+
+	buf.Reset()
+	if err := (ResultEncoder{Format: FormatNDJSON}).Encode(&buf, encodeResults); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var decoded SearchResult
+	if len(lines) != 2 || json.Unmarshal([]byte(lines[1]), &decoded) != nil || decoded.MagnetURI != encodeResults[1].MagnetURI {
+		t.Errorf("Expected one JSON object per line, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := (ResultEncoder{Format: FormatCSV}).Encode(&buf, encodeResults); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(records) != 3 {
+		t.Fatalf("Expected a header and 2 records, got %v, %v", records, err)
+	}
+	if records[1][0] != encodeResults[0].Title || records[1][2] != "900" || records[1][6] != "2000;2040" {
+		t.Errorf("Unexpected record %q", records[1])
+	}
+
+	buf.Reset()
+	enc := ResultEncoder{Format: FormatTemplate, Template: "{{.Seeders}} {{.Title}}"}
+	if err := enc.Encode(&buf, encodeResults); err != nil || buf.String() != "9 Big, \"quoted\"\n3 Small\n" {
+		t.Errorf("Expected one line per result, got %q, %v", buf.String(), err)
+	}
+}
+
+func TestResultEncoderErrors(t *testing.T) {
+	if err := (ResultEncoder{Format: "xml"}).Encode(&bytes.Buffer{}, encodeResults); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+	if err := (ResultEncoder{Format: FormatTemplate, Template: "{{.Title"}).Encode(&bytes.Buffer{}, encodeResults); err == nil {
+		t.Error("Expected an invalid template error")
+	}
+	if err := (ResultEncoder{Format: FormatTemplate, Template: "{{.Nope}}"}).Encode(&bytes.Buffer{}, encodeResults); err == nil {
+		t.Error("Expected an unknown field error")
+	}
+
+	if f, err := ParseOutputFormat("CSV"); err != nil || f != FormatCSV {
+		t.Errorf("Expected FormatCSV, got %q, %v", f, err)
+	}
+	if _, err := ParseOutputFormat("yaml"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+}
+
+func TestResultEncoderColumns(t *testing.T) {
+	grabs := 42
+	results := []SearchResult{
+		{Title: "A | B\nC", Size: 1, Grabs: &grabs, Languages: []string{"en", "fr"}, Gain: 1.5},
+		{Title: "D"},
+	}
+	columns := []string{"title", "Grabs", "languages", "gain"}
+
+	var buf bytes.Buffer
+	if err := (ResultEncoder{Format: FormatMarkdown, Columns: columns}).Encode(&buf, results); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := "| title | grabs | languages | gain |\n" +
+		"| --- | --- | --- | --- |\n" +
+		"| A \\| B C | 42 | en;fr | 1.5 |\n" +
+		"| D |  |  | 0 |\n"
+	if buf.String() != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, buf.String())
+	}
+
+	buf.Reset()
+	if err := (ResultEncoder{Format: FormatNDJSON, Columns: columns}).Encode(&buf, results); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || lines[0] != `{"gain":1.5,"grabs":42,"languages":["en","fr"],"title":"A | B\nC"}` || lines[1] != `{"gain":0,"grabs":null,"languages":null,"title":"D"}` {
+		t.Errorf("Expected objects of the selected columns, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := (ResultEncoder{Columns: []string{"seeders", "title"}}).Encode(&buf, results); err != nil || !strings.HasPrefix(buf.String(), "SEEDERS  TITLE") {
+		t.Errorf("Expected a table of the selected columns, got %q, %v", buf.String(), err)
+	}
+
+	if err := (ResultEncoder{Format: FormatCSV, Columns: []string{"title", "color"}}).Encode(&buf, results); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("Expected ErrUnknownColumn, got %v", err)
+	}
+}
+
+func TestResultColumnsCoverSearchResult(t *testing.T) {
+	if n := reflect.TypeOf(SearchResult{}).NumField(); len(ResultColumns()) != n {
+		t.Errorf("Expected a column for each of the %d SearchResult fields, got %d", n, len(ResultColumns()))
+	}
+}
@@ -0,0 +1,140 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrMetadataNotFound is returned by a MetadataProvider that has no record of a title
+var ErrMetadataNotFound = errors.New("metadata not found")
+
+// Metadata describes a title as known by an external metadata provider
+type Metadata struct {
+	Title  string   `json:"title"`
+	Year   int      `json:"year,omitempty"`
+	IMDbID string   `json:"imdb_id,omitempty"`
+	TMDbID int      `json:"tmdb_id,omitempty"`
+	TVDBID int      `json:"tvdb_id,omitempty"`
+	Genres []string `json:"genres,omitempty"`
+	Poster string   `json:"poster,omitempty"`
+}
+
+// MetadataProvider looks up title metadata at an external service such as
+// TMDb or OMDb. Implementations return ErrMetadataNotFound for unknown titles.
+type MetadataProvider interface {
+	LookupTitle(ctx context.Context, title string, year int) (*Metadata, error)
+}
+
+// CachedProviderConfig configures a CachedProvider
+type CachedProviderConfig struct {
+	// TTL is how long successful lookups are cached
+	TTL time.Duration
+	// NegativeTTL is how long "not found" answers are cached
+	NegativeTTL time.Duration
+	// SoftFail treats provider errors as "not found" (cached for NegativeTTL)
+	// instead of returning them, so a flaky provider never fails a search
+	SoftFail bool
+	// Clock defaults to the system clock
+	Clock Clock
+	// IDMap, if set, learns the identifiers of every successful lookup
+	IDMap *IDMap
+}
+
+// ProviderStats counts how a CachedProvider answered lookups
+type ProviderStats struct {
+	Hits         int64 `json:"hits"`
+	NegativeHits int64 `json:"negative_hits"`
+	Misses       int64 `json:"misses"`
+	Errors       int64 `json:"errors"`
+}
+
+// CachedProvider wraps a MetadataProvider with positive and negative caching
+type CachedProvider struct {
+	provider MetadataProvider
+	config   CachedProviderConfig
+
+	mu      sync.Mutex
+	entries map[string]cachedMetadata
+	stats   ProviderStats
+}
+
+type cachedMetadata struct {
+	metadata *Metadata // nil for a negative entry
+	expires  time.Time
+}
+
+// NewCachedProvider wraps provider with the caching behavior in config
+func NewCachedProvider(provider MetadataProvider, config CachedProviderConfig) *CachedProvider {
+	if config.Clock == nil {
+		config.Clock = systemClock{}
+	}
+	return &CachedProvider{
+		provider: provider,
+		config:   config,
+		entries:  make(map[string]cachedMetadata),
+	}
+}
+
+// LookupTitle answers from the cache when possible, otherwise asks the
+// wrapped provider and caches the outcome
+func (p *CachedProvider) LookupTitle(ctx context.Context, title string, year int) (*Metadata, error) {
+	key := metadataKey(title, year)
+	now := p.config.Clock.Now()
+
+	p.mu.Lock()
+	if entry, ok := p.entries[key]; ok && now.Before(entry.expires) {
+		if entry.metadata == nil {
+			p.stats.NegativeHits++
+			p.mu.Unlock()
+			return nil, ErrMetadataNotFound
+		}
+		p.stats.Hits++
+		p.mu.Unlock()
+		return entry.metadata, nil
+	}
+	p.stats.Misses++
+	p.mu.Unlock()
+
+	metadata, err := p.provider.LookupTitle(ctx, title, year)
+	switch {
+	case err == nil:
+		if p.config.IDMap != nil {
+			p.config.IDMap.AddMetadata(metadata)
+		}
+		p.store(key, metadata, now.Add(p.config.TTL))
+		return metadata, nil
+	case errors.Is(err, ErrMetadataNotFound):
+		p.store(key, nil, now.Add(p.config.NegativeTTL))
+		return nil, err
+	default:
+		p.mu.Lock()
+		p.stats.Errors++
+		p.mu.Unlock()
+		if !p.config.SoftFail {
+			return nil, err
+		}
+		p.store(key, nil, now.Add(p.config.NegativeTTL))
+		return nil, ErrMetadataNotFound
+	}
+}
+
+// Stats returns a snapshot of the lookup counters
+func (p *CachedProvider) Stats() ProviderStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stats
+}
+
+func (p *CachedProvider) store(key string, metadata *Metadata, expires time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.entries[key] = cachedMetadata{metadata: metadata, expires: expires}
+}
+
+func metadataKey(title string, year int) string {
+	return strings.ToLower(strings.TrimSpace(title)) + "|" + strconv.Itoa(year)
+}
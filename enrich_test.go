@@ -0,0 +1,84 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// stepClock is a minimal manually advanced Clock for tests in this package
+type stepClock struct{ now time.Time }
+
+func (s *stepClock) Now() time.Time                         { return s.now }
+func (s *stepClock) After(d time.Duration) <-chan time.Time { return make(chan time.Time) }
+func (s *stepClock) NewTicker(d time.Duration) Ticker       { return systemTicker{time.NewTicker(d)} }
+
+type fakeProvider struct {
+	calls int
+	known map[string]*Metadata
+	err   error
+}
+
+func (f *fakeProvider) LookupTitle(ctx context.Context, title string, year int) (*Metadata, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	if m, ok := f.known[title]; ok {
+		return m, nil
+	}
+	return nil, ErrMetadataNotFound
+}
+
+func TestCachedProvider_NegativeCache(t *testing.T) {
+	clock := &stepClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	provider := &fakeProvider{known: map[string]*Metadata{"The Matrix": {Title: "The Matrix", Year: 1999}}}
+	cached := NewCachedProvider(provider, CachedProviderConfig{TTL: time.Hour, NegativeTTL: 10 * time.Minute, Clock: clock})
+
+	for i := 0; i < 3; i++ {
+		if _, err := cached.LookupTitle(context.Background(), "Unknown Film", 2020); !errors.Is(err, ErrMetadataNotFound) {
+			t.Fatalf("Expected ErrMetadataNotFound, got %v", err)
+		}
+	}
+	if provider.calls != 1 {
+		t.Errorf("Expected 1 provider call, got %d", provider.calls)
+	}
+
+	clock.now = clock.now.Add(11 * time.Minute)
+	cached.LookupTitle(context.Background(), "Unknown Film", 2020)
+	if provider.calls != 2 {
+		t.Errorf("Expected negative entry to expire, got %d provider calls", provider.calls)
+	}
+
+	m, err := cached.LookupTitle(context.Background(), "The Matrix", 1999)
+	if err != nil || m.Year != 1999 {
+		t.Fatalf("Expected metadata for The Matrix, got %v, %v", m, err)
+	}
+
+	stats := cached.Stats()
+	if stats.NegativeHits != 2 || stats.Misses != 3 {
+		t.Errorf("Expected 2 negative hits and 3 misses, got %+v", stats)
+	}
+}
+
+func TestCachedProvider_SoftFail(t *testing.T) {
+	provider := &fakeProvider{err: errors.New("rate limited")}
+
+	strict := NewCachedProvider(provider, CachedProviderConfig{NegativeTTL: time.Minute})
+	if _, err := strict.LookupTitle(context.Background(), "Film", 0); err == nil || errors.Is(err, ErrMetadataNotFound) {
+		t.Errorf("Expected provider error without soft-fail, got %v", err)
+	}
+
+	soft := NewCachedProvider(provider, CachedProviderConfig{NegativeTTL: time.Minute, SoftFail: true})
+	for i := 0; i < 2; i++ {
+		if _, err := soft.LookupTitle(context.Background(), "Film", 0); !errors.Is(err, ErrMetadataNotFound) {
+			t.Errorf("Expected ErrMetadataNotFound in soft-fail mode, got %v", err)
+		}
+	}
+
+	stats := soft.Stats()
+	if stats.Errors != 1 || stats.NegativeHits != 1 {
+		t.Errorf("Expected 1 error and 1 negative hit, got %+v", stats)
+	}
+}
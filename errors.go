@@ -0,0 +1,156 @@
+package jackett
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sentinel errors for the failure modes callers commonly branch on. Errors
+// returned by the client wrap them, so test with errors.Is:
+//
+//	if errors.Is(err, jackett.ErrRateLimited) { ... }
+var (
+	// ErrInvalidAPIKey means Jackett rejected the API key or admin session
+	ErrInvalidAPIKey = errors.New("jackett: invalid API key")
+	// ErrIndexerNotFound means the indexer doesn't exist or isn't configured
+	ErrIndexerNotFound = errors.New("jackett: indexer not found")
+	// ErrRateLimited means Jackett or a tracker refused the request for
+	// exceeding a request or download limit
+	ErrRateLimited = errors.New("jackett: rate limited")
+	// ErrServerUnavailable means Jackett couldn't be reached or reported
+	// itself temporarily unavailable
+	ErrServerUnavailable = errors.New("jackett: server unavailable")
+	// ErrAlreadyDownloaded means the release was downloaded before, as
+	// recorded by the store given to WithDownloadSeenStore
+	ErrAlreadyDownloaded = errors.New("jackett: release already downloaded")
+	// ErrInvalidBaseURL means the base URL given to NewClient is malformed
+	ErrInvalidBaseURL = errors.New("jackett: invalid base URL")
+)
+
+// TorznabError is an error reported by a torznab endpoint as an <error>
+// payload. See the torznab specification for the meaning of the codes.
+type TorznabError struct {
+	Code        int
+	Description string
+}
+
+func (e *TorznabError) Error() string {
+	return fmt.Sprintf("torznab error %d: %s", e.Code, e.Description)
+}
+
+// Is maps torznab error codes to the package's sentinel errors
+func (e *TorznabError) Is(target error) bool {
+	switch target {
+	case ErrInvalidAPIKey:
+		// Incorrect user credentials, account suspended, insufficient
+		// privileges
+		return e.Code >= 100 && e.Code <= 102
+	case ErrRateLimited:
+		// Request limit reached, download limit reached
+		return e.Code == 500 || e.Code == 501
+	case ErrIndexerNotFound:
+		return e.Code == 201 && strings.Contains(strings.ToLower(e.Description), "indexer")
+	}
+	return false
+}
+
+// newTorznabError converts a torznab error payload into a TorznabError
+func newTorznabError(body torznabErrorBody) *TorznabError {
+	code, _ := strconv.Atoi(strings.TrimSpace(body.Code))
+	return &TorznabError{Code: code, Description: body.Description}
+}
+
+// torznabErrorPeek is how much of a response is examined for a torznab error
+const torznabErrorPeek = 512
+
+// parseTorznabError returns the TorznabError in data, if data is a torznab
+// error payload
+func parseTorznabError(data []byte) (*TorznabError, bool) {
+	if !looksLikeTorznabError(data) {
+		return nil, false
+	}
+	var body torznabErrorBody
+	if xml.Unmarshal(data, &body) != nil {
+		return nil, false
+	}
+	return newTorznabError(body), true
+}
+
+// looksLikeTorznabError reports whether prefix, the start of a response,
+// opens an XML <error> element
+func looksLikeTorznabError(prefix []byte) bool {
+	rest := bytes.TrimLeft(bytes.TrimPrefix(prefix, []byte("\xef\xbb\xbf")), " \t\r\n")
+	for bytes.HasPrefix(rest, []byte("<?")) {
+		end := bytes.Index(rest, []byte("?>"))
+		if end < 0 {
+			return false
+		}
+		rest = bytes.TrimLeft(rest[end+2:], " \t\r\n")
+	}
+	if !bytes.HasPrefix(rest, []byte("<error")) || len(rest) == len("<error") {
+		return false
+	}
+	switch rest[len("<error")] {
+	case ' ', '\t', '\r', '\n', '/', '>':
+		return true
+	}
+	return false
+}
+
+// inspectTorznabError checks a successful response body for a torznab error
+// payload, which Jackett sends with a 200 status. If there is none, the
+// returned reader yields the complete body.
+func inspectTorznabError(body io.Reader) (io.Reader, error) {
+	br := bufio.NewReaderSize(body, torznabErrorPeek)
+	prefix, _ := br.Peek(torznabErrorPeek)
+	if !looksLikeTorznabError(prefix) {
+		return br, nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(br, maxErrorBodySize))
+	if err != nil {
+		return nil, err
+	}
+	if torznabErr, ok := parseTorznabError(data); ok {
+		return nil, torznabErr
+	}
+	return io.MultiReader(bytes.NewReader(data), br), nil
+}
+
+// statusError reports a non-2xx response from Jackett
+type statusError struct {
+	code int
+	body string
+	// path is the request path, used to tell a missing indexer from any
+	// other missing resource
+	path string
+	// retryAfter is the delay requested by a Retry-After header, if any
+	retryAfter time.Duration
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected response code: %d, response: %s", e.code, e.body)
+}
+
+// Is maps HTTP status codes to the package's sentinel errors
+func (e *statusError) Is(target error) bool {
+	switch target {
+	case ErrInvalidAPIKey:
+		return e.code == http.StatusUnauthorized
+	case ErrIndexerNotFound:
+		return e.code == http.StatusNotFound && strings.Contains(e.path, "/indexers/")
+	case ErrRateLimited:
+		return e.code == http.StatusTooManyRequests
+	case ErrServerUnavailable:
+		return e.code == http.StatusBadGateway || e.code == http.StatusServiceUnavailable || e.code == http.StatusGatewayTimeout
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSentinelErrorsFromStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		call   func(*Client) error
+		want   error
+	}{
+		{http.StatusUnauthorized, func(c *Client) error { _, err := c.GetIndexers(); return err }, ErrInvalidAPIKey},
+		{http.StatusNotFound, func(c *Client) error { _, err := c.SearchWithIndexer("missing", "q"); return err }, ErrIndexerNotFound},
+		{http.StatusNotFound, func(c *Client) error { _, err := c.GetIndexerConfig("missing"); return err }, ErrIndexerNotFound},
+		{http.StatusTooManyRequests, func(c *Client) error { _, err := c.Search("q"); return err }, ErrRateLimited},
+		{http.StatusServiceUnavailable, func(c *Client) error { _, err := c.GetServerConfig(); return err }, ErrServerUnavailable},
+		{http.StatusBadGateway, func(c *Client) error { return c.DeleteIndexer("x") }, ErrServerUnavailable},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "failure", tt.status)
+		}))
+		client, err := NewClient(server.URL, "test-api-key")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		err = tt.call(client)
+		server.Close()
+		if !errors.Is(err, tt.want) {
+			t.Errorf("Status %d: expected %v, got %v", tt.status, tt.want, err)
+		}
+	}
+}
+
+func TestSentinelErrorsAreDistinct(t *testing.T) {
+	err := &statusError{code: http.StatusNotFound, path: "/api/v2.0/server/config"}
+	for _, sentinel := range []error{ErrInvalidAPIKey, ErrIndexerNotFound, ErrRateLimited, ErrServerUnavailable} {
+		if errors.Is(err, sentinel) {
+			t.Errorf("Expected a 404 outside /indexers/ not to match %v", sentinel)
+		}
+	}
+}
+
+func TestServerUnavailableOnConnectionFailure(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	client, err := NewClient(url, "test-api-key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.GetIndexers(); !errors.Is(err, ErrServerUnavailable) {
+		t.Errorf("Expected ErrServerUnavailable, got %v", err)
+	}
+}
+
+func TestTorznabErrorFromSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><error code="100" description="Invalid API Key" />`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "bad-key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	_, err = client.TorznabSearch(context.Background(), "test-indexer", nil)
+	var torznabErr *TorznabError
+	if !errors.As(err, &torznabErr) {
+		t.Fatalf("Expected a TorznabError, got %v", err)
+	}
+	if torznabErr.Code != 100 || torznabErr.Description != "Invalid API Key" {
+		t.Errorf("Expected code 100 Invalid API Key, got %d %s", torznabErr.Code, torznabErr.Description)
+	}
+	if !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected the error to match ErrInvalidAPIKey, got %v", err)
+	}
+}
+
+func TestTorznabErrorIs(t *testing.T) {
+	tests := []struct {
+		err  *TorznabError
+		want error
+	}{
+		{&TorznabError{Code: 101, Description: "Account suspended"}, ErrInvalidAPIKey},
+		{&TorznabError{Code: 500, Description: "Request limit reached"}, ErrRateLimited},
+		{&TorznabError{Code: 501, Description: "Download limit reached"}, ErrRateLimited},
+		{&TorznabError{Code: 201, Description: "Indexer is not configured"}, ErrIndexerNotFound},
+	}
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.want) {
+			t.Errorf("Expected %v to match %v", tt.err, tt.want)
+		}
+	}
+
+	if errors.Is(&TorznabError{Code: 201, Description: "Incorrect parameter"}, ErrIndexerNotFound) {
+		t.Error("Expected an unrelated incorrect parameter error not to match ErrIndexerNotFound")
+	}
+}
+
+func TestTorznabErrorOnEveryEndpoint(t *testing.T) {
+	const invalidKey = `<?xml version="1.0" encoding="UTF-8"?>
+<error code="100" description="Invalid API Key" />`
+
+	for _, status := range []int{http.StatusOK, http.StatusBadRequest} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(status)
+			w.Write([]byte(invalidKey))
+		}))
+		client, err := NewClient(server.URL, "bad-key")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		calls := map[string]func() error{
+			"GetIndexers":     func() error { _, err := client.GetIndexers(); return err },
+			"Search":          func() error { _, err := client.Search("q"); return err },
+			"GetServerConfig": func() error { _, err := client.GetServerConfig(); return err },
+			"DownloadTorrent": func() error { _, err := client.DownloadTorrent(server.URL + "/dl/x/"); return err },
+		}
+		for name, call := range calls {
+			err := call()
+			var torznabErr *TorznabError
+			if !errors.As(err, &torznabErr) || torznabErr.Code != 100 {
+				if name == "DownloadTorrent" && status == http.StatusOK {
+					// A 200 download is a torrent file, whatever it contains
+					continue
+				}
+				t.Errorf("%s with status %d: expected TorznabError 100, got %v", name, status, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidAPIKey) {
+				t.Errorf("%s with status %d: expected ErrInvalidAPIKey, got %v", name, status, err)
+			}
+		}
+		server.Close()
+	}
+}
+
+func TestLooksLikeTorznabError(t *testing.T) {
+	tests := map[string]bool{
+		`<error code="100" description="x"/>`:                                   true,
+		"\xef\xbb\xbf\n  <?xml version=\"1.0\"?>\n<error code=\"100\" />":       true,
+		`<?xml version="1.0"?><?xml-stylesheet href="s.xsl"?><error code="1"/>`: true,
+		`<errors><error code="1"/></errors>`:                                    false,
+		`<rss><channel></channel></rss>`:                                        false,
+		`{"Results": []}`:                                                       false,
+		`<?xml version="1.0"`:                                                   false,
+	}
+	for input, want := range tests {
+		if got := looksLikeTorznabError([]byte(input)); got != want {
+			t.Errorf("looksLikeTorznabError(%q): expected %v, got %v", input, want, got)
+		}
+	}
+}
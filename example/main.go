@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
 
@@ -48,6 +49,11 @@ func main() {
 	if len(indexers) > 0 {
 		fmt.Println("\nSearching for 'The Matrix 1999'...")
 		results, err := client.Search("The Matrix 1999")
+		var partial *jackett.PartialError
+		if errors.As(err, &partial) {
+			log.Printf("Warning: %v", err)
+			err = nil
+		}
 		if err != nil {
 			log.Printf("Warning: Search failed: %v", err)
 		} else {
@@ -63,7 +69,7 @@ func main() {
 					i+1,
 					result.Title,
 					result.Seeders,
-					formatSize(result.Size))
+					jackett.FormatSize(result.Size))
 				// Show additional fields if present
 				if result.Tracker != "" {
 					fmt.Printf("      Tracker: %s\n", result.Tracker)
@@ -91,17 +97,3 @@ func main() {
 
 	fmt.Println("\nExample completed successfully!")
 }
-
-// formatSize converts bytes to human readable format
-func formatSize(bytes int64) string {
-	const unit = 1024
-	if bytes < unit {
-		return fmt.Sprintf("%d B", bytes)
-	}
-	div, exp := int64(unit), 0
-	for n := bytes / unit; n >= unit; n /= unit {
-		div *= unit
-		exp++
-	}
-	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
-}
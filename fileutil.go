@@ -0,0 +1,74 @@
+package jackett
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// link is os.Link, replaced by tests simulating file systems without hard
+// links
+var link = os.Link
+
+// writeFileAtomic replaces path with data so readers never observe a partially
+// written file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// writeFileExclusive writes data to path, which must not exist yet. Like
+// writeFileAtomic, readers never observe a partially written file, though on
+// file systems without hard links (such as FAT or some network shares) they
+// may briefly see an empty one. If path already exists the error satisfies
+// errors.Is(err, fs.ErrExist).
+func writeFileExclusive(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	// Unlike rename, link fails rather than replacing an existing file
+	err = link(tmp.Name(), path)
+	if !errors.Is(err, syscall.EPERM) && !errors.Is(err, syscall.ENOTSUP) && !errors.Is(err, errors.ErrUnsupported) {
+		return err
+	}
+
+	// Without hard links, claim the name with an empty file, which fails if
+	// it exists, and replace that
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
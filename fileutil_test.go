@@ -0,0 +1,36 @@
+package jackett
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestWriteFileExclusiveWithoutHardLinks(t *testing.T) {
+	defer func(orig func(string, string) error) { link = orig }(link)
+	link = func(oldname, newname string) error {
+		return &os.LinkError{Op: "link", Old: oldname, New: newname, Err: syscall.EPERM}
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.torrent")
+	if err := writeFileExclusive(path, []byte("data"), 0644); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "data" {
+		t.Errorf("Expected the file to be written, got %q", data)
+	}
+
+	if err := writeFileExclusive(path, []byte("other"), 0644); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("Expected fs.ErrExist, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "data" {
+		t.Errorf("Expected the existing file to be kept, got %q", data)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected no temporary files left behind, got %v", entries)
+	}
+}
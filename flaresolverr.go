@@ -0,0 +1,88 @@
+package jackett
+
+import (
+	"context"
+	"time"
+)
+
+// Server config keys holding the FlareSolverr settings
+const (
+	flareSolverrURLKey        = "flaresolverrurl"
+	flareSolverrMaxTimeoutKey = "flaresolverr_maxtimeout"
+)
+
+// FlareSolverrConfig holds Jackett's FlareSolverr settings. An empty URL means
+// FlareSolverr is disabled.
+type FlareSolverrConfig struct {
+	URL        string        `json:"url"`
+	MaxTimeout time.Duration `json:"max_timeout"`
+}
+
+// GetFlareSolverrConfig retrieves the FlareSolverr settings from the server config
+func (c *Client) GetFlareSolverrConfig() (*FlareSolverrConfig, error) {
+	return c.GetFlareSolverrConfigContext(context.Background())
+}
+
+// GetFlareSolverrConfigContext is like GetFlareSolverrConfig but honors ctx
+// for cancellation
+func (c *Client) GetFlareSolverrConfigContext(ctx context.Context) (*FlareSolverrConfig, error) {
+	config, err := c.GetServerConfigContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	flareSolverr := &FlareSolverrConfig{}
+	flareSolverr.URL, _ = config[flareSolverrURLKey].(string)
+	if ms, ok := config[flareSolverrMaxTimeoutKey].(float64); ok {
+		flareSolverr.MaxTimeout = time.Duration(ms) * time.Millisecond
+	}
+
+	return flareSolverr, nil
+}
+
+// SetFlareSolverr points Jackett at a FlareSolverr instance, leaving the rest
+// of the server config untouched. An empty flareSolverrURL disables
+// FlareSolverr; a zero maxTimeout keeps the current timeout.
+//
+// Jackett can only replace the whole config, so the config is read, changed
+// and written back. The update is not atomic: a change another writer makes
+// between the read and the write is lost.
+func (c *Client) SetFlareSolverr(flareSolverrURL string, maxTimeout time.Duration) error {
+	return c.SetFlareSolverrContext(context.Background(), flareSolverrURL, maxTimeout)
+}
+
+// SetFlareSolverrContext is like SetFlareSolverr but honors ctx for
+// cancellation
+func (c *Client) SetFlareSolverrContext(ctx context.Context, flareSolverrURL string, maxTimeout time.Duration) error {
+	// The metadata cache may be stale, and writing it back would undo newer
+	// changes
+	config, err := c.readServerConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := requireFeature(config, FeatureFlareSolverr); err != nil {
+		return err
+	}
+	if maxTimeout > 0 {
+		if err := requireFeature(config, FeatureFlareSolverrMaxTimeout); err != nil {
+			return err
+		}
+	}
+
+	config[flareSolverrURLKey] = flareSolverrURL
+	if maxTimeout > 0 {
+		config[flareSolverrMaxTimeoutKey] = maxTimeout.Milliseconds()
+	}
+	return c.SetServerConfigContext(ctx, config)
+}
+
+// readServerConfig retrieves and decodes the server config, bypassing the
+// cache
+func (c *Client) readServerConfig(ctx context.Context) (map[string]interface{}, error) {
+	respData, err := c.fetchServerConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return decodeServerConfig(respData)
+}
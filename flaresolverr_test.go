@@ -0,0 +1,104 @@
+package jackett
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetFlareSolverrConfig(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2.0/server/config": {statusCode: http.StatusOK, responseBody: `{"flaresolverrurl": "http://flaresolverr:8191", "flaresolverr_maxtimeout": 55000}`},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "GET", url: "/api/v2.0/server/config"},
+	}
+
+	client, _, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	config, err := client.GetFlareSolverrConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if config.URL != "http://flaresolverr:8191" {
+		t.Errorf("Expected URL 'http://flaresolverr:8191', got '%s'", config.URL)
+	}
+	if config.MaxTimeout != 55*time.Second {
+		t.Errorf("Expected max timeout 55s, got %v", config.MaxTimeout)
+	}
+}
+
+func TestSetFlareSolverr(t *testing.T) {
+	var posted map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2.0/server/config" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(`{"port": 9117, "flaresolverrurl": "", "flaresolverr_maxtimeout": 55000}`))
+		case "POST":
+			if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Expected JSON content type, got '%s'", ct)
+			}
+			if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+				t.Errorf("Failed to decode posted config: %v", err)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "test-api-key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := client.SetFlareSolverr("http://flaresolverr:8191", 2*time.Minute); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if posted["flaresolverrurl"] != "http://flaresolverr:8191" {
+		t.Errorf("Expected flaresolverrurl to be set, got %v", posted["flaresolverrurl"])
+	}
+	if posted["flaresolverr_maxtimeout"] != float64(120000) {
+		t.Errorf("Expected flaresolverr_maxtimeout 120000, got %v", posted["flaresolverr_maxtimeout"])
+	}
+	if posted["port"] != float64(9117) {
+		t.Errorf("Expected unrelated settings to be preserved, got port %v", posted["port"])
+	}
+}
+
+func TestSetFlareSolverrContext(t *testing.T) {
+	var posts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			posts++
+		}
+		w.Write([]byte(`{"flaresolverrurl": ""}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "test-api-key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.SetFlareSolverrContext(ctx, "http://flaresolverr:8191", 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if posts != 0 {
+		t.Errorf("Expected the config not to be written, got %d posts", posts)
+	}
+}
@@ -0,0 +1,89 @@
+package jackett
+
+import "sync"
+
+// flightGroup coalesces concurrent calls with the same key into one, in the
+// manner of golang.org/x/sync/singleflight
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	wg   sync.WaitGroup
+	val  interface{}
+	err  error
+	dups int
+}
+
+// WithRequestCoalescing makes identical concurrent searches, torznab
+// searches and indexer, capability and server config lookups share a single
+// upstream request, preventing bursts against rate-limited trackers when many
+// goroutines ask for the same thing at once. Coalesced callers all receive the
+// outcome of the first caller's request, including an error caused by that
+// caller's context being cancelled.
+func WithRequestCoalescing() Option {
+	return func(c *Client) {
+		c.flights = &flightGroup{calls: make(map[string]*flightCall)}
+	}
+}
+
+// do calls fn, unless a call with the same key is already in flight, in which
+// case it waits for that call and returns its result. shared reports whether
+// the result went to more than one caller.
+func (g *flightGroup) do(key string, fn func() (interface{}, error)) (val interface{}, err error, shared bool) {
+	if g == nil {
+		val, err = fn()
+		return val, err, false
+	}
+
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		call.dups++
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.val, call.err, true
+	}
+	call := &flightCall{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.val, call.err = fn()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	shared = call.dups > 0
+	g.mu.Unlock()
+	call.wg.Done()
+
+	return call.val, call.err, shared
+}
+
+// coalesce runs fetch through the client's flight group. The returned bytes
+// may be shared with other callers and must not be modified.
+func (c *Client) coalesce(key string, fetch func() ([]byte, error)) ([]byte, error) {
+	val, err, _ := c.flights.do(key, func() (interface{}, error) {
+		return fetch()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val.([]byte), nil
+}
+
+// coalesceSearch runs search through the client's flight group, giving each
+// caller that shared the result its own copy
+func (c *Client) coalesceSearch(key string, search func() (*SearchResponse, error)) (*SearchResponse, error) {
+	val, err, shared := c.flights.do(key, func() (interface{}, error) {
+		return search()
+	})
+	if err != nil {
+		return nil, err
+	}
+	response := val.(*SearchResponse)
+	if shared {
+		response = cloneSearchResponse(response)
+	}
+	return response, nil
+}
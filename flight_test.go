@@ -0,0 +1,139 @@
+package jackett
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForDups blocks until n callers are waiting on the call for key
+func waitForDups(t *testing.T, g *flightGroup, key string, n int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		call, ok := g.calls[key]
+		dups := 0
+		if ok {
+			dups = call.dups
+		}
+		g.mu.Unlock()
+		if dups == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %d coalesced callers", n)
+}
+
+func TestRequestCoalescingSearch(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		w.Write([]byte(hookSearchJSON))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithOptions(server.URL, "test-api-key", WithRequestCoalescing())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	const callers = 5
+	responses := make([]*SearchResponse, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i], errs[i] = client.Search("ubuntu")
+		}(i)
+	}
+	waitForDups(t, client.flights, searchCacheKey("all", "ubuntu"), callers-1)
+	close(release)
+	wg.Wait()
+
+	if requests != 1 {
+		t.Errorf("Expected 1 upstream request, got %d", requests)
+	}
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil {
+			t.Fatalf("Expected no error, got %v", errs[i])
+		}
+		if len(responses[i].Results) != 3 {
+			t.Errorf("Expected 3 results, got %d", len(responses[i].Results))
+		}
+	}
+	if &responses[0].Results[0] == &responses[1].Results[0] {
+		t.Error("Expected coalesced callers to receive independent copies")
+	}
+
+	// Once the call completes, a new search goes upstream again
+	if _, err := client.Search("ubuntu"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 upstream requests, got %d", requests)
+	}
+}
+
+func TestRequestCoalescingGetIndexers(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		w.Write([]byte(allIndexersXML))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithOptions(server.URL, "test-api-key", WithRequestCoalescing())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	const callers = 3
+	var failures int32
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if indexers, err := client.GetIndexers(); err != nil || len(indexers) != 2 {
+				atomic.AddInt32(&failures, 1)
+			}
+		}()
+	}
+	waitForDups(t, client.flights, "indexers", callers-1)
+	close(release)
+	wg.Wait()
+
+	if failures != 0 {
+		t.Errorf("Expected every caller to get 2 indexers, %d did not", failures)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 upstream request, got %d", requests)
+	}
+}
+
+func TestFlightGroupNilRunsEveryCall(t *testing.T) {
+	var g *flightGroup
+	calls := 0
+	for i := 0; i < 2; i++ {
+		_, _, shared := g.do("key", func() (interface{}, error) {
+			calls++
+			return nil, nil
+		})
+		if shared {
+			t.Error("Expected no sharing without a flight group")
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+}
@@ -0,0 +1,92 @@
+package jackett
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// headersKey is the context key of headers set by ContextWithHeaders
+type headersKey struct{}
+
+// WithHeaders adds headers to every request sent to the Jackett instance,
+// e.g. credentials required by a reverse proxy in front of it. They are not
+// sent with downloads from other hosts, nor kept when Jackett redirects a
+// download to one. Values replace any the client would
+// otherwise send under the same name.
+func WithHeaders(header http.Header) Option {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = http.Header{}
+		}
+		for name, values := range header {
+			c.headers[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+		}
+	}
+}
+
+// ContextWithHeaders returns a copy of ctx that makes the client add header
+// to the requests of calls using it, overriding headers set by WithHeaders:
+//
+//	ctx := jackett.ContextWithHeaders(ctx, http.Header{"X-Request-Id": {id}})
+//	indexers, err := client.GetIndexersContext(ctx)
+func ContextWithHeaders(ctx context.Context, header http.Header) context.Context {
+	merged := http.Header{}
+	if outer, ok := ctx.Value(headersKey{}).(http.Header); ok {
+		for name, values := range outer {
+			merged[name] = values
+		}
+	}
+	for name, values := range header {
+		merged[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+	}
+	return context.WithValue(ctx, headersKey{}, merged)
+}
+
+// WithBasicAuth sends HTTP basic auth credentials with every request to the
+// Jackett instance, for instances behind an authenticating reverse proxy
+// such as nginx or Authelia. They override credentials embedded in the base
+// URL, and an Authorization header set by WithHeaders overrides them.
+func WithBasicAuth(username, password string) Option {
+	return func(c *Client) {
+		c.basicAuth = url.UserPassword(username, password)
+	}
+}
+
+// setHeaders applies the basic auth, WithHeaders and ContextWithHeaders
+// settings to a request for the Jackett instance
+func (c *Client) setHeaders(req *http.Request) {
+	if c.basicAuth != nil {
+		password, _ := c.basicAuth.Password()
+		req.SetBasicAuth(c.basicAuth.Username(), password)
+	}
+	for name, values := range c.headers {
+		req.Header[name] = append([]string(nil), values...)
+	}
+	if header, ok := req.Context().Value(headersKey{}).(http.Header); ok {
+		for name, values := range header {
+			req.Header[name] = append([]string(nil), values...)
+		}
+	}
+}
+
+// stripHeaders removes the basic auth, WithHeaders and ContextWithHeaders
+// settings from a redirected request that leaves the Jackett instance. Go
+// forwards custom headers across hosts, and the Authorization header to
+// other paths of the same host.
+func (c *Client) stripHeaders(req *http.Request) {
+	if c.isOwnURL(req.URL) {
+		return
+	}
+	if c.basicAuth != nil {
+		req.Header.Del("Authorization")
+	}
+	for name := range c.headers {
+		req.Header.Del(name)
+	}
+	if header, ok := req.Context().Value(headersKey{}).(http.Header); ok {
+		for name := range header {
+			req.Header.Del(name)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package jackett
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// History operations
+const (
+	HistorySearch  = "search"
+	HistoryTorznab = "torznab"
+	// HistoryChoice records the result a tool chose (see RecordChoice)
+	HistoryChoice = "choice"
+)
+
+// HistoryEntry is one recorded search, or the choice of a result. Unlike
+// AuditRecord it keeps the query in plain text, as it exists to answer
+// "why did my tool grab that release".
+type HistoryEntry struct {
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`
+	Indexer   string    `json:"indexer,omitempty"`
+	Query     string    `json:"query,omitempty"`
+	// Params are the torznab parameters of torznab searches, without the
+	// API key
+	Params   url.Values    `json:"params,omitempty"`
+	Results  int           `json:"results"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"`
+	// Chosen is the result of a HistoryChoice entry, with its credentials
+	// removed
+	Chosen *SearchResult `json:"chosen,omitempty"`
+}
+
+// HistoryFilter selects history entries. Zero fields match everything.
+type HistoryFilter struct {
+	Since time.Time
+	Until time.Time
+	// Operation matches exactly
+	Operation string
+	// Indexer matches exactly
+	Indexer string
+	// Query matches entries whose query contains it, ignoring case
+	Query string
+	// Limit keeps only the most recent matches
+	Limit int
+}
+
+// Match reports whether entry passes the filter, ignoring Limit
+func (f HistoryFilter) Match(entry HistoryEntry) bool {
+	switch {
+	case !f.Since.IsZero() && entry.Time.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !entry.Time.Before(f.Until):
+		return false
+	case f.Operation != "" && entry.Operation != f.Operation:
+		return false
+	case f.Indexer != "" && entry.Indexer != f.Indexer:
+		return false
+	case f.Query != "" && !strings.Contains(strings.ToLower(entry.Query), strings.ToLower(f.Query)):
+		return false
+	}
+	return true
+}
+
+// HistoryStore stores search history. Add may be called concurrently;
+// Query returns matching entries oldest first.
+type HistoryStore interface {
+	Add(HistoryEntry) error
+	Query(HistoryFilter) ([]HistoryEntry, error)
+}
+
+// WithHistory records every search, including those answered from the
+// search cache, to store. Failures to record are logged if WithLogger is in
+// effect but don't fail the search.
+func WithHistory(store HistoryStore) Option {
+	return func(c *Client) {
+		c.history = store
+	}
+}
+
+// History returns the recorded entries matching filter, oldest first
+func (c *Client) History(filter HistoryFilter) ([]HistoryEntry, error) {
+	if c.history == nil {
+		return nil, errors.New("history error: no history store configured")
+	}
+	return c.history.Query(filter)
+}
+
+// RecordChoice records that result was chosen from a search for query on
+// indexerID, completing the history of that search. Its links are stored
+// without API keys and tracker passkeys, as by SearchResult.Links. It does
+// nothing without WithHistory.
+func (c *Client) RecordChoice(indexerID, query string, result SearchResult) error {
+	if c.history == nil {
+		return nil
+	}
+	result = result.withoutCredentials()
+	return c.history.Add(HistoryEntry{
+		Time:      c.clock.Now(),
+		Operation: HistoryChoice,
+		Indexer:   indexerID,
+		Query:     query,
+		Results:   1,
+		Chosen:    &result,
+	})
+}
+
+// recordHistory adds a search that started at start to the history
+func (c *Client) recordHistory(ctx context.Context, operation, indexerID, query string, params url.Values, start time.Time, response *SearchResponse, err error) {
+	if c.history == nil {
+		return
+	}
+
+	entry := HistoryEntry{
+		Time:      start,
+		Operation: operation,
+		Indexer:   indexerID,
+		Query:     query,
+		Duration:  c.clock.Now().Sub(start),
+	}
+	if len(params) > 0 {
+		entry.Params = url.Values{}
+		for k, v := range params {
+			if !strings.EqualFold(k, "apikey") {
+				entry.Params[k] = v
+			}
+		}
+	}
+	if response != nil {
+		entry.Results = len(response.Results)
+	}
+	if err != nil {
+		entry.Error = c.redact(ctx, err.Error())
+	}
+
+	if err := c.history.Add(entry); err != nil && c.logger != nil {
+		c.logger.Warn("jackett history record failed", slog.Any("error", err))
+	}
+}
+
+// ExportHistory writes entries to w as JSON lines
+func ExportHistory(w io.Writer, entries []HistoryEntry) error {
+	enc := json.NewEncoder(w)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MemoryHistory is a HistoryStore keeping the most recent entries in memory
+type MemoryHistory struct {
+	mu      sync.Mutex
+	max     int
+	entries []HistoryEntry
+}
+
+// NewMemoryHistory returns a store keeping up to max entries (unbounded if
+// max < 1)
+func NewMemoryHistory(max int) *MemoryHistory {
+	return &MemoryHistory{max: max}
+}
+
+// Add appends entry, dropping the oldest entry if the store is full
+func (h *MemoryHistory) Add(entry HistoryEntry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries = append(h.entries, entry)
+	if h.max > 0 && len(h.entries) > h.max {
+		h.entries = append(h.entries[:0], h.entries[len(h.entries)-h.max:]...)
+	}
+	return nil
+}
+
+// Query returns the entries matching filter
+func (h *MemoryHistory) Query(filter HistoryFilter) ([]HistoryEntry, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return filterHistory(h.entries, filter), nil
+}
+
+// FileHistory is a HistoryStore appending entries to a file as JSON lines,
+// the format of ExportHistory. Queries read the whole file.
+type FileHistory struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileHistory returns a store at path; the file is created on first Add
+func NewFileHistory(path string) *FileHistory {
+	return &FileHistory{path: path}
+}
+
+// Add appends entry to the file
+func (h *FileHistory) Add(entry HistoryEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	f, err := os.OpenFile(h.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return f.Close()
+}
+
+// Query returns the entries matching filter. Lines that fail to decode,
+// such as one torn by a crash, are skipped.
+func (h *FileHistory) Query(filter HistoryFilter) ([]HistoryEntry, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	f, err := os.Open(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil && filter.Match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return limitHistory(entries, filter.Limit), nil
+}
+
+// filterHistory returns a copy of the entries matching filter
+func filterHistory(entries []HistoryEntry, filter HistoryFilter) []HistoryEntry {
+	var matched []HistoryEntry
+	for _, entry := range entries {
+		if filter.Match(entry) {
+			matched = append(matched, entry)
+		}
+	}
+	return limitHistory(matched, filter.Limit)
+}
+
+// limitHistory keeps the last limit entries
+func limitHistory(entries []HistoryEntry, limit int) []HistoryEntry {
+	if limit > 0 && len(entries) > limit {
+		return entries[len(entries)-limit:]
+	}
+	return entries
+}
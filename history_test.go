@@ -0,0 +1,149 @@
+package jackett
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2.0/indexers/all/results":
+			w.Write([]byte(hookSearchJSON))
+		case "/api/v2.0/indexers/all/results/torznab/api":
+			w.Write([]byte(torznabFeedXML))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	history := NewMemoryHistory(0)
+	client, err := NewClientWithOptions(server.URL, "test-api-key", WithHistory(history), WithSearchCache(time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response, _ := client.Search("ubuntu")
+	client.Search("ubuntu")
+	client.TorznabSearch(context.Background(), "all", url.Values{"t": {"tvsearch"}, "q": {"show"}, "season": {"1"}})
+	client.SearchWithIndexer("missing", "ubuntu")
+	if err := client.RecordChoice("all", "ubuntu", response.Results[1]); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	entries, err := client.History(HistoryFilter{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("Expected 5 entries including the cached search, got %+v", entries)
+	}
+	if e := entries[0]; e.Operation != HistorySearch || e.Query != "ubuntu" || e.Results != 3 || e.Error != "" {
+		t.Errorf("Unexpected search entry %+v", e)
+	}
+	if e := entries[2]; e.Operation != HistoryTorznab || e.Query != "show" || e.Params.Get("season") != "1" || e.Params.Has("apikey") || e.Results != 1 {
+		t.Errorf("Unexpected torznab entry %+v", e)
+	}
+	if e := entries[3]; e.Indexer != "missing" || e.Error == "" || strings.Contains(e.Error, "test-api-key") {
+		t.Errorf("Unexpected failed entry %+v", e)
+	}
+	if e := entries[4]; e.Operation != HistoryChoice || e.Chosen == nil || e.Chosen.Title != response.Results[1].Title {
+		t.Errorf("Unexpected choice entry %+v", e)
+	}
+
+	choices, _ := client.History(HistoryFilter{Query: "UBU", Operation: HistoryChoice})
+	if len(choices) != 1 {
+		t.Errorf("Expected 1 choice, got %+v", choices)
+	}
+	recent, _ := client.History(HistoryFilter{Limit: 2})
+	if len(recent) != 2 || recent[1].Operation != HistoryChoice {
+		t.Errorf("Expected the 2 most recent entries, got %+v", recent)
+	}
+
+	var buf bytes.Buffer
+	if err := ExportHistory(&buf, entries); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 5 || !json.Valid([]byte(lines[0])) {
+		t.Errorf("Expected 5 JSON lines, got %q", buf.String())
+	}
+}
+
+func TestMemoryHistoryLimit(t *testing.T) {
+	history := NewMemoryHistory(2)
+	for _, q := range []string{"a", "b", "c"} {
+		history.Add(HistoryEntry{Operation: HistorySearch, Query: q})
+	}
+	entries, _ := history.Query(HistoryFilter{})
+	if len(entries) != 2 || entries[0].Query != "b" || entries[1].Query != "c" {
+		t.Errorf("Expected the 2 newest entries, got %+v", entries)
+	}
+}
+
+func TestFileHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	history := NewFileHistory(path)
+	if entries, err := history.Query(HistoryFilter{}); err != nil || len(entries) != 0 {
+		t.Errorf("Expected an empty history, got %+v, %v", entries, err)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, q := range []string{"a", "b", "c"} {
+		history.Add(HistoryEntry{Time: start.Add(time.Duration(i) * time.Hour), Operation: HistorySearch, Query: q})
+	}
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	f.WriteString(`{"time":"2024-01-01T`)
+	f.Close()
+
+	entries, err := history.Query(HistoryFilter{Since: start.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(entries) != 2 || entries[0].Query != "b" || entries[1].Query != "c" {
+		t.Errorf("Unexpected entries %+v", entries)
+	}
+}
+
+func TestRecordChoiceStripsCredentials(t *testing.T) {
+	history := NewMemoryHistory(0)
+	client, err := NewClientWithOptions("http://localhost:9117", "test-api-key", WithHistory(history))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	blackhole := "http://localhost:9117/bh/a/?jackett_apikey=test-api-key&path=x"
+	result := SearchResult{
+		Title:         "Private",
+		Link:          "http://localhost:9117/dl/a/?jackett_apikey=test-api-key&path=x",
+		BlackholeLink: &blackhole,
+		MagnetURI:     "magnet:?xt=urn:btih:abc&tr=https%3A%2F%2Fprivate.example%2Fannounce%3Fpasskey%3Dfeedface1234",
+	}
+	if err := client.RecordChoice("all", "ubuntu", result); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	entries, _ := client.History(HistoryFilter{})
+	if len(entries) != 1 || entries[0].Chosen == nil {
+		t.Fatalf("Expected the choice, got %+v", entries)
+	}
+	data, _ := json.Marshal(entries[0].Chosen)
+	if strings.Contains(string(data), "test-api-key") || strings.Contains(string(data), "feedface1234") {
+		t.Errorf("Expected the chosen result's credentials to be removed, got %s", data)
+	}
+	if chosen := entries[0].Chosen; chosen.Link != "http://localhost:9117/dl/a/?path=x" || *chosen.BlackholeLink != "http://localhost:9117/bh/a/?path=x" {
+		t.Errorf("Expected the links to be kept without keys, got %q and %q", chosen.Link, *chosen.BlackholeLink)
+	}
+	if strings.Contains(*result.BlackholeLink, "path=x") && !strings.Contains(*result.BlackholeLink, "test-api-key") {
+		t.Error("Expected the caller's result to be left unchanged")
+	}
+}
@@ -0,0 +1,77 @@
+package jackett
+
+import (
+	"net/http"
+	"time"
+)
+
+// HookEvent describes a Jackett API call to the event hooks
+type HookEvent struct {
+	Method string
+	// Endpoint is the URL path, without the query and thus the API key
+	Endpoint string
+	// Status is the HTTP status code, or 0 if no response was received
+	Status int
+	// Duration is the time from sending the request until the response body
+	// was consumed; it is zero for OnRequest
+	Duration time.Duration
+	// Err is the error the call failed with, if any
+	Err error
+}
+
+// Hooks are callbacks invoked around every Jackett API call, for custom
+// telemetry without wrapping the HTTP client. Each field may be nil. Hooks
+// run synchronously on the calling goroutine, so they should be quick.
+type Hooks struct {
+	// OnRequest is called before a request is sent
+	OnRequest func(HookEvent)
+	// OnResponse is called once a response has been handled, whatever its
+	// status
+	OnResponse func(HookEvent)
+	// OnError is called when a call fails, whether or not a response was
+	// received. For an error response both OnResponse and OnError are
+	// called.
+	OnError func(HookEvent)
+}
+
+// WithHooks registers event hooks for every API call. Retried requests
+// trigger the hooks once per attempt. Hooks from several WithHooks options
+// are all called, in order.
+func WithHooks(hooks Hooks) Option {
+	return func(c *Client) {
+		c.hooks = append(c.hooks, hooks)
+	}
+}
+
+// hookList is the set of hooks registered on a client
+type hookList []Hooks
+
+// start runs OnRequest for req and returns a function that runs OnResponse
+// and OnError once the call has finished
+func (h hookList) start(clock Clock, req *http.Request) func(status int, err error) {
+	if len(h) == 0 {
+		return func(int, error) {}
+	}
+
+	event := HookEvent{Method: req.Method, Endpoint: req.URL.Path}
+	for _, hooks := range h {
+		if hooks.OnRequest != nil {
+			hooks.OnRequest(event)
+		}
+	}
+
+	start := clock.Now()
+	return func(status int, err error) {
+		event.Status = status
+		event.Duration = clock.Now().Sub(start)
+		event.Err = err
+		for _, hooks := range h {
+			if status != 0 && hooks.OnResponse != nil {
+				hooks.OnResponse(event)
+			}
+			if err != nil && hooks.OnError != nil {
+				hooks.OnError(event)
+			}
+		}
+	}
+}
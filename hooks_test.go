@@ -0,0 +1,76 @@
+package jackett
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithHooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2.0/server/config":
+			w.Write([]byte(`{"app_version": "0.22.0"}`))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	var requests, responses, failures []HookEvent
+	client, err := NewClientWithOptions(server.URL, "test-api-key", WithHooks(Hooks{
+		OnRequest:  func(e HookEvent) { requests = append(requests, e) },
+		OnResponse: func(e HookEvent) { responses = append(responses, e) },
+		OnError:    func(e HookEvent) { failures = append(failures, e) },
+	}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.GetServerConfig(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(requests) != 1 || len(responses) != 1 || len(failures) != 0 {
+		t.Fatalf("Expected 1 request and 1 response, got %d, %d and %d failures", len(requests), len(responses), len(failures))
+	}
+	if e := responses[0]; e.Method != "GET" || e.Endpoint != "/api/v2.0/server/config" || e.Status != http.StatusOK || e.Err != nil {
+		t.Errorf("Unexpected response event %+v", e)
+	}
+
+	if _, err := client.GetIndexers(); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if len(failures) != 1 || len(responses) != 2 {
+		t.Fatalf("Expected the failure to trigger OnResponse and OnError, got %d and %d", len(responses), len(failures))
+	}
+	if e := failures[0]; e.Status != http.StatusServiceUnavailable || !errors.Is(e.Err, ErrServerUnavailable) {
+		t.Errorf("Unexpected error event %+v", e)
+	}
+}
+
+func TestWithHooksNoResponse(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	var responses, failures int
+	client, err := NewClientWithOptions(server.URL, "test-api-key", WithHooks(Hooks{
+		OnResponse: func(HookEvent) { responses++ },
+		OnError: func(e HookEvent) {
+			failures++
+			if e.Status != 0 {
+				t.Errorf("Expected status 0, got %d", e.Status)
+			}
+		},
+	}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.GetServerConfig(); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if responses != 0 || failures != 1 {
+		t.Errorf("Expected only OnError, got %d responses and %d failures", responses, failures)
+	}
+}
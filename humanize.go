@@ -0,0 +1,77 @@
+package jackett
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidSize is returned by ParseSize for text that isn't a size
+var ErrInvalidSize = errors.New("jackett: invalid size")
+
+// sizeUnits are the unit prefixes of FormatSize and ParseSize, in powers of
+// 1024
+const sizeUnits = "KMGTPE"
+
+// FormatSize formats a byte count the way Jackett and most trackers display
+// it, in powers of 1024 with one decimal, e.g. "1.5 GB" or "700 B"
+func FormatSize(bytes int64) string {
+	if bytes < 0 {
+		// -bytes overflows for math.MinInt64, its magnitude as a uint64 doesn't
+		return "-" + formatSize(uint64(-(bytes+1))+1)
+	}
+	return formatSize(uint64(bytes))
+}
+
+// formatSize formats a non-negative byte count for FormatSize
+func formatSize(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), sizeUnits[exp])
+}
+
+// FormatRate formats a transfer rate in bytes per second, e.g. "2.5 MB/s"
+func FormatRate(bytesPerSecond float64) string {
+	return FormatSize(int64(math.Round(bytesPerSecond))) + "/s"
+}
+
+// ParseSize parses a size as written by FormatSize or typed by a user, e.g.
+// "1.5 GB", "700MiB", "4g" or "1048576". Units are case-insensitive and, like
+// FormatSize's, powers of 1024: "GB" and "GiB" are the same.
+func ParseSize(s string) (int64, error) {
+	text := strings.TrimSpace(s)
+	end := len(text)
+	for end > 0 && (text[end-1] < '0' || text[end-1] > '9') && text[end-1] != '.' {
+		end--
+	}
+	number, unit := strings.TrimSpace(text[:end]), strings.ToUpper(strings.TrimSpace(text[end:]))
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("%w %q", ErrInvalidSize, s)
+	}
+
+	multiplier := 1.0
+	switch unit = strings.TrimSuffix(strings.TrimSuffix(unit, "B"), "I"); {
+	case unit == "":
+	case len(unit) == 1 && strings.Contains(sizeUnits, unit):
+		multiplier = math.Pow(1024, float64(strings.Index(sizeUnits, unit)+1))
+	default:
+		return 0, fmt.Errorf("%w %q", ErrInvalidSize, s)
+	}
+
+	bytes := math.Round(value * multiplier)
+	if bytes >= math.MaxInt64 {
+		return 0, fmt.Errorf("%w %q: too large", ErrInvalidSize, s)
+	}
+	return int64(bytes), nil
+}
@@ -0,0 +1,69 @@
+package jackett
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KB"},
+		{1536, "1.5 KB"},
+		{700 << 20, "700.0 MB"},
+		{3 << 29, "1.5 GB"},
+		{1 << 60, "1.0 EB"},
+		{-2048, "-2.0 KB"},
+		{math.MaxInt64, "8.0 EB"},
+		{math.MinInt64, "-8.0 EB"},
+	}
+	for _, tt := range tests {
+		if got := FormatSize(tt.bytes); got != tt.want {
+			t.Errorf("FormatSize(%d) = %q, want %q", tt.bytes, got, tt.want)
+		}
+	}
+
+	if got := FormatRate(2.5 * (1 << 20)); got != "2.5 MB/s" {
+		t.Errorf("FormatRate = %q, want 2.5 MB/s", got)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		text string
+		want int64
+	}{
+		{"1048576", 1 << 20},
+		{"700 B", 700},
+		{"1.5 GB", 3 << 29},
+		{"1.5GB", 3 << 29},
+		{"700MiB", 700 << 20},
+		{"4g", 4 << 30},
+		{" 2 tb ", 2 << 40},
+		{".5k", 512},
+		{"1.0 EB", 1 << 60},
+	}
+	for _, tt := range tests {
+		got, err := ParseSize(tt.text)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, %v, want %d", tt.text, got, err, tt.want)
+		}
+	}
+
+	for _, bytes := range []int64{0, 512, 1536, 700 << 20, 3 << 29} {
+		if got, err := ParseSize(FormatSize(bytes)); err != nil || got != bytes {
+			t.Errorf("ParseSize(FormatSize(%d)) = %d, %v", bytes, got, err)
+		}
+	}
+
+	for _, text := range []string{"", "GB", "1.5 XB", "-1 GB", "1.5 GBs", "1..5", "9 EB"} {
+		if _, err := ParseSize(text); !errors.Is(err, ErrInvalidSize) {
+			t.Errorf("ParseSize(%q): expected ErrInvalidSize, got %v", text, err)
+		}
+	}
+}
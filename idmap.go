@@ -0,0 +1,209 @@
+package jackett
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MediaIDs holds the identifiers of one title across metadata databases.
+// Zero values mean unknown.
+type MediaIDs struct {
+	IMDb   string `json:"imdb,omitempty"` // "tt0133093"
+	TMDb   int    `json:"tmdb,omitempty"`
+	TVDB   int    `json:"tvdb,omitempty"`
+	TVMaze int    `json:"tvmaze,omitempty"`
+	Trakt  int    `json:"trakt,omitempty"`
+	Douban int    `json:"douban,omitempty"`
+}
+
+// IsZero reports whether no identifier is known
+func (ids MediaIDs) IsZero() bool {
+	return ids == MediaIDs{}
+}
+
+// keys returns the index keys of every known identifier
+func (ids MediaIDs) keys() []string {
+	var keys []string
+	if ids.IMDb != "" {
+		keys = append(keys, "imdb:"+ids.IMDb)
+	}
+	for _, k := range []struct {
+		prefix string
+		id     int
+	}{{"tmdb:", ids.TMDb}, {"tvdb:", ids.TVDB}, {"tvmaze:", ids.TVMaze}, {"trakt:", ids.Trakt}, {"douban:", ids.Douban}} {
+		if k.id != 0 {
+			keys = append(keys, k.prefix+strconv.Itoa(k.id))
+		}
+	}
+	return keys
+}
+
+// merge fills unknown identifiers in ids from other
+func (ids *MediaIDs) merge(other MediaIDs) {
+	if ids.IMDb == "" {
+		ids.IMDb = other.IMDb
+	}
+	if ids.TMDb == 0 {
+		ids.TMDb = other.TMDb
+	}
+	if ids.TVDB == 0 {
+		ids.TVDB = other.TVDB
+	}
+	if ids.TVMaze == 0 {
+		ids.TVMaze = other.TVMaze
+	}
+	if ids.Trakt == 0 {
+		ids.Trakt = other.Trakt
+	}
+	if ids.Douban == 0 {
+		ids.Douban = other.Douban
+	}
+}
+
+// ExternalIDs returns the metadata database identifiers a result carries,
+// with the IMDb ID in its usual "tt0133093" form rather than Jackett's
+// number. Missing identifiers are zero.
+func (r SearchResult) ExternalIDs() MediaIDs {
+	ids := MediaIDs{IMDb: formatIMDb(r.Imdb)}
+	for _, id := range []struct {
+		dst *int
+		src *int
+	}{{&ids.TMDb, r.TMDb}, {&ids.TVDB, r.TVDBId}, {&ids.TVMaze, r.TVMazeId}, {&ids.Trakt, r.TraktId}, {&ids.Douban, r.DoubanId}} {
+		if id.src != nil && *id.src > 0 {
+			*id.dst = *id.src
+		}
+	}
+	return ids
+}
+
+// IDMap maps between IMDb, TMDb, TVDB, TVMaze, Trakt and Douban identifiers,
+// learned from search results and metadata lookups, so a structured search
+// can use whichever identifier an indexer supports. It is safe for
+// concurrent use.
+type IDMap struct {
+	mu    sync.Mutex
+	index map[string]*MediaIDs
+}
+
+// NewIDMap returns an empty IDMap
+func NewIDMap() *IDMap {
+	return &IDMap{index: make(map[string]*MediaIDs)}
+}
+
+// Add records that the identifiers in ids refer to the same title, merging
+// with anything already known about any of them
+func (m *IDMap) Add(ids MediaIDs) {
+	if id, err := NormalizeIMDbID(ids.IMDb); err == nil {
+		ids.IMDb = id
+	}
+	keys := ids.keys()
+	if len(keys) < 2 {
+		return // nothing to map
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	merged := ids
+	for _, key := range keys {
+		if existing, ok := m.index[key]; ok {
+			merged.merge(*existing)
+		}
+	}
+	entry := &merged
+	for _, key := range entry.keys() {
+		m.index[key] = entry
+	}
+}
+
+// AddResult records the identifiers carried by a search result
+func (m *IDMap) AddResult(result SearchResult) {
+	m.Add(result.ExternalIDs())
+}
+
+// AddMetadata records the identifiers found by a metadata lookup
+func (m *IDMap) AddMetadata(metadata *Metadata) {
+	if metadata == nil {
+		return
+	}
+	m.Add(MediaIDs{IMDb: metadata.IMDbID, TMDb: metadata.TMDbID, TVDB: metadata.TVDBID})
+}
+
+// Lookup returns every known identifier for id, which is an IMDb ID
+// ("tt0133093") or a prefixed ID ("tmdb:603", "tvdb:81189", "tvmaze:169",
+// "trakt:481")
+func (m *IDMap) Lookup(id string) (MediaIDs, bool) {
+	key := strings.ToLower(strings.TrimSpace(id))
+	if strings.HasPrefix(key, "tt") {
+		if imdb, err := NormalizeIMDbID(key); err == nil {
+			key = "imdb:" + imdb
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.index[key]
+	if !ok {
+		return MediaIDs{}, false
+	}
+	return *entry, true
+}
+
+// Save writes the map to path as JSON, replacing the file atomically
+func (m *IDMap) Save(path string) error {
+	m.mu.Lock()
+	seen := make(map[*MediaIDs]bool)
+	var entries []MediaIDs
+	for _, entry := range m.index {
+		if !seen[entry] {
+			seen[entry] = true
+			entries = append(entries, *entry)
+		}
+	}
+	m.mu.Unlock()
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode id map: %w", err)
+	}
+
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to save id map: %w", err)
+	}
+	return nil
+}
+
+// LoadIDMap reads a map written by Save. A missing file yields an empty map.
+func LoadIDMap(path string) (*IDMap, error) {
+	m := NewIDMap()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load id map: %w", err)
+	}
+
+	var entries []MediaIDs
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode id map: %w", err)
+	}
+	for _, entry := range entries {
+		m.Add(entry)
+	}
+	return m, nil
+}
+
+// formatIMDb renders Jackett's numeric IMDb field in canonical form
+func formatIMDb(imdb *int) string {
+	if imdb == nil || *imdb <= 0 {
+		return ""
+	}
+	return FormatIMDbID(*imdb)
+}
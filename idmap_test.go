@@ -0,0 +1,90 @@
+package jackett
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestIDMap(t *testing.T) {
+	m := NewIDMap()
+
+	imdb, tmdb, tvdb := 133093, 603, 81189
+	m.AddResult(SearchResult{Imdb: &imdb, TMDb: &tmdb})
+	// A later source links TMDb to TVDB; the entries must merge
+	m.Add(MediaIDs{TMDb: 603, TVDB: tvdb})
+
+	ids, ok := m.Lookup("tt0133093")
+	if !ok {
+		t.Fatal("Expected IMDb lookup to succeed")
+	}
+	want := MediaIDs{IMDb: "tt0133093", TMDb: 603, TVDB: 81189}
+	if ids != want {
+		t.Errorf("Expected %+v, got %+v", want, ids)
+	}
+
+	if ids, ok := m.Lookup("tvdb:81189"); !ok || ids.IMDb != "tt0133093" {
+		t.Errorf("Expected TVDB lookup to find tt0133093, got %+v, %v", ids, ok)
+	}
+	if _, ok := m.Lookup("tmdb:1"); ok {
+		t.Error("Expected unknown ID lookup to fail")
+	}
+}
+
+func TestSearchResult_ExternalIDs(t *testing.T) {
+	imdb, tmdb, tvdb, tvmaze, trakt, douban, zero := 1234567, 603, 81189, 82, 481, 1291843, 0
+	r := SearchResult{Imdb: &imdb, TMDb: &tmdb, TVDBId: &tvdb, TVMazeId: &tvmaze, TraktId: &trakt, DoubanId: &douban}
+	want := MediaIDs{IMDb: "tt1234567", TMDb: 603, TVDB: 81189, TVMaze: 82, Trakt: 481, Douban: 1291843}
+	if ids := r.ExternalIDs(); ids != want {
+		t.Errorf("Expected %+v, got %+v", want, ids)
+	}
+
+	imdb = 133093
+	if ids := (SearchResult{Imdb: &imdb, TMDb: &zero}).ExternalIDs(); ids != (MediaIDs{IMDb: "tt0133093"}) {
+		t.Errorf("Expected a zero-padded IMDb ID and no TMDb ID, got %+v", ids)
+	}
+	if ids := (SearchResult{}).ExternalIDs(); !ids.IsZero() {
+		t.Errorf("Expected no identifiers, got %+v", ids)
+	}
+
+	m := NewIDMap()
+	m.AddResult(SearchResult{TMDb: &tmdb, DoubanId: &douban})
+	if ids, ok := m.Lookup("douban:1291843"); !ok || ids.TMDb != 603 {
+		t.Errorf("Expected Douban lookup to find TMDb 603, got %+v, %v", ids, ok)
+	}
+}
+
+func TestIDMap_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ids.json")
+
+	m := NewIDMap()
+	m.Add(MediaIDs{IMDb: "tt0944947", TVDB: 121361, TVMaze: 82})
+	if err := m.Save(path); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	loaded, err := LoadIDMap(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ids, ok := loaded.Lookup("tvmaze:82"); !ok || ids.IMDb != "tt0944947" {
+		t.Errorf("Expected loaded map to resolve tvmaze:82, got %+v, %v", ids, ok)
+	}
+
+	empty, err := LoadIDMap(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil || empty == nil {
+		t.Errorf("Expected empty map for missing file, got %v, %v", empty, err)
+	}
+}
+
+func TestCachedProvider_PopulatesIDMap(t *testing.T) {
+	ids := NewIDMap()
+	provider := &fakeProvider{known: map[string]*Metadata{"The Matrix": {Title: "The Matrix", IMDbID: "tt0133093", TMDbID: 603}}}
+	cached := NewCachedProvider(provider, CachedProviderConfig{IDMap: ids})
+
+	cached.LookupTitle(context.Background(), "The Matrix", 1999)
+
+	if got, ok := ids.Lookup("tmdb:603"); !ok || got.IMDb != "tt0133093" {
+		t.Errorf("Expected lookup to populate the ID map, got %+v, %v", got, ok)
+	}
+}
@@ -0,0 +1,51 @@
+package jackett
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidIMDbID is returned for text that isn't an IMDb ID
+var ErrInvalidIMDbID = errors.New("jackett: invalid IMDb ID")
+
+// ParseIMDbID parses an IMDb ID with or without its "tt" prefix and leading
+// zeros, e.g. "tt0133093", "0133093" or "133093", into its number
+func ParseIMDbID(s string) (int, error) {
+	digits := strings.TrimSpace(s)
+	if len(digits) > 2 && strings.EqualFold(digits[:2], "tt") {
+		digits = digits[2:]
+	}
+	id, err := strconv.Atoi(digits)
+	if err != nil || id <= 0 || digits[0] == '+' {
+		return 0, fmt.Errorf("%w %q", ErrInvalidIMDbID, s)
+	}
+	return id, nil
+}
+
+// FormatIMDbID formats an IMDb ID number the way IMDb and Jackett write it:
+// "tt" and at least seven digits, e.g. "tt0133093"
+func FormatIMDbID(id int) string {
+	return fmt.Sprintf("tt%07d", id)
+}
+
+// NormalizeIMDbID returns the canonical form of an IMDb ID written any of
+// the ways ParseIMDbID accepts
+func NormalizeIMDbID(s string) (string, error) {
+	id, err := ParseIMDbID(s)
+	if err != nil {
+		return "", err
+	}
+	return FormatIMDbID(id), nil
+}
+
+// normalizeIMDbParam rewrites the imdbid parameter of a torznab query in
+// canonical form, since indexers differ in which forms they accept. Values
+// that aren't IMDb IDs are left for the indexer to reject.
+func normalizeIMDbParam(query url.Values) {
+	if id, err := NormalizeIMDbID(query.Get("imdbid")); err == nil {
+		query.Set("imdbid", id)
+	}
+}
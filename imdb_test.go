@@ -0,0 +1,85 @@
+package jackett
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestParseIMDbID(t *testing.T) {
+	for _, s := range []string{"tt0133093", "TT0133093", "0133093", "133093", " tt133093 "} {
+		if id, err := ParseIMDbID(s); err != nil || id != 133093 {
+			t.Errorf("ParseIMDbID(%q) = %d, %v, want 133093", s, id, err)
+		}
+		if id, err := NormalizeIMDbID(s); err != nil || id != "tt0133093" {
+			t.Errorf("NormalizeIMDbID(%q) = %q, %v, want tt0133093", s, id, err)
+		}
+	}
+	for _, s := range []string{"", "tt", "tt0", "-5", "+5", "nm0000206", "tt12a"} {
+		if _, err := ParseIMDbID(s); !errors.Is(err, ErrInvalidIMDbID) {
+			t.Errorf("ParseIMDbID(%q): expected ErrInvalidIMDbID, got %v", s, err)
+		}
+	}
+
+	if got := FormatIMDbID(133093); got != "tt0133093" {
+		t.Errorf("Expected tt0133093, got %q", got)
+	}
+	if got := FormatIMDbID(12345678); got != "tt12345678" {
+		t.Errorf("Expected eight-digit IDs unpadded, got %q", got)
+	}
+}
+
+func TestIMDbParamNormalization(t *testing.T) {
+	var imdbIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		imdbIDs = append(imdbIDs, r.URL.Query().Get("imdbid"))
+		w.Write([]byte(torznabFeedXML))
+	}))
+	defer server.Close()
+	client, _ := NewClient(server.URL, "key")
+
+	for _, id := range []string{"133093", "tt133093", "not-an-id"} {
+		if _, err := client.TorznabSearch(context.Background(), "all", url.Values{"t": {"movie"}, "imdbid": {id}}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	want := []string{"tt0133093", "tt0133093", "not-an-id"}
+	for i := range want {
+		if i >= len(imdbIDs) || imdbIDs[i] != want[i] {
+			t.Fatalf("Expected imdbid params %v, got %v", want, imdbIDs)
+		}
+	}
+
+	params := SearchRequest{Mode: SearchModeMovie, IMDbID: "133093"}.params()
+	if got := params.Get("imdbid"); got != "tt0133093" {
+		t.Errorf("Expected a planned imdbid of tt0133093, got %q", got)
+	}
+	if got := prowlarrQuery(url.Values{"q": {"matrix"}, "imdbid": {"133093"}}); got != "matrix {ImdbId:tt0133093}" {
+		t.Errorf("Unexpected Prowlarr query %q", got)
+	}
+}
+
+func TestIMDbResultParsing(t *testing.T) {
+	for _, data := range []string{`{"Imdb": 133093}`, `{"Imdb": "tt0133093"}`, `{"Imdb": "0133093"}`} {
+		var r SearchResult
+		if err := json.Unmarshal([]byte(data), &r); err != nil || r.Imdb == nil || *r.Imdb != 133093 {
+			t.Errorf("%s: expected 133093, got %v, %v", data, r.Imdb, err)
+		}
+	}
+	for _, data := range []string{`{"Imdb": null}`, `{"Imdb": 0}`, `{"Imdb": ""}`, `{}`} {
+		var r SearchResult
+		if err := json.Unmarshal([]byte(data), &r); err != nil || r.Imdb != nil {
+			t.Errorf("%s: expected no IMDb ID, got %v, %v", data, r.Imdb, err)
+		}
+	}
+
+	m := NewIDMap()
+	m.Add(MediaIDs{IMDb: "133093", TMDb: 603})
+	if ids, ok := m.Lookup("tt133093"); !ok || ids.IMDb != "tt0133093" {
+		t.Errorf("Expected unpadded IDs to be normalized, got %+v, %v", ids, ok)
+	}
+}
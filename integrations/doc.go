@@ -0,0 +1,3 @@
+// Package integrations sends releases found through Jackett straight to a
+// torrent client such as qBittorrent or Transmission.
+package integrations
@@ -0,0 +1,35 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cehbz/jackett"
+)
+
+// AddOptions controls how a torrent client adds a release. Zero values leave
+// the client's defaults in place.
+type AddOptions struct {
+	Category string
+	SavePath string
+	Paused   bool
+}
+
+// DownloadClient is a torrent client that releases can be added to
+type DownloadClient interface {
+	// Add adds a downloaded release, either a .torrent file or a magnet URI
+	Add(ctx context.Context, release *jackett.DownloadedRelease, opts AddOptions) error
+}
+
+// Send fetches result through client (see jackett.SearchResult.Fetch) and
+// adds it to dc
+func Send(ctx context.Context, client *jackett.Client, dc DownloadClient, result jackett.SearchResult, opts AddOptions) error {
+	release, err := result.Fetch(ctx, client)
+	if err != nil {
+		return err
+	}
+	if err := dc.Add(ctx, release, opts); err != nil {
+		return fmt.Errorf("add %q error: %w", result.Title, err)
+	}
+	return nil
+}
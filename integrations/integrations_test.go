@@ -0,0 +1,43 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cehbz/jackett"
+)
+
+type recordingClient struct {
+	added []*jackett.DownloadedRelease
+	opts  []AddOptions
+	err   error
+}
+
+func (r *recordingClient) Add(ctx context.Context, release *jackett.DownloadedRelease, opts AddOptions) error {
+	r.added = append(r.added, release)
+	r.opts = append(r.opts, opts)
+	return r.err
+}
+
+func TestSend(t *testing.T) {
+	client, err := jackett.NewClient("http://localhost:9117", "test-api-key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	dc := &recordingClient{}
+	result := jackett.SearchResult{Title: "Release", MagnetURI: "magnet:?xt=urn:btih:abc"}
+	if err := Send(context.Background(), client, dc, result, AddOptions{Category: "tv"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(dc.added) != 1 || dc.added[0].MagnetURI != result.MagnetURI || dc.opts[0].Category != "tv" {
+		t.Errorf("Expected magnet to be added with category, got %+v %+v", dc.added, dc.opts)
+	}
+
+	dc.err = errors.New("client offline")
+	if err := Send(context.Background(), client, dc, result, AddOptions{}); err == nil {
+		t.Fatal("Expected error, got none")
+	}
+}
@@ -0,0 +1,147 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+
+	"github.com/cehbz/jackett"
+)
+
+// QBittorrent adds releases through the qBittorrent Web API (v2). It logs in
+// on first use and again whenever the session expires. It is safe for
+// concurrent use.
+type QBittorrent struct {
+	client   *http.Client
+	baseURL  string
+	username string
+	password string
+}
+
+// NewQBittorrent initializes a qBittorrent Web API client. baseURL is the
+// address of the Web UI, e.g. "http://localhost:8080". httpClient is
+// optional; its cookie jar is replaced to hold the session.
+func NewQBittorrent(baseURL, username, password string, httpClient ...*http.Client) (*QBittorrent, error) {
+	client := &http.Client{}
+	if len(httpClient) > 0 && httpClient[0] != nil {
+		copied := *httpClient[0]
+		client = &copied
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	client.Jar = jar
+
+	return &QBittorrent{
+		client:   client,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		username: username,
+		password: password,
+	}, nil
+}
+
+// Add implements DownloadClient
+func (q *QBittorrent) Add(ctx context.Context, release *jackett.DownloadedRelease, opts AddOptions) error {
+	status, body, err := q.add(ctx, release, opts)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusForbidden {
+		if err := q.login(ctx); err != nil {
+			return err
+		}
+		if status, body, err = q.add(ctx, release, opts); err != nil {
+			return err
+		}
+	}
+
+	if status != http.StatusOK {
+		return fmt.Errorf("qbittorrent add failed (%d): %s", status, body)
+	}
+	// qBittorrent reports rejected torrents with a 200 and a "Fails." body
+	if strings.TrimSpace(body) == "Fails." {
+		return fmt.Errorf("qbittorrent rejected the torrent")
+	}
+	return nil
+}
+
+func (q *QBittorrent) add(ctx context.Context, release *jackett.DownloadedRelease, opts AddOptions) (int, string, error) {
+	var form bytes.Buffer
+	w := multipart.NewWriter(&form)
+	if release.IsMagnet() {
+		w.WriteField("urls", release.MagnetURI)
+	} else {
+		part, err := w.CreateFormFile("torrents", "release.torrent")
+		if err != nil {
+			return 0, "", err
+		}
+		part.Write(release.Torrent)
+	}
+	if opts.Category != "" {
+		w.WriteField("category", opts.Category)
+	}
+	if opts.SavePath != "" {
+		w.WriteField("savepath", opts.SavePath)
+	}
+	if opts.Paused {
+		// qBittorrent 5 renamed paused to stopped
+		w.WriteField("paused", "true")
+		w.WriteField("stopped", "true")
+	}
+	if err := w.Close(); err != nil {
+		return 0, "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/add", &form)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("Referer", q.baseURL)
+
+	return q.do(req)
+}
+
+func (q *QBittorrent) login(ctx context.Context) error {
+	form := url.Values{}
+	form.Set("username", q.username)
+	form.Set("password", q.password)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/auth/login", strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Referer", q.baseURL)
+
+	status, body, err := q.do(req)
+	if err != nil {
+		return fmt.Errorf("qbittorrent login error: %w", err)
+	}
+	if status != http.StatusOK || strings.TrimSpace(body) != "Ok." {
+		return fmt.Errorf("qbittorrent login failed (%d): %s", status, body)
+	}
+	return nil
+}
+
+func (q *QBittorrent) do(req *http.Request) (int, string, error) {
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	return resp.StatusCode, string(body), nil
+}
@@ -0,0 +1,96 @@
+package integrations
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cehbz/jackett"
+)
+
+func TestQBittorrent_Add(t *testing.T) {
+	var logins int
+	var added []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/auth/login":
+			logins++
+			if r.PostFormValue("username") != "admin" || r.PostFormValue("password") != "secret" {
+				w.Write([]byte("Fails."))
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "session", Path: "/"})
+			w.Write([]byte("Ok."))
+		case "/api/v2/torrents/add":
+			if c, err := r.Cookie("SID"); err != nil || c.Value != "session" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Errorf("Failed to parse form: %v", err)
+			}
+			fields := map[string]string{}
+			for k, v := range r.MultipartForm.Value {
+				fields[k] = v[0]
+			}
+			if files := r.MultipartForm.File["torrents"]; len(files) == 1 {
+				f, _ := files[0].Open()
+				data, _ := io.ReadAll(f)
+				fields["torrents"] = string(data)
+			}
+			added = append(added, fields)
+			w.Write([]byte("Ok."))
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	qb, err := NewQBittorrent(server.URL, "admin", "secret")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	opts := AddOptions{Category: "tv", SavePath: "/downloads/tv", Paused: true}
+	if err := qb.Add(context.Background(), &jackett.DownloadedRelease{MagnetURI: "magnet:?xt=urn:btih:abc"}, opts); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := qb.Add(context.Background(), &jackett.DownloadedRelease{Torrent: []byte("torrent data")}, AddOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if logins != 1 {
+		t.Errorf("Expected 1 login, got %d", logins)
+	}
+	if len(added) != 2 {
+		t.Fatalf("Expected 2 torrents added, got %d", len(added))
+	}
+	if added[0]["urls"] != "magnet:?xt=urn:btih:abc" || added[0]["category"] != "tv" || added[0]["savepath"] != "/downloads/tv" || added[0]["paused"] != "true" {
+		t.Errorf("Expected magnet with options, got %v", added[0])
+	}
+	if added[1]["torrents"] != "torrent data" || added[1]["category"] != "" {
+		t.Errorf("Expected torrent upload without options, got %v", added[1])
+	}
+}
+
+func TestQBittorrent_LoginFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/auth/login" {
+			w.Write([]byte("Fails."))
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	qb, err := NewQBittorrent(server.URL, "admin", "wrong")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := qb.Add(context.Background(), &jackett.DownloadedRelease{MagnetURI: "magnet:?xt=urn:btih:abc"}, AddOptions{}); err == nil {
+		t.Fatal("Expected error, got none")
+	}
+}
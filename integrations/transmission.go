@@ -0,0 +1,137 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/cehbz/jackett"
+)
+
+// transmissionSessionHeader carries Transmission's CSRF token
+const transmissionSessionHeader = "X-Transmission-Session-Id"
+
+// Transmission adds releases through Transmission's RPC API. It is safe for
+// concurrent use.
+type Transmission struct {
+	client   *http.Client
+	rpcURL   string
+	username string
+	password string
+
+	mu        sync.Mutex
+	sessionID string
+}
+
+// NewTransmission initializes a Transmission RPC client. rpcURL is the RPC
+// endpoint, e.g. "http://localhost:9091/transmission/rpc". Empty credentials
+// disable authentication.
+func NewTransmission(rpcURL, username, password string, httpClient ...*http.Client) (*Transmission, error) {
+	client := http.DefaultClient
+	if len(httpClient) > 0 && httpClient[0] != nil {
+		client = httpClient[0]
+	}
+
+	return &Transmission{
+		client:   client,
+		rpcURL:   rpcURL,
+		username: username,
+		password: password,
+	}, nil
+}
+
+type transmissionRequest struct {
+	Method    string                 `json:"method"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+type transmissionResponse struct {
+	Result string `json:"result"`
+}
+
+// Add implements DownloadClient. Adding a torrent Transmission already has is
+// not an error.
+func (t *Transmission) Add(ctx context.Context, release *jackett.DownloadedRelease, opts AddOptions) error {
+	args := map[string]interface{}{}
+	if release.IsMagnet() {
+		args["filename"] = release.MagnetURI
+	} else {
+		args["metainfo"] = base64.StdEncoding.EncodeToString(release.Torrent)
+	}
+	if opts.SavePath != "" {
+		args["download-dir"] = opts.SavePath
+	}
+	if opts.Paused {
+		args["paused"] = true
+	}
+	if opts.Category != "" {
+		// Transmission has no categories; labels are the closest equivalent
+		args["labels"] = []string{opts.Category}
+	}
+
+	body, err := json.Marshal(transmissionRequest{Method: "torrent-add", Arguments: args})
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	resp, err := t.call(ctx, body)
+	if err != nil {
+		return err
+	}
+	if resp.Result != "success" {
+		return fmt.Errorf("transmission add failed: %s", resp.Result)
+	}
+	return nil
+}
+
+// call performs an RPC, fetching a new session ID and retrying once when
+// Transmission answers 409 Conflict
+func (t *Transmission) call(ctx context.Context, body []byte) (*transmissionResponse, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "POST", t.rpcURL, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if t.username != "" || t.password != "" {
+			req.SetBasicAuth(t.username, t.password)
+		}
+		t.mu.Lock()
+		if t.sessionID != "" {
+			req.Header.Set(transmissionSessionHeader, t.sessionID)
+		}
+		t.mu.Unlock()
+
+		resp, err := t.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("request failed: %w", err)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusConflict && attempt == 0 {
+			t.mu.Lock()
+			t.sessionID = resp.Header.Get(transmissionSessionHeader)
+			t.mu.Unlock()
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("transmission request failed (%d): %s", resp.StatusCode, strings.TrimSpace(string(data)))
+		}
+
+		var rpcResp transmissionResponse
+		if err := json.Unmarshal(data, &rpcResp); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		return &rpcResp, nil
+	}
+}
@@ -0,0 +1,78 @@
+package integrations
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cehbz/jackett"
+)
+
+func TestTransmission_Add(t *testing.T) {
+	var requests []transmissionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "admin" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get(transmissionSessionHeader) != "token" {
+			w.Header().Set(transmissionSessionHeader, "token")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		var req transmissionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		requests = append(requests, req)
+		w.Write([]byte(`{"result": "success", "arguments": {"torrent-added": {"id": 1}}}`))
+	}))
+	defer server.Close()
+
+	tr, err := NewTransmission(server.URL+"/transmission/rpc", "admin", "secret")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	opts := AddOptions{Category: "movies", SavePath: "/downloads/movies", Paused: true}
+	if err := tr.Add(context.Background(), &jackett.DownloadedRelease{Torrent: []byte("torrent data")}, opts); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := tr.Add(context.Background(), &jackett.DownloadedRelease{MagnetURI: "magnet:?xt=urn:btih:abc"}, AddOptions{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 RPCs, got %d", len(requests))
+	}
+	args := requests[0].Arguments
+	if requests[0].Method != "torrent-add" || args["metainfo"] != base64.StdEncoding.EncodeToString([]byte("torrent data")) {
+		t.Errorf("Expected torrent-add with metainfo, got %+v", requests[0])
+	}
+	if args["download-dir"] != "/downloads/movies" || args["paused"] != true {
+		t.Errorf("Expected download dir and paused flag, got %v", args)
+	}
+	if requests[1].Arguments["filename"] != "magnet:?xt=urn:btih:abc" {
+		t.Errorf("Expected magnet as filename, got %v", requests[1].Arguments)
+	}
+}
+
+func TestTransmission_Failure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result": "invalid or corrupt torrent file"}`))
+	}))
+	defer server.Close()
+
+	tr, err := NewTransmission(server.URL, "", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := tr.Add(context.Background(), &jackett.DownloadedRelease{Torrent: []byte("junk")}, AddOptions{}); err == nil {
+		t.Fatal("Expected error, got none")
+	}
+}
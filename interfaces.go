@@ -0,0 +1,41 @@
+package jackett
+
+import (
+	"context"
+	"io"
+	"net/url"
+)
+
+// Searcher searches indexers. It is implemented by *Client, *MultiClient,
+// *ProwlarrClient and *TorznabClient, so code that only searches can depend
+// on it and be tested with a fake.
+type Searcher interface {
+	Search(query string) (*SearchResponse, error)
+	SearchWithIndexer(indexerID, query string) (*SearchResponse, error)
+	TorznabSearch(ctx context.Context, indexerID string, params url.Values) (*SearchResponse, error)
+}
+
+// TorrentDownloader downloads .torrent files from result links. It is
+// implemented by *Client, *ProwlarrClient and *TorznabClient.
+type TorrentDownloader interface {
+	DownloadTorrent(link string) ([]byte, error)
+	DownloadTorrentContext(ctx context.Context, link string, w io.Writer) (int64, error)
+}
+
+// IndexerManager lists, tests and configures indexers. It is implemented by
+// *Client.
+type IndexerManager interface {
+	GetIndexersContext(ctx context.Context) ([]Indexer, error)
+	GetIndexerCaps(ctx context.Context, indexerID string) (*Caps, []Category, error)
+	TestIndexerContext(ctx context.Context, indexerID string) error
+	GetIndexerConfigContext(ctx context.Context, indexerID string) ([]IndexerConfigField, error)
+	SetIndexerConfigContext(ctx context.Context, indexerID string, fields []IndexerConfigField) error
+	DeleteIndexer(indexerID string) error
+}
+
+var (
+	_ Searcher          = (*Client)(nil)
+	_ Searcher          = (*MultiClient)(nil)
+	_ TorrentDownloader = (*Client)(nil)
+	_ IndexerManager    = (*Client)(nil)
+)
@@ -0,0 +1,51 @@
+package jackett
+
+import (
+	"context"
+	"net/url"
+	"testing"
+)
+
+// fakeSearcher answers every search with the same results
+type fakeSearcher struct {
+	results []SearchResult
+	queries []string
+}
+
+func (f *fakeSearcher) Search(query string) (*SearchResponse, error) {
+	return f.SearchWithIndexer("all", query)
+}
+
+func (f *fakeSearcher) SearchWithIndexer(indexerID, query string) (*SearchResponse, error) {
+	f.queries = append(f.queries, indexerID+":"+query)
+	return &SearchResponse{Results: f.results}, nil
+}
+
+func (f *fakeSearcher) TorznabSearch(ctx context.Context, indexerID string, params url.Values) (*SearchResponse, error) {
+	return f.SearchWithIndexer(indexerID, params.Get("q"))
+}
+
+func TestSearcherFake(t *testing.T) {
+	bestSeeded := func(s Searcher, query string) (string, error) {
+		response, err := s.Search(query)
+		if err != nil {
+			return "", err
+		}
+		best := response.Results[0]
+		for _, r := range response.Results[1:] {
+			if r.Seeders > best.Seeders {
+				best = r
+			}
+		}
+		return best.Title, nil
+	}
+
+	fake := &fakeSearcher{results: []SearchResult{{Title: "a", Seeders: 1}, {Title: "b", Seeders: 9}}}
+	title, err := bestSeeded(fake, "ubuntu")
+	if err != nil || title != "b" {
+		t.Errorf("Expected b, got %q, %v", title, err)
+	}
+	if len(fake.queries) != 1 || fake.queries[0] != "all:ubuntu" {
+		t.Errorf("Expected one search of all indexers, got %v", fake.queries)
+	}
+}
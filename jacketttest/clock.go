@@ -0,0 +1,141 @@
+package jacketttest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cehbz/jackett"
+)
+
+// FakeClock is a jackett.Clock whose time only moves when Advance or Set is
+// called. It is safe for concurrent use.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*waiter
+}
+
+// waiter is a pending After channel or an active ticker
+type waiter struct {
+	deadline time.Time
+	period   time.Duration // zero for one-shot waiters
+	c        chan time.Time
+}
+
+// NewFakeClock returns a FakeClock set to start
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now returns the fake current time
+func (f *FakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// After returns a channel that receives the fake time once it has been
+// advanced by at least d
+func (f *FakeClock) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &waiter{deadline: f.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		w.c <- f.now
+		return w.c
+	}
+	f.waiters = append(f.waiters, w)
+	return w.c
+}
+
+// NewTicker returns a ticker that fires each time the fake time crosses a
+// multiple of d. Like time.Ticker, ticks are dropped if the receiver falls
+// behind.
+func (f *FakeClock) NewTicker(d time.Duration) jackett.Ticker {
+	if d <= 0 {
+		panic("jacketttest: non-positive interval for NewTicker")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &waiter{deadline: f.now.Add(d), period: d, c: make(chan time.Time, 1)}
+	f.waiters = append(f.waiters, w)
+	return &fakeTicker{clock: f, w: w}
+}
+
+// Advance moves the fake time forward by d, firing every timer and ticker
+// that comes due in deadline order
+func (f *FakeClock) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set moves the fake time to t, firing every timer and ticker that comes due
+// in deadline order. Setting a time in the past fires nothing.
+func (f *FakeClock) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for {
+		sort.SliceStable(f.waiters, func(i, j int) bool {
+			return f.waiters[i].deadline.Before(f.waiters[j].deadline)
+		})
+		if len(f.waiters) == 0 || f.waiters[0].deadline.After(t) {
+			break
+		}
+
+		w := f.waiters[0]
+		f.now = w.deadline
+		select {
+		case w.c <- w.deadline:
+		default:
+		}
+
+		if w.period > 0 {
+			w.deadline = w.deadline.Add(w.period)
+		} else {
+			f.waiters = f.waiters[1:]
+		}
+	}
+
+	if t.After(f.now) {
+		f.now = t
+	}
+}
+
+// Waiters returns the number of pending After channels and active tickers,
+// which lets tests wait until the code under test is blocked on the clock
+func (f *FakeClock) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// BlockUntil waits until at least n After channels and tickers are pending
+func (f *FakeClock) BlockUntil(n int) {
+	for f.Waiters() < n {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func (f *FakeClock) remove(w *waiter) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, other := range f.waiters {
+		if other == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+type fakeTicker struct {
+	clock *FakeClock
+	w     *waiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.w.c }
+func (t *fakeTicker) Stop()               { t.clock.remove(t.w) }
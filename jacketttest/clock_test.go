@@ -0,0 +1,92 @@
+package jacketttest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cehbz/jackett"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFakeClock_After(t *testing.T) {
+	clock := NewFakeClock(epoch)
+	ch := clock.After(time.Minute)
+
+	clock.Advance(59 * time.Second)
+	select {
+	case <-ch:
+		t.Fatal("Expected timer not to fire before deadline")
+	default:
+	}
+
+	clock.Advance(time.Second)
+	select {
+	case got := <-ch:
+		if !got.Equal(epoch.Add(time.Minute)) {
+			t.Errorf("Expected fire time %v, got %v", epoch.Add(time.Minute), got)
+		}
+	default:
+		t.Fatal("Expected timer to fire at deadline")
+	}
+
+	if clock.Waiters() != 0 {
+		t.Errorf("Expected 0 waiters, got %d", clock.Waiters())
+	}
+}
+
+func TestFakeClock_Ticker(t *testing.T) {
+	clock := NewFakeClock(epoch)
+	ticker := clock.NewTicker(10 * time.Second)
+
+	clock.Advance(10 * time.Second)
+	<-ticker.C()
+
+	// Ticks are dropped while the receiver is behind, like time.Ticker
+	clock.Advance(30 * time.Second)
+	<-ticker.C()
+	select {
+	case <-ticker.C():
+		t.Fatal("Expected extra ticks to be dropped")
+	default:
+	}
+
+	ticker.Stop()
+	clock.Advance(time.Minute)
+	select {
+	case <-ticker.C():
+		t.Fatal("Expected stopped ticker not to fire")
+	default:
+	}
+
+	if !clock.Now().Equal(epoch.Add(100 * time.Second)) {
+		t.Errorf("Expected now %v, got %v", epoch.Add(100*time.Second), clock.Now())
+	}
+}
+
+func TestFakeClock_DrivesClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2.0/indexers/all/results/torznab" {
+			w.Write([]byte(`<indexers><indexer id="a" configured="true"><title>A</title></indexer></indexers>`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client, err := jackett.NewClientWithOptions(server.URL, "key", jackett.WithClock(NewFakeClock(epoch)))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	report, err := client.TestAllIndexers(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(report) != 1 || report[0].Latency != 0 {
+		t.Errorf("Expected one report with zero fake latency, got %+v", report)
+	}
+}
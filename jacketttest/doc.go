@@ -0,0 +1,4 @@
+// Package jacketttest provides utilities for testing code built on the
+// jackett client: a controllable Clock, a fake Jackett server and a
+// record/replay transport for fixtures captured from a real Jackett.
+package jacketttest
@@ -0,0 +1,387 @@
+package jacketttest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/cehbz/jackett"
+	"github.com/cehbz/jackett/torrent"
+)
+
+// RecorderMode selects whether a Recorder talks to a real server
+type RecorderMode int
+
+const (
+	// ModeReplay answers requests from the fixture file without network access
+	ModeReplay RecorderMode = iota
+	// ModeRecord forwards requests to the real server and records them
+	ModeRecord
+)
+
+// redacted replaces secrets in fixtures
+const redacted = "REDACTED"
+
+// Interaction is a recorded request and its response
+type Interaction struct {
+	Method string `json:"method"`
+	// URL is the path and query of the request, with API keys redacted
+	URL         string      `json:"url"`
+	RequestBody string      `json:"request_body,omitempty"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        string      `json:"body,omitempty"`
+	// BodyBase64 holds binary bodies such as torrent files
+	BodyBase64 string `json:"body_base64,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records interactions with a real
+// Jackett to a fixture file and replays them later, so tests can run against
+// real tracker payloads without network access. Fixtures are sanitized: API
+// keys, tracker passkeys (see jackett.RedactSecrets) and registered secrets
+// are replaced in URLs, header values and bodies, including the announce URLs
+// of recorded .torrent files; credential headers are dropped and compressed
+// bodies are stored decompressed.
+//
+// Replayed requests are matched on method, path, query and body, ignoring
+// the host and API key. Each recorded interaction is used once, in order;
+// once all matches are used up the last one is repeated.
+type Recorder struct {
+	path string
+	mode RecorderMode
+	base http.RoundTripper
+
+	mu           sync.Mutex
+	secrets      []string
+	interactions []Interaction
+	used         []bool
+}
+
+// NewRecorder returns a Recorder for the fixture file at path. In
+// ModeReplay the file is loaded now; in ModeRecord requests are forwarded to
+// base (http.DefaultTransport if nil) and the file is written by Save.
+func NewRecorder(path string, mode RecorderMode, base http.RoundTripper) (*Recorder, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	r := &Recorder{path: path, mode: mode, base: base}
+	if mode == ModeRecord {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	if err := json.Unmarshal(data, &r.interactions); err != nil {
+		return nil, fmt.Errorf("failed to decode fixture %s: %w", path, err)
+	}
+	r.used = make([]bool, len(r.interactions))
+	return r, nil
+}
+
+// Redact registers secrets, such as an admin password or tracker
+// credentials, to be removed from recorded requests and responses
+func (r *Recorder) Redact(secrets ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range secrets {
+		if s != "" {
+			r.secrets = append(r.secrets, s)
+		}
+	}
+}
+
+// Client returns an http.Client using the recorder, for jackett.WithHTTPClient
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Save writes the recorded interactions to the fixture file
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0644)
+}
+
+// RoundTrip records or replays req
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, reqBody)
+	}
+	return r.record(req, reqBody)
+}
+
+func (r *Recorder) record(req *http.Request, reqBody []byte) (*http.Response, error) {
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = zr
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Method:      req.Method,
+		URL:         r.sanitize(req.URL.RequestURI()),
+		RequestBody: r.sanitize(string(reqBody)),
+		Status:      resp.StatusCode,
+		Header:      r.sanitizeHeader(resp.Header),
+	}
+	if scrubbed, ok := r.sanitizeTorrent(data); ok {
+		interaction.BodyBase64 = base64.StdEncoding.EncodeToString(scrubbed)
+	} else if utf8.Valid(data) {
+		interaction.Body = r.sanitize(string(data))
+	} else {
+		interaction.BodyBase64 = base64.StdEncoding.EncodeToString(data)
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.mu.Unlock()
+
+	// Hand the caller the decompressed body
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = int64(len(data))
+	resp.Uncompressed = true
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, reqBody []byte) (*http.Response, error) {
+	target := r.sanitize(req.URL.RequestURI())
+	body := r.sanitize(string(reqBody))
+
+	r.mu.Lock()
+	match := -1
+	for i, in := range r.interactions {
+		if in.Method != req.Method || !sameURL(in.URL, target) || in.RequestBody != body {
+			continue
+		}
+		match = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if match >= 0 {
+		r.used[match] = true
+	}
+	r.mu.Unlock()
+
+	if match < 0 {
+		return nil, fmt.Errorf("jacketttest: no recorded interaction for %s %s", req.Method, target)
+	}
+
+	in := r.interactions[match]
+	data := []byte(in.Body)
+	if in.BodyBase64 != "" {
+		var err error
+		if data, err = base64.StdEncoding.DecodeString(in.BodyBase64); err != nil {
+			return nil, fmt.Errorf("jacketttest: corrupt fixture body: %w", err)
+		}
+	}
+	header := in.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+		StatusCode:    in.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
+
+// sanitize removes API keys, tracker credentials and registered secrets
+// from s
+func (r *Recorder) sanitize(s string) string {
+	s = jackett.RedactSecrets(s)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return s
+}
+
+// sanitizeHeader drops credentials and headers describing the encoding of
+// the original body, and sanitizes the values of the others, such as a
+// Location carrying a download link's key
+func (r *Recorder) sanitizeHeader(header http.Header) http.Header {
+	clean := http.Header{}
+	for name, values := range header {
+		lower := strings.ToLower(name)
+		switch {
+		case lower == "content-encoding", lower == "content-length", lower == "date":
+		case strings.Contains(lower, "cookie"), strings.Contains(lower, "authorization"),
+			strings.Contains(lower, "secret"), strings.Contains(lower, "token"):
+		default:
+			for _, value := range values {
+				clean.Add(name, r.sanitize(value))
+			}
+		}
+	}
+	return clean
+}
+
+// sameURL compares request URIs regardless of query parameter order
+func sameURL(a, b string) bool {
+	ua, errA := url.Parse(a)
+	ub, errB := url.Parse(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return ua.Path == ub.Path && canonicalQuery(ua.Query()) == canonicalQuery(ub.Query())
+}
+
+func canonicalQuery(query url.Values) string {
+	for _, values := range query {
+		sort.Strings(values)
+	}
+	return query.Encode()
+}
+
+// torrentURLKeys are the keys of a .torrent file's top-level dictionary
+// whose strings may hold tracker credentials
+var torrentURLKeys = map[string]bool{"announce": true, "announce-list": true, "comment": true, "url-list": true}
+
+// sanitizeTorrent sanitizes the announce URLs and other credential-bearing
+// strings of a .torrent file, re-encoding only those values so that the info
+// dictionary and thus the info hash are kept. It reports false for data
+// that isn't a torrent.
+func (r *Recorder) sanitizeTorrent(data []byte) ([]byte, bool) {
+	decoded, err := torrent.Decode(data)
+	if dict, ok := decoded.(map[string]interface{}); err != nil || !ok || dict["info"] == nil {
+		return nil, false
+	}
+
+	var out bytes.Buffer
+	out.WriteByte('d')
+	pos := 1
+	for data[pos] != 'e' {
+		keyEnd := bencodeEnd(data, pos)
+		valueEnd := bencodeEnd(data, keyEnd)
+		key, _ := torrent.Decode(data[pos:keyEnd])
+		out.Write(data[pos:keyEnd])
+		if torrentURLKeys[key.(string)] {
+			value, _ := torrent.Decode(data[keyEnd:valueEnd])
+			encodeBencode(&out, r.sanitizeStrings(value))
+		} else {
+			out.Write(data[keyEnd:valueEnd])
+		}
+		pos = valueEnd
+	}
+	out.WriteByte('e')
+	return out.Bytes(), true
+}
+
+// sanitizeStrings sanitizes the strings of a decoded bencoded value
+func (r *Recorder) sanitizeStrings(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return r.sanitize(v)
+	case []interface{}:
+		clean := make([]interface{}, len(v))
+		for i, item := range v {
+			clean[i] = r.sanitizeStrings(item)
+		}
+		return clean
+	case map[string]interface{}:
+		clean := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			clean[key] = r.sanitizeStrings(item)
+		}
+		return clean
+	}
+	return v
+}
+
+// bencodeEnd returns the offset just past the bencoded value at pos of data,
+// which must be valid
+func bencodeEnd(data []byte, pos int) int {
+	switch c := data[pos]; {
+	case c == 'i':
+		return pos + bytes.IndexByte(data[pos:], 'e') + 1
+	case c == 'l', c == 'd':
+		pos++
+		for data[pos] != 'e' {
+			pos = bencodeEnd(data, pos)
+		}
+		return pos + 1
+	default:
+		colon := pos + bytes.IndexByte(data[pos:], ':')
+		var length int
+		fmt.Sscan(string(data[pos:colon]), &length)
+		return colon + 1 + length
+	}
+}
+
+// encodeBencode writes a decoded bencoded value
+func encodeBencode(w *bytes.Buffer, v interface{}) {
+	switch v := v.(type) {
+	case string:
+		fmt.Fprintf(w, "%d:%s", len(v), v)
+	case int64:
+		fmt.Fprintf(w, "i%de", v)
+	case []interface{}:
+		w.WriteByte('l')
+		for _, item := range v {
+			encodeBencode(w, item)
+		}
+		w.WriteByte('e')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		w.WriteByte('d')
+		for _, key := range keys {
+			encodeBencode(w, key)
+			encodeBencode(w, v[key])
+		}
+		w.WriteByte('e')
+	}
+}
@@ -0,0 +1,139 @@
+package jacketttest
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/jackett"
+	"github.com/cehbz/jackett/torrent"
+)
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	server, _ := newTestServer(t)
+	server.SetAPIKey("real-secret-key")
+	link := server.AddTorrent("alpha", "ubuntu.torrent", []byte("d4:infod4:name6:ubuntuee\xff"))
+	fixture := filepath.Join(t.TempDir(), "search.json")
+
+	recorder, err := NewRecorder(fixture, ModeRecord, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	recorded, err := jackett.NewClientWithOptions(server.URL, "real-secret-key", jackett.WithHTTPClient(recorder.Client()))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want, err := recorded.Search("ubuntu")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := recorded.DownloadTorrent(link); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	server.Close()
+
+	data, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Contains(string(data), "real-secret-key") {
+		t.Errorf("Expected the API key to be redacted from the fixture:\n%s", data)
+	}
+
+	replayer, err := NewRecorder(fixture, ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	replayed, _ := jackett.NewClientWithOptions("http://jackett.invalid", "other-key", jackett.WithHTTPClient(replayer.Client()))
+	got, err := replayed.Search("ubuntu")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(got.Results) != len(want.Results) || got.Results[0].Title != want.Results[0].Title {
+		t.Errorf("Expected replayed results %+v, got %+v", want.Results, got.Results)
+	}
+
+	torrent, err := replayed.DownloadTorrent("http://jackett.invalid" + strings.TrimPrefix(link, server.URL))
+	if err != nil || string(torrent) != "d4:infod4:name6:ubuntuee\xff" {
+		t.Errorf("Expected the binary torrent to replay, got %q, %v", torrent, err)
+	}
+
+	if _, err := replayed.Search("debian"); err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Errorf("Expected an unrecorded request to fail, got %v", err)
+	}
+}
+
+func TestRecorder_Redact(t *testing.T) {
+	recorder, _ := NewRecorder("", ModeRecord, nil)
+	recorder.Redact("hunter2", "")
+
+	got := recorder.sanitize(`{"link":"http://host/dl?jackett_apikey=abc&path=x","password":"hunter2"}`)
+	want := `{"link":"http://host/dl?jackett_apikey=REDACTED&path=x","password":"REDACTED"}`
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestRecorder_SanitizeHeaders(t *testing.T) {
+	recorder, _ := NewRecorder("", ModeRecord, nil)
+	recorder.Redact("proxy-secret")
+
+	header := recorder.sanitizeHeader(http.Header{
+		"Location":       {"http://host/dl/x/?jackett_apikey=abc&path=y"},
+		"X-Proxy-Info":   {"user proxy-secret"},
+		"Set-Cookie":     {"session=1"},
+		"Content-Length": {"10"},
+		"Content-Type":   {"text/html"},
+	})
+	want := http.Header{
+		"Location":     {"http://host/dl/x/?jackett_apikey=REDACTED&path=y"},
+		"X-Proxy-Info": {"user REDACTED"},
+		"Content-Type": {"text/html"},
+	}
+	if !reflect.DeepEqual(header, want) {
+		t.Errorf("Expected %v, got %v", want, header)
+	}
+}
+
+func TestRecorder_SanitizeTorrent(t *testing.T) {
+	recorder, _ := NewRecorder("", ModeRecord, nil)
+	info := "d6:lengthi1e4:name6:ubuntu12:piece lengthi16384e6:pieces0:e"
+	announce := "https://tracker.example/announce.php?passkey=feedface1234"
+	data := []byte("d8:announce" + strconv.Itoa(len(announce)) + ":" + announce +
+		"13:announce-listll" + strconv.Itoa(len(announce)) + ":" + announce + "ee" +
+		"4:info" + info + "e")
+
+	scrubbed, ok := recorder.sanitizeTorrent(data)
+	if !ok {
+		t.Fatal("Expected a torrent to be recognized")
+	}
+	if bytes.Contains(scrubbed, []byte("feedface1234")) {
+		t.Errorf("Expected the passkey to be removed, got %q", scrubbed)
+	}
+	before, err := torrent.Parse(data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	after, err := torrent.Parse(scrubbed)
+	if err != nil {
+		t.Fatalf("Expected the scrubbed torrent to stay valid, got %v", err)
+	}
+	if after.InfoHash != before.InfoHash || after.Announce != "https://tracker.example/announce.php?passkey=REDACTED" {
+		t.Errorf("Expected the info hash kept and the announce URL redacted, got %+v", after)
+	}
+	if len(after.AnnounceList) != 1 || after.AnnounceList[0][0] != after.Announce {
+		t.Errorf("Expected the announce list redacted, got %v", after.AnnounceList)
+	}
+
+	if _, ok := recorder.sanitizeTorrent([]byte(`{"Results": []}`)); ok {
+		t.Error("Expected JSON not to be taken for a torrent")
+	}
+}
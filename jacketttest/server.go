@@ -0,0 +1,629 @@
+package jacketttest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cehbz/jackett"
+)
+
+// DefaultAPIKey is the API key a Server accepts unless APIKey is changed
+const DefaultAPIKey = "test-api-key"
+
+// Server is an in-process fake Jackett built on httptest. It serves the
+// endpoints the jackett client uses (indexer listing and capabilities, JSON
+// and torznab searches, indexer tests and configuration, server
+// configuration and torrent downloads) from canned data, and can inject
+// errors and latency. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	apiKey   string
+	indexers []*fakeIndexer
+	config   map[string]interface{}
+	torrents map[string][]byte
+	faults   []*fault
+	latency  time.Duration
+	requests []Request
+	handlers map[string]http.HandlerFunc
+	inFlight int
+	peak     int
+}
+
+// Request is a request received by a Server
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+}
+
+// fakeIndexer is a configured indexer and its canned results
+type fakeIndexer struct {
+	indexer jackett.Indexer
+	results []jackett.SearchResult
+	failure string
+	config  []jackett.IndexerConfigField
+}
+
+// fault makes requests under a path prefix fail with a status
+type fault struct {
+	prefix    string
+	status    int
+	remaining int // negative for unlimited
+}
+
+// NewServer starts a fake Jackett without indexers. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		apiKey:   DefaultAPIKey,
+		config:   map[string]interface{}{"app_version": "0.22.0"},
+		torrents: make(map[string][]byte),
+		handlers: make(map[string]http.HandlerFunc),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// APIKey returns the API key the server accepts
+func (s *Server) APIKey() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.apiKey
+}
+
+// SetAPIKey changes the API key the server accepts
+func (s *Server) SetAPIKey(apiKey string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiKey = apiKey
+}
+
+// NewClient returns a jackett client for the server using its API key
+func (s *Server) NewClient(opts ...jackett.Option) (*jackett.Client, error) {
+	return jackett.NewClientWithOptions(s.URL, s.APIKey(), opts...)
+}
+
+// AddIndexer configures an indexer serving results. Without Caps the indexer
+// supports plain text searches only. Results lacking a tracker are
+// attributed to the indexer.
+func (s *Server) AddIndexer(indexer jackett.Indexer, results ...jackett.SearchResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addIndexer(indexer)
+	s.setResults(indexer.ID, results)
+}
+
+// addIndexer configures indexer, replacing any indexer with its ID;
+// s.mu must be held
+func (s *Server) addIndexer(indexer jackett.Indexer) *fakeIndexer {
+	indexer.Configured = true
+	if indexer.Name == "" {
+		indexer.Name = indexer.ID
+	}
+	if indexer.Caps == nil {
+		indexer.Caps = &jackett.Caps{
+			Server: "Jackett",
+			Limits: jackett.Limits{Default: 100, Max: 100},
+			Searching: jackett.Searching{
+				Search: &jackett.SearchType{Available: true, SupportedParams: []string{"q"}},
+			},
+		}
+	}
+
+	s.removeIndexer(indexer.ID)
+	idx := &fakeIndexer{indexer: indexer}
+	s.indexers = append(s.indexers, idx)
+	return idx
+}
+
+// SetResults replaces the results of an indexer
+func (s *Server) SetResults(indexerID string, results ...jackett.SearchResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setResults(indexerID, results)
+}
+
+func (s *Server) setResults(indexerID string, results []jackett.SearchResult) {
+	idx := s.indexer(indexerID)
+	if idx == nil {
+		return
+	}
+	idx.results = make([]jackett.SearchResult, len(results))
+	for i, r := range results {
+		if r.TrackerId == "" {
+			r.TrackerId = idx.indexer.ID
+		}
+		if r.Tracker == "" {
+			r.Tracker = idx.indexer.Name
+		}
+		idx.results[i] = r
+	}
+}
+
+// FailIndexer makes an indexer fail with message: aggregate searches report
+// it as failed, searches of it alone and its test return an error. An empty
+// message makes it healthy again.
+func (s *Server) FailIndexer(indexerID, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if idx := s.indexer(indexerID); idx != nil {
+		idx.failure = message
+	}
+}
+
+// SetIndexerConfig replaces the configuration form an indexer serves. Clients
+// saving a configuration replace it too, and saving one for an indexer that
+// isn't configured adds the indexer, as in Jackett.
+func (s *Server) SetIndexerConfig(indexerID string, fields ...jackett.IndexerConfigField) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if idx := s.indexer(indexerID); idx != nil {
+		idx.config = fields
+	}
+}
+
+// IndexerConfig returns the configuration form of an indexer, nil if it
+// isn't configured
+func (s *Server) IndexerConfig(indexerID string) []jackett.IndexerConfigField {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if idx := s.indexer(indexerID); idx != nil {
+		return append([]jackett.IndexerConfigField{}, idx.config...)
+	}
+	return nil
+}
+
+// AddTorrent serves data as a torrent file of an indexer and returns its
+// download link, suitable for SearchResult.Link
+func (s *Server) AddTorrent(indexerID, name string, data []byte) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.torrents[indexerID+"/"+name] = data
+
+	query := url.Values{}
+	query.Set("jackett_apikey", s.apiKey)
+	query.Set("path", name)
+	return fmt.Sprintf("%s/dl/%s/?%s", s.URL, url.PathEscape(indexerID), query.Encode())
+}
+
+// SetServerConfig replaces the server configuration
+func (s *Server) SetServerConfig(config map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config = config
+}
+
+// FailRequests makes the next times requests whose path starts with prefix
+// fail with status; times below one fails them until ClearFaults
+func (s *Server) FailRequests(prefix string, status, times int) {
+	if times < 1 {
+		times = -1
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault{prefix: prefix, status: status, remaining: times})
+}
+
+// ClearFaults removes all faults set up by FailRequests
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// SetLatency delays every response by d
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// Handle serves requests for pattern with h instead of the fake's own
+// endpoints, for responses the fake can't produce. pattern is a path,
+// optionally preceded by a method and a space, e.g. "/releases" or
+// "POST /api/v2.0/server/update"; a pattern with a method takes precedence.
+// Handled requests are recorded and subject to faults and latency, but h
+// checks the API key itself if it cares.
+func (s *Server) Handle(pattern string, h http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[pattern] = h
+}
+
+// PeakConcurrency returns the largest number of requests the server has
+// handled at once, e.g. to check a client's concurrency limit. Set a latency
+// so that concurrent requests overlap.
+func (s *Server) PeakConcurrency() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.peak
+}
+
+// Requests returns the requests received so far, in order
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// indexer returns the indexer with id; s.mu must be held
+func (s *Server) indexer(id string) *fakeIndexer {
+	for _, idx := range s.indexers {
+		if idx.indexer.ID == id {
+			return idx
+		}
+	}
+	return nil
+}
+
+// removeIndexer deletes the indexer with id, reporting whether it existed;
+// s.mu must be held
+func (s *Server) removeIndexer(id string) bool {
+	for i, idx := range s.indexers {
+		if idx.indexer.ID == id {
+			s.indexers = append(s.indexers[:i], s.indexers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// fault returns the status an injected fault imposes on path, or 0;
+// s.mu must be held
+func (s *Server) fault(path string) int {
+	for i, f := range s.faults {
+		if !strings.HasPrefix(path, f.prefix) {
+			continue
+		}
+		if f.remaining > 0 {
+			f.remaining--
+			if f.remaining == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return f.status
+	}
+	return 0
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query()})
+	latency := s.latency
+	status := s.fault(r.URL.Path)
+	apiKey := s.apiKey
+	handler, ok := s.handlers[r.Method+" "+r.URL.Path]
+	if !ok {
+		handler = s.handlers[r.URL.Path]
+	}
+	s.inFlight++
+	if s.inFlight > s.peak {
+		s.peak = s.inFlight
+	}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if status != 0 {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	if handler != nil {
+		handler(w, r)
+		return
+	}
+
+	path := r.URL.Path
+	if strings.HasPrefix(path, "/dl/") {
+		s.serveTorrent(w, r)
+		return
+	}
+	if !strings.HasPrefix(path, "/api/v2.0/") {
+		http.NotFound(w, r)
+		return
+	}
+	if r.URL.Query().Get("apikey") != apiKey {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusUnauthorized)
+		io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><error code="100" description="Invalid API Key" />`)
+		return
+	}
+
+	path = strings.TrimPrefix(path, "/api/v2.0/")
+	switch {
+	case path == "server/config":
+		s.serveConfig(w, r)
+	case path == "indexers/all/results/torznab" && r.URL.Query().Get("t") == "indexers":
+		s.serveIndexers(w, r)
+	case strings.HasPrefix(path, "indexers/"):
+		parts := strings.SplitN(strings.TrimPrefix(path, "indexers/"), "/", 2)
+		id, _ := url.PathUnescape(parts[0])
+		rest := ""
+		if len(parts) == 2 {
+			rest = parts[1]
+		}
+		s.serveIndexer(w, r, id, rest)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) serveConfig(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case "GET":
+		writeJSON(w, s.config)
+	case "POST":
+		var update map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for k, v := range update {
+			s.config[k] = v
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) serveIndexers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	var response jackett.TorznabIndexersResponse
+	for _, idx := range s.indexers {
+		response.Indexers = append(response.Indexers, toTorznabIndexer(idx.indexer))
+	}
+	s.mu.Unlock()
+
+	writeXML(w, response)
+}
+
+func (s *Server) serveIndexer(w http.ResponseWriter, r *http.Request, id, rest string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var targets []*fakeIndexer
+	if id == "all" {
+		targets = s.indexers
+	} else if idx := s.indexer(id); idx != nil {
+		targets = []*fakeIndexer{idx}
+	} else if rest == "config" && r.Method == "POST" {
+		// Saving a configuration sets the indexer up
+		targets = []*fakeIndexer{s.addIndexer(jackett.Indexer{ID: id})}
+	} else {
+		http.Error(w, "Indexer is not configured", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case rest == "results" && r.Method == "GET":
+		if id != "all" && targets[0].failure != "" {
+			http.Error(w, targets[0].failure, http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, search(targets, r.URL.Query().Get("Query")))
+	case rest == "results/torznab/api" && r.Method == "GET":
+		query := r.URL.Query()
+		if query.Get("t") == "caps" && id != "all" {
+			writeXML(w, struct {
+				XMLName xml.Name `xml:"caps"`
+				jackett.TorznabCaps
+			}{TorznabCaps: toTorznabCaps(targets[0].indexer)})
+			return
+		}
+		if id != "all" && targets[0].failure != "" {
+			writeTorznabError(w, 900, targets[0].failure)
+			return
+		}
+		writeFeed(w, search(targets, query.Get("q")).Results)
+	case rest == "test" && r.Method == "POST":
+		if targets[0].failure != "" {
+			http.Error(w, targets[0].failure, http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case rest == "config" && r.Method == "GET":
+		writeJSON(w, append([]jackett.IndexerConfigField{}, targets[0].config...))
+	case rest == "config" && r.Method == "POST":
+		var fields []jackett.IndexerConfigField
+		if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		targets[0].config = fields
+		w.WriteHeader(http.StatusNoContent)
+	case rest == "" && r.Method == "DELETE":
+		s.removeIndexer(id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) serveTorrent(w http.ResponseWriter, r *http.Request) {
+	indexerID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/dl/"), "/")
+
+	s.mu.Lock()
+	data, ok := s.torrents[indexerID+"/"+r.URL.Query().Get("path")]
+	apiKey := s.apiKey
+	s.mu.Unlock()
+
+	if r.URL.Query().Get("jackett_apikey") != apiKey {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-bittorrent")
+	w.Write(data)
+}
+
+// search returns the results of the indexers whose title contains every
+// word of query; an empty query matches everything
+func search(indexers []*fakeIndexer, query string) jackett.SearchResponse {
+	words := strings.Fields(strings.ToLower(query))
+	response := jackett.SearchResponse{Results: []jackett.SearchResult{}, Indexers: []jackett.IndexerStatus{}}
+
+	for _, idx := range indexers {
+		status := jackett.IndexerStatus{ID: idx.indexer.ID, Name: idx.indexer.Name, Status: jackett.IndexerStatusOK}
+		if idx.failure != "" {
+			status.Status = jackett.IndexerStatusError
+			status.Error = idx.failure
+			response.Indexers = append(response.Indexers, status)
+			continue
+		}
+		for _, r := range idx.results {
+			if matches(r.Title, words) {
+				response.Results = append(response.Results, r)
+				status.Results++
+			}
+		}
+		response.Indexers = append(response.Indexers, status)
+	}
+	return response
+}
+
+func matches(title string, words []string) bool {
+	title = strings.ToLower(title)
+	for _, word := range words {
+		if !strings.Contains(title, word) {
+			return false
+		}
+	}
+	return true
+}
+
+func toTorznabIndexer(indexer jackett.Indexer) jackett.TorznabIndexer {
+	return jackett.TorznabIndexer{
+		ID:          indexer.ID,
+		Configured:  indexer.Configured,
+		Title:       indexer.Name,
+		Description: indexer.Description,
+		Link:        indexer.SiteLink,
+		Language:    indexer.Language,
+		Type:        indexer.Type,
+		Caps:        toTorznabCaps(indexer),
+	}
+}
+
+// formatLimit formats a limit as a torznab attribute, empty if unknown
+func formatLimit(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func toTorznabCaps(indexer jackett.Indexer) jackett.TorznabCaps {
+	var caps jackett.TorznabCaps
+	if indexer.Caps != nil {
+		caps.Server.Title = indexer.Caps.Server
+		caps.Limits = jackett.TorznabLimits{Default: formatLimit(indexer.Caps.Limits.Default), Max: formatLimit(indexer.Caps.Limits.Max)}
+		searchType := func(t *jackett.SearchType) *jackett.TorznabSearchType {
+			if t == nil {
+				return nil
+			}
+			available := "no"
+			if t.Available {
+				available = "yes"
+			}
+			return &jackett.TorznabSearchType{Available: available, SupportedParams: strings.Join(t.SupportedParams, ",")}
+		}
+		searching := indexer.Caps.Searching
+		caps.Searching = jackett.TorznabSearching{
+			Search:      searchType(searching.Search),
+			TVSearch:    searchType(searching.TVSearch),
+			MovieSearch: searchType(searching.MovieSearch),
+			MusicSearch: searchType(searching.MusicSearch),
+			AudioSearch: searchType(searching.AudioSearch),
+			BookSearch:  searchType(searching.BookSearch),
+		}
+	}
+	for _, c := range indexer.Categories {
+		category := jackett.TorznabCategory{ID: c.ID, Name: c.Name}
+		for _, sub := range c.Subcats {
+			category.Subcats = append(category.Subcats, jackett.TorznabSubcat{ID: sub.ID, Name: sub.Name})
+		}
+		caps.Categories.Categories = append(caps.Categories.Categories, category)
+	}
+	return caps
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeXML(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	io.WriteString(w, xml.Header)
+	xml.NewEncoder(w).Encode(v)
+}
+
+func writeTorznabError(w http.ResponseWriter, code int, description string) {
+	w.Header().Set("Content-Type", "application/xml")
+	fmt.Fprintf(w, `%s<error code="%d" description="%s" />`, xml.Header, code, escape(description))
+}
+
+// writeFeed writes results as a torznab RSS feed
+func writeFeed(w http.ResponseWriter, results []jackett.SearchResult) {
+	w.Header().Set("Content-Type", "application/rss+xml")
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<rss version="2.0" xmlns:torznab="http://torznab.com/schemas/2015/feed"><channel>`)
+	for _, r := range results {
+		b.WriteString("<item>")
+		fmt.Fprintf(&b, "<title>%s</title><guid>%s</guid><link>%s</link>", escape(r.Title), escape(r.GUID), escape(r.Link))
+		fmt.Fprintf(&b, `<jackettindexer id="%s">%s</jackettindexer>`, escape(r.TrackerId), escape(r.Tracker))
+		fmt.Fprintf(&b, "<size>%d</size>", r.Size)
+		for _, category := range r.Category {
+			fmt.Fprintf(&b, "<category>%d</category>", category)
+		}
+		attr := func(name, value string) {
+			fmt.Fprintf(&b, `<torznab:attr name="%s" value="%s" />`, name, escape(value))
+		}
+		attr("seeders", strconv.Itoa(r.Seeders))
+		attr("peers", strconv.Itoa(r.Peers))
+		if r.InfoHash != "" {
+			attr("infohash", r.InfoHash)
+		}
+		if r.MagnetURI != "" {
+			attr("magneturl", r.MagnetURI)
+		}
+		b.WriteString("</item>")
+	}
+	b.WriteString("</channel></rss>")
+	io.WriteString(w, b.String())
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,219 @@
+package jacketttest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cehbz/jackett"
+)
+
+func newTestServer(t *testing.T) (*Server, *jackett.Client) {
+	t.Helper()
+	server := NewServer()
+	t.Cleanup(server.Close)
+
+	server.AddIndexer(jackett.Indexer{ID: "alpha", Name: "Alpha", Type: "public"},
+		jackett.SearchResult{Title: "Ubuntu 24.04 Desktop", Seeders: 100, Size: 6 << 30},
+		jackett.SearchResult{Title: "Debian 12", Seeders: 50},
+	)
+	server.AddIndexer(jackett.Indexer{ID: "beta", Name: "Beta", Type: "private"},
+		jackett.SearchResult{Title: "Ubuntu 22.04 Server", Seeders: 10},
+	)
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return server, client
+}
+
+func TestServer_Search(t *testing.T) {
+	server, client := newTestServer(t)
+
+	response, err := client.Search("ubuntu")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(response.Results) != 2 || len(response.Indexers) != 2 {
+		t.Fatalf("Expected 2 results from 2 indexers, got %d and %d", len(response.Results), len(response.Indexers))
+	}
+	if response.Results[0].TrackerId != "alpha" || response.Results[1].Tracker != "Beta" {
+		t.Errorf("Expected results attributed to their indexers, got %+v", response.Results)
+	}
+
+	response, err = client.SearchWithIndexer("alpha", "")
+	if err != nil || len(response.Results) != 2 {
+		t.Errorf("Expected every result of alpha, got %v, %v", response, err)
+	}
+
+	feed, err := client.TorznabSearch(context.Background(), "beta", url.Values{"q": {"ubuntu"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(feed.Results) != 1 || feed.Results[0].Title != "Ubuntu 22.04 Server" || feed.Results[0].Seeders != 10 {
+		t.Errorf("Unexpected torznab results %+v", feed.Results)
+	}
+
+	if n := len(server.Requests()); n != 3 {
+		t.Errorf("Expected 3 recorded requests, got %d", n)
+	}
+}
+
+func TestServer_Indexers(t *testing.T) {
+	_, client := newTestServer(t)
+
+	indexers, err := client.GetIndexers()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(indexers) != 2 || indexers[0].ID != "alpha" || !indexers[0].Configured || indexers[1].Type != "private" {
+		t.Fatalf("Unexpected indexers %+v", indexers)
+	}
+
+	caps, _, err := client.GetIndexerCaps(context.Background(), "alpha")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if caps.Searching.Search == nil || !caps.Searching.Search.Available {
+		t.Errorf("Expected default search caps, got %+v", caps)
+	}
+
+	if err := client.DeleteIndexer("beta"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	client.Invalidate()
+	if indexers, _ := client.GetIndexers(); len(indexers) != 1 {
+		t.Errorf("Expected 1 indexer after deletion, got %d", len(indexers))
+	}
+}
+
+func TestServer_FailIndexer(t *testing.T) {
+	server, client := newTestServer(t)
+	server.FailIndexer("beta", "Cloudflare challenge")
+
+	response, err := client.Search("ubuntu")
+	var partial *jackett.PartialError
+	if !errors.As(err, &partial) || len(partial.Failed) != 1 || partial.Failed[0].Message != "Cloudflare challenge" {
+		t.Fatalf("Expected a partial error for beta, got %v", err)
+	}
+	if len(response.Results) != 1 {
+		t.Errorf("Expected alpha's result, got %d", len(response.Results))
+	}
+
+	report, err := client.TestAllIndexers(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !report[0].OK || report[1].OK {
+		t.Errorf("Expected only beta to fail its test, got %+v", report)
+	}
+
+	server.FailIndexer("beta", "")
+	if _, err := client.SearchWithIndexer("beta", "ubuntu"); err != nil {
+		t.Errorf("Expected beta to recover, got %v", err)
+	}
+}
+
+func TestServer_FaultsAndAuth(t *testing.T) {
+	server, client := newTestServer(t)
+
+	server.FailRequests("/api/v2.0/server/config", http.StatusServiceUnavailable, 1)
+	if _, err := client.GetServerConfig(); !errors.Is(err, jackett.ErrServerUnavailable) {
+		t.Errorf("Expected ErrServerUnavailable, got %v", err)
+	}
+	config, err := client.GetServerConfig()
+	if err != nil || config["app_version"] != "0.22.0" {
+		t.Errorf("Expected the fault to be used up, got %v, %v", config, err)
+	}
+
+	wrongKey, _ := jackett.NewClient(server.URL, "wrong")
+	if _, err := wrongKey.GetServerConfig(); !errors.Is(err, jackett.ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey, got %v", err)
+	}
+}
+
+func TestServer_DownloadAndLatency(t *testing.T) {
+	server, client := newTestServer(t)
+
+	link := server.AddTorrent("alpha", "ubuntu.torrent", []byte("d4:infod4:name6:ubuntuee"))
+	data, err := client.DownloadTorrent(link)
+	if err != nil || string(data) != "d4:infod4:name6:ubuntuee" {
+		t.Errorf("Expected the torrent, got %q, %v", data, err)
+	}
+
+	server.SetLatency(200 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetIndexersContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the latency to exceed the deadline, got %v", err)
+	}
+}
+
+func TestServer_IndexerConfig(t *testing.T) {
+	server, client := newTestServer(t)
+	siteLink := jackett.IndexerConfigField{ID: "sitelink", Type: "inputstring", Value: json.RawMessage(`"https://alpha.example/"`)}
+	server.SetIndexerConfig("alpha", siteLink)
+
+	fields, err := client.GetIndexerConfig("alpha")
+	if err != nil || len(fields) != 1 || string(fields[0].Value) != `"https://alpha.example/"` {
+		t.Fatalf("Expected the configured site link, got %+v, %v", fields, err)
+	}
+
+	if err := client.SetIndexerConfig("gamma", fields); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := server.IndexerConfig("gamma"); len(got) != 1 || got[0].ID != "sitelink" {
+		t.Errorf("Expected the saved config, got %+v", got)
+	}
+	if indexers, _ := client.GetIndexers(); len(indexers) != 3 {
+		t.Errorf("Expected saving a config to add gamma, got %d indexers", len(indexers))
+	}
+}
+
+func TestServer_Handle(t *testing.T) {
+	server, client := newTestServer(t)
+	server.Handle("/releases", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	})
+	server.Handle("POST /api/v2.0/server/config", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	resp, err := http.Get(server.URL + "/releases")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the handler to answer, got %d", resp.StatusCode)
+	}
+
+	if err := client.SetServerConfig(map[string]interface{}{"port": 9117}); err == nil {
+		t.Error("Expected the handler to reject the config")
+	}
+	if _, err := client.GetServerConfig(); err != nil {
+		t.Errorf("Expected GET to reach the fake, got %v", err)
+	}
+}
+
+func TestServer_PeakConcurrency(t *testing.T) {
+	server, _ := newTestServer(t)
+	link := server.AddTorrent("alpha", "ubuntu.torrent", []byte("d4:infod4:name6:ubuntuee"))
+	server.SetLatency(20 * time.Millisecond)
+
+	client, err := server.NewClient(jackett.WithMaxConcurrentRequests(2))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.DownloadTorrents(context.Background(), []string{link, link, link, link}, 4); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if peak := server.PeakConcurrency(); peak != 2 {
+		t.Errorf("Expected a peak of 2 requests, got %d", peak)
+	}
+}
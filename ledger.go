@@ -0,0 +1,176 @@
+package jackett
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// EpisodeKey identifies a TV episode independently of the release carrying it
+type EpisodeKey struct {
+	Series  string `json:"series"` // normalized series title
+	Season  int    `json:"season"`
+	Episode int    `json:"episode"`
+}
+
+var episodePattern = regexp.MustCompile(`(?i)^(.*?)[\s._-]+(?:s(\d{1,2})[\s._-]?e(\d{1,3})|(\d{1,2})x(\d{2,3}))\b`)
+
+// ParseEpisode extracts the episode a release title refers to, recognizing
+// the "S01E02" and "1x02" conventions
+func ParseEpisode(title string) (EpisodeKey, bool) {
+	m := episodePattern.FindStringSubmatch(title)
+	if m == nil {
+		return EpisodeKey{}, false
+	}
+
+	season, episode := m[2], m[3]
+	if season == "" {
+		season, episode = m[4], m[5]
+	}
+	s, _ := strconv.Atoi(season)
+	e, _ := strconv.Atoi(episode)
+
+	return EpisodeKey{Series: strings.Join(tokenize(m[1]), " "), Season: s, Episode: e}, true
+}
+
+// Quality is a coarse release quality, ordered from worst to best
+type Quality int
+
+const (
+	QualityUnknown Quality = iota
+	QualitySD
+	Quality720p
+	Quality1080p
+	Quality2160p
+)
+
+func (q Quality) String() string {
+	switch q {
+	case QualitySD:
+		return "SD"
+	case Quality720p:
+		return "720p"
+	case Quality1080p:
+		return "1080p"
+	case Quality2160p:
+		return "2160p"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseQuality infers the quality of a release from its title
+func ParseQuality(title string) Quality {
+	for _, token := range tokenize(title) {
+		switch token {
+		case "2160p", "4k", "uhd":
+			return Quality2160p
+		case "1080p", "1080i":
+			return Quality1080p
+		case "720p":
+			return Quality720p
+		case "480p", "576p", "sdtv", "dvdrip", "xvid":
+			return QualitySD
+		}
+	}
+	return QualityUnknown
+}
+
+// QualityProfile decides which releases are worth grabbing
+type QualityProfile struct {
+	// Min rejects releases below this quality
+	Min Quality
+	// Cutoff stops upgrades once an episode has been grabbed at this
+	// quality. QualityUnknown, the zero value, means no cutoff: every
+	// release better than the grabbed one is an upgrade.
+	Cutoff Quality
+}
+
+// GrabDecision is the verdict on a release for an episode
+type GrabDecision int
+
+const (
+	// GrabNew means the episode has not been grabbed yet
+	GrabNew GrabDecision = iota
+	// GrabUpgrade means the release improves on the grabbed quality
+	GrabUpgrade
+	// GrabSkip means the episode was already grabbed at an equal or
+	// sufficient quality
+	GrabSkip
+	// GrabRejected means the release is below the profile's minimum quality
+	GrabRejected
+)
+
+func (d GrabDecision) String() string {
+	switch d {
+	case GrabNew:
+		return "new"
+	case GrabUpgrade:
+		return "upgrade"
+	case GrabSkip:
+		return "skip"
+	default:
+		return "rejected"
+	}
+}
+
+// GrabLedger remembers which episodes were grabbed at which quality, so a
+// new release of an already grabbed episode is recognized as an upgrade or a
+// duplicate instead of a fresh match. It is safe for concurrent use.
+type GrabLedger struct {
+	mu    sync.Mutex
+	grabs map[EpisodeKey]Quality
+}
+
+// NewGrabLedger returns an empty ledger
+func NewGrabLedger() *GrabLedger {
+	return &GrabLedger{grabs: make(map[EpisodeKey]Quality)}
+}
+
+// Record notes that result was grabbed. Results that don't name an episode
+// are ignored.
+func (l *GrabLedger) Record(result SearchResult) {
+	key, ok := ParseEpisode(result.Title)
+	if !ok {
+		return
+	}
+	quality := ParseQuality(result.Title)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if existing, ok := l.grabs[key]; !ok || quality > existing {
+		l.grabs[key] = quality
+	}
+}
+
+// Grabbed returns the best quality recorded for an episode
+func (l *GrabLedger) Grabbed(key EpisodeKey) (Quality, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	quality, ok := l.grabs[key]
+	return quality, ok
+}
+
+// Assess decides what grabbing result would mean under profile. Results that
+// don't name an episode are assessed on quality alone.
+func (l *GrabLedger) Assess(result SearchResult, profile QualityProfile) GrabDecision {
+	quality := ParseQuality(result.Title)
+	if quality < profile.Min {
+		return GrabRejected
+	}
+
+	key, ok := ParseEpisode(result.Title)
+	if !ok {
+		return GrabNew
+	}
+	grabbed, ok := l.Grabbed(key)
+	if !ok {
+		return GrabNew
+	}
+
+	if quality <= grabbed || (profile.Cutoff != QualityUnknown && grabbed >= profile.Cutoff) {
+		return GrabSkip
+	}
+	return GrabUpgrade
+}
@@ -0,0 +1,69 @@
+package jackett
+
+import "testing"
+
+func TestParseEpisode(t *testing.T) {
+	tests := []struct {
+		title string
+		want  EpisodeKey
+		ok    bool
+	}{
+		{"Show.Name.S01E02.1080p.WEB.x264", EpisodeKey{"show name", 1, 2}, true},
+		{"Show Name - 3x10 - Title 720p", EpisodeKey{"show name", 3, 10}, true},
+		{"Some.Movie.2019.1080p", EpisodeKey{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseEpisode(tt.title)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseEpisode(%q) = %+v, %v, expected %+v, %v", tt.title, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGrabLedger_Assess(t *testing.T) {
+	ledger := NewGrabLedger()
+	profile := QualityProfile{Min: Quality720p, Cutoff: Quality1080p}
+
+	sd := SearchResult{Title: "Show.S01E01.480p.HDTV"}
+	hd := SearchResult{Title: "Show.S01E01.720p.WEB"}
+	fullHD := SearchResult{Title: "Show.S01E01.1080p.WEB"}
+	uhd := SearchResult{Title: "Show.S01E01.2160p.WEB"}
+
+	if d := ledger.Assess(sd, profile); d != GrabRejected {
+		t.Errorf("Expected %v for SD, got %v", GrabRejected, d)
+	}
+	if d := ledger.Assess(hd, profile); d != GrabNew {
+		t.Errorf("Expected %v before any grab, got %v", GrabNew, d)
+	}
+
+	ledger.Record(hd)
+	if d := ledger.Assess(SearchResult{Title: "Show.S01E01.720p.HDTV.REPACK"}, profile); d != GrabSkip {
+		t.Errorf("Expected %v for same quality, got %v", GrabSkip, d)
+	}
+	if d := ledger.Assess(fullHD, profile); d != GrabUpgrade {
+		t.Errorf("Expected %v for better quality, got %v", GrabUpgrade, d)
+	}
+
+	ledger.Record(fullHD)
+	if d := ledger.Assess(uhd, profile); d != GrabSkip {
+		t.Errorf("Expected %v once cutoff is met, got %v", GrabSkip, d)
+	}
+	if d := ledger.Assess(SearchResult{Title: "Show.S01E02.720p.WEB"}, profile); d != GrabNew {
+		t.Errorf("Expected %v for another episode, got %v", GrabNew, d)
+	}
+}
+
+func TestGrabLedger_AssessWithoutCutoff(t *testing.T) {
+	ledger := NewGrabLedger()
+	profile := QualityProfile{Min: Quality720p}
+
+	ledger.Record(SearchResult{Title: "Show.S01E01.720p.WEB"})
+	if d := ledger.Assess(SearchResult{Title: "Show.S01E01.1080p.WEB"}, profile); d != GrabUpgrade {
+		t.Errorf("Expected %v without a cutoff, got %v", GrabUpgrade, d)
+	}
+
+	ledger.Record(SearchResult{Title: "Show.S01E01.2160p.WEB"})
+	if d := ledger.Assess(SearchResult{Title: "Show.S01E01.1080p.WEB"}, profile); d != GrabSkip {
+		t.Errorf("Expected %v for a worse release, got %v", GrabSkip, d)
+	}
+}
@@ -0,0 +1,109 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClientClosed is returned by requests made after Close
+var ErrClientClosed = errors.New("jackett: client closed")
+
+// lifecycle tracks in-flight requests and background goroutines so Close can
+// shut a Client down cleanly
+type lifecycle struct {
+	mu       sync.Mutex
+	closed   bool
+	inflight sync.WaitGroup
+	workers  sync.WaitGroup
+	ctx      context.Context // cancelled by Close to stop background work
+	cancel   context.CancelFunc
+	closers  []func(context.Context) error
+}
+
+func newLifecycle() *lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &lifecycle{ctx: ctx, cancel: cancel}
+}
+
+// begin registers an in-flight request; the returned func must be called
+// when the request completes
+func (l *lifecycle) begin() (func(), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil, ErrClientClosed
+	}
+	l.inflight.Add(1)
+	return l.inflight.Done, nil
+}
+
+// goBackground runs fn in a goroutine owned by the client. fn must return
+// once ctx is cancelled.
+func (c *Client) goBackground(fn func(ctx context.Context)) {
+	l := c.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return
+	}
+	l.workers.Add(1)
+	go func() {
+		defer l.workers.Done()
+		fn(l.ctx)
+	}()
+}
+
+// OnClose registers fn to run during Close, after background work has
+// stopped and in-flight requests have finished. It is meant for flushing
+// persistent state such as an IDMap or stats snapshot.
+func (c *Client) OnClose(fn func(ctx context.Context) error) {
+	c.lifecycle.mu.Lock()
+	defer c.lifecycle.mu.Unlock()
+	c.lifecycle.closers = append(c.lifecycle.closers, fn)
+}
+
+// Done returns a channel that is closed when Close is called, for
+// long-running components built on the client to stop with it
+func (c *Client) Done() <-chan struct{} {
+	return c.lifecycle.ctx.Done()
+}
+
+// Close shuts the client down: new requests fail with ErrClientClosed,
+// background goroutines are stopped, in-flight requests are awaited until
+// ctx expires, and OnClose functions run. Close returns ctx.Err() if the
+// deadline passed before everything finished, joined with any OnClose errors.
+func (c *Client) Close(ctx context.Context) error {
+	l := c.lifecycle
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	closers := l.closers
+	l.mu.Unlock()
+
+	l.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		l.workers.Wait()
+		l.inflight.Wait()
+		close(done)
+	}()
+
+	var errs []error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		errs = append(errs, ctx.Err())
+	}
+
+	for _, fn := range closers {
+		if err := fn(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,101 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClose_WaitsForInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, "test-api-key")
+
+	requestErr := make(chan error, 1)
+	go func() {
+		_, err := client.GetServerConfig()
+		requestErr <- err
+	}()
+	<-started
+
+	var flushed int32
+	client.OnClose(func(ctx context.Context) error {
+		atomic.AddInt32(&flushed, 1)
+		return nil
+	})
+
+	closeErr := make(chan error, 1)
+	go func() { closeErr <- client.Close(context.Background()) }()
+
+	select {
+	case <-closeErr:
+		t.Fatal("Expected Close to wait for the in-flight request")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-closeErr; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := <-requestErr; err != nil {
+		t.Errorf("Expected in-flight request to complete, got %v", err)
+	}
+	if atomic.LoadInt32(&flushed) != 1 {
+		t.Error("Expected OnClose function to run once")
+	}
+
+	if _, err := client.GetServerConfig(); err == nil || !strings.Contains(err.Error(), ErrClientClosed.Error()) {
+		t.Errorf("Expected ErrClientClosed after Close, got %v", err)
+	}
+}
+
+func TestClose_Deadline(t *testing.T) {
+	client, _ := NewClient("http://localhost:9117", "test-api-key")
+
+	client.goBackground(func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(time.Second) // ignores the shutdown for too long
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := client.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestClose_StopsBackgroundWork(t *testing.T) {
+	client, _ := NewClient("http://localhost:9117", "test-api-key")
+
+	stopped := make(chan struct{})
+	client.goBackground(func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Error("Expected background goroutine to have stopped")
+	}
+	select {
+	case <-client.Done():
+	default:
+		t.Error("Expected Done channel to be closed")
+	}
+}
@@ -0,0 +1,71 @@
+package jackett
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrResponseTooLarge is returned when a response body exceeds the client's
+// maximum response size
+var ErrResponseTooLarge = errors.New("jackett: response too large")
+
+// WithMaxResponseBytes caps the size of API response bodies. Requests whose
+// response exceeds n bytes fail with ErrResponseTooLarge instead of being
+// read into memory. Zero or less means no limit, the default.
+func WithMaxResponseBytes(n int64) Option {
+	return func(c *Client) {
+		c.maxResponseBytes = n
+	}
+}
+
+// WithMaxResults caps the number of results a search returns. Further
+// results are decoded and discarded without being retained, and the
+// response is marked Truncated. Zero or less means no limit, the default.
+func WithMaxResults(n int) Option {
+	return func(c *Client) {
+		c.maxResults = n
+	}
+}
+
+// limitReader reads at most remaining bytes from r, failing with
+// ErrResponseTooLarge if r holds more
+type limitReader struct {
+	r         io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		l.exceeded = true
+		return 0, ErrResponseTooLarge
+	}
+	// Read one byte past the limit so an exact-size body still reaches EOF
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		l.exceeded = true
+		return 0, ErrResponseTooLarge
+	}
+	return n, err
+}
+
+// limitResults wraps hook so that at most max results are retained,
+// setting *truncated once a result is discarded
+func limitResults(hook ResultHook, max int, truncated *bool) ResultHook {
+	kept := 0
+	return func(r *SearchResult) bool {
+		if hook != nil && !hook(r) {
+			return false
+		}
+		if kept >= max {
+			*truncated = true
+			return false
+		}
+		kept++
+		return true
+	}
+}
@@ -0,0 +1,67 @@
+package jackett
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestLimitReader(t *testing.T) {
+	exact := &limitReader{r: strings.NewReader("12345"), remaining: 5}
+	if data, err := io.ReadAll(exact); err != nil || string(data) != "12345" {
+		t.Errorf("Expected exact-size body to be read, got %q (%v)", data, err)
+	}
+
+	over := &limitReader{r: strings.NewReader("123456"), remaining: 5}
+	if _, err := io.ReadAll(over); !errors.Is(err, ErrResponseTooLarge) || !over.exceeded {
+		t.Errorf("Expected ErrResponseTooLarge, got %v", err)
+	}
+}
+
+func TestWithMaxResponseBytes(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2.0/indexers/all/results": {statusCode: http.StatusOK, responseBody: hookSearchJSON},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "GET", url: "/api/v2.0/indexers/all/results"},
+	}
+
+	client, _, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	WithMaxResponseBytes(64)(client)
+
+	if _, err := client.Search("test"); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("Expected ErrResponseTooLarge, got %v", err)
+	}
+}
+
+func TestWithMaxResults(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2.0/indexers/all/results": {statusCode: http.StatusOK, responseBody: hookSearchJSON},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "GET", url: "/api/v2.0/indexers/all/results", query: url.Values{"apikey": []string{"test-api-key"}, "Query": []string{"test"}}},
+	}
+
+	client, _, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	WithMaxResults(2)(client)
+
+	response, err := client.Search("test")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(response.Results) != 2 || !response.Truncated {
+		t.Errorf("Expected 2 results marked truncated, got %d (truncated=%v)", len(response.Results), response.Truncated)
+	}
+	if len(response.Indexers) != 1 {
+		t.Errorf("Expected indexer statuses after truncated results, got %v", response.Indexers)
+	}
+}
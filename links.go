@@ -0,0 +1,163 @@
+package jackett
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// ResultLinks are the clickable URLs of a search result. Fields are empty
+// when the result has no such link.
+type ResultLinks struct {
+	// Details is the release's page on the tracker
+	Details string
+	// Download fetches the torrent, NZB or magnet URI
+	Download string
+	// Blackhole makes Jackett save the release to its blackhole directory
+	Blackhole string
+}
+
+// Links returns r's links without credentials, safe to show to anyone: API
+// keys, tracker passkeys and authkeys are removed, and so are the trackers of
+// a magnet link whose announce URLs carry a passkey. Links through Jackett
+// then only work for users who can reach it with a key of their own; see
+// Client.AuthenticatedLinks.
+func (r SearchResult) Links() ResultLinks {
+	links := ResultLinks{
+		Details:  stripCredentials(r.Details),
+		Download: stripCredentials(r.downloadLink()),
+	}
+	if r.BlackholeLink != nil {
+		links.Blackhole = stripCredentials(*r.BlackholeLink)
+	}
+	return links
+}
+
+// AuthenticatedLinks returns r's links with the client's API key on those
+// that point at this Jackett instance, so that they work when clicked. The
+// key is replaced if Jackett put a different one there, e.g. before
+// SetAPIKey rotated it. Only render these links for users trusted with the
+// key; the details link never needs it and has any credentials removed.
+func (c *Client) AuthenticatedLinks(ctx context.Context, r SearchResult) ResultLinks {
+	links := ResultLinks{
+		Details:  stripCredentials(r.Details),
+		Download: c.AuthenticatedURL(ctx, r.downloadLink()),
+	}
+	if r.BlackholeLink != nil {
+		links.Blackhole = c.AuthenticatedURL(ctx, *r.BlackholeLink)
+	}
+	return links
+}
+
+// withoutCredentials returns a copy of r whose links are stripped of
+// credentials as by Links, for results sent to or stored by third parties
+func (r SearchResult) withoutCredentials() SearchResult {
+	r.Link = stripCredentials(r.Link)
+	r.MagnetURI = stripCredentials(r.MagnetURI)
+	r.GUID = stripCredentials(r.GUID)
+	r.Details = stripCredentials(r.Details)
+	if r.BlackholeLink != nil {
+		blackhole := stripCredentials(*r.BlackholeLink)
+		r.BlackholeLink = &blackhole
+	}
+	return r
+}
+
+// downloadLink is the link fetching r: the Jackett link, or the magnet URI
+// if there is none
+func (r SearchResult) downloadLink() string {
+	if r.Link != "" {
+		return r.Link
+	}
+	return r.MagnetURI
+}
+
+// AuthenticatedURL returns link with the client's API key if it points at
+// this Jackett instance, and unchanged otherwise. Jackett's download and
+// blackhole links carry the key as jackett_apikey, its API as apikey.
+func (c *Client) AuthenticatedURL(ctx context.Context, link string) string {
+	u, err := url.Parse(link)
+	if err != nil || !c.isOwnURL(u) {
+		return link
+	}
+	query := u.Query()
+	if query.Has("jackett_apikey") {
+		query.Set("jackett_apikey", c.apiKeyFor(ctx))
+	} else {
+		query.Set("apikey", c.apiKeyFor(ctx))
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// StripAPIKey returns link without the apikey and jackett_apikey query
+// parameters. It returns "" for a link it can't parse, since it can't be
+// sure such a link is free of keys.
+func StripAPIKey(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	query := u.Query()
+	if !query.Has("apikey") && !query.Has("jackett_apikey") {
+		return link
+	}
+	query.Del("apikey")
+	query.Del("jackett_apikey")
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// credentialParams are the query parameters of links that hold Jackett API
+// keys or tracker credentials
+var credentialParams = []string{"apikey", "jackett_apikey", "passkey", "authkey", "torrent_pass"}
+
+// stripCredentials returns link without API keys and tracker credentials:
+// the credentialParams are removed, as are a magnet link's trackers whose
+// announce URLs carry a passkey, and anything else RedactSecrets finds is
+// redacted. It returns "" for a link it can't parse.
+func stripCredentials(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	query := u.Query()
+	changed := false
+	for _, param := range credentialParams {
+		for key := range query {
+			if strings.EqualFold(key, param) {
+				query.Del(key)
+				changed = true
+			}
+		}
+	}
+	if u.Scheme == "magnet" {
+		var trackers []string
+		for _, tr := range query["tr"] {
+			if RedactSecrets(tr) == tr {
+				trackers = append(trackers, tr)
+			}
+		}
+		if len(trackers) != len(query["tr"]) {
+			query["tr"] = trackers
+			changed = true
+		}
+	}
+	if changed {
+		u.RawQuery = query.Encode()
+		link = u.String()
+	}
+	return RedactSecrets(link)
+}
+
+// isOwnURL reports whether u points at this Jackett instance, and so may be
+// given its API key and credentials: the same scheme and host as the base
+// URL, and a path under its path, so that other applications behind the same
+// host or reverse proxy aren't
+func (c *Client) isOwnURL(u *url.URL) bool {
+	baseURL, _ := url.Parse(c.baseURL)
+	if u.Host == "" || !strings.EqualFold(u.Scheme, baseURL.Scheme) || !strings.EqualFold(u.Host, baseURL.Host) {
+		return false
+	}
+	return baseURL.Path == "" || u.Path == baseURL.Path || strings.HasPrefix(u.Path, baseURL.Path+"/")
+}
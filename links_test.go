@@ -0,0 +1,125 @@
+package jackett
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestResultLinks(t *testing.T) {
+	blackhole := "http://localhost:9117/bh/test/?jackett_apikey=old&path=abc&file=Ubuntu"
+	result := SearchResult{
+		Link:          "http://localhost:9117/dl/test/?jackett_apikey=old&path=abc&file=Ubuntu",
+		BlackholeLink: &blackhole,
+		Details:       "https://tracker.example/details.php?id=42&apikey=leaked",
+	}
+	client, _ := NewClient("http://localhost:9117", "new")
+
+	links := result.Links()
+	want := ResultLinks{
+		Details:   "https://tracker.example/details.php?id=42",
+		Download:  "http://localhost:9117/dl/test/?file=Ubuntu&path=abc",
+		Blackhole: "http://localhost:9117/bh/test/?file=Ubuntu&path=abc",
+	}
+	if links != want {
+		t.Errorf("Expected %+v, got %+v", want, links)
+	}
+
+	links = client.AuthenticatedLinks(context.Background(), result)
+	want = ResultLinks{
+		Details:   "https://tracker.example/details.php?id=42",
+		Download:  "http://localhost:9117/dl/test/?file=Ubuntu&jackett_apikey=new&path=abc",
+		Blackhole: "http://localhost:9117/bh/test/?file=Ubuntu&jackett_apikey=new&path=abc",
+	}
+	if links != want {
+		t.Errorf("Expected %+v, got %+v", want, links)
+	}
+
+	ctx := ContextWithAPIKey(context.Background(), "override")
+	if got := client.AuthenticatedLinks(ctx, result).Download; got != "http://localhost:9117/dl/test/?file=Ubuntu&jackett_apikey=override&path=abc" {
+		t.Errorf("Expected the per-call key, got %q", got)
+	}
+}
+
+func TestResultLinks_Magnet(t *testing.T) {
+	magnet := "magnet:?xt=urn:btih:abc&dn=Ubuntu"
+	client, _ := NewClient("http://localhost:9117", "key")
+	result := SearchResult{MagnetURI: magnet}
+
+	if got := result.Links(); got != (ResultLinks{Download: magnet}) {
+		t.Errorf("Expected only the magnet link, got %+v", got)
+	}
+	if got := client.AuthenticatedLinks(context.Background(), result).Download; got != magnet {
+		t.Errorf("Expected the magnet link unchanged, got %q", got)
+	}
+}
+
+func TestAuthenticatedURL(t *testing.T) {
+	client, _ := NewClient("http://localhost:9117", "key")
+	ctx := context.Background()
+
+	tests := map[string]string{
+		"http://localhost:9117/api/v2.0/indexers/all/results": "http://localhost:9117/api/v2.0/indexers/all/results?apikey=key",
+		"https://tracker.example/download.php?id=42":          "https://tracker.example/download.php?id=42",
+		"/dl/test/?path=abc":                                  "/dl/test/?path=abc",
+	}
+	for link, want := range tests {
+		if got := client.AuthenticatedURL(ctx, link); got != want {
+			t.Errorf("AuthenticatedURL(%q) = %q, want %q", link, got, want)
+		}
+	}
+}
+
+func TestStripAPIKey(t *testing.T) {
+	tests := map[string]string{
+		"http://localhost:9117/dl/test/?jackett_apikey=key&path=abc": "http://localhost:9117/dl/test/?path=abc",
+		"http://localhost:9117/api?apikey=key":                       "http://localhost:9117/api",
+		"https://tracker.example/details.php?id=42":                  "https://tracker.example/details.php?id=42",
+		"":                         "",
+		"http://bad host/?apikey=": "",
+	}
+	for link, want := range tests {
+		if got := StripAPIKey(link); got != want {
+			t.Errorf("StripAPIKey(%q) = %q, want %q", link, got, want)
+		}
+	}
+}
+
+func TestResultLinks_TrackerCredentials(t *testing.T) {
+	magnet := "magnet:?xt=urn:btih:abc&dn=Show" +
+		"&tr=https%3A%2F%2Fprivate.example%2Fannounce.php%3Fpasskey%3Dfeedface1234" +
+		"&tr=udp%3A%2F%2Ftracker.opentrackr.org%3A1337%2Fannounce"
+	result := SearchResult{
+		MagnetURI: magnet,
+		Details:   "https://private.example/details.php?id=1&authkey=aaa&torrent_pass=bbb",
+	}
+
+	links := result.Links()
+	if strings.Contains(links.Download, "feedface1234") || strings.Contains(links.Download, "private.example") {
+		t.Errorf("Expected the private tracker to be dropped, got %q", links.Download)
+	}
+	if !strings.Contains(links.Download, "tracker.opentrackr.org") || !strings.Contains(links.Download, "btih") {
+		t.Errorf("Expected the public tracker and info hash to be kept, got %q", links.Download)
+	}
+	if links.Details != "https://private.example/details.php?id=1" {
+		t.Errorf("Expected the tracker credentials to be removed, got %q", links.Details)
+	}
+}
+
+func TestAuthenticatedURL_OtherApplications(t *testing.T) {
+	client, _ := NewClient("https://host.example/jackett", "key")
+	ctx := context.Background()
+
+	tests := map[string]string{
+		"https://host.example/jackett/dl/test/?path=abc": "https://host.example/jackett/dl/test/?apikey=key&path=abc",
+		"https://host.example/prowlarr/api/v1/search":    "https://host.example/prowlarr/api/v1/search",
+		"https://host.example/jackettfoo/dl/test/":       "https://host.example/jackettfoo/dl/test/",
+		"http://host.example/jackett/dl/test/?path=abc":  "http://host.example/jackett/dl/test/?path=abc",
+		"https://HOST.example/jackett/dl/test/?path=abc": "https://HOST.example/jackett/dl/test/?apikey=key&path=abc",
+	}
+	for link, want := range tests {
+		if got := client.AuthenticatedURL(ctx, link); got != want {
+			t.Errorf("AuthenticatedURL(%q) = %q, want %q", link, got, want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package jackett
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// WithLogger logs the client's activity to logger: every API request at
+// debug level (method, endpoint, query without the API key, status and
+// duration), and retries and failing indexers at warn level. By default
+// nothing is logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// redactedQuery encodes query without the API keys
+func redactedQuery(query url.Values) string {
+	if query.Get("apikey") == "" && query.Get("jackett_apikey") == "" {
+		return query.Encode()
+	}
+	clean := make(url.Values, len(query))
+	for k, v := range query {
+		if k != "apikey" && k != "jackett_apikey" {
+			clean[k] = v
+		}
+	}
+	return clean.Encode()
+}
+
+// logRequest logs a finished API request at debug level
+func (c *Client) logRequest(req *http.Request, status int, duration time.Duration, err error) {
+	ctx := req.Context()
+	if c.logger == nil || !c.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("endpoint", req.URL.Path),
+		slog.String("query", redactedQuery(req.URL.Query())),
+		slog.Int("status", status),
+		slog.Duration("duration", duration),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "jackett request", attrs...)
+}
+
+// logRetry logs at warn level that a failed operation will be retried
+func (c *Client) logRetry(ctx context.Context, operation string, attempt int, delay time.Duration, err error) {
+	if c.logger == nil {
+		return
+	}
+	c.logger.LogAttrs(ctx, slog.LevelWarn, "jackett retry",
+		slog.String("operation", operation),
+		slog.Int("attempt", attempt),
+		slog.Duration("delay", delay),
+		slog.Any("error", err),
+	)
+}
+
+// logIndexerErrors logs at warn level each indexer that failed in a search
+func (c *Client) logIndexerErrors(ctx context.Context, statuses []IndexerStatus) {
+	if c.logger == nil {
+		return
+	}
+	for _, status := range statuses {
+		if status.Status == IndexerStatusError {
+			c.logger.LogAttrs(ctx, slog.LevelWarn, "jackett indexer error",
+				slog.String("indexer", status.ID),
+				slog.String("name", status.Name),
+				slog.String("error", status.Error),
+			)
+		}
+	}
+}
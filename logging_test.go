@@ -0,0 +1,59 @@
+package jackett
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithLogger(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"Results": [], "Indexers": [
+			{"ID": "a", "Name": "A", "Status": 2, "Results": 0, "Error": null},
+			{"ID": "b", "Name": "B", "Status": 1, "Results": 0, "Error": "Cloudflare challenge"}
+		]}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client, err := NewClientWithOptions(server.URL, "secret-api-key",
+		WithLogger(logger),
+		WithRetry(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.Search("ubuntu"); err == nil {
+		t.Fatal("Expected a partial error")
+	}
+
+	logs := buf.String()
+	for _, want := range []string{
+		`level=DEBUG msg="jackett request" method=GET endpoint=/api/v2.0/indexers/all/results query="Query=ubuntu" status=503`,
+		`level=WARN msg="jackett retry" operation="GET /api/v2.0/indexers/all/results" attempt=1`,
+		`query="Query=ubuntu" status=200`,
+		`level=WARN msg="jackett indexer error" indexer=b name=B error="Cloudflare challenge"`,
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("Expected logs to contain %q, got:\n%s", want, logs)
+		}
+	}
+	if strings.Contains(logs, "secret-api-key") {
+		t.Errorf("Expected the API key to be left out of the logs, got:\n%s", logs)
+	}
+	if strings.Contains(logs, "indexer=a") {
+		t.Errorf("Expected only failing indexers to be logged, got:\n%s", logs)
+	}
+}
@@ -0,0 +1,91 @@
+package jackett
+
+import "strings"
+
+// MergeOptions configures how search responses are merged
+type MergeOptions struct {
+	// Dedup drops results that are the same release as an earlier one: the
+	// same GUID or info hash, or the same link if they have neither
+	Dedup bool
+}
+
+// MergeResponses combines the responses of several searches, e.g. one per
+// season of a show, into one. See MergeOptions.Merge.
+func MergeResponses(responses ...*SearchResponse) *SearchResponse {
+	return MergeOptions{}.Merge(responses...)
+}
+
+// Merge combines responses into one, skipping nil ones. Results are
+// concatenated in order. Indexers appearing in several responses are
+// reported once, with their result counts added up; an indexer that failed
+// in any of the searches is reported as failed, with each distinct error
+// message, so that a missing part of the results isn't hidden. The merged
+// response is Truncated if any of them was.
+func (o MergeOptions) Merge(responses ...*SearchResponse) *SearchResponse {
+	merged := &SearchResponse{}
+	indexers := make(map[string]int)
+	seen := make(map[string]bool)
+
+	for _, response := range responses {
+		if response == nil {
+			continue
+		}
+		merged.Truncated = merged.Truncated || response.Truncated
+
+		for _, r := range response.Results {
+			if o.Dedup && anyKeySeen(seen, releaseKeys(r)) {
+				continue
+			}
+			merged.Results = append(merged.Results, r)
+		}
+
+		for _, status := range response.Indexers {
+			key := status.ID
+			if key == "" {
+				key = "name:" + status.Name
+			}
+			i, ok := indexers[key]
+			if !ok {
+				indexers[key] = len(merged.Indexers)
+				merged.Indexers = append(merged.Indexers, status)
+				continue
+			}
+			mergeIndexerStatus(&merged.Indexers[i], status)
+		}
+	}
+	return merged
+}
+
+// anyKeySeen reports whether any of keys is in seen, adding them all
+func anyKeySeen(seen map[string]bool, keys []string) bool {
+	found := false
+	for _, key := range keys {
+		found = found || seen[key]
+		seen[key] = true
+	}
+	return found
+}
+
+// mergeIndexerStatus folds the outcome of another search of the same
+// indexer into status
+func mergeIndexerStatus(status *IndexerStatus, other IndexerStatus) {
+	status.Results += other.Results
+	if status.Name == "" {
+		status.Name = other.Name
+	}
+	if other.Status == IndexerStatusError {
+		status.Status = IndexerStatusError
+	}
+	if other.Error == "" {
+		return
+	}
+	for _, message := range strings.Split(status.Error, "; ") {
+		if message == other.Error {
+			return
+		}
+	}
+	if status.Error != "" {
+		status.Error += "; "
+	}
+	status.Error += other.Error
+}
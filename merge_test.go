@@ -0,0 +1,78 @@
+package jackett
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeResponses(t *testing.T) {
+	season1 := &SearchResponse{
+		Results: []SearchResult{
+			{Title: "Show S01", GUID: "a", Tracker: "One"},
+			{Title: "Show S01 Pack", InfoHash: "ABCDEF0123456789ABCDEF0123456789ABCDEF01", Tracker: "Two"},
+		},
+		Indexers: []IndexerStatus{
+			{ID: "one", Name: "One", Status: IndexerStatusOK, Results: 1},
+			{ID: "two", Name: "Two", Status: IndexerStatusOK, Results: 1},
+		},
+	}
+	season2 := &SearchResponse{
+		Results: []SearchResult{
+			{Title: "Show S01", GUID: "a", Tracker: "One"},
+			{Title: "Show S01 Pack", InfoHash: "abcdef0123456789abcdef0123456789abcdef01", Tracker: "Three"},
+			{Title: "Show S02", Link: "http://localhost:9117/dl/one/?path=2", Tracker: "One"},
+		},
+		Indexers: []IndexerStatus{
+			{ID: "one", Name: "One", Status: IndexerStatusOK, Results: 2},
+			{ID: "two", Name: "Two", Status: IndexerStatusError, Error: "timeout"},
+			{ID: "three", Name: "Three", Status: IndexerStatusOK, Results: 1},
+		},
+		Truncated: true,
+	}
+	season3 := &SearchResponse{
+		Indexers: []IndexerStatus{
+			{ID: "two", Name: "Two", Status: IndexerStatusError, Error: "timeout"},
+		},
+	}
+
+	merged := MergeResponses(season1, nil, season2, season3)
+	if len(merged.Results) != 5 {
+		t.Errorf("Expected all 5 results, got %d", len(merged.Results))
+	}
+	if !merged.Truncated {
+		t.Error("Expected the merged response to be truncated")
+	}
+	wantIndexers := []IndexerStatus{
+		{ID: "one", Name: "One", Status: IndexerStatusOK, Results: 3},
+		{ID: "two", Name: "Two", Status: IndexerStatusError, Results: 1, Error: "timeout"},
+		{ID: "three", Name: "Three", Status: IndexerStatusOK, Results: 1},
+	}
+	if !reflect.DeepEqual(merged.Indexers, wantIndexers) {
+		t.Errorf("Expected indexers %+v, got %+v", wantIndexers, merged.Indexers)
+	}
+
+	deduped := MergeOptions{Dedup: true}.Merge(season1, season2)
+	var titles []string
+	for _, r := range deduped.Results {
+		titles = append(titles, r.Title+"/"+r.Tracker)
+	}
+	wantTitles := []string{"Show S01/One", "Show S01 Pack/Two", "Show S02/One"}
+	if !reflect.DeepEqual(titles, wantTitles) {
+		t.Errorf("Expected %v, got %v", wantTitles, titles)
+	}
+}
+
+func TestMergeResponses_Errors(t *testing.T) {
+	merged := MergeResponses(
+		&SearchResponse{Indexers: []IndexerStatus{{ID: "one", Status: IndexerStatusError, Error: "timeout"}}},
+		&SearchResponse{Indexers: []IndexerStatus{{ID: "one", Name: "One", Status: IndexerStatusError, Error: "HTTP 500"}}},
+	)
+	want := IndexerStatus{ID: "one", Name: "One", Status: IndexerStatusError, Error: "timeout; HTTP 500"}
+	if len(merged.Indexers) != 1 || merged.Indexers[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, merged.Indexers)
+	}
+
+	if merged := MergeResponses(); merged == nil || len(merged.Results) != 0 || len(merged.Indexers) != 0 {
+		t.Errorf("Expected an empty response, got %+v", merged)
+	}
+}
@@ -0,0 +1,209 @@
+package jackett
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HealthEventKind identifies a change in the health of Jackett or an indexer
+type HealthEventKind int
+
+const (
+	// ServerDown means Jackett stopped answering or rejected the credentials
+	ServerDown HealthEventKind = iota
+	// ServerUp means Jackett is reachable again after being down
+	ServerUp
+	// IndexerFailing means an indexer's test started failing
+	IndexerFailing
+	// IndexerRecovered means a failing indexer's test passes again
+	IndexerRecovered
+)
+
+func (k HealthEventKind) String() string {
+	switch k {
+	case ServerDown:
+		return "server down"
+	case ServerUp:
+		return "server up"
+	case IndexerFailing:
+		return "indexer failing"
+	case IndexerRecovered:
+		return "indexer recovered"
+	}
+	return "unknown"
+}
+
+// HealthEvent reports a health transition observed by a Monitor
+type HealthEvent struct {
+	Kind HealthEventKind
+	Time time.Time
+	// IndexerID and IndexerName are set for indexer events
+	IndexerID   string
+	IndexerName string
+	// Err is the failure behind ServerDown and IndexerFailing events
+	Err error
+}
+
+// Monitor periodically pings Jackett and tests its indexers, reporting
+// transitions between healthy and failing as HealthEvents. Everything is
+// assumed healthy at the start, so only failures and later recoveries are
+// reported. Indexers are not tested while the server is down.
+type Monitor struct {
+	// Client is the Jackett client being monitored
+	Client *Client
+	// Interval between checks, 1 minute by default
+	Interval time.Duration
+	// Concurrency of indexer tests, 4 by default
+	Concurrency int
+	// SkipIndexers limits the monitor to pinging the server
+	SkipIndexers bool
+	// OnEvent, if set, is called for each event from the monitoring goroutine
+	OnEvent func(HealthEvent)
+	// Events, if set, receives each event. Sends block, so the channel must
+	// be drained or buffered.
+	Events chan<- HealthEvent
+
+	mu         sync.Mutex
+	serverDown bool
+	failing    map[string]bool
+}
+
+// Run checks health every Interval until ctx is cancelled or the client is
+// closed. The first check happens immediately.
+func (m *Monitor) Run(ctx context.Context) error {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := m.Client.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, event := range m.Check(ctx) {
+			if err := m.emit(ctx, event); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-m.Client.Done():
+			return ErrClientClosed
+		case <-ticker.C():
+		}
+	}
+}
+
+// emit delivers event to the callback and the channel
+func (m *Monitor) emit(ctx context.Context, event HealthEvent) error {
+	if m.OnEvent != nil {
+		m.OnEvent(event)
+	}
+	if m.Events == nil {
+		return nil
+	}
+	select {
+	case m.Events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-m.Client.Done():
+		return ErrClientClosed
+	}
+}
+
+// Check pings the server and tests the indexers once, returning the
+// transitions since the previous check. It does not call OnEvent or send on
+// Events; Run does. A check interrupted by ctx reports nothing.
+func (m *Monitor) Check(ctx context.Context) []HealthEvent {
+	_, pingErr := m.Client.Ping(ctx)
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	var report []IndexerHealth
+	if pingErr == nil && !m.SkipIndexers {
+		concurrency := m.Concurrency
+		if concurrency <= 0 {
+			concurrency = 4
+		}
+		var err error
+		report, err = m.Client.TestAllIndexers(ctx, concurrency)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			// The indexer list itself failed, so the server is not healthy
+			pingErr = err
+		}
+	}
+	now := m.Client.clock.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var events []HealthEvent
+	switch {
+	case pingErr != nil && !m.serverDown:
+		m.serverDown = true
+		events = append(events, HealthEvent{Kind: ServerDown, Time: now, Err: pingErr})
+	case pingErr == nil && m.serverDown:
+		m.serverDown = false
+		events = append(events, HealthEvent{Kind: ServerUp, Time: now})
+	}
+	if pingErr != nil || m.SkipIndexers {
+		return events
+	}
+
+	if m.failing == nil {
+		m.failing = make(map[string]bool)
+	}
+	seen := make(map[string]bool, len(report))
+	for _, h := range report {
+		seen[h.ID] = true
+		switch {
+		case !h.OK && !m.failing[h.ID]:
+			m.failing[h.ID] = true
+			events = append(events, HealthEvent{
+				Kind:        IndexerFailing,
+				Time:        now,
+				IndexerID:   h.ID,
+				IndexerName: h.Name,
+				Err:         &IndexerError{ID: h.ID, Name: h.Name, Message: h.Error},
+			})
+		case h.OK && m.failing[h.ID]:
+			delete(m.failing, h.ID)
+			events = append(events, HealthEvent{Kind: IndexerRecovered, Time: now, IndexerID: h.ID, IndexerName: h.Name})
+		}
+	}
+	// Forget indexers that were removed from Jackett
+	for id := range m.failing {
+		if !seen[id] {
+			delete(m.failing, id)
+		}
+	}
+	return events
+}
+
+// ServerReachable reports whether the last check found the server healthy
+func (m *Monitor) ServerReachable() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return !m.serverDown
+}
+
+// FailingIndexers returns the IDs of the indexers whose last test failed
+func (m *Monitor) FailingIndexers() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make([]string, 0, len(m.failing))
+	for id := range m.failing {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
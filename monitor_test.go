@@ -0,0 +1,128 @@
+package jackett_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cehbz/jackett"
+	"github.com/cehbz/jackett/jacketttest"
+)
+
+// newMonitorFake serves a healthy and a failing indexer
+func newMonitorFake(t *testing.T) *jacketttest.Server {
+	server := jacketttest.NewServer()
+	t.Cleanup(server.Close)
+
+	server.AddIndexer(jackett.Indexer{ID: "configured-indexer", Name: "Configured Indexer"})
+	server.AddIndexer(jackett.Indexer{ID: "failing-indexer", Name: "Failing Indexer"})
+	server.FailIndexer("failing-indexer", "Cloudflare challenge")
+	return server
+}
+
+func eventKinds(events []jackett.HealthEvent) []jackett.HealthEventKind {
+	var kinds []jackett.HealthEventKind
+	for _, e := range events {
+		kinds = append(kinds, e.Kind)
+	}
+	return kinds
+}
+
+func TestMonitorCheckTransitions(t *testing.T) {
+	server := newMonitorFake(t)
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	monitor := &jackett.Monitor{Client: client}
+	ctx := context.Background()
+
+	// One indexer fails from the start; the other is healthy
+	events := monitor.Check(ctx)
+	if len(events) != 1 || events[0].Kind != jackett.IndexerFailing || events[0].IndexerID != "failing-indexer" {
+		t.Fatalf("Expected the failing indexer to be reported failing, got %+v", events)
+	}
+	var indexerErr *jackett.IndexerError
+	if !errors.As(events[0].Err, &indexerErr) || indexerErr.Name != "Failing Indexer" {
+		t.Errorf("Expected an IndexerError, got %v", events[0].Err)
+	}
+	if events := monitor.Check(ctx); len(events) != 0 {
+		t.Errorf("Expected no events without a change, got %+v", events)
+	}
+
+	server.FailIndexer("configured-indexer", "Login failed")
+	events = monitor.Check(ctx)
+	if len(events) != 1 || events[0].Kind != jackett.IndexerFailing || events[0].IndexerID != "configured-indexer" {
+		t.Errorf("Expected the configured indexer to be reported failing, got %+v", events)
+	}
+	if got := monitor.FailingIndexers(); !reflect.DeepEqual(got, []string{"configured-indexer", "failing-indexer"}) {
+		t.Errorf("Expected both indexers failing, got %v", got)
+	}
+
+	server.FailRequests("/", http.StatusServiceUnavailable, 0)
+	events = monitor.Check(ctx)
+	if len(events) != 1 || events[0].Kind != jackett.ServerDown || !errors.Is(events[0].Err, jackett.ErrServerUnavailable) {
+		t.Errorf("Expected a server down event, got %+v", events)
+	}
+	if monitor.ServerReachable() {
+		t.Error("Expected the server to be unreachable")
+	}
+	if events := monitor.Check(ctx); len(events) != 0 {
+		t.Errorf("Expected no events while the server stays down, got %+v", events)
+	}
+
+	server.ClearFaults()
+	server.FailIndexer("configured-indexer", "")
+	events = monitor.Check(ctx)
+	if want := []jackett.HealthEventKind{jackett.ServerUp, jackett.IndexerRecovered}; !reflect.DeepEqual(eventKinds(events), want) {
+		t.Errorf("Expected events %v, got %v", want, eventKinds(events))
+	}
+	if !monitor.ServerReachable() {
+		t.Error("Expected the server to be reachable")
+	}
+}
+
+func TestMonitorRun(t *testing.T) {
+	server := jacketttest.NewServer()
+	defer server.Close()
+	server.FailRequests("/", http.StatusServiceUnavailable, 0)
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var callbacks atomic.Int32
+	events := make(chan jackett.HealthEvent)
+	monitor := &jackett.Monitor{
+		Client:       client,
+		Interval:     10 * time.Millisecond,
+		SkipIndexers: true,
+		OnEvent:      func(jackett.HealthEvent) { callbacks.Add(1) },
+		Events:       events,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- monitor.Run(ctx) }()
+
+	if e := <-events; e.Kind != jackett.ServerDown {
+		t.Errorf("Expected a server down event, got %v", e.Kind)
+	}
+	server.ClearFaults()
+	if e := <-events; e.Kind != jackett.ServerUp {
+		t.Errorf("Expected a server up event, got %v", e.Kind)
+	}
+	if n := callbacks.Load(); n != 2 {
+		t.Errorf("Expected 2 callbacks, got %d", n)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
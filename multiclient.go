@@ -0,0 +1,319 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNoInstances is returned by NewMultiClient without any clients
+	ErrNoInstances = errors.New("jackett: no instances")
+	// ErrNilInstance is returned by NewMultiClient when a client is nil
+	ErrNilInstance = errors.New("jackett: nil instance")
+)
+
+// MultiClient routes calls across redundant Jackett instances, each reached
+// through its own Client. Calls go to the first healthy instance in the order
+// given, or are spread across the healthy instances with WithBalancing; an
+// instance that fails with a network error, a 5xx or 429 response
+// or rejected credentials is marked down and the call moves on to the next.
+// Down instances are skipped until their cooldown expires or CheckHealth finds
+// them reachable again. If every instance is down, all of them are tried.
+type MultiClient struct {
+	clients  []*Client
+	cooldown time.Duration
+	sticky   bool
+	balance  Balance
+
+	mu        sync.Mutex
+	downUntil []time.Time
+	pinned    map[string]int
+	next      int
+	latency   []time.Duration // moving average per instance, 0 until measured
+}
+
+// Balance selects how a MultiClient orders its healthy instances
+type Balance int
+
+const (
+	// Failover prefers instances in the order they were given, so all load
+	// goes to the first healthy one
+	Failover Balance = iota
+	// RoundRobin rotates calls across the healthy instances
+	RoundRobin
+	// LeastLatency prefers the healthy instance with the lowest average
+	// response time. Instances not measured yet are tried first.
+	LeastLatency
+)
+
+// MultiOption configures a MultiClient created by NewMultiClient
+type MultiOption func(*MultiClient)
+
+// WithStickyIndexers keeps routing calls for an indexer to the instance that
+// last served it while that instance stays healthy, instead of always
+// preferring the first one. This keeps per-indexer state such as sessions and
+// cached results on one instance.
+func WithStickyIndexers() MultiOption {
+	return func(m *MultiClient) {
+		m.sticky = true
+	}
+}
+
+// WithBalancing spreads calls across the healthy instances instead of
+// sending them all to the first one. Failed calls still move on to the
+// remaining instances.
+func WithBalancing(balance Balance) MultiOption {
+	return func(m *MultiClient) {
+		m.balance = balance
+	}
+}
+
+// WithFailoverCooldown sets how long a failed instance is skipped before it
+// is tried again, 30 seconds by default
+func WithFailoverCooldown(d time.Duration) MultiOption {
+	return func(m *MultiClient) {
+		m.cooldown = d
+	}
+}
+
+// NewMultiClient returns a MultiClient over clients, in order of preference.
+// Every client must be non-nil.
+func NewMultiClient(clients []*Client, opts ...MultiOption) (*MultiClient, error) {
+	if len(clients) == 0 {
+		return nil, ErrNoInstances
+	}
+	for i, client := range clients {
+		if client == nil {
+			return nil, fmt.Errorf("%w at index %d", ErrNilInstance, i)
+		}
+	}
+
+	m := &MultiClient{
+		clients:   append([]*Client(nil), clients...),
+		cooldown:  30 * time.Second,
+		downUntil: make([]time.Time, len(clients)),
+		pinned:    make(map[string]int),
+		latency:   make([]time.Duration, len(clients)),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m, nil
+}
+
+// Clients returns the underlying clients in order of preference
+func (m *MultiClient) Clients() []*Client {
+	return append([]*Client(nil), m.clients...)
+}
+
+// now reads the clock of the first client, which all failover bookkeeping uses
+func (m *MultiClient) now() time.Time {
+	return m.clients[0].clock.Now()
+}
+
+// order returns the instance indexes to try for indexerID: healthy ones
+// first, starting with the pinned instance and then in the order chosen by
+// the balancing strategy, followed by the down ones
+func (m *MultiClient) order(indexerID string) []int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	up := func(i int) bool { return !now.Before(m.downUntil[i]) }
+
+	var healthy, down []int
+	pinned, ok := m.pinned[indexerID]
+	ok = ok && m.sticky && up(pinned)
+	for i := range m.clients {
+		switch {
+		case ok && i == pinned:
+		case up(i):
+			healthy = append(healthy, i)
+		default:
+			down = append(down, i)
+		}
+	}
+
+	switch m.balance {
+	case RoundRobin:
+		if len(healthy) > 0 {
+			start := m.next % len(healthy)
+			healthy = append(healthy[start:], healthy[:start]...)
+			m.next++
+		}
+	case LeastLatency:
+		sort.SliceStable(healthy, func(a, b int) bool {
+			return m.latency[healthy[a]] < m.latency[healthy[b]]
+		})
+	}
+
+	var order []int
+	if ok {
+		order = append(order, pinned)
+	}
+	order = append(order, healthy...)
+	return append(order, down...)
+}
+
+// observe folds a response time into an instance's moving average
+func (m *MultiClient) observe(i int, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.latency[i] == 0 {
+		m.latency[i] = d
+		return
+	}
+	m.latency[i] = m.latency[i]*3/4 + d/4
+}
+
+func (m *MultiClient) markDown(i int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.downUntil[i] = m.now().Add(m.cooldown)
+}
+
+func (m *MultiClient) markUp(i int, indexerID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.downUntil[i] = time.Time{}
+	if m.sticky && indexerID != "" {
+		m.pinned[indexerID] = i
+	}
+}
+
+// shouldFailover reports whether err suggests another instance may succeed
+func shouldFailover(err error) bool {
+	return IsRetryable(err) || errors.Is(err, ErrInvalidAPIKey)
+}
+
+// Do calls fn with the client of each instance in turn until one succeeds or
+// fails with an error that another instance would not fix, which is returned
+// as is. indexerID is used for sticky routing and may be empty. If every
+// instance fails, the errors are returned joined.
+func (m *MultiClient) Do(ctx context.Context, indexerID string, fn func(*Client) error) error {
+	var errs []error
+	for _, i := range m.order(indexerID) {
+		start := m.now()
+		err := fn(m.clients[i])
+		if err == nil {
+			m.observe(i, m.now().Sub(start))
+		}
+		if err == nil || !shouldFailover(err) {
+			m.markUp(i, indexerID)
+			return err
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		m.markDown(i)
+		errs = append(errs, fmt.Errorf("%s: %w", m.clients[i].baseURL, err))
+	}
+	return errors.Join(errs...)
+}
+
+// Search searches all indexers on the first healthy instance, or the one
+// chosen by the balancing strategy
+func (m *MultiClient) Search(query string) (*SearchResponse, error) {
+	return m.SearchWithIndexer("all", query)
+}
+
+// SearchWithIndexer searches one indexer on the first healthy instance
+func (m *MultiClient) SearchWithIndexer(indexerID, query string) (*SearchResponse, error) {
+	var response *SearchResponse
+	err := m.Do(context.Background(), indexerID, func(c *Client) error {
+		var err error
+		response, err = c.SearchWithIndexer(indexerID, query)
+		return err
+	})
+	return response, err
+}
+
+// TorznabSearch runs a raw torznab query on the first healthy instance
+func (m *MultiClient) TorznabSearch(ctx context.Context, indexerID string, params url.Values) (*SearchResponse, error) {
+	var response *SearchResponse
+	err := m.Do(ctx, indexerID, func(c *Client) error {
+		var err error
+		response, err = c.TorznabSearch(ctx, indexerID, params)
+		return err
+	})
+	return response, err
+}
+
+// GetIndexersContext lists the indexers of the first healthy instance
+func (m *MultiClient) GetIndexersContext(ctx context.Context) ([]Indexer, error) {
+	var indexers []Indexer
+	err := m.Do(ctx, "", func(c *Client) error {
+		var err error
+		indexers, err = c.GetIndexersContext(ctx)
+		return err
+	})
+	return indexers, err
+}
+
+// CheckHealth pings every instance, marking it up or down accordingly, and
+// returns the ping errors by instance (nil for healthy ones)
+func (m *MultiClient) CheckHealth(ctx context.Context) []error {
+	errs := make([]error, len(m.clients))
+	var wg sync.WaitGroup
+	for i, c := range m.clients {
+		wg.Add(1)
+		go func(i int, c *Client) {
+			defer wg.Done()
+			info, err := c.Ping(ctx)
+			if err == nil {
+				m.observe(i, info.Latency)
+			}
+			errs[i] = err
+		}(i, c)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			m.markDown(i)
+		} else {
+			m.markUp(i, "")
+		}
+	}
+	return errs
+}
+
+// Healthy reports which instances are currently considered up
+func (m *MultiClient) Healthy() []bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	healthy := make([]bool, len(m.clients))
+	for i := range m.clients {
+		healthy[i] = !now.Before(m.downUntil[i])
+	}
+	return healthy
+}
+
+// Run calls CheckHealth every interval, one minute if not positive, until
+// ctx is cancelled. The first check happens immediately.
+func (m *MultiClient) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := m.clients[0].clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		m.CheckHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+	}
+}
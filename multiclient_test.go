@@ -0,0 +1,219 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newInstance serves search results titled name unless up is false
+func newInstance(name string, up *atomic.Bool, requests *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !up.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v2.0/server/config":
+			w.Write([]byte(`{"app_version": "0.22.0"}`))
+		case "/api/v2.0/indexers/all/results/torznab":
+			w.Write([]byte(allIndexersXML))
+		default:
+			w.Write([]byte(`{"Results": [{"Title": "` + name + `"}], "Indexers": []}`))
+		}
+	}))
+}
+
+func newTestMultiClient(t *testing.T, clock Clock, urls []string, opts ...MultiOption) *MultiClient {
+	t.Helper()
+	var clients []*Client
+	for _, u := range urls {
+		c, err := NewClientWithOptions(u, "test-api-key", WithClock(clock))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		clients = append(clients, c)
+	}
+	m, err := NewMultiClient(clients, opts...)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return m
+}
+
+func searchTitle(t *testing.T, m *MultiClient, indexerID string) string {
+	t.Helper()
+	response, err := m.SearchWithIndexer(indexerID, "test")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return response.Results[0].Title
+}
+
+func TestMultiClientFailover(t *testing.T) {
+	var primaryUp, secondaryUp atomic.Bool
+	var primaryRequests, secondaryRequests atomic.Int32
+	primaryUp.Store(true)
+	secondaryUp.Store(true)
+	primary := newInstance("primary", &primaryUp, &primaryRequests)
+	defer primary.Close()
+	secondary := newInstance("secondary", &secondaryUp, &secondaryRequests)
+	defer secondary.Close()
+
+	clock := &stepClock{now: time.Now()}
+	m := newTestMultiClient(t, clock, []string{primary.URL, secondary.URL})
+
+	if title := searchTitle(t, m, "all"); title != "primary" {
+		t.Errorf("Expected the primary instance to be used, got %s", title)
+	}
+
+	primaryUp.Store(false)
+	if title := searchTitle(t, m, "all"); title != "secondary" {
+		t.Errorf("Expected failover to the secondary instance, got %s", title)
+	}
+	if got := m.Healthy(); !reflect.DeepEqual(got, []bool{false, true}) {
+		t.Errorf("Expected only the secondary to be healthy, got %v", got)
+	}
+
+	// The primary is skipped during its cooldown
+	before := primaryRequests.Load()
+	primaryUp.Store(true)
+	if title := searchTitle(t, m, "all"); title != "secondary" {
+		t.Errorf("Expected the secondary instance during the cooldown, got %s", title)
+	}
+	if primaryRequests.Load() != before {
+		t.Error("Expected no requests to the primary during its cooldown")
+	}
+
+	clock.now = clock.now.Add(31 * time.Second)
+	if title := searchTitle(t, m, "all"); title != "primary" {
+		t.Errorf("Expected the primary instance after the cooldown, got %s", title)
+	}
+
+	primaryUp.Store(false)
+	secondaryUp.Store(false)
+	_, err := m.Search("test")
+	if !errors.Is(err, ErrServerUnavailable) {
+		t.Errorf("Expected ErrServerUnavailable when every instance is down, got %v", err)
+	}
+
+	// With every instance down, all of them are still tried
+	secondaryUp.Store(true)
+	if title := searchTitle(t, m, "all"); title != "secondary" {
+		t.Errorf("Expected the secondary instance, got %s", title)
+	}
+}
+
+func TestMultiClientStickyIndexers(t *testing.T) {
+	var primaryUp, secondaryUp atomic.Bool
+	var requests atomic.Int32
+	primaryUp.Store(true)
+	secondaryUp.Store(true)
+	primary := newInstance("primary", &primaryUp, &requests)
+	defer primary.Close()
+	secondary := newInstance("secondary", &secondaryUp, &requests)
+	defer secondary.Close()
+
+	clock := &stepClock{now: time.Now()}
+	m := newTestMultiClient(t, clock, []string{primary.URL, secondary.URL}, WithStickyIndexers())
+
+	primaryUp.Store(false)
+	if title := searchTitle(t, m, "indexer-a"); title != "secondary" {
+		t.Fatalf("Expected failover to the secondary instance, got %s", title)
+	}
+
+	// After the primary recovers, indexer-a stays on the secondary
+	primaryUp.Store(true)
+	if errs := m.CheckHealth(context.Background()); errs[0] != nil || errs[1] != nil {
+		t.Fatalf("Expected both instances healthy, got %v", errs)
+	}
+	if title := searchTitle(t, m, "indexer-a"); title != "secondary" {
+		t.Errorf("Expected indexer-a to stick to the secondary instance, got %s", title)
+	}
+	if title := searchTitle(t, m, "indexer-b"); title != "primary" {
+		t.Errorf("Expected indexer-b on the primary instance, got %s", title)
+	}
+}
+
+func TestNewMultiClientRequiresInstances(t *testing.T) {
+	if _, err := NewMultiClient(nil); !errors.Is(err, ErrNoInstances) {
+		t.Errorf("Expected ErrNoInstances, got %v", err)
+	}
+	client, _ := NewClient("http://localhost:9117", "test-api-key")
+	if _, err := NewMultiClient([]*Client{client, nil}); !errors.Is(err, ErrNilInstance) {
+		t.Errorf("Expected ErrNilInstance, got %v", err)
+	}
+}
+
+func TestMultiClientRoundRobin(t *testing.T) {
+	var up atomic.Bool
+	var requests [3]atomic.Int32
+	up.Store(true)
+	var urls []string
+	for i, name := range []string{"a", "b", "c"} {
+		server := newInstance(name, &up, &requests[i])
+		defer server.Close()
+		urls = append(urls, server.URL)
+	}
+
+	m := newTestMultiClient(t, &stepClock{now: time.Now()}, urls, WithBalancing(RoundRobin))
+	var titles []string
+	for i := 0; i < 6; i++ {
+		titles = append(titles, searchTitle(t, m, "all"))
+	}
+	if want := []string{"a", "b", "c", "a", "b", "c"}; !reflect.DeepEqual(titles, want) {
+		t.Errorf("Expected %v, got %v", want, titles)
+	}
+}
+
+func TestMultiClientLeastLatency(t *testing.T) {
+	var up atomic.Bool
+	var requests atomic.Int32
+	up.Store(true)
+	var urls []string
+	for _, name := range []string{"a", "b", "c"} {
+		server := newInstance(name, &up, &requests)
+		defer server.Close()
+		urls = append(urls, server.URL)
+	}
+
+	m := newTestMultiClient(t, &stepClock{now: time.Now()}, urls, WithBalancing(LeastLatency))
+	m.observe(0, 300*time.Millisecond)
+	m.observe(1, 100*time.Millisecond)
+	m.observe(2, 200*time.Millisecond)
+	if title := searchTitle(t, m, "all"); title != "b" {
+		t.Errorf("Expected the fastest instance, got %s", title)
+	}
+
+	// Slow responses raise the average until another instance is preferred
+	m.observe(1, 2*time.Second)
+	if got := m.order(""); !reflect.DeepEqual(got, []int{2, 0, 1}) {
+		t.Errorf("Expected order [2 0 1], got %v", got)
+	}
+}
+
+func TestMultiClientRunDefaultsInterval(t *testing.T) {
+	var up atomic.Bool
+	var requests atomic.Int32
+	up.Store(true)
+	server := newInstance("a", &up, &requests)
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, "test-api-key")
+	m, err := NewMultiClient([]*Client{client})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := m.Run(ctx, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package jackett
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Media types of downloadable releases, as given in torznab enclosures
+const (
+	ContentTypeTorrent = "application/x-bittorrent"
+	ContentTypeNZB     = "application/x-nzb"
+)
+
+// IsUsenet reports whether r is a newznab result from a usenet indexer,
+// whose Link serves an NZB file rather than a torrent. Such results have no
+// seeders or peers; Grabs is their only popularity measure.
+func (r SearchResult) IsUsenet() bool {
+	return strings.EqualFold(r.ContentType, ContentTypeNZB) || r.UsenetDate != ""
+}
+
+// IsNZB reports whether the release is an NZB file
+func (d *DownloadedRelease) IsNZB() bool {
+	return len(d.NZB) > 0
+}
+
+// looksLikeNZB reports whether data is an NZB document, which unlike a
+// bencoded torrent is XML with an <nzb> root element
+func looksLikeNZB(data []byte) bool {
+	rest := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), " \t\r\n")
+	for bytes.HasPrefix(rest, []byte("<?")) || bytes.HasPrefix(rest, []byte("<!")) {
+		end := bytes.IndexByte(rest, '>')
+		if end < 0 {
+			return false
+		}
+		rest = bytes.TrimLeft(rest[end+1:], " \t\r\n")
+	}
+	return bytes.HasPrefix(rest, []byte("<nzb"))
+}
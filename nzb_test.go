@@ -0,0 +1,102 @@
+package jackett
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const newznabFeedXML = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:newznab="http://www.newznab.com/DTD/2010/feeds/attributes/">
+  <channel>
+    <item>
+      <title>Show.Name.S01E02.1080p.WEB.x264</title>
+      <guid>https://usenet.example/details/1</guid>
+      <jackettindexer id="usenet-indexer">Usenet Indexer</jackettindexer>
+      <pubDate>Mon, 01 Jan 2024 12:00:00 +0000</pubDate>
+      <enclosure url="https://usenet.example/getnzb/1" length="2147483648" type="application/x-nzb" />
+      <newznab:attr name="category" value="5040" />
+      <newznab:attr name="grabs" value="317" />
+      <newznab:attr name="usenetdate" value="Sun, 31 Dec 2023 22:30:00 +0000" />
+    </item>
+  </channel>
+</rss>`
+
+const testNZB = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE nzb PUBLIC "-//newzBin//DTD NZB 1.1//EN" "http://www.newzbin.com/DTD/nzb/nzb-1.1.dtd">
+<nzb xmlns="http://www.newzbin.com/DTD/2003/nzb"><file subject="show.rar"/></nzb>`
+
+func TestParseNewznabFeed(t *testing.T) {
+	results, err := parseTorznabFeed([]byte(newznabFeedXML))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	r := results[0]
+	if !r.IsUsenet() || r.ContentType != ContentTypeNZB {
+		t.Errorf("Expected a usenet result, got %+v", r)
+	}
+	if r.UsenetDate != "2023-12-31T22:30:00Z" || r.Link != "https://usenet.example/getnzb/1" || r.Size != 2147483648 {
+		t.Errorf("Unexpected usenet fields %+v", r)
+	}
+	if r.Grabs == nil || *r.Grabs != 317 || r.Seeders != 0 {
+		t.Errorf("Expected grabs-only stats, got grabs %v, seeders %d", r.Grabs, r.Seeders)
+	}
+
+	torrents, _ := parseTorznabFeed([]byte(torznabFeedXML))
+	if torrents[0].IsUsenet() {
+		t.Error("Expected a torrent result not to be usenet")
+	}
+}
+
+func TestLooksLikeNZB(t *testing.T) {
+	tests := []struct {
+		data string
+		want bool
+	}{
+		{testNZB, true},
+		{"\xef\xbb\xbf<nzb></nzb>", true},
+		{testTorrent, false},
+		{`<?xml version="1.0"?><error code="100"/>`, false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := looksLikeNZB([]byte(tt.data)); got != tt.want {
+			t.Errorf("looksLikeNZB(%.20q) = %v, expected %v", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestSaveNZB(t *testing.T) {
+	dir := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentTypeNZB)
+		w.Write([]byte(testNZB))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, "key")
+	result := SearchResult{Title: "Show", Link: server.URL + "/getnzb/1", ContentType: ContentTypeNZB, InfoHash: "0123456789abcdef0123456789abcdef01234567"}
+
+	release, err := result.Fetch(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !release.IsNZB() || release.Torrent != nil || result.Verify(release) != nil {
+		t.Errorf("Expected a verified NZB release, got %+v", release)
+	}
+
+	path, err := client.SaveTorrent(context.Background(), result, dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if path != filepath.Join(dir, "Show.nzb") {
+		t.Errorf("Expected an .nzb file, got %s", path)
+	}
+	if data, _ := os.ReadFile(path); string(data) != testNZB {
+		t.Errorf("Unexpected file contents %q", data)
+	}
+}
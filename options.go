@@ -0,0 +1,103 @@
+package jackett
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option configures a Client created by NewClientWithOptions
+type Option func(*Client)
+
+// NewClientWithOptions initializes a new Jackett client configured by opts.
+// baseURL should be the full URL to the Jackett instance, e.g. "http://localhost:9117"
+//
+// Options are applied in order. Those adjusting the http.Client, such as
+// WithTimeout and WithTransportConfig, copy it rather than modify it, and
+// should come after WithHTTPClient:
+//
+//	client, err := jackett.NewClientWithOptions(url, apiKey,
+//		jackett.WithHTTPClient(httpClient),
+//		jackett.WithTimeout(30*time.Second),
+//		jackett.WithUserAgent("sonarr-helper/1.2"),
+//		jackett.WithRetry(jackett.RetryPolicy{MaxAttempts: 3, Backoff: time.Second}),
+//		jackett.WithCache(jackett.CacheConfig{Metadata: 10 * time.Minute}),
+//	)
+func NewClientWithOptions(baseURL, apiKey string, opts ...Option) (*Client, error) {
+	jClient, err := NewClient(baseURL, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, opt := range opts {
+		opt(jClient)
+	}
+	jClient.opts = opts
+	jClient.applyTLS()
+	if jClient.optionErr != nil {
+		return nil, jClient.optionErr
+	}
+	jClient.warnInsecureTLS()
+
+	return jClient, nil
+}
+
+// WithHTTPClient sets the http.Client used for requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.client = httpClient
+		}
+	}
+}
+
+// WithClock sets the Clock used for all time-based behavior. It is mainly
+// useful in tests together with jacketttest.FakeClock.
+func WithClock(clock Clock) Option {
+	return func(c *Client) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// WithMaxDownloadSize limits the size of torrent downloads (16 MiB by
+// default). A limit of zero or less disables the check.
+func WithMaxDownloadSize(n int64) Option {
+	return func(c *Client) {
+		c.maxDownloadSize = n
+	}
+}
+
+// WithDownloadRetry retries torrent downloads that fail transiently: network
+// errors, 429 Too Many Requests and 5xx responses. Retry-After headers are
+// honored. By default downloads are not retried.
+func WithDownloadRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.downloadRetry = policy
+	}
+}
+
+// WithTimeout limits the time of each HTTP request, including reading the
+// response body. The http.Client is copied, not modified.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		hc := *c.client
+		hc.Timeout = d
+		c.client = &hc
+	}
+}
+
+// WithUserAgent sets the User-Agent header of all requests, so Jackett's
+// logs and reverse proxies can tell which application is calling
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// setUserAgent applies the WithUserAgent setting to req
+func (c *Client) setUserAgent(req *http.Request) {
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+}
@@ -0,0 +1,69 @@
+package jackett
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithTimeout(t *testing.T) {
+	httpClient := &http.Client{}
+	client, err := NewClientWithOptions("http://localhost:9117", "key", WithHTTPClient(httpClient), WithTimeout(5*time.Second))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if client.client.Timeout != 5*time.Second {
+		t.Errorf("Expected a 5s timeout, got %v", client.client.Timeout)
+	}
+	if httpClient.Timeout != 0 {
+		t.Errorf("Expected the caller's http.Client to be left alone, got %v", httpClient.Timeout)
+	}
+}
+
+func TestWithUserAgent(t *testing.T) {
+	var mu sync.Mutex
+	agents := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		agents[r.URL.Path] = r.UserAgent()
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/api/v2.0/indexers/all/results":
+			w.Write([]byte(hookSearchJSON))
+		default:
+			w.Write([]byte(testTorrent))
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClientWithOptions(server.URL, "key", WithUserAgent("sonarr-helper/1.2"))
+	if _, err := client.Search("ubuntu"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.DownloadTorrent(server.URL + "/dl/a/"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, path := range []string{"/api/v2.0/indexers/all/results", "/dl/a/"} {
+		if agents[path] != "sonarr-helper/1.2" {
+			t.Errorf("Expected the User-Agent on %s, got %q", path, agents[path])
+		}
+	}
+}
+
+func TestWithCache(t *testing.T) {
+	client, _ := NewClientWithOptions("http://localhost:9117", "key", WithCache(CacheConfig{Metadata: time.Minute}))
+	if client.cache == nil || client.cache.ttl != time.Minute {
+		t.Errorf("Expected a metadata cache, got %+v", client.cache)
+	}
+	if client.searchCache != nil {
+		t.Error("Expected no search cache")
+	}
+
+	client, _ = NewClientWithOptions("http://localhost:9117", "key", WithCache(CacheConfig{Search: 30 * time.Second}))
+	if client.cache != nil || client.searchCache == nil || client.searchCache.ttl != 30*time.Second {
+		t.Errorf("Expected only a search cache, got %+v, %+v", client.cache, client.searchCache)
+	}
+}
@@ -0,0 +1,45 @@
+package jackett
+
+import (
+	"sort"
+	"time"
+)
+
+// publishDateLayouts are the forms PublishDate takes: RFC 3339 from Jackett,
+// Prowlarr and parsed torznab feeds, and sometimes no zone from older Jackett
+var publishDateLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"}
+
+// PublishTime parses PublishDate, the zero time if it is missing or
+// malformed. Dates without a zone are taken as UTC.
+func (r SearchResult) PublishTime() time.Time {
+	for _, layout := range publishDateLayouts {
+		if t, err := time.Parse(layout, r.PublishDate); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// SortByPublishDate sorts results newest first, then by GUID, with results
+// lacking a publish date last. Equal results keep their order, so results
+// merged from concurrent searches always come out the same way.
+func SortByPublishDate(results []SearchResult) {
+	type dated struct {
+		result SearchResult
+		time   time.Time
+	}
+	sorted := make([]dated, len(results))
+	for i, r := range results {
+		sorted[i] = dated{r, r.PublishTime()}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if !a.time.Equal(b.time) {
+			return a.time.After(b.time)
+		}
+		return a.result.GUID < b.result.GUID
+	})
+	for i := range sorted {
+		results[i] = sorted[i].result
+	}
+}
@@ -0,0 +1,80 @@
+package jackett
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPublishTime(t *testing.T) {
+	want := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	for _, date := range []string{"2024-03-01T12:30:00Z", "2024-03-01T13:30:00+01:00", "2024-03-01T12:30:00.000Z", "2024-03-01T12:30:00"} {
+		if got := (SearchResult{PublishDate: date}).PublishTime(); !got.Equal(want) {
+			t.Errorf("PublishTime(%q) = %v, want %v", date, got, want)
+		}
+	}
+	for _, date := range []string{"", "yesterday", "Fri, 01 Mar 2024 12:30:00 +0000"} {
+		if got := (SearchResult{PublishDate: date}).PublishTime(); !got.IsZero() {
+			t.Errorf("PublishTime(%q) = %v, want the zero time", date, got)
+		}
+	}
+}
+
+func TestSortByPublishDate(t *testing.T) {
+	results := []SearchResult{
+		{Title: "undated", GUID: "a"},
+		{Title: "old", GUID: "z", PublishDate: "2024-01-01T00:00:00Z"},
+		{Title: "new b", GUID: "b", PublishDate: "2024-02-01T01:00:00+01:00"},
+		{Title: "new a", GUID: "a", PublishDate: "2024-02-01T00:00:00Z"},
+		{Title: "undated first", GUID: ""},
+	}
+	SortByPublishDate(results)
+
+	var titles []string
+	for _, r := range results {
+		titles = append(titles, r.Title)
+	}
+	want := []string{"new a", "new b", "old", "undated first", "undated"}
+	if !reflect.DeepEqual(titles, want) {
+		t.Errorf("Expected %v, got %v", want, titles)
+	}
+}
+
+func TestSearchPlan_ExecuteOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.Split(r.URL.Path, "/")[4]
+		if id == "slow" {
+			time.Sleep(20 * time.Millisecond)
+		}
+		fmt.Fprintf(w, `<rss><channel>
+			<item><title>%[1]s old</title><guid>%[1]s-old</guid><pubDate>Mon, 01 Jan 2024 00:00:00 +0000</pubDate></item>
+			<item><title>%[1]s new</title><guid>%[1]s-new</guid><pubDate>Thu, 01 Feb 2024 00:00:00 +0000</pubDate></item>
+		</channel></rss>`, id)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, "test-api-key")
+	want := []string{"fast new", "slow new", "fast old", "slow old"}
+	for _, ids := range [][]string{{"slow", "fast"}, {"fast", "slow"}} {
+		plan := &SearchPlan{}
+		for _, id := range ids {
+			plan.Steps = append(plan.Steps, PlanStep{IndexerID: id, Mode: SearchModeGeneral, Pages: 1})
+		}
+		response, err := plan.Execute(context.Background(), client)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		var titles []string
+		for _, r := range response.Results {
+			titles = append(titles, r.Title)
+		}
+		if !reflect.DeepEqual(titles, want) {
+			t.Errorf("Steps %v: expected %v, got %v", ids, want, titles)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package jackett
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrAllIndexersFailed is returned when every indexer in an aggregate search
+// failed. The error also wraps an IndexerError for each indexer.
+var ErrAllIndexersFailed = errors.New("jackett: all indexers failed")
+
+// IndexerError is the failure of one indexer in an aggregate search
+type IndexerError struct {
+	ID      string
+	Name    string
+	Message string
+}
+
+func (e *IndexerError) Error() string {
+	return e.ID + ": " + e.Message
+}
+
+// PartialError is returned together with the results of an aggregate search
+// in which some indexers failed but at least one succeeded. The results are
+// usable; Failed lists the indexers that are missing from them.
+type PartialError struct {
+	Failed []IndexerError
+	// Succeeded is the number of indexers that answered
+	Succeeded int
+}
+
+func (e *PartialError) Error() string {
+	failures := make([]string, len(e.Failed))
+	for i := range e.Failed {
+		failures[i] = e.Failed[i].Error()
+	}
+	return fmt.Sprintf("%d of %d indexers failed: %s", len(e.Failed), len(e.Failed)+e.Succeeded, strings.Join(failures, "; "))
+}
+
+// Unwrap returns the individual indexer failures
+func (e *PartialError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i := range e.Failed {
+		errs[i] = &e.Failed[i]
+	}
+	return errs
+}
+
+// indexerFailures inspects the per-indexer statuses of a search: it returns
+// nil if none failed, a *PartialError if some failed, and an error wrapping
+// ErrAllIndexersFailed if all did
+func indexerFailures(statuses []IndexerStatus) error {
+	var failed []IndexerError
+	for _, status := range statuses {
+		if status.Status == IndexerStatusError {
+			failed = append(failed, IndexerError{ID: status.ID, Name: status.Name, Message: status.Error})
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+
+	partial := &PartialError{Failed: failed, Succeeded: len(statuses) - len(failed)}
+	if partial.Succeeded > 0 {
+		return partial
+	}
+	return fmt.Errorf("%w: %w", ErrAllIndexersFailed, errors.Join(partial.Unwrap()...))
+}
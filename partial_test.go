@@ -0,0 +1,93 @@
+package jackett_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/jackett"
+	"github.com/cehbz/jackett/jacketttest"
+)
+
+// newSearchFake serves indexer A with a result and the given indexers
+// failing with their messages, keyed by name
+func newSearchFake(t *testing.T, failing ...[2]string) *jacketttest.Server {
+	server := jacketttest.NewServer()
+	t.Cleanup(server.Close)
+
+	server.AddIndexer(jackett.Indexer{ID: "a", Name: "A"}, jackett.SearchResult{Title: "Ubuntu 22.04"})
+	for _, f := range failing {
+		server.AddIndexer(jackett.Indexer{ID: strings.ToLower(f[0]), Name: f[0]})
+		server.FailIndexer(strings.ToLower(f[0]), f[1])
+	}
+	return server
+}
+
+func TestSearchPartialError(t *testing.T) {
+	server := newSearchFake(t, [2]string{"B", "Cloudflare challenge"}, [2]string{"C", "Login failed"})
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response, err := client.Search("ubuntu")
+	var partial *jackett.PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected a PartialError, got %v", err)
+	}
+	if response == nil || len(response.Results) != 1 {
+		t.Fatalf("Expected the successful indexer's result alongside the error, got %+v", response)
+	}
+	if partial.Succeeded != 1 || len(partial.Failed) != 2 {
+		t.Errorf("Expected 1 success and 2 failures, got %+v", partial)
+	}
+	if partial.Failed[0].ID != "b" || partial.Failed[0].Message != "Cloudflare challenge" {
+		t.Errorf("Expected b to fail with its message, got %+v", partial.Failed[0])
+	}
+	if !strings.Contains(err.Error(), "2 of 3 indexers failed") {
+		t.Errorf("Expected a summary in the message, got %q", err.Error())
+	}
+
+	var indexerErr *jackett.IndexerError
+	if !errors.As(err, &indexerErr) || indexerErr.ID != "b" {
+		t.Errorf("Expected the first IndexerError to be reachable with errors.As, got %v", indexerErr)
+	}
+	if errors.Is(err, jackett.ErrAllIndexersFailed) {
+		t.Error("Expected a partial failure not to match jackett.ErrAllIndexersFailed")
+	}
+}
+
+func TestSearchAllIndexersFailed(t *testing.T) {
+	server := newSearchFake(t, [2]string{"B", "Cloudflare challenge"})
+	server.FailIndexer("a", "Cloudflare challenge")
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response, err := client.Search("ubuntu")
+	if !errors.Is(err, jackett.ErrAllIndexersFailed) {
+		t.Fatalf("Expected jackett.ErrAllIndexersFailed, got %v", err)
+	}
+	if response != nil {
+		t.Errorf("Expected no response on total failure, got %+v", response)
+	}
+	var partial *jackett.PartialError
+	if errors.As(err, &partial) {
+		t.Error("Expected a total failure not to be a PartialError")
+	}
+	if !strings.Contains(err.Error(), "Cloudflare challenge") {
+		t.Errorf("Expected the indexer's message in the error, got %q", err.Error())
+	}
+}
+
+func TestSearchNoFailures(t *testing.T) {
+	server := newSearchFake(t)
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.Search("ubuntu"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package jackett
+
+import (
+	"context"
+	"time"
+)
+
+// ServerInfo summarizes a reachable Jackett instance
+type ServerInfo struct {
+	AppVersion string `json:"app_version"`
+	// Latency is the round trip of the server config request
+	Latency time.Duration `json:"latency"`
+	// Indexers is the number of configured indexers
+	Indexers int `json:"indexers"`
+}
+
+// Ping checks that Jackett is reachable and the credentials are accepted,
+// returning the server version, API latency and configured indexer count.
+// It is suitable for health dashboards and readiness probes.
+func (c *Client) Ping(ctx context.Context) (*ServerInfo, error) {
+	// Bypass the metadata cache so the latency is real
+	start := c.clock.Now()
+	respData, err := c.fetchServerConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	latency := c.clock.Now().Sub(start)
+
+	config, err := decodeServerConfig(respData)
+	if err != nil {
+		return nil, err
+	}
+
+	indexers, err := c.GetIndexersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ServerInfo{Latency: latency, Indexers: len(indexers)}
+	info.AppVersion, _ = config["app_version"].(string)
+	return info, nil
+}
@@ -0,0 +1,60 @@
+package jackett
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestPing(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2.0/server/config":                {statusCode: http.StatusOK, responseBody: `{"app_version": "0.22.100"}`},
+		"/api/v2.0/indexers/all/results/torznab": {statusCode: http.StatusOK, responseBody: allIndexersXML},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "GET", url: "/api/v2.0/server/config"},
+		{method: "GET", url: "/api/v2.0/indexers/all/results/torznab"},
+	}
+
+	client, mockTransport, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	info, err := client.Ping(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if info.AppVersion != "0.22.100" {
+		t.Errorf("Expected app version '0.22.100', got '%s'", info.AppVersion)
+	}
+	if info.Indexers != 2 {
+		t.Errorf("Expected 2 indexers, got %d", info.Indexers)
+	}
+	if info.Latency <= 0 {
+		t.Errorf("Expected positive latency, got %v", info.Latency)
+	}
+
+	if mockTransport.requestIndex != len(mockTransport.expectedRequests) {
+		t.Errorf("Not all expected requests were made")
+	}
+}
+
+func TestPing_Unreachable(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2.0/server/config": {statusCode: http.StatusBadGateway, responseBody: "bad gateway"},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "GET", url: "/api/v2.0/server/config"},
+	}
+
+	client, _, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.Ping(context.Background()); err == nil {
+		t.Fatal("Expected error, got none")
+	}
+}
@@ -0,0 +1,325 @@
+package jackett
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SearchMode is a torznab search function
+type SearchMode string
+
+const (
+	SearchModeGeneral SearchMode = "search"
+	SearchModeTV      SearchMode = "tvsearch"
+	SearchModeMovie   SearchMode = "movie"
+	SearchModeMusic   SearchMode = "music"
+	SearchModeBook    SearchMode = "book"
+)
+
+// SearchRequest is a structured search. Only the fields relevant to Mode are
+// used; identifiers an indexer doesn't support are dropped during planning.
+type SearchRequest struct {
+	Mode       SearchMode `json:"mode,omitempty"` // SearchModeGeneral if empty
+	Query      string     `json:"query,omitempty"`
+	Categories []int      `json:"categories,omitempty"`
+
+	IMDbID   string `json:"imdb_id,omitempty"` // "tt0133093", with or without the "tt"
+	TMDbID   int    `json:"tmdb_id,omitempty"`
+	TVDBID   int    `json:"tvdb_id,omitempty"`
+	TVMazeID int    `json:"tvmaze_id,omitempty"`
+	Season   int    `json:"season,omitempty"`
+	Episode  int    `json:"episode,omitempty"`
+	Year     int    `json:"year,omitempty"`
+	Artist   string `json:"artist,omitempty"`
+	Album    string `json:"album,omitempty"`
+	Author   string `json:"author,omitempty"`
+	Title    string `json:"title,omitempty"`
+
+	// Limit is the page size, clamped to each indexer's maximum; the
+	// indexer default if zero
+	Limit int `json:"limit,omitempty"`
+	// MaxResults per indexer, fetched across pages; one page if zero
+	MaxResults int `json:"max_results,omitempty"`
+}
+
+// params returns every torznab parameter the request sets for its mode
+func (r SearchRequest) params() url.Values {
+	p := url.Values{}
+	set := func(key, value string) {
+		if value != "" && value != "0" {
+			p.Set(key, value)
+		}
+	}
+
+	imdbID := r.IMDbID
+	if id, err := NormalizeIMDbID(imdbID); err == nil {
+		imdbID = id
+	}
+
+	set("q", r.Query)
+	switch r.mode() {
+	case SearchModeTV:
+		set("imdbid", imdbID)
+		set("tmdbid", strconv.Itoa(r.TMDbID))
+		set("tvdbid", strconv.Itoa(r.TVDBID))
+		set("tvmazeid", strconv.Itoa(r.TVMazeID))
+		set("season", strconv.Itoa(r.Season))
+		set("ep", strconv.Itoa(r.Episode))
+		set("year", strconv.Itoa(r.Year))
+	case SearchModeMovie:
+		set("imdbid", imdbID)
+		set("tmdbid", strconv.Itoa(r.TMDbID))
+		set("year", strconv.Itoa(r.Year))
+	case SearchModeMusic:
+		set("artist", r.Artist)
+		set("album", r.Album)
+		set("year", strconv.Itoa(r.Year))
+	case SearchModeBook:
+		set("author", r.Author)
+		set("title", r.Title)
+		set("year", strconv.Itoa(r.Year))
+	}
+	return p
+}
+
+func (r SearchRequest) mode() SearchMode {
+	if r.Mode == "" {
+		return SearchModeGeneral
+	}
+	return r.Mode
+}
+
+// fallbackQuery folds the structured fields into a free-text query for
+// indexers that only support generic search
+func (r SearchRequest) fallbackQuery() string {
+	parts := []string{r.Query}
+	switch r.mode() {
+	case SearchModeTV:
+		switch {
+		case r.Season > 0 && r.Episode > 0:
+			parts = append(parts, fmt.Sprintf("S%02dE%02d", r.Season, r.Episode))
+		case r.Season > 0:
+			parts = append(parts, fmt.Sprintf("S%02d", r.Season))
+		}
+	case SearchModeMovie:
+		if r.Year > 0 {
+			parts = append(parts, strconv.Itoa(r.Year))
+		}
+	case SearchModeMusic:
+		parts = append(parts, r.Artist, r.Album)
+	case SearchModeBook:
+		parts = append(parts, r.Author, r.Title)
+	}
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+}
+
+// PlanStep is the work planned for one indexer
+type PlanStep struct {
+	IndexerID   string     `json:"indexer_id"`
+	IndexerName string     `json:"indexer_name"`
+	Mode        SearchMode `json:"mode"`
+	// Params are the torznab parameters sent, excluding paging and API key
+	Params url.Values `json:"params"`
+	Limit  int        `json:"limit"`
+	Pages  int        `json:"pages"`
+}
+
+// SearchPlan describes how a structured search will be executed. Plans are
+// plain data: they can be inspected, logged, cached, and executed later.
+type SearchPlan struct {
+	Request SearchRequest `json:"request"`
+	Steps   []PlanStep    `json:"steps"`
+	// Skipped maps indexer IDs that can't serve the request to the reason
+	Skipped map[string]string `json:"skipped,omitempty"`
+}
+
+// PlanSearch decides, from the capabilities of each indexer, which indexers
+// to query with which torznab function and parameters, and how many pages to
+// fetch from each
+func PlanSearch(req SearchRequest, indexers []Indexer) *SearchPlan {
+	plan := &SearchPlan{Request: req, Skipped: make(map[string]string)}
+	for _, indexer := range indexers {
+		step, reason := planStep(req, indexer)
+		if reason != "" {
+			plan.Skipped[indexer.ID] = reason
+			continue
+		}
+		plan.Steps = append(plan.Steps, step)
+	}
+	return plan
+}
+
+func planStep(req SearchRequest, indexer Indexer) (PlanStep, string) {
+	step := PlanStep{IndexerID: indexer.ID, IndexerName: indexer.Name, Mode: req.mode()}
+
+	supported, ok := supportedParams(indexer.Caps, step.Mode)
+	if !ok && step.Mode != SearchModeGeneral {
+		step.Mode = SearchModeGeneral
+		supported, ok = supportedParams(indexer.Caps, step.Mode)
+	}
+	if !ok {
+		return step, "search not available"
+	}
+
+	step.Params = url.Values{}
+	if step.Mode == req.mode() {
+		for key, values := range req.params() {
+			if key == "q" || supported[key] {
+				step.Params[key] = values
+			}
+		}
+	} else if q := req.fallbackQuery(); q != "" {
+		step.Params.Set("q", q)
+	}
+	if len(step.Params) == 0 && req.mode() != SearchModeGeneral {
+		return step, "no supported search parameters"
+	}
+
+	if len(req.Categories) > 0 {
+		cats := matchingCategories(req.Categories, indexer.Categories)
+		if len(cats) == 0 {
+			return step, "no matching categories"
+		}
+		step.Params.Set("cat", joinInts(cats))
+	}
+
+	step.Limit, step.Pages = pageCount(req, indexer.Caps)
+	return step, ""
+}
+
+// supportedParams returns the parameters the indexer accepts for mode, or
+// false if the mode isn't available
+func supportedParams(caps *Caps, mode SearchMode) (map[string]bool, bool) {
+	if caps == nil {
+		// Nothing known; assume plain search works
+		return map[string]bool{"q": true}, mode == SearchModeGeneral
+	}
+
+	st := caps.SearchType(mode)
+	if !st.IsAvailable() {
+		return nil, false
+	}
+
+	params := make(map[string]bool)
+	for _, p := range st.Params() {
+		params[p] = true
+	}
+	return params, true
+}
+
+// matchingCategories keeps the wanted categories the indexer offers, either
+// exactly or through a category in the same top-level group
+func matchingCategories(wanted []int, available []Category) []int {
+	if len(available) == 0 {
+		return wanted // capabilities unknown
+	}
+
+	offered := make(map[int]bool)
+	groups := make(map[int]bool)
+	for _, cat := range flattenCategories(available) {
+		offered[cat.ID] = true
+		groups[cat.ID/1000] = true
+	}
+
+	var matched []int
+	for _, id := range wanted {
+		if offered[id] || (id < 100000 && groups[id/1000]) {
+			matched = append(matched, id)
+		}
+	}
+	return matched
+}
+
+// pageCount picks the page size within the indexer's limits and how many
+// pages cover MaxResults
+func pageCount(req SearchRequest, caps *Caps) (limit, pages int) {
+	limit = req.Limit
+	if caps != nil {
+		limit = caps.Limits.Clamp(limit)
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+
+	pages = 1
+	if req.MaxResults > limit {
+		pages = (req.MaxResults + limit - 1) / limit
+	}
+	return limit, pages
+}
+
+func joinInts(ids []int) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = strconv.Itoa(id)
+	}
+	return strings.Join(s, ",")
+}
+
+// Execute runs every step of the plan concurrently, within the client's
+// WithMaxConcurrentRequests budget, and merges the results, newest first
+// (see SortByPublishDate) however the searches finished.
+// Each indexer's outcome is reported in the response's Indexers list. If
+// some steps failed the error is a *PartialError; if all of them did, it
+// wraps ErrAllIndexersFailed.
+func (p *SearchPlan) Execute(ctx context.Context, c *Client) (*SearchResponse, error) {
+	statuses := make([]IndexerStatus, len(p.Steps))
+	results := make([][]SearchResult, len(p.Steps))
+
+	var wg sync.WaitGroup
+	for i, step := range p.Steps {
+		wg.Add(1)
+		go func(i int, step PlanStep) {
+			defer wg.Done()
+			results[i], statuses[i] = c.executeStep(ctx, step, p.Request.MaxResults)
+		}(i, step)
+	}
+	wg.Wait()
+
+	response := &SearchResponse{Indexers: statuses}
+	for i := range statuses {
+		response.Results = append(response.Results, results[i]...)
+	}
+	SortByPublishDate(response.Results)
+	return response, indexerFailures(statuses)
+}
+
+// executeStep fetches the pages of a single step, stopping early when an
+// indexer runs out of results
+func (c *Client) executeStep(ctx context.Context, step PlanStep, maxResults int) ([]SearchResult, IndexerStatus) {
+	status := IndexerStatus{ID: step.IndexerID, Name: step.IndexerName, Status: IndexerStatusOK}
+
+	var results []SearchResult
+	for page := 0; page < step.Pages; page++ {
+		params := url.Values{}
+		for k, v := range step.Params {
+			params[k] = v
+		}
+		params.Set("t", string(step.Mode))
+		params.Set("limit", strconv.Itoa(step.Limit))
+		if page > 0 {
+			params.Set("offset", strconv.Itoa(page*step.Limit))
+		}
+
+		response, err := c.TorznabSearch(ctx, step.IndexerID, params)
+		if err != nil {
+			status.Status = IndexerStatusError
+			status.Error = err.Error()
+			break
+		}
+		results = append(results, response.Results...)
+		if len(response.Results) < step.Limit {
+			break
+		}
+	}
+
+	if maxResults > 0 && len(results) > maxResults {
+		results = results[:maxResults]
+	}
+	status.Results = int64(len(results))
+	return results, status
+}
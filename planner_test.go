@@ -0,0 +1,132 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func plannerIndexers() []Indexer {
+	return []Indexer{
+		{
+			ID: "tv-full", Name: "TV Full",
+			Caps: &Caps{
+				Limits: Limits{Default: 50, Max: 100},
+				Searching: Searching{
+					Search:   &SearchType{Available: true, SupportedParams: []string{"q"}},
+					TVSearch: &SearchType{Available: true, SupportedParams: []string{"q", "season", "ep", "tvdbid"}},
+				},
+			},
+			Categories: []Category{{ID: 5000, Name: "TV", Subcats: []Subcat{{ID: 5040, Name: "TV/HD"}}}},
+		},
+		{
+			ID: "search-only", Name: "Search Only",
+			Caps: &Caps{
+				Limits:    Limits{Default: 100, Max: 100},
+				Searching: Searching{Search: &SearchType{Available: true, SupportedParams: []string{"q"}}, TVSearch: &SearchType{Available: false}},
+			},
+			Categories: []Category{{ID: 5000, Name: "TV"}},
+		},
+		{
+			ID: "movies", Name: "Movies",
+			Caps: &Caps{
+				Searching: Searching{TVSearch: &SearchType{Available: true, SupportedParams: []string{"q"}}},
+			},
+			Categories: []Category{{ID: 2000, Name: "Movies"}},
+		},
+	}
+}
+
+func TestPlanSearch(t *testing.T) {
+	req := SearchRequest{
+		Mode: SearchModeTV, Query: "Show Name", TVDBID: 121361, IMDbID: "tt0944947",
+		Season: 1, Episode: 2, Categories: []int{5040}, MaxResults: 150,
+	}
+
+	plan := PlanSearch(req, plannerIndexers())
+
+	if len(plan.Steps) != 2 {
+		t.Fatalf("Expected 2 steps, got %d", len(plan.Steps))
+	}
+
+	full := plan.Steps[0]
+	if full.Mode != SearchModeTV {
+		t.Errorf("Expected tvsearch for tv-full, got %s", full.Mode)
+	}
+	if full.Params.Get("tvdbid") != "121361" || full.Params.Get("ep") != "2" {
+		t.Errorf("Expected supported IDs to be kept, got %v", full.Params)
+	}
+	if full.Params.Has("imdbid") {
+		t.Errorf("Expected unsupported imdbid to be dropped, got %v", full.Params)
+	}
+	if full.Limit != 50 || full.Pages != 3 {
+		t.Errorf("Expected 3 pages of 50, got %d pages of %d", full.Pages, full.Limit)
+	}
+
+	fallback := plan.Steps[1]
+	if fallback.Mode != SearchModeGeneral || fallback.Params.Get("q") != "Show Name S01E02" {
+		t.Errorf("Expected generic fallback query, got %s %v", fallback.Mode, fallback.Params)
+	}
+	if fallback.Params.Get("cat") != "5040" {
+		t.Errorf("Expected category to match via its group, got '%s'", fallback.Params.Get("cat"))
+	}
+
+	if plan.Skipped["movies"] != "no matching categories" {
+		t.Errorf("Expected movies to be skipped for categories, got %v", plan.Skipped)
+	}
+}
+
+func TestSearchPlan_Execute(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/api/v2.0/indexers/tv-full/results/torznab/api":
+			// Two full pages of 2, then a short one
+			offset, _ := strconv.Atoi(q.Get("offset"))
+			count := 2
+			if offset >= 4 {
+				count = 1
+			}
+			var items strings.Builder
+			for i := 0; i < count; i++ {
+				fmt.Fprintf(&items, `<item><title>Show S01E02 %d</title><jackettindexer id="tv-full">TV Full</jackettindexer></item>`, offset+i)
+			}
+			fmt.Fprintf(w, `<rss><channel>%s</channel></rss>`, items.String())
+		case "/api/v2.0/indexers/search-only/results/torznab/api":
+			w.Write([]byte(`<error code="900" description="Tracker down" />`))
+		default:
+			t.Errorf("Unexpected request: %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, "test-api-key")
+	plan := &SearchPlan{Steps: []PlanStep{
+		{IndexerID: "tv-full", Mode: SearchModeTV, Limit: 2, Pages: 5},
+		{IndexerID: "search-only", Mode: SearchModeGeneral, Limit: 100, Pages: 1},
+	}}
+
+	response, err := plan.Execute(context.Background(), client)
+	var partial *PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected a PartialError, got %v", err)
+	}
+	if partial.Succeeded != 1 || len(partial.Failed) != 1 || partial.Failed[0].ID != "search-only" {
+		t.Errorf("Expected search-only to be the only failure, got %+v", partial)
+	}
+
+	if len(response.Results) != 5 {
+		t.Errorf("Expected 5 results across pages, got %d", len(response.Results))
+	}
+	if response.Indexers[0].Status != IndexerStatusOK || response.Indexers[0].Results != 5 {
+		t.Errorf("Expected tv-full OK with 5 results, got %+v", response.Indexers[0])
+	}
+	if response.Indexers[1].Status != IndexerStatusError || !strings.Contains(response.Indexers[1].Error, "Tracker down") {
+		t.Errorf("Expected search-only error, got %+v", response.Indexers[1])
+	}
+}
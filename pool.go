@@ -0,0 +1,70 @@
+package jackett
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"sync"
+)
+
+// Pools of buffers and decompressors reused across requests, which cuts
+// allocations considerably for daemons polling Jackett at high frequency
+
+// maxPooledBufferSize keeps buffers that grew for an unusually large response
+// from being retained by the pool
+const maxPooledBufferSize = 4 << 20
+
+var readBufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+var copyBufferPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 32*1024)
+		return &buf
+	},
+}
+
+var gzipReaderPool sync.Pool
+
+// readAll is like io.ReadAll but reads through a pooled buffer, so only the
+// returned slice is allocated once the pool is warm
+func readAll(r io.Reader) ([]byte, error) {
+	buf := readBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBufferSize {
+			readBufferPool.Put(buf)
+		}
+	}()
+
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), buf.Bytes()...), nil
+}
+
+// copyPooled is like io.Copy but uses a pooled copy buffer
+func copyPooled(w io.Writer, r io.Reader) (int64, error) {
+	buf := copyBufferPool.Get().(*[]byte)
+	defer copyBufferPool.Put(buf)
+
+	return io.CopyBuffer(w, r, *buf)
+}
+
+// getGzipReader returns a pooled gzip.Reader reading from r; release it with
+// putGzipReader once done
+func getGzipReader(r io.Reader) (*gzip.Reader, error) {
+	if gz, ok := gzipReaderPool.Get().(*gzip.Reader); ok {
+		if err := gz.Reset(r); err != nil {
+			gzipReaderPool.Put(gz)
+			return nil, err
+		}
+		return gz, nil
+	}
+	return gzip.NewReader(r)
+}
+
+func putGzipReader(gz *gzip.Reader) {
+	gzipReaderPool.Put(gz)
+}
@@ -0,0 +1,141 @@
+package jackett
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+)
+
+func TestReadAllReturnsIndependentSlices(t *testing.T) {
+	first, err := readAll(bytes.NewReader([]byte("first response")))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	second, err := readAll(bytes.NewReader([]byte("second")))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if string(first) != "first response" {
+		t.Errorf("Expected first response to survive buffer reuse, got %q", first)
+	}
+	if string(second) != "second" {
+		t.Errorf("Expected %q, got %q", "second", second)
+	}
+}
+
+func TestPooledGzipReaderReuse(t *testing.T) {
+	for _, want := range []string{"one", "two"} {
+		gz, err := getGzipReader(bytes.NewReader(gzipBytes(t, want)))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		got, err := io.ReadAll(gz)
+		putGzipReader(gz)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if string(got) != want {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+	}
+}
+
+func gzipBytes(tb testing.TB, s string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(s)); err != nil {
+		tb.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		tb.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// The benchmarks below compare the pooled helpers with their unpooled
+// equivalents on payloads typical of polling: a mid-sized search response
+// and a torrent download
+
+func BenchmarkReadAll_Pooled(b *testing.B) {
+	data := largeSearchJSON(500)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := readAll(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadAll_Unpooled(b *testing.B) {
+	data := largeSearchJSON(500)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := io.ReadAll(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// onlyWriter hides any ReaderFrom implementation, as for a file wrapped by
+// io.MultiWriter, so the copy goes through a buffer
+type onlyWriter struct{ io.Writer }
+
+// onlyReader likewise hides any WriterTo implementation
+type onlyReader struct{ io.Reader }
+
+func BenchmarkCopy_Pooled(b *testing.B) {
+	data := bytes.Repeat([]byte{'x'}, 256*1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := copyPooled(onlyWriter{io.Discard}, onlyReader{bytes.NewReader(data)}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCopy_Unpooled(b *testing.B) {
+	data := bytes.Repeat([]byte{'x'}, 256*1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := io.Copy(onlyWriter{io.Discard}, onlyReader{bytes.NewReader(data)}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGzipReader_Pooled(b *testing.B) {
+	data := gzipBytes(b, string(largeSearchJSON(500)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gz, err := getGzipReader(bytes.NewReader(data))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := io.Copy(io.Discard, gz); err != nil {
+			b.Fatal(err)
+		}
+		putGzipReader(gz)
+	}
+}
+
+func BenchmarkGzipReader_Unpooled(b *testing.B) {
+	data := gzipBytes(b, string(largeSearchJSON(500)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := io.Copy(io.Discard, gz); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
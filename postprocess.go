@@ -0,0 +1,52 @@
+package jackett
+
+// PostProcessor transforms a search response before the client returns it,
+// e.g. to normalize titles, remap categories or tag results for scoring. It
+// may modify the response and its results in place.
+type PostProcessor func(*SearchResponse)
+
+// WithPostProcessor makes the client run p on the response of every search,
+// including raw torznab queries and searches with a ResultHook. Responses
+// are processed once, when they arrive from Jackett, so cached and shared
+// responses are returned already processed. Post-processors from several
+// WithPostProcessor options run in order.
+func WithPostProcessor(p PostProcessor) Option {
+	return func(c *Client) {
+		c.postProcessors = append(c.postProcessors, p)
+	}
+}
+
+// ProcessResults returns a PostProcessor running hook on each result, which
+// it may modify or drop by returning false
+func ProcessResults(hook ResultHook) PostProcessor {
+	return func(response *SearchResponse) {
+		kept := response.Results[:0]
+		for i := range response.Results {
+			if hook(&response.Results[i]) {
+				kept = append(kept, response.Results[i])
+			}
+		}
+		response.Results = kept
+	}
+}
+
+// RemapCategories returns a PostProcessor replacing the categories of each
+// result according to mapping, e.g. an indexer's custom category IDs by the
+// standard ones. Categories not in mapping are kept.
+func RemapCategories(mapping map[int]int) PostProcessor {
+	return ProcessResults(func(r *SearchResult) bool {
+		for i, cat := range r.Category {
+			if to, ok := mapping[cat]; ok {
+				r.Category[i] = to
+			}
+		}
+		return true
+	})
+}
+
+// postProcess runs the client's post-processors on response
+func (c *Client) postProcess(response *SearchResponse) {
+	for _, p := range c.postProcessors {
+		p(response)
+	}
+}
@@ -0,0 +1,68 @@
+package jackett
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithPostProcessor(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if strings.HasSuffix(r.URL.Path, "/torznab/api") {
+			w.Write([]byte(torznabFeedXML))
+			return
+		}
+		w.Write([]byte(`{"Results": [
+			{"Title": "  ubuntu 24.04 ", "Category": [100001, 4000], "Seeders": 10},
+			{"Title": "dead", "Category": [4000], "Seeders": 0}
+		], "Indexers": [{"ID": "one", "Status": 2, "Results": 2}]}`))
+	}))
+	defer server.Close()
+
+	calls := 0
+	client, _ := NewClientWithOptions(server.URL, "key",
+		WithCache(CacheConfig{Search: time.Minute}),
+		WithPostProcessor(func(response *SearchResponse) { calls++ }),
+		WithPostProcessor(RemapCategories(map[int]int{100001: 4050})),
+		WithPostProcessor(ProcessResults(func(r *SearchResult) bool {
+			r.Title = strings.TrimSpace(r.Title)
+			return r.Seeders > 0
+		})),
+	)
+
+	for i := 0; i < 2; i++ {
+		response, err := client.Search("ubuntu")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(response.Results) != 1 {
+			t.Fatalf("Expected the dead result to be dropped, got %+v", response.Results)
+		}
+		if r := response.Results[0]; r.Title != "ubuntu 24.04" || !reflect.DeepEqual(r.Category, []int{4050, 4000}) {
+			t.Errorf("Expected a processed result, got %+v", r)
+		}
+	}
+	if requests != 1 || calls != 1 {
+		t.Errorf("Expected the cached response to be processed once, got %d requests and %d calls", requests, calls)
+	}
+
+	response, err := client.TorznabSearch(context.Background(), "all", url.Values{"q": {"show"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if calls != 2 || len(response.Results) != 1 {
+		t.Errorf("Expected torznab searches to be processed, got %d calls and %+v", calls, response.Results)
+	}
+
+	response, _ = client.SearchWithHook("all", "ubuntu", func(r *SearchResult) bool { return true })
+	if calls != 3 || len(response.Results) != 1 {
+		t.Errorf("Expected hooked searches to be processed, got %d calls and %+v", calls, response.Results)
+	}
+}
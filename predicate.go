@@ -0,0 +1,52 @@
+package jackett
+
+// Predicate reports whether a search result is wanted. Predicates combine
+// with And, Or and Not, and filter responses with SearchResponse.Where.
+// Everywhere a Predicate is accepted, nil means one accepting every result.
+type Predicate func(SearchResult) bool
+
+// Where returns a copy of r holding only the results p accepts. The indexer
+// statuses are shared with r.
+func (r *SearchResponse) Where(p Predicate) *SearchResponse {
+	filtered := *r
+	filtered.Results = nil
+	for _, result := range r.Results {
+		if p == nil || p(result) {
+			filtered.Results = append(filtered.Results, result)
+		}
+	}
+	return &filtered
+}
+
+// And accepts results that every one of predicates accepts; with none, it
+// accepts everything
+func And(predicates ...Predicate) Predicate {
+	return func(r SearchResult) bool {
+		for _, p := range predicates {
+			if p != nil && !p(r) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or accepts results that any of predicates accepts; with none, it accepts
+// nothing
+func Or(predicates ...Predicate) Predicate {
+	return func(r SearchResult) bool {
+		for _, p := range predicates {
+			if p == nil || p(r) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not accepts the results p rejects
+func Not(p Predicate) Predicate {
+	return func(r SearchResult) bool {
+		return p != nil && !p(r)
+	}
+}
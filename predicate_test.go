@@ -0,0 +1,107 @@
+package jackett
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSearchResponseWhere(t *testing.T) {
+	response := &SearchResponse{
+		Results: []SearchResult{
+			{Title: "Ubuntu 24.04", Seeders: 50, Size: 6 << 30},
+			{Title: "Ubuntu 22.04", Seeders: 0, Size: 4 << 30},
+			{Title: "Debian 12", Seeders: 20, Size: 700 << 20},
+			{Title: "Ubuntu Server", Seeders: 5, Size: 2 << 30},
+		},
+		Indexers:  []IndexerStatus{{ID: "one", Status: IndexerStatusOK, Results: 4}},
+		Truncated: true,
+	}
+	alive := Predicate(func(r SearchResult) bool { return r.Seeders > 0 })
+	ubuntu := Predicate(func(r SearchResult) bool { return strings.HasPrefix(r.Title, "Ubuntu") })
+	small := Predicate(func(r SearchResult) bool { return r.Size < 1<<30 })
+
+	tests := []struct {
+		name      string
+		predicate Predicate
+		want      []string
+	}{
+		{"nil", nil, []string{"Ubuntu 24.04", "Ubuntu 22.04", "Debian 12", "Ubuntu Server"}},
+		{"and", And(alive, ubuntu), []string{"Ubuntu 24.04", "Ubuntu Server"}},
+		{"or", Or(small, Not(alive)), []string{"Ubuntu 22.04", "Debian 12"}},
+		{"not", Not(ubuntu), []string{"Debian 12"}},
+		{"not nil", Not(nil), nil},
+		{"nested", And(alive, Or(small, Not(ubuntu)), nil), []string{"Debian 12"}},
+		{"empty and", And(), []string{"Ubuntu 24.04", "Ubuntu 22.04", "Debian 12", "Ubuntu Server"}},
+		{"empty or", Or(), nil},
+	}
+	for _, tt := range tests {
+		filtered := response.Where(tt.predicate)
+		var titles []string
+		for _, r := range filtered.Results {
+			titles = append(titles, r.Title)
+		}
+		if !reflect.DeepEqual(titles, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, titles)
+		}
+		if !filtered.Truncated || len(filtered.Indexers) != 1 {
+			t.Errorf("%s: expected the response metadata to be kept, got %+v", tt.name, filtered)
+		}
+	}
+
+	chained := response.Where(alive).Where(Not(small))
+	if len(chained.Results) != 2 || len(response.Results) != 4 {
+		t.Errorf("Expected chaining to leave the original intact, got %d and %d results", len(chained.Results), len(response.Results))
+	}
+}
+
+func TestPredicateNil(t *testing.T) {
+	result := SearchResult{Title: "Ubuntu 24.04"}
+	reject := Predicate(func(SearchResult) bool { return false })
+
+	tests := []struct {
+		name      string
+		predicate Predicate
+		want      bool
+	}{
+		{"where nil", nil, true},
+		{"and nil", And(nil), true},
+		{"and nil reject", And(nil, reject), false},
+		{"or nil", Or(nil), true},
+		{"or nil reject", Or(reject, nil), true},
+		{"not nil", Not(nil), false},
+		{"not and nil", Not(And(nil)), false},
+	}
+	for _, tt := range tests {
+		got := len((&SearchResponse{Results: []SearchResult{result}}).Where(tt.predicate).Results) == 1
+		if got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestBackfillFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(torznabFeedXML))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, "test-api-key")
+	var progress []BackfillProgress
+	backfill := &Backfill{
+		Client:     client,
+		Items:      []WantedItem{{Key: "a", Request: SearchRequest{Query: "a"}}},
+		Indexers:   plannerIndexers()[:1],
+		Filter:     func(r SearchResult) bool { return r.Seeders > 100 },
+		OnProgress: func(p BackfillProgress) { progress = append(progress, p) },
+	}
+	if err := backfill.Run(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(progress) != 1 || progress[0].Response == nil || len(progress[0].Response.Results) != 0 {
+		t.Errorf("Expected the filter to drop the result, got %+v", progress)
+	}
+}
@@ -0,0 +1,376 @@
+package jackett
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProwlarrClient speaks Prowlarr's v1 API behind the same Searcher and
+// TorrentDownloader interfaces as Client, so tools can support users who
+// moved from Jackett to Prowlarr with one code path. Results and indexers
+// are converted to this package's types; Prowlarr's numeric indexer IDs
+// become decimal strings. It is safe for concurrent use.
+type ProwlarrClient struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+	// MaxDownloadSize limits torrent downloads like WithMaxDownloadSize
+	MaxDownloadSize int64
+}
+
+var (
+	_ Searcher          = (*ProwlarrClient)(nil)
+	_ TorrentDownloader = (*ProwlarrClient)(nil)
+)
+
+// prowlarrRelease is a search result of /api/v1/search
+type prowlarrRelease struct {
+	GUID                 string   `json:"guid"`
+	Protocol             string   `json:"protocol"`
+	IndexerID            int      `json:"indexerId"`
+	Indexer              string   `json:"indexer"`
+	Title                string   `json:"title"`
+	Size                 int64    `json:"size"`
+	PublishDate          string   `json:"publishDate"`
+	DownloadURL          string   `json:"downloadUrl"`
+	MagnetURL            string   `json:"magnetUrl"`
+	InfoURL              string   `json:"infoUrl"`
+	InfoHash             string   `json:"infoHash"`
+	Seeders              *int     `json:"seeders"`
+	Leechers             *int     `json:"leechers"`
+	Grabs                *int     `json:"grabs"`
+	Files                *int     `json:"files"`
+	ImdbID               int      `json:"imdbId"`
+	TmdbID               int      `json:"tmdbId"`
+	TvdbID               int      `json:"tvdbId"`
+	DownloadVolumeFactor float64  `json:"downloadVolumeFactor"`
+	UploadVolumeFactor   float64  `json:"uploadVolumeFactor"`
+	MinimumRatio         *float64 `json:"minimumRatio"`
+	MinimumSeedTime      *int64   `json:"minimumSeedTime"`
+	Categories           []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"categories"`
+}
+
+// prowlarrIndexer is an indexer of /api/v1/indexer
+type prowlarrIndexer struct {
+	ID           int      `json:"id"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Language     string   `json:"language"`
+	Privacy      string   `json:"privacy"`
+	Enable       bool     `json:"enable"`
+	IndexerURLs  []string `json:"indexerUrls"`
+	Capabilities struct {
+		Categories []prowlarrCategory `json:"categories"`
+	} `json:"capabilities"`
+}
+
+type prowlarrCategory struct {
+	ID            int                `json:"id"`
+	Name          string             `json:"name"`
+	SubCategories []prowlarrCategory `json:"subCategories"`
+}
+
+// NewProwlarrClient initializes a client for the Prowlarr instance at baseURL,
+// e.g. "http://localhost:9696". The base URL is validated like NewClient's.
+func NewProwlarrClient(baseURL, apiKey string, httpClient ...*http.Client) (*ProwlarrClient, error) {
+	baseURL, _, err := normalizeBaseURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	client := newDefaultHTTPClient()
+	if len(httpClient) > 0 && httpClient[0] != nil {
+		client = httpClient[0]
+	}
+	return &ProwlarrClient{
+		client:          client,
+		baseURL:         baseURL,
+		apiKey:          apiKey,
+		MaxDownloadSize: defaultMaxDownloadSize,
+	}, nil
+}
+
+// Search searches all enabled indexers
+func (p *ProwlarrClient) Search(query string) (*SearchResponse, error) {
+	return p.SearchWithIndexer("all", query)
+}
+
+// SearchWithIndexer searches one indexer, given by its numeric ID, or "all"
+func (p *ProwlarrClient) SearchWithIndexer(indexerID, query string) (*SearchResponse, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	return p.TorznabSearch(context.Background(), indexerID, params)
+}
+
+// TorznabSearch translates torznab parameters into a Prowlarr search: t
+// selects the search type, cat the categories, and imdbid, tmdbid, tvdbid,
+// season and ep become Prowlarr's {ImdbId:...} style query tokens
+func (p *ProwlarrClient) TorznabSearch(ctx context.Context, indexerID string, params url.Values) (*SearchResponse, error) {
+	query := url.Values{}
+	query.Set("query", prowlarrQuery(params))
+	query.Set("type", prowlarrSearchType(params.Get("t")))
+	if indexerID != "" && indexerID != "all" {
+		if _, err := strconv.Atoi(indexerID); err != nil {
+			return nil, fmt.Errorf("search error: %w: Prowlarr indexer IDs are numeric, got %q", ErrIndexerNotFound, indexerID)
+		}
+		query.Set("indexerIds", indexerID)
+	}
+	for _, cats := range params["cat"] {
+		for _, cat := range strings.Split(cats, ",") {
+			if cat != "" {
+				query.Add("categories", cat)
+			}
+		}
+	}
+	if limit := params.Get("limit"); limit != "" {
+		query.Set("limit", limit)
+	}
+	if offset := params.Get("offset"); offset != "" {
+		query.Set("offset", offset)
+	}
+
+	var releases []prowlarrRelease
+	if err := p.getJSON(ctx, "/api/v1/search", query, &releases); err != nil {
+		return nil, fmt.Errorf("search error: %w", err)
+	}
+	return convertProwlarrReleases(releases), nil
+}
+
+// GetIndexersContext retrieves the indexers configured in Prowlarr
+func (p *ProwlarrClient) GetIndexersContext(ctx context.Context) ([]Indexer, error) {
+	var indexers []prowlarrIndexer
+	if err := p.getJSON(ctx, "/api/v1/indexer", nil, &indexers); err != nil {
+		return nil, fmt.Errorf("get indexers error: %w", err)
+	}
+
+	converted := make([]Indexer, len(indexers))
+	for i, idx := range indexers {
+		indexer := Indexer{
+			ID:          strconv.Itoa(idx.ID),
+			Name:        idx.Name,
+			Description: idx.Description,
+			Type:        prowlarrPrivacy(idx.Privacy),
+			Configured:  idx.Enable,
+			Language:    idx.Language,
+		}
+		if len(idx.IndexerURLs) > 0 {
+			indexer.SiteLink = idx.IndexerURLs[0]
+		}
+		for _, cat := range idx.Capabilities.Categories {
+			category := Category{ID: cat.ID, Name: cat.Name}
+			for _, sub := range cat.SubCategories {
+				category.Subcats = append(category.Subcats, Subcat{ID: sub.ID, Name: sub.Name})
+			}
+			indexer.Categories = append(indexer.Categories, category)
+		}
+		converted[i] = indexer
+	}
+	return converted, nil
+}
+
+// DownloadTorrent downloads the .torrent file behind a result link
+func (p *ProwlarrClient) DownloadTorrent(link string) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := p.DownloadTorrentContext(context.Background(), link, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DownloadTorrentContext streams the .torrent file behind a result link to
+// w. The API key is only sent if the link points at this Prowlarr instance.
+func (p *ProwlarrClient) DownloadTorrentContext(ctx context.Context, link string, w io.Writer) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", link, nil)
+	if err != nil {
+		return 0, fmt.Errorf("invalid download link: %w", err)
+	}
+	if p.sameHost(req.URL) {
+		req.Header.Set("X-Api-Key", p.apiKey)
+	}
+
+	resp, err := stopAtMagnet(p.client, func(req *http.Request) {
+		// Prowlarr redirects downloads of some indexers to the tracker
+		if !p.sameHost(req.URL) {
+			req.Header.Del("X-Api-Key")
+		}
+	}).Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("download error: %w: %w", ErrServerUnavailable, err)
+	}
+	defer resp.Body.Close()
+	return copyTorrent(resp, w, p.MaxDownloadSize)
+}
+
+// sameHost reports whether u points at this Prowlarr instance
+func (p *ProwlarrClient) sameHost(u *url.URL) bool {
+	base, err := url.Parse(p.baseURL)
+	return err == nil && strings.EqualFold(u.Host, base.Host)
+}
+
+// getJSON sends an authenticated GET request to a v1 endpoint and decodes
+// the response into v
+func (p *ProwlarrClient) getJSON(ctx context.Context, endpoint string, query url.Values, v interface{}) error {
+	apiURL, err := url.Parse(p.baseURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse base URL: %w", err)
+	}
+	if err := joinPath(apiURL, endpoint); err != nil {
+		return err
+	}
+	apiURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Api-Key", p.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("request failed: %w", err)
+		}
+		return fmt.Errorf("request failed: %w: %w", ErrServerUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		retryAfter, _ := parseRetryAfter(resp.Header.Get("Retry-After"), systemClock{}.Now())
+		return &statusError{code: resp.StatusCode, body: string(body), path: req.URL.Path, retryAfter: retryAfter}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// prowlarrQuery builds a Prowlarr search term from torznab parameters
+func prowlarrQuery(params url.Values) string {
+	terms := []string{params.Get("q")}
+	tokens := []struct{ param, token string }{
+		{"imdbid", "ImdbId"}, {"tmdbid", "TmdbId"}, {"tvdbid", "TvdbId"},
+		{"season", "Season"}, {"ep", "Episode"},
+	}
+	for _, t := range tokens {
+		v := params.Get(t.param)
+		if t.param == "imdbid" {
+			if id, err := NormalizeIMDbID(v); err == nil {
+				v = id
+			}
+		}
+		if v != "" {
+			terms = append(terms, "{"+t.token+":"+v+"}")
+		}
+	}
+	return strings.TrimSpace(strings.Join(terms, " "))
+}
+
+// prowlarrSearchType maps a torznab function to a Prowlarr search type
+func prowlarrSearchType(t string) string {
+	switch t {
+	case "tvsearch", "tv-search":
+		return "tvsearch"
+	case "movie", "movie-search":
+		return "movie"
+	case "music", "audio", "music-search":
+		return "music"
+	case "book", "book-search":
+		return "book"
+	}
+	return "search"
+}
+
+// prowlarrPrivacy maps Prowlarr's privacy to Jackett's indexer type
+func prowlarrPrivacy(privacy string) string {
+	switch strings.ToLower(privacy) {
+	case "semiprivate":
+		return "semi-private"
+	case "":
+		return ""
+	}
+	return strings.ToLower(privacy)
+}
+
+// convertProwlarrReleases converts Prowlarr search results, reporting each
+// indexer that returned results as successful. Prowlarr doesn't report
+// failing indexers in search responses.
+func convertProwlarrReleases(releases []prowlarrRelease) *SearchResponse {
+	response := &SearchResponse{Results: make([]SearchResult, len(releases))}
+	counts := map[int]*IndexerStatus{}
+	for i, r := range releases {
+		result := SearchResult{
+			Title:                r.Title,
+			Size:                 r.Size,
+			Link:                 r.DownloadURL,
+			MagnetURI:            r.MagnetURL,
+			GUID:                 r.GUID,
+			PublishDate:          r.PublishDate,
+			Tracker:              r.Indexer,
+			TrackerId:            strconv.Itoa(r.IndexerID),
+			InfoHash:             r.InfoHash,
+			Details:              r.InfoURL,
+			Grabs:                r.Grabs,
+			Files:                r.Files,
+			DownloadVolumeFactor: r.DownloadVolumeFactor,
+			UploadVolumeFactor:   r.UploadVolumeFactor,
+			MinimumRatio:         r.MinimumRatio,
+			MinimumSeedTime:      r.MinimumSeedTime,
+		}
+		if r.Protocol == "usenet" {
+			result.ContentType = ContentTypeNZB
+		}
+		if r.Seeders != nil {
+			result.Seeders = *r.Seeders
+			result.Peers = *r.Seeders
+		}
+		if r.Leechers != nil {
+			result.Peers += *r.Leechers
+		}
+		var names []string
+		for _, cat := range r.Categories {
+			result.Category = append(result.Category, cat.ID)
+			names = append(names, cat.Name)
+		}
+		result.CategoryDesc = strings.Join(names, ", ")
+		for _, id := range []struct {
+			v   int
+			dst **int
+		}{{r.ImdbID, &result.Imdb}, {r.TmdbID, &result.TMDb}, {r.TvdbID, &result.TVDBId}} {
+			if id.v != 0 {
+				v := id.v
+				*id.dst = &v
+			}
+		}
+		response.Results[i] = result
+
+		status, ok := counts[r.IndexerID]
+		if !ok {
+			status = &IndexerStatus{ID: strconv.Itoa(r.IndexerID), Name: r.Indexer, Status: IndexerStatusOK}
+			counts[r.IndexerID] = status
+		}
+		status.Results++
+	}
+
+	ids := make([]int, 0, len(counts))
+	for id := range counts {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		response.Indexers = append(response.Indexers, *counts[id])
+	}
+	return response
+}
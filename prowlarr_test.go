@@ -0,0 +1,202 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const prowlarrSearchJSON = `[
+	{"guid":"a","indexerId":2,"indexer":"Tracker B","title":"Show S01E01","size":1024,
+	 "publishDate":"2024-01-02T03:04:05Z","downloadUrl":"DOWNLOAD/a","infoUrl":"https://tracker/a",
+	 "seeders":10,"leechers":5,"grabs":3,"tvdbId":81189,"downloadVolumeFactor":0,"uploadVolumeFactor":1,
+	 "categories":[{"id":5000,"name":"TV"},{"id":5040,"name":"TV/HD"}]},
+	{"guid":"b","indexerId":1,"indexer":"Tracker A","title":"Show S01E01 720p","size":512,
+	 "magnetUrl":"magnet:?xt=urn:btih:abc","infoHash":"abc","seeders":1,"downloadVolumeFactor":1,"uploadVolumeFactor":1},
+	{"guid":"c","indexerId":2,"indexer":"Tracker B","title":"Show S01E01 1080p","size":2048}
+]`
+
+func TestProwlarrSearch(t *testing.T) {
+	var got url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/prowlarr/api/v1/search" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		got = r.URL.Query()
+		w.Write([]byte(prowlarrSearchJSON))
+	}))
+	defer server.Close()
+
+	client, err := NewProwlarrClient(server.URL+"/prowlarr/", "key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response, err := client.Search("show")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got.Get("query") != "show" || got.Get("type") != "search" || got.Has("indexerIds") {
+		t.Errorf("Unexpected search parameters %v", got)
+	}
+	if len(response.Results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(response.Results))
+	}
+
+	r := response.Results[0]
+	if r.Link != "DOWNLOAD/a" || r.Details != "https://tracker/a" || r.Tracker != "Tracker B" || r.TrackerId != "2" {
+		t.Errorf("Unexpected result %+v", r)
+	}
+	if r.Seeders != 10 || r.Peers != 15 || r.Grabs == nil || *r.Grabs != 3 || r.TVDBId == nil || *r.TVDBId != 81189 || r.Imdb != nil {
+		t.Errorf("Unexpected result counts or IDs %+v", r)
+	}
+	if len(r.Category) != 2 || r.Category[1] != 5040 || r.CategoryDesc != "TV, TV/HD" || r.DownloadVolumeFactor != 0 {
+		t.Errorf("Unexpected result categories or factors %+v", r)
+	}
+	if response.Results[1].MagnetURI != "magnet:?xt=urn:btih:abc" || response.Results[1].InfoHash != "abc" {
+		t.Errorf("Unexpected magnet result %+v", response.Results[1])
+	}
+
+	if len(response.Indexers) != 2 || response.Indexers[0].ID != "1" || response.Indexers[1].Results != 2 ||
+		response.Indexers[1].Status != IndexerStatusOK {
+		t.Errorf("Unexpected indexer statuses %+v", response.Indexers)
+	}
+
+	if _, err := client.SearchWithIndexer("2", "show"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got.Get("indexerIds") != "2" {
+		t.Errorf("Expected the indexer to be searched, got %v", got)
+	}
+
+	if _, err := client.SearchWithIndexer("tracker-a", "show"); !errors.Is(err, ErrIndexerNotFound) {
+		t.Errorf("Expected ErrIndexerNotFound for a Jackett indexer ID, got %v", err)
+	}
+}
+
+func TestProwlarrTorznabSearch(t *testing.T) {
+	var got url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client, _ := NewProwlarrClient(server.URL, "key")
+	params := url.Values{
+		"t":      {"tvsearch"},
+		"q":      {"show"},
+		"cat":    {"5000,5040"},
+		"tvdbid": {"81189"},
+		"season": {"1"},
+		"ep":     {"2"},
+	}
+	if _, err := client.TorznabSearch(context.Background(), "all", params); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got.Get("type") != "tvsearch" || got.Get("query") != "show {TvdbId:81189} {Season:1} {Episode:2}" {
+		t.Errorf("Unexpected search parameters %v", got)
+	}
+	if cats := got["categories"]; len(cats) != 2 || cats[0] != "5000" || cats[1] != "5040" {
+		t.Errorf("Unexpected categories %v", cats)
+	}
+}
+
+func TestProwlarrGetIndexers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/indexer" || r.Header.Get("X-Api-Key") != "key" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`[
+			{"id":1,"name":"Tracker A","privacy":"semiPrivate","enable":true,"language":"en-US",
+			 "indexerUrls":["https://tracker-a/"],
+			 "capabilities":{"categories":[{"id":5000,"name":"TV","subCategories":[{"id":5040,"name":"TV/HD"}]}]}},
+			{"id":2,"name":"Tracker B","privacy":"public","enable":false}
+		]`))
+	}))
+	defer server.Close()
+
+	client, _ := NewProwlarrClient(server.URL, "key")
+	indexers, err := client.GetIndexersContext(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(indexers) != 2 {
+		t.Fatalf("Expected 2 indexers, got %d", len(indexers))
+	}
+	a := indexers[0]
+	if a.ID != "1" || a.Type != "semi-private" || !a.Configured || a.SiteLink != "https://tracker-a/" || a.Language != "en-US" {
+		t.Errorf("Unexpected indexer %+v", a)
+	}
+	if len(a.Categories) != 1 || len(a.Categories[0].Subcats) != 1 || a.Categories[0].Subcats[0].ID != 5040 {
+		t.Errorf("Unexpected categories %+v", a.Categories)
+	}
+	if indexers[1].Type != "public" || indexers[1].Configured {
+		t.Errorf("Unexpected indexer %+v", indexers[1])
+	}
+}
+
+func TestProwlarrDownloadTorrent(t *testing.T) {
+	var trackerKey string
+	tracker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trackerKey = r.Header.Get("X-Api-Key")
+		w.Write([]byte(testTorrent))
+	}))
+	defer tracker.Close()
+
+	var sentKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sentKey = r.Header.Get("X-Api-Key")
+		switch r.URL.Path {
+		case "/1/download":
+			w.Write([]byte(testTorrent))
+		case "/2/download":
+			http.Redirect(w, r, "magnet:?xt=urn:btih:abc", http.StatusFound)
+		case "/3/download":
+			http.Redirect(w, r, tracker.URL+"/torrent", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewProwlarrClient(server.URL, "key")
+	data, err := client.DownloadTorrent(server.URL + "/1/download")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(data) != testTorrent || sentKey != "key" {
+		t.Errorf("Unexpected download %q with key %q", data, sentKey)
+	}
+
+	var magnet *MagnetRedirectError
+	if _, err := client.DownloadTorrent(server.URL + "/2/download"); !errors.As(err, &magnet) || magnet.MagnetURI != "magnet:?xt=urn:btih:abc" {
+		t.Errorf("Expected a MagnetRedirectError, got %v", err)
+	}
+
+	data, err = client.DownloadTorrent(server.URL + "/3/download")
+	if err != nil || string(data) != testTorrent {
+		t.Fatalf("Expected the redirect to be followed, got %q, %v", data, err)
+	}
+	if trackerKey != "" {
+		t.Error("Expected the API key not to be forwarded to another host")
+	}
+
+	if _, err := client.DownloadTorrent(server.URL + "/4/download"); err == nil {
+		t.Error("Expected an error for a missing torrent")
+	}
+
+	client.MaxDownloadSize = 4
+	if _, err := client.DownloadTorrent(server.URL + "/1/download"); !errors.Is(err, ErrDownloadTooLarge) {
+		t.Errorf("Expected ErrDownloadTooLarge, got %v", err)
+	}
+}
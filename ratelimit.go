@@ -0,0 +1,171 @@
+package jackett
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit is a token bucket limit: Rate requests per second on average,
+// with bursts of up to Burst requests
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// Every returns the RateLimit allowing one request per interval, with no
+// bursting
+func Every(interval time.Duration) RateLimit {
+	return RateLimit{Rate: float64(time.Second) / float64(interval), Burst: 1}
+}
+
+// tokenBucket implements a RateLimit
+type tokenBucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &tokenBucket{limit: limit, tokens: float64(limit.Burst)}
+}
+
+// reserve takes a token, returning how long to wait before it may be used
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	if b.last.IsZero() {
+		b.last = now
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.limit.Rate
+		if burst := float64(b.limit.Burst); b.tokens > burst {
+			b.tokens = burst
+		}
+		b.last = now
+	}
+
+	b.tokens--
+	if b.tokens >= 0 || b.limit.Rate <= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.limit.Rate * float64(time.Second))
+}
+
+// rateLimiter holds the client's global and per-indexer token buckets
+type rateLimiter struct {
+	mu      sync.Mutex
+	global  *tokenBucket
+	indexer map[string]*tokenBucket
+}
+
+// WithRateLimit limits the rate of requests that reach trackers (searches,
+// torznab searches and downloads) across all indexers. Requests over the
+// limit wait for their turn.
+func WithRateLimit(limit RateLimit) Option {
+	return func(c *Client) {
+		c.limiter().setGlobal(limit)
+	}
+}
+
+// WithIndexerRateLimit limits the rate of searches and downloads for one
+// indexer, in addition to any global limit. Searches of "all" are limited by
+// the rate limit of "all", not those of the individual indexers.
+func WithIndexerRateLimit(indexerID string, limit RateLimit) Option {
+	return func(c *Client) {
+		c.limiter().setIndexer(indexerID, limit)
+	}
+}
+
+// SeedRateLimits sets per-indexer rate limits from the configured indexers
+// and their advertised capabilities. Jackett doesn't advertise request rates
+// itself, so limitFor decides the limit for each indexer; returning false
+// leaves that indexer's current limit in place.
+func (c *Client) SeedRateLimits(ctx context.Context, limitFor func(Indexer) (RateLimit, bool)) error {
+	indexers, err := c.GetIndexersContext(ctx)
+	if err != nil {
+		return fmt.Errorf("seed rate limits error: %w", err)
+	}
+
+	limiter := c.limiter()
+	for _, indexer := range indexers {
+		if !indexer.Configured {
+			continue
+		}
+		if limit, ok := limitFor(indexer); ok {
+			limiter.setIndexer(indexer.ID, limit)
+		}
+	}
+	return nil
+}
+
+// limiter returns the client's rate limiter, creating it on first use
+func (c *Client) limiter() *rateLimiter {
+	c.limiterOnce.Do(func() {
+		c.rateLimiter = &rateLimiter{indexer: make(map[string]*tokenBucket)}
+	})
+	return c.rateLimiter
+}
+
+func (r *rateLimiter) setGlobal(limit RateLimit) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.global = newTokenBucket(limit)
+}
+
+func (r *rateLimiter) setIndexer(indexerID string, limit RateLimit) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.indexer[strings.ToLower(indexerID)] = newTokenBucket(limit)
+}
+
+// waitForRateLimit blocks until a request to indexerID ("" if unknown) is
+// allowed by the global and per-indexer limits, or ctx is done
+func (c *Client) waitForRateLimit(ctx context.Context, indexerID string) error {
+	r := c.limiter()
+
+	now := c.clock.Now()
+	r.mu.Lock()
+	var wait time.Duration
+	if r.global != nil {
+		wait = r.global.reserve(now)
+	}
+	if b, ok := r.indexer[strings.ToLower(indexerID)]; ok && indexerID != "" {
+		if w := b.reserve(now); w > wait {
+			wait = w
+		}
+	}
+	r.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-c.clock.After(wait):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// linkIndexer extracts the indexer ID from a Jackett download link such as
+// http://localhost:9117/dl/1337x/?jackett_apikey=...
+func linkIndexer(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "dl" {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
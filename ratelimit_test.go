@@ -0,0 +1,141 @@
+package jackett
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newTokenBucket(RateLimit{Rate: 2, Burst: 2})
+
+	expected := []time.Duration{0, 0, 500 * time.Millisecond, time.Second}
+	for i, want := range expected {
+		if got := b.reserve(start); got != want {
+			t.Errorf("Reservation %d: expected wait %v, got %v", i, want, got)
+		}
+	}
+
+	// A second later the debt of two tokens is paid off, so the next
+	// request waits for one token
+	if got := b.reserve(start.Add(time.Second)); got != 500*time.Millisecond {
+		t.Errorf("Expected wait 500ms after refill, got %v", got)
+	}
+
+	// Refilling stops at the burst size
+	if got := b.reserve(start.Add(time.Hour)); got != 0 {
+		t.Errorf("Expected no wait after a long pause, got %v", got)
+	}
+	if got := b.reserve(start.Add(time.Hour)); got != 0 {
+		t.Errorf("Expected no wait within the burst, got %v", got)
+	}
+	if got := b.reserve(start.Add(time.Hour)); got != 500*time.Millisecond {
+		t.Errorf("Expected wait 500ms beyond the burst, got %v", got)
+	}
+}
+
+func TestEvery(t *testing.T) {
+	limit := Every(2 * time.Second)
+	if limit.Rate != 0.5 || limit.Burst != 1 {
+		t.Errorf("Expected 0.5/s with burst 1, got %+v", limit)
+	}
+}
+
+func TestRateLimitSearches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(hookSearchJSON))
+	}))
+	defer server.Close()
+
+	clock := &recordingClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	client, err := NewClientWithOptions(server.URL, "test-api-key",
+		WithClock(clock),
+		WithRateLimit(RateLimit{Rate: 10, Burst: 1}),
+		WithIndexerRateLimit("slow", Every(time.Second)),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, indexerID := range []string{"fast", "slow", "slow"} {
+		if _, err := client.SearchWithIndexer(indexerID, "ubuntu"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	// The clock doesn't advance: the second search waits for the global
+	// limit, the third for the slow indexer's
+	expected := []time.Duration{100 * time.Millisecond, time.Second}
+	if len(clock.delays) != len(expected) {
+		t.Fatalf("Expected delays %v, got %v", expected, clock.delays)
+	}
+	for i, want := range expected {
+		if clock.delays[i] != want {
+			t.Errorf("Delay %d: expected %v, got %v", i, want, clock.delays[i])
+		}
+	}
+}
+
+func TestRateLimitCancelled(t *testing.T) {
+	client, err := NewClientWithOptions("http://localhost:9117", "test-api-key",
+		WithClock(&stepClock{}),
+		WithRateLimit(Every(time.Hour)),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := client.waitForRateLimit(context.Background(), "any"); err != nil {
+		t.Fatalf("Expected the first request to pass, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.waitForRateLimit(ctx, "any"); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestSeedRateLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(allIndexersXML))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "test-api-key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var seen []string
+	err = client.SeedRateLimits(context.Background(), func(indexer Indexer) (RateLimit, bool) {
+		seen = append(seen, indexer.ID)
+		return Every(time.Minute), indexer.Caps != nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(seen) != 1 || seen[0] != "configured-indexer" {
+		t.Errorf("Expected only the configured indexer to be seeded, got %v", seen)
+	}
+	if _, ok := client.rateLimiter.indexer["configured-indexer"]; !ok {
+		t.Error("Expected a rate limit for configured-indexer")
+	}
+}
+
+func TestLinkIndexer(t *testing.T) {
+	tests := map[string]string{
+		"http://localhost:9117/dl/1337x/?jackett_apikey=key&path=abc": "1337x",
+		"http://localhost:9117/jackett/dl/rarbg/?path=abc":            "rarbg",
+		"https://tracker.example.com/download/123":                    "",
+		"::not a url": "",
+	}
+	for link, want := range tests {
+		if got := linkIndexer(link); got != want {
+			t.Errorf("linkIndexer(%q): expected %q, got %q", link, want, got)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package jackett
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RawResponse is the undecoded response to a request made with Do
+type RawResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Do sends a request to an endpoint of the Jackett API that this package
+// doesn't wrap yet. path is an escaped path relative to the base URL, such as
+// "/api/v2.0/indexers/all/results". The API key is added to params unless
+// they already hold one, and a non-nil body is sent as JSON. Like every other
+// call, the request carries the admin session, auth provider credentials and
+// headers, and counts towards hooks, logs and concurrency limits.
+//
+// Unlike the typed methods, Do doesn't retry or interpret the response: any
+// status is returned without error, and err is only set if no response was
+// received or its body couldn't be read.
+func (c *Client) Do(ctx context.Context, method, path string, params url.Values, body []byte) (*RawResponse, error) {
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+	if query.Get("apikey") == "" {
+		query.Set("apikey", c.apiKeyFor(ctx))
+	}
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	req, err := c.newRequest(ctx, method, path, query, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	var raw *RawResponse
+	err = c.exchange(c.client, req, func(resp *http.Response, body io.Reader) error {
+		return c.limitBody(body, func(body io.Reader) error {
+			data, err := readAll(body)
+			if err != nil {
+				return err
+			}
+			header := resp.Header.Clone()
+			if strings.EqualFold(header.Get("Content-Encoding"), "gzip") {
+				// The body was decompressed
+				header.Del("Content-Encoding")
+				header.Del("Content-Length")
+			}
+			raw = &RawResponse{StatusCode: resp.StatusCode, Header: header, Body: data}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
@@ -0,0 +1,67 @@
+package jackett
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestDo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jackett/api/v2.0/server/logs":
+			if r.URL.Query().Get("apikey") != "key" || r.URL.Query().Get("level") != "error" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Header().Set("X-Total", "1")
+			gz := gzip.NewWriter(w)
+			gz.Write([]byte(`[{"Message":"boom"}]`))
+			gz.Close()
+		case "/jackett/api/v2.0/server/echo":
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+			w.WriteHeader(http.StatusTeapot)
+			w.Write(body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL+"/jackett", "key")
+	resp, err := client.Do(context.Background(), "GET", "/api/v2.0/server/logs", url.Values{"level": {"error"}}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || string(resp.Body) != `[{"Message":"boom"}]` || resp.Header.Get("X-Total") != "1" {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+	if resp.Header.Get("Content-Encoding") != "" {
+		t.Error("Expected the Content-Encoding of the decompressed body to be dropped")
+	}
+
+	resp, err = client.Do(context.Background(), "POST", "/api/v2.0/server/echo", nil, []byte(`{"a":1}`))
+	if err != nil {
+		t.Fatalf("Expected no error for a non-2xx status, got %v", err)
+	}
+	if resp.StatusCode != http.StatusTeapot || string(resp.Body) != `{"a":1}` || resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+
+	resp, err = client.Do(context.Background(), "GET", "/api/v2.0/server/logs", url.Values{"apikey": {"other"}}, nil)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected the caller's API key to be kept, got %+v, %v", resp, err)
+	}
+
+	client.Close(context.Background())
+	if _, err := client.Do(context.Background(), "GET", "/api/v2.0/server/logs", nil, nil); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Expected ErrClientClosed, got %v", err)
+	}
+}
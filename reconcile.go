@@ -0,0 +1,122 @@
+package jackett
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// IndexerDiff lists how a target instance's indexers differ from a source's
+type IndexerDiff struct {
+	// Missing indexers are configured on the source but not the target
+	Missing []IndexerBackup `json:"missing,omitempty"`
+	// Extra indexers are configured on the target but not the source
+	Extra []string `json:"extra,omitempty"`
+	// Changed indexers are configured on both with different settings
+	Changed []IndexerChange `json:"changed,omitempty"`
+}
+
+// IndexerChange describes an indexer whose settings differ between instances
+type IndexerChange struct {
+	ID string `json:"id"`
+	// Fields are the IDs of the differing settings
+	Fields []string `json:"fields"`
+	// Source is the configuration on the source instance
+	Source IndexerBackup `json:"source"`
+}
+
+// Empty reports whether the instances are in sync
+func (d *IndexerDiff) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Extra) == 0 && len(d.Changed) == 0
+}
+
+// DiffIndexers compares the configured indexers and their settings on source
+// and target
+func DiffIndexers(ctx context.Context, source, target *Client) (*IndexerDiff, error) {
+	sourceExport, err := source.ExportIndexersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	targetExport, err := target.ExportIndexersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	targetByID := make(map[string]IndexerBackup, len(targetExport.Indexers))
+	for _, backup := range targetExport.Indexers {
+		targetByID[backup.ID] = backup
+	}
+
+	diff := &IndexerDiff{}
+	for _, backup := range sourceExport.Indexers {
+		other, ok := targetByID[backup.ID]
+		delete(targetByID, backup.ID)
+		if !ok {
+			diff.Missing = append(diff.Missing, backup)
+			continue
+		}
+		if fields := changedFields(backup.Config, other.Config); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, IndexerChange{ID: backup.ID, Fields: fields, Source: backup})
+		}
+	}
+	for _, backup := range targetExport.Indexers {
+		if _, ok := targetByID[backup.ID]; ok {
+			diff.Extra = append(diff.Extra, backup.ID)
+		}
+	}
+
+	return diff, nil
+}
+
+// Apply makes target match the source the diff was computed from: missing
+// indexers are added and changed ones overwritten. Extra indexers are only
+// deleted if removeExtra is set. Apply continues past individual failures;
+// all failures are returned joined in the error.
+func (d *IndexerDiff) Apply(ctx context.Context, target *Client, removeExtra bool) error {
+	var errs []error
+	for _, backup := range d.Missing {
+		if err := target.SetIndexerConfigContext(ctx, backup.ID, backup.Config); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, change := range d.Changed {
+		if err := target.SetIndexerConfigContext(ctx, change.ID, change.Source.Config); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if removeExtra {
+		for _, id := range d.Extra {
+			if err := target.DeleteIndexer(id); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// changedFields returns the IDs of settings whose values differ
+func changedFields(a, b []IndexerConfigField) []string {
+	values := make(map[string]json.RawMessage, len(b))
+	for _, field := range b {
+		values[field.ID] = field.Value
+	}
+
+	var changed []string
+	for _, field := range a {
+		other, ok := values[field.ID]
+		if !ok || !jsonEqual(field.Value, other) {
+			changed = append(changed, field.ID)
+		}
+	}
+	return changed
+}
+
+// jsonEqual compares JSON values ignoring insignificant whitespace
+func jsonEqual(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
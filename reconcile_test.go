@@ -0,0 +1,83 @@
+package jackett_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/cehbz/jackett"
+	"github.com/cehbz/jackett/jacketttest"
+)
+
+// newReconcileFake serves indexers with the given configs, keyed by indexer
+// ID and given as JSON
+func newReconcileFake(t *testing.T, configs map[string]string) *jacketttest.Server {
+	server := jacketttest.NewServer()
+	t.Cleanup(server.Close)
+
+	for _, id := range []string{"a", "b", "c"} {
+		config, ok := configs[id]
+		if !ok {
+			continue
+		}
+		var fields []jackett.IndexerConfigField
+		if err := json.Unmarshal([]byte(config), &fields); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		server.AddIndexer(jackett.Indexer{ID: id})
+		server.SetIndexerConfig(id, fields...)
+	}
+	return server
+}
+
+func TestDiffIndexers(t *testing.T) {
+	source := newReconcileFake(t, map[string]string{
+		"a": `[{"id": "sitelink", "type": "inputstring", "value": "https://a.example/"}]`,
+		"b": `[{"id": "sitelink", "type": "inputstring", "value": "https://b.example/"}, {"id": "freeleech", "type": "inputbool", "value": true}]`,
+	})
+	target := newReconcileFake(t, map[string]string{
+		"b": `[{"id": "sitelink", "type": "inputstring", "value": "https://b.example/"}, {"id": "freeleech", "type": "inputbool", "value":false}]`,
+		"c": `[{"id": "sitelink", "type": "inputstring", "value": "https://c.example/"}]`,
+	})
+
+	sourceClient, _ := source.NewClient()
+	targetClient, _ := target.NewClient()
+
+	diff, err := jackett.DiffIndexers(context.Background(), sourceClient, targetClient)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(diff.Missing) != 1 || diff.Missing[0].ID != "a" {
+		t.Errorf("Expected 'a' missing, got %+v", diff.Missing)
+	}
+	if !reflect.DeepEqual(diff.Extra, []string{"c"}) {
+		t.Errorf("Expected 'c' extra, got %v", diff.Extra)
+	}
+	if len(diff.Changed) != 1 || !reflect.DeepEqual(diff.Changed[0].Fields, []string{"freeleech"}) {
+		t.Errorf("Expected freeleech changed on 'b', got %+v", diff.Changed)
+	}
+
+	if err := diff.Apply(context.Background(), targetClient, true); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var writes []string
+	for _, r := range target.Requests() {
+		if r.Method != "GET" {
+			writes = append(writes, r.Method+" "+r.Path)
+		}
+	}
+	want := []string{
+		"POST /api/v2.0/indexers/a/config",
+		"POST /api/v2.0/indexers/b/config",
+		"DELETE /api/v2.0/indexers/c",
+	}
+	if !reflect.DeepEqual(writes, want) {
+		t.Errorf("Expected writes %v, got %v", want, writes)
+	}
+	if !reflect.DeepEqual(target.IndexerConfig("a"), source.IndexerConfig("a")) {
+		t.Errorf("Expected 'a' to be configured like the source, got %+v", target.IndexerConfig("a"))
+	}
+}
@@ -0,0 +1,124 @@
+package jackett
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// redactedPlaceholder replaces secrets in error messages and logs
+const redactedPlaceholder = "REDACTED"
+
+// secretParam matches API keys and tracker credentials passed as query
+// parameters, including the jackett_apikey of download links, keys of other
+// Jackett instances and passkeys in the percent-encoded announce URLs of
+// magnet links' tr parameters
+var secretParam = regexp.MustCompile(`(?i)((?:jackett_)?apikey|passkey|authkey|torrent_pass)(=|%3D)[^&\s"'<>%]+`)
+
+// announcePathKey and pathKeyAnnounce match passkeys in announce paths, as
+// in /announce/<passkey> and /<passkey>/announce, plain or percent-encoded
+var (
+	announcePathKey = regexp.MustCompile(`(?i)((?:/|%2F)announce(?:\.php)?(?:/|%2F))[^/?&\s"'<>%]+`)
+	pathKeyAnnounce = regexp.MustCompile(`(?i)(/|%2F)[0-9a-z]{16,}((?:/|%2F)announce)`)
+)
+
+// RedactSecrets returns s with the secrets of any URLs in it replaced by
+// "REDACTED": Jackett API keys, and tracker passkeys and authkeys in query
+// parameters or announce paths. It is applied to everything the client logs
+// or reports in errors, and suits other output that may quote links, such as
+// test fixtures.
+func RedactSecrets(s string) string {
+	s = secretParam.ReplaceAllString(s, "${1}${2}"+redactedPlaceholder)
+	s = announcePathKey.ReplaceAllString(s, "${1}"+redactedPlaceholder)
+	return pathKeyAnnounce.ReplaceAllString(s, "${1}"+redactedPlaceholder+"${2}")
+}
+
+// redactedError is an error whose message had secrets removed. It unwraps to
+// the original error so errors.Is and errors.As keep working.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// redact removes the API key (both the client's and the one ctx carries, see
+// ContextWithAPIKey), the admin and basic auth passwords and any extra
+// secrets from s
+func (c *Client) redact(ctx context.Context, s string, extra ...string) string {
+	s = RedactSecrets(s)
+
+	c.session.mu.Lock()
+	password := c.session.password
+	c.session.mu.Unlock()
+
+	secrets := []string{c.apiKeyFor(context.Background()), c.apiKeyFor(ctx), password}
+	if c.basicAuth != nil {
+		basicPassword, _ := c.basicAuth.Password()
+		secrets = append(secrets, basicPassword)
+	}
+	for _, secret := range append(secrets, extra...) {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redactedPlaceholder)
+		}
+	}
+	return s
+}
+
+// redactError returns err with the secrets redact removes taken out of its
+// message. URLs echoed by the HTTP client are cleaned in place as well, so
+// they don't leak through errors.As.
+func (c *Client) redactError(ctx context.Context, err error, extra ...string) error {
+	if err == nil {
+		return nil
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = c.redact(ctx, urlErr.URL, extra...)
+	}
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		statusErr.body = c.redact(ctx, statusErr.body, extra...)
+	}
+
+	msg := err.Error()
+	if redacted := c.redact(ctx, msg, extra...); redacted != msg {
+		return &redactedError{msg: redacted, err: err}
+	}
+	return err
+}
+
+// sensitiveField reports whether an indexer config field holds a tracker
+// credential such as a password, cookie or passkey
+func sensitiveField(field IndexerConfigField) bool {
+	if field.Type == "password" {
+		return true
+	}
+	id := strings.ToLower(field.ID)
+	for _, s := range []string{"password", "cookie", "passkey", "apikey", "token", "secret"} {
+		if strings.Contains(id, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldSecrets returns the string values of the sensitive fields
+func fieldSecrets(fields []IndexerConfigField) []string {
+	var secrets []string
+	for _, field := range fields {
+		if !sensitiveField(field) {
+			continue
+		}
+		var value string
+		if err := json.Unmarshal(field.Value, &value); err == nil && value != "" {
+			secrets = append(secrets, value)
+		}
+	}
+	return secrets
+}
@@ -0,0 +1,163 @@
+package jackett
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const secretKey = "s3cr3t-api-key"
+
+func TestRedactEchoedResponse(t *testing.T) {
+	// A proxy error page echoing the request, including the API key
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		io.WriteString(w, "upstream failed for "+r.URL.String())
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, secretKey)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	_, err = client.GetIndexers()
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if strings.Contains(err.Error(), secretKey) {
+		t.Errorf("Expected the API key to be redacted, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "apikey=REDACTED") {
+		t.Errorf("Expected a redaction marker, got %v", err)
+	}
+	if !errors.Is(err, ErrServerUnavailable) {
+		t.Errorf("Expected ErrServerUnavailable, got %v", err)
+	}
+}
+
+func TestRedactTransportErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	client, err := NewClient(server.URL, secretKey)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	_, err = client.GetIndexers()
+	if err == nil || strings.Contains(err.Error(), secretKey) {
+		t.Errorf("Expected a redacted error, got %v", err)
+	}
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) || strings.Contains(urlErr.URL, secretKey) {
+		t.Errorf("Expected the url.Error to be redacted, got %v", urlErr)
+	}
+
+	// Download links carry the key of the instance that produced them
+	link := server.URL + "/dl/indexer/?jackett_apikey=other-instance-key&path=abc"
+	_, err = client.DownloadTorrent(link)
+	if err == nil || strings.Contains(err.Error(), "other-instance-key") {
+		t.Errorf("Expected a redacted error, got %v", err)
+	}
+}
+
+func TestRedactTrackerCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, secretKey)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	fields := []IndexerConfigField{
+		{ID: "username", Type: "inputstring", Value: json.RawMessage(`"alice"`)},
+		{ID: "password", Type: "password", Value: json.RawMessage(`"hunter2"`)},
+		{ID: "cookieheader", Type: "inputstring", Value: json.RawMessage(`"uid=1; pass=abcdef"`)},
+	}
+	err = client.SetIndexerConfig("tracker", fields)
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	for _, secret := range []string{"hunter2", "uid=1; pass=abcdef"} {
+		if strings.Contains(err.Error(), secret) {
+			t.Errorf("Expected %q to be redacted, got %v", secret, err)
+		}
+	}
+	if !strings.Contains(err.Error(), "alice") {
+		t.Errorf("Expected non-secret fields to be kept, got %v", err)
+	}
+}
+
+func TestRedactLoginPassword(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		io.WriteString(w, "bad login "+r.PostFormValue("password"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, secretKey)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	err = client.Login("correct horse battery")
+	if err == nil || strings.Contains(err.Error(), "correct horse battery") {
+		t.Errorf("Expected a redacted error, got %v", err)
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	tests := map[string]string{
+		"http://localhost:9117/dl/a/?jackett_apikey=abc&path=x":                            "http://localhost:9117/dl/a/?jackett_apikey=REDACTED&path=x",
+		"https://tracker.example/announce.php?passkey=0123abcd":                            "https://tracker.example/announce.php?passkey=REDACTED",
+		"https://t.example/torrents.php?action=download&id=1&authkey=aaa&torrent_pass=bbb": "https://t.example/torrents.php?action=download&id=1&authkey=REDACTED&torrent_pass=REDACTED",
+		"https://tracker.example/announce/0123456789abcdef":                                "https://tracker.example/announce/REDACTED",
+		"https://tracker.example/0123456789abcdef0123456789abcdef/announce":                "https://tracker.example/REDACTED/announce",
+		"udp://tracker.opentrackr.org:1337/announce":                                       "udp://tracker.opentrackr.org:1337/announce",
+		"https://tracker.example/details.php?id=42":                                        "https://tracker.example/details.php?id=42",
+	}
+	for in, want := range tests {
+		if got := RedactSecrets(in); got != want {
+			t.Errorf("RedactSecrets(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	// A private tracker's magnet link, announce URLs percent-encoded in tr
+	magnet := "magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567&dn=Show.S01E01" +
+		"&tr=https%3A%2F%2Ftracker.example%2Fannounce.php%3Fpasskey%3Dfeedface1234" +
+		"&tr=https%3A%2F%2Fother.example%2Fannounce%2Fcafebabe5678" +
+		"&tr=https%3A%2F%2Fthird.example%2F0123456789abcdef0123%2Fannounce"
+	got := RedactSecrets(magnet)
+	for _, secret := range []string{"feedface1234", "cafebabe5678", "0123456789abcdef0123%2F"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("Expected %q to be redacted, got %q", secret, got)
+		}
+	}
+	if !strings.Contains(got, "xt=urn:btih:0123456789abcdef0123456789abcdef01234567") {
+		t.Errorf("Expected the info hash to be kept, got %q", got)
+	}
+}
+
+func TestRedactTrackerPasskeyInError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad announce https://tracker.example/announce.php?passkey=feedface1234", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, secretKey)
+	_, err := client.DownloadTorrent(server.URL + "/dl/indexer/?path=abc")
+	if err == nil || strings.Contains(err.Error(), "feedface1234") {
+		t.Errorf("Expected the tracker passkey to be redacted, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package jackett_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/cehbz/jackett"
+	"github.com/cehbz/jackett/jacketttest"
+)
+
+const testMagnetURI = "magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567&dn=test"
+
+// newDownloadFake serves a torrent, a link redirecting to it and a link
+// redirecting to a magnet URI, returning the server and the torrent's link
+func newDownloadFake(t *testing.T) (*jacketttest.Server, string) {
+	server := jacketttest.NewServer()
+	t.Cleanup(server.Close)
+
+	link := server.AddTorrent("test", "torrent", []byte("torrent file data"))
+	server.Handle("/dl/magnet", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, testMagnetURI, http.StatusFound)
+	})
+	server.Handle("/dl/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, link, http.StatusFound)
+	})
+	return server, link
+}
+
+func TestDownloadRelease(t *testing.T) {
+	server, _ := newDownloadFake(t)
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	release, err := client.DownloadRelease(context.Background(), server.URL+"/dl/magnet")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !release.IsMagnet() || release.MagnetURI != testMagnetURI {
+		t.Errorf("Expected magnet URI %s, got %+v", testMagnetURI, release)
+	}
+
+	release, err = client.DownloadRelease(context.Background(), server.URL+"/dl/redirect")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if release.IsMagnet() || string(release.Torrent) != "torrent file data" {
+		t.Errorf("Expected torrent data after following redirect, got %+v", release)
+	}
+
+	release, err = client.DownloadRelease(context.Background(), testMagnetURI)
+	if err != nil || release.MagnetURI != testMagnetURI {
+		t.Errorf("Expected magnet link to be returned as-is, got %+v (%v)", release, err)
+	}
+}
+
+func TestDownloadTorrent_MagnetRedirect(t *testing.T) {
+	server, _ := newDownloadFake(t)
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	_, err = client.DownloadTorrent(server.URL + "/dl/magnet")
+	var magnet *jackett.MagnetRedirectError
+	if !errors.As(err, &magnet) {
+		t.Fatalf("Expected MagnetRedirectError, got %v", err)
+	}
+	if magnet.MagnetURI != testMagnetURI {
+		t.Errorf("Expected magnet URI %s, got %s", testMagnetURI, magnet.MagnetURI)
+	}
+}
+
+func TestSearchResult_Fetch(t *testing.T) {
+	server, link := newDownloadFake(t)
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The magnet URI wins without any request being made
+	withMagnet := jackett.SearchResult{Title: "a", MagnetURI: testMagnetURI, Link: server.URL + "/dl/unused"}
+	release, err := withMagnet.Fetch(context.Background(), client)
+	if err != nil || release.MagnetURI != testMagnetURI {
+		t.Errorf("Expected magnet URI %s, got %+v (%v)", testMagnetURI, release, err)
+	}
+	if n := len(server.Requests()); n != 0 {
+		t.Errorf("Expected no request for a magnet URI, got %d", n)
+	}
+
+	withLink := jackett.SearchResult{Title: "b", Link: link}
+	release, err = withLink.Fetch(context.Background(), client)
+	if err != nil || string(release.Torrent) != "torrent file data" {
+		t.Errorf("Expected torrent data, got %+v (%v)", release, err)
+	}
+
+	if _, err := (jackett.SearchResult{Title: "c"}).Fetch(context.Background(), client); err == nil {
+		t.Error("Expected error for result without link, got none")
+	}
+}
@@ -0,0 +1,145 @@
+package jackett
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// RelevanceQuery is what search results are scored against
+type RelevanceQuery struct {
+	Title string
+	Year  int // zero if unknown
+}
+
+// ParseRelevanceQuery splits a free-text query into title and release year,
+// e.g. "The Matrix 1999" becomes {Title: "The Matrix", Year: 1999}
+func ParseRelevanceQuery(query string) RelevanceQuery {
+	var q RelevanceQuery
+	var title []string
+	for _, token := range tokenize(query) {
+		if year, ok := parseYear(token); ok && q.Year == 0 {
+			q.Year = year
+			continue
+		}
+		title = append(title, token)
+	}
+	q.Title = strings.Join(title, " ")
+	return q
+}
+
+// ScoreTitle rates how well a result title matches q, from 0 (unrelated) to 1.
+// Every query token is matched against the closest title token, so extra
+// release tags like "1080p" or "x264" in the title don't lower the score,
+// while typos and punctuation differences are tolerated. When q has a year,
+// a matching year in the title raises the score and a different year lowers it.
+func ScoreTitle(q RelevanceQuery, title string) float64 {
+	queryTokens := tokenize(q.Title)
+	titleTokens := tokenize(title)
+
+	tokenScore := 1.0
+	if len(queryTokens) > 0 {
+		var sum float64
+		for _, qt := range queryTokens {
+			var best float64
+			for _, tt := range titleTokens {
+				if s := tokenSimilarity(qt, tt); s > best {
+					best = s
+				}
+			}
+			sum += best
+		}
+		tokenScore = sum / float64(len(queryTokens))
+	}
+
+	if q.Year == 0 {
+		return tokenScore
+	}
+
+	yearScore := 0.5 // title doesn't mention a year
+	for _, tt := range titleTokens {
+		if year, ok := parseYear(tt); ok {
+			if year == q.Year {
+				yearScore = 1
+				break
+			}
+			yearScore = 0
+		}
+	}
+	return 0.8*tokenScore + 0.2*yearScore
+}
+
+// Rank scores every result against q, drops results scoring below threshold,
+// and orders the rest by descending score. Results with equal scores keep
+// their original order.
+func (r *SearchResponse) Rank(q RelevanceQuery, threshold float64) {
+	kept := r.Results[:0]
+	for _, result := range r.Results {
+		result.Score = ScoreTitle(q, result.Title)
+		if result.Score >= threshold {
+			kept = append(kept, result)
+		}
+	}
+	r.Results = kept
+
+	sort.SliceStable(r.Results, func(i, j int) bool {
+		return r.Results[i].Score > r.Results[j].Score
+	})
+}
+
+// tokenize lowercases s and splits it on anything but letters and digits
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func parseYear(token string) (int, bool) {
+	if len(token) != 4 {
+		return 0, false
+	}
+	year, err := strconv.Atoi(token)
+	if err != nil || year < 1900 || year > 2099 {
+		return 0, false
+	}
+	return year, true
+}
+
+// tokenSimilarity is 1 for identical tokens, the normalized edit-distance
+// similarity for near misses, and 0 for everything else
+func tokenSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	similarity := 1 - float64(levenshtein(ra, rb))/float64(longest)
+	if similarity < 0.75 {
+		return 0
+	}
+	return similarity
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,46 @@
+package jackett
+
+import "testing"
+
+func TestParseRelevanceQuery(t *testing.T) {
+	q := ParseRelevanceQuery("The Matrix (1999)")
+	if q.Title != "the matrix" || q.Year != 1999 {
+		t.Errorf("Expected {the matrix 1999}, got %+v", q)
+	}
+}
+
+func TestScoreTitle(t *testing.T) {
+	q := ParseRelevanceQuery("The Matrix 1999")
+
+	exact := ScoreTitle(q, "The.Matrix.1999.1080p.BluRay.x264")
+	typo := ScoreTitle(q, "The Matrx 1999 720p")
+	wrongYear := ScoreTitle(q, "The Matrix Resurrections 2021 2160p")
+	unrelated := ScoreTitle(q, "Some Other Film 1999")
+
+	if exact != 1 {
+		t.Errorf("Expected exact match to score 1, got %f", exact)
+	}
+	if !(exact > typo && typo > wrongYear && wrongYear > unrelated) {
+		t.Errorf("Expected exact > typo > wrong year > unrelated, got %f, %f, %f, %f", exact, typo, wrongYear, unrelated)
+	}
+}
+
+func TestRank(t *testing.T) {
+	response := &SearchResponse{Results: []SearchResult{
+		{Title: "Totally Unrelated 2005"},
+		{Title: "The Matrix Reloaded 2003"},
+		{Title: "The.Matrix.1999.2160p"},
+	}}
+
+	response.Rank(ParseRelevanceQuery("The Matrix 1999"), 0.5)
+
+	if len(response.Results) != 2 {
+		t.Fatalf("Expected 2 results above threshold, got %d", len(response.Results))
+	}
+	if response.Results[0].Title != "The.Matrix.1999.2160p" {
+		t.Errorf("Expected best match first, got '%s'", response.Results[0].Title)
+	}
+	if response.Results[0].Score <= response.Results[1].Score {
+		t.Errorf("Expected descending scores, got %f then %f", response.Results[0].Score, response.Results[1].Score)
+	}
+}
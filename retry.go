@@ -0,0 +1,210 @@
+package jackett
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. MaxAttempts counts
+// the first try, so values below 2 disable retries. The delay before each
+// retry starts at Backoff and doubles with every attempt, up to MaxBackoff
+// if set; without it, doubling stops before the delay would overflow.
+// Jitter randomizes each delay by up to that fraction in either direction
+// (0.2 means ±20%) so that many clients don't retry in lockstep.
+// A server's Retry-After header takes precedence over the computed delay,
+// but a request whose Retry-After exceeds MaxBackoff is not retried.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	Jitter      float64
+	// Budget, if set, limits retries across every request sharing it
+	Budget *RetryBudget
+}
+
+// RetryBudget caps how much extra load retries can cause: each request
+// earns Ratio of a retry, and at most Min retries can be made back to back.
+// When a server is failing persistently the budget runs dry and requests
+// fail fast instead of multiplying the load on it. Create one with
+// NewRetryBudget; it is safe for concurrent use.
+type RetryBudget struct {
+	mu     sync.Mutex
+	ratio  float64
+	max    float64
+	tokens float64
+}
+
+// NewRetryBudget returns a RetryBudget allowing ratio retries per request on
+// average (0.1 means one retry for every ten requests) and bursts of min
+// retries. min is at least 1.
+func NewRetryBudget(ratio float64, min int) *RetryBudget {
+	if min < 1 {
+		min = 1
+	}
+	return &RetryBudget{ratio: ratio, max: float64(min), tokens: float64(min)}
+}
+
+// deposit credits the budget for a new request
+func (b *RetryBudget) deposit() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens += b.ratio
+	if b.tokens > b.max {
+		b.tokens = b.max
+	}
+}
+
+// withdraw takes one retry from the budget, reporting whether one was left
+func (b *RetryBudget) withdraw() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// backoff returns the delay before retry number attempt (starting at 1)
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || delay < p.MaxBackoff) && delay <= math.MaxInt64/2; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		jittered := float64(delay) + (rand.Float64()*2-1)*p.Jitter*float64(delay)
+		if jittered >= math.MaxInt64 {
+			return math.MaxInt64
+		}
+		delay = time.Duration(jittered)
+	}
+	return delay
+}
+
+// retryableStatus reports whether a response status indicates a transient
+// failure worth retrying: 429 Too Many Requests and server errors other than
+// those that will never succeed (501 Not Implemented, 505 HTTP Version Not
+// Supported)
+func retryableStatus(code int) bool {
+	switch {
+	case code == http.StatusTooManyRequests:
+		return true
+	case code == http.StatusNotImplemented, code == http.StatusHTTPVersionNotSupported:
+		return false
+	}
+	return code >= 500 && code <= 599
+}
+
+// IsRetryable reports whether err is a transient failure that may succeed
+// if the request is repeated: network errors and timeouts, 5xx responses,
+// 429 Too Many Requests and torznab request limits. Rejected credentials,
+// missing resources, malformed responses, size limits and cancellation are
+// permanent. An expired context deadline counts as a timeout, so retry loops
+// should also stop once their context is done. The client's own retry
+// machinery uses the same classification.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, ErrClientClosed) {
+		return false
+	}
+
+	var torznabErr *TorznabError
+	if errors.As(err, &torznabErr) {
+		return errors.Is(torznabErr, ErrRateLimited)
+	}
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return retryableStatus(statusErr.code)
+	}
+
+	switch {
+	case errors.Is(err, ErrRedirectNotAllowed), errors.Is(err, ErrDownloadTooLarge), errors.Is(err, ErrResponseTooLarge):
+		return false
+	}
+
+	var certErr *tls.CertificateVerificationError
+	if errors.As(err, &certErr) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	return errors.Is(err, ErrServerUnavailable) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// parseRetryAfter interprets a Retry-After header, which holds either a
+// number of seconds or an HTTP date
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// WithRetry retries Jackett API GET requests (searches, indexer and
+// configuration lookups) that fail transiently as classified by IsRetryable.
+// Retry-After headers are honored, and no retry is attempted if its delay
+// would run past the context's deadline. Requests that modify Jackett are
+// never retried, nor are searches whose response had already started
+// streaming to a ResultHook. By default API requests are not retried.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// retryDelay returns how long to wait before retry number attempt, and
+// whether the retry should be made at all given the policy's MaxBackoff,
+// ctx's deadline and the policy's budget
+func (c *Client) retryDelay(ctx context.Context, policy RetryPolicy, attempt int, retryAfter time.Duration) (time.Duration, bool) {
+	delay := policy.backoff(attempt)
+	if retryAfter > 0 {
+		// Retrying sooner than the server asked would only fail again
+		if policy.MaxBackoff > 0 && retryAfter > policy.MaxBackoff {
+			return 0, false
+		}
+		delay = retryAfter
+	}
+	if deadline, ok := ctx.Deadline(); ok && !c.clock.Now().Add(delay).Before(deadline) {
+		return 0, false
+	}
+	return delay, policy.Budget.withdraw()
+}
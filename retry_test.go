@@ -0,0 +1,347 @@
+package jackett
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// recordingClock fires every After immediately, recording the requested delays
+type recordingClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	delays []time.Duration
+}
+
+func (r *recordingClock) Now() time.Time { return r.now }
+
+func (r *recordingClock) After(d time.Duration) <-chan time.Time {
+	r.mu.Lock()
+	r.delays = append(r.delays, d)
+	r.mu.Unlock()
+	c := make(chan time.Time, 1)
+	c <- r.now.Add(d)
+	return c
+}
+
+func (r *recordingClock) NewTicker(d time.Duration) Ticker { return systemTicker{time.NewTicker(d)} }
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{Backoff: time.Second, MaxBackoff: 3 * time.Second}
+	expected := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}
+	for i, want := range expected {
+		if got := p.backoff(i + 1); got != want {
+			t.Errorf("backoff(%d) = %v, expected %v", i+1, got, want)
+		}
+	}
+
+	p = RetryPolicy{Backoff: time.Second, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		if got := p.backoff(1); got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("Expected jittered backoff within ±50%%, got %v", got)
+		}
+	}
+
+	// Without MaxBackoff, doubling stops short of overflowing
+	p = RetryPolicy{Backoff: time.Hour}
+	longest := p.backoff(40)
+	if longest < p.backoff(20) || p.backoff(1000) != longest {
+		t.Errorf("Expected the delay to level off, got %v and %v", longest, p.backoff(1000))
+	}
+	p.Jitter = 0.5
+	if got := p.backoff(1000); got < longest/2 {
+		t.Errorf("Expected a jittered delay of at least %v, got %v", longest/2, got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if d, ok := parseRetryAfter("120", now); !ok || d != 2*time.Minute {
+		t.Errorf("Expected 2m from seconds, got %v (%v)", d, ok)
+	}
+	if d, ok := parseRetryAfter("Mon, 01 Jan 2024 12:00:30 GMT", now); !ok || d != 30*time.Second {
+		t.Errorf("Expected 30s from HTTP date, got %v (%v)", d, ok)
+	}
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Error("Expected invalid Retry-After to be ignored")
+	}
+}
+
+func TestDownloadTorrent_Retry(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte("torrent file data"))
+		}
+	}))
+	defer server.Close()
+
+	clock := &recordingClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	client, err := NewClientWithOptions(server.URL, "test-api-key",
+		WithClock(clock), WithDownloadRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Second}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data, err := client.DownloadTorrent(server.URL + "/dl/test")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(data) != "torrent file data" {
+		t.Errorf("Expected torrent data, got %q", data)
+	}
+
+	expected := []time.Duration{7 * time.Second, 2 * time.Second}
+	if len(clock.delays) != 2 || clock.delays[0] != expected[0] || clock.delays[1] != expected[1] {
+		t.Errorf("Expected delays %v, got %v", expected, clock.delays)
+	}
+}
+
+func TestDownloadTorrent_NoRetryOnClientError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithOptions(server.URL, "test-api-key",
+		WithClock(&recordingClock{}), WithDownloadRetry(RetryPolicy{MaxAttempts: 3}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.DownloadTorrent(server.URL + "/dl/test"); err == nil {
+		t.Fatal("Expected error, got none")
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected 1 attempt, got %d", n)
+	}
+}
+
+// timeoutError is a net.Error reporting a timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"timeout", &url.Error{Op: "Get", URL: "http://x", Err: timeoutError{}}, true},
+		{"connection refused", &url.Error{Op: "Get", URL: "http://x", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}, true},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"server unavailable", fmt.Errorf("request failed: %w: %w", ErrServerUnavailable, errors.New("boom")), true},
+		{"429", &statusError{code: http.StatusTooManyRequests}, true},
+		{"500", fmt.Errorf("search error: %w", &statusError{code: http.StatusInternalServerError}), true},
+		{"503", &statusError{code: http.StatusServiceUnavailable}, true},
+		{"501", &statusError{code: http.StatusNotImplemented}, false},
+		{"401", &statusError{code: http.StatusUnauthorized}, false},
+		{"403", &statusError{code: http.StatusForbidden}, false},
+		{"404", &statusError{code: http.StatusNotFound}, false},
+		{"torznab request limit", &TorznabError{Code: 500, Description: "Request limit reached"}, true},
+		{"torznab invalid key", &TorznabError{Code: 100, Description: "Invalid API Key"}, false},
+		{"malformed response", fmt.Errorf("failed to decode search response: %w", &json.SyntaxError{Offset: 3}), false},
+		{"too large", fmt.Errorf("%w: more than 10 bytes", ErrResponseTooLarge), false},
+		{"redirect", fmt.Errorf("%w: cross-host", ErrRedirectNotAllowed), false},
+		{"cancelled", &url.Error{Op: "Get", URL: "http://x", Err: context.Canceled}, false},
+		{"deadline", fmt.Errorf("search error: %w", context.DeadlineExceeded), true},
+		{"closed", ErrClientClosed, false},
+	}
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Errorf("%s: expected IsRetryable %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+// flakyServer fails the first failures requests with status, then serves body
+func flakyServer(failures int32, status int, header http.Header, body string, requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(requests, 1) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			http.Error(w, "unavailable", status)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+}
+
+func TestWithRetryAPIRequests(t *testing.T) {
+	var requests int32
+	server := flakyServer(2, http.StatusServiceUnavailable, nil, hookSearchJSON, &requests)
+	defer server.Close()
+
+	clock := &recordingClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	client, err := NewClientWithOptions(server.URL, "test-api-key", WithClock(clock),
+		WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Second}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response, err := client.Search("ubuntu")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(response.Results) != 3 {
+		t.Errorf("Expected 3 results, got %d", len(response.Results))
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+	if len(clock.delays) != 2 || clock.delays[0] != time.Second || clock.delays[1] != 2*time.Second {
+		t.Errorf("Expected delays [1s 2s], got %v", clock.delays)
+	}
+}
+
+func TestWithRetryHonorsRetryAfter(t *testing.T) {
+	var requests int32
+	server := flakyServer(1, http.StatusTooManyRequests, http.Header{"Retry-After": {"7"}}, allIndexersXML, &requests)
+	defer server.Close()
+
+	clock := &recordingClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	client, err := NewClientWithOptions(server.URL, "test-api-key", WithClock(clock),
+		WithRetry(RetryPolicy{MaxAttempts: 2, Backoff: time.Second}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.GetIndexers(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(clock.delays) != 1 || clock.delays[0] != 7*time.Second {
+		t.Errorf("Expected a 7s delay, got %v", clock.delays)
+	}
+}
+
+func TestRetryAfterBeyondMaxBackoff(t *testing.T) {
+	var requests int32
+	server := flakyServer(1, http.StatusTooManyRequests, http.Header{"Retry-After": {"3600"}}, allIndexersXML, &requests)
+	defer server.Close()
+
+	clock := &recordingClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Second, MaxBackoff: time.Minute}
+	client, err := NewClientWithOptions(server.URL, "test-api-key", WithClock(clock),
+		WithRetry(policy), WithDownloadRetry(policy))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.GetIndexers(); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if requests != 1 {
+		t.Errorf("Expected an API request not to be retried, got %d requests", requests)
+	}
+	requests = 0
+	if _, err := client.DownloadTorrent(server.URL + "/dl/test"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if requests != 1 || len(clock.delays) != 0 {
+		t.Errorf("Expected a download not to be retried, got %d requests and delays %v", requests, clock.delays)
+	}
+}
+
+func TestWithRetrySkipsPermanentFailuresAndWrites(t *testing.T) {
+	var requests int32
+	server := flakyServer(10, http.StatusNotFound, nil, "", &requests)
+	defer server.Close()
+
+	client, err := NewClientWithOptions(server.URL, "test-api-key", WithClock(&recordingClock{}),
+		WithRetry(RetryPolicy{MaxAttempts: 3}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.Search("ubuntu"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if requests != 1 {
+		t.Errorf("Expected a 404 not to be retried, got %d requests", requests)
+	}
+
+	requests = 0
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+	if err := client.SetServerConfig(map[string]interface{}{}); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if requests != 1 {
+		t.Errorf("Expected a POST not to be retried, got %d requests", requests)
+	}
+}
+
+func TestWithRetryRespectsDeadline(t *testing.T) {
+	var requests int32
+	server := flakyServer(10, http.StatusServiceUnavailable, nil, "", &requests)
+	defer server.Close()
+
+	clock := &recordingClock{now: time.Now()}
+	client, err := NewClientWithOptions(server.URL, "test-api-key", WithClock(clock),
+		WithRetry(RetryPolicy{MaxAttempts: 5, Backoff: time.Hour}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if _, err := client.GetIndexersContext(ctx); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if requests != 1 || len(clock.delays) != 0 {
+		t.Errorf("Expected no retry past the deadline, got %d requests and delays %v", requests, clock.delays)
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	var requests int32
+	server := flakyServer(100, http.StatusServiceUnavailable, nil, "", &requests)
+	defer server.Close()
+
+	budget := NewRetryBudget(0.5, 1)
+	client, err := NewClientWithOptions(server.URL, "test-api-key", WithClock(&recordingClock{}),
+		WithRetry(RetryPolicy{MaxAttempts: 3, Budget: budget}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The first search spends the single retry the budget holds, the
+	// second earns half a retry, not enough for one, and the third earns
+	// the other half
+	expected := []int32{2, 1, 2}
+	for i, want := range expected {
+		requests = 0
+		if _, err := client.Search("ubuntu"); err == nil {
+			t.Fatal("Expected an error")
+		}
+		if requests != want {
+			t.Errorf("Search %d: expected %d requests, got %d", i, want, requests)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package jackett
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// searchCache holds recent search responses so that several consumers
+// issuing the same query within seconds hit the trackers only once
+type searchCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]searchCacheEntry
+}
+
+type searchCacheEntry struct {
+	response *SearchResponse
+	expires  time.Time
+}
+
+// WithSearchCache caches search responses for ttl, keyed by the indexer (or
+// "all") and the normalized query: case, surrounding and repeated whitespace
+// and the order of torznab parameters and categories don't matter. Keep ttl
+// short; seconds to a few minutes is typical. Searches with a ResultHook are
+// never cached.
+func WithSearchCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.searchCache = &searchCache{ttl: ttl, entries: make(map[string]searchCacheEntry)}
+	}
+}
+
+// get returns a copy of the unexpired response cached under key
+func (s *searchCache) get(key string, now time.Time) (*SearchResponse, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || !now.Before(entry.expires) {
+		return nil, false
+	}
+	return cloneSearchResponse(entry.response), true
+}
+
+// put caches a copy of response under key, dropping expired entries so the
+// cache doesn't grow without bound
+func (s *searchCache) put(key string, response *SearchResponse, now time.Time) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k, entry := range s.entries {
+		if !now.Before(entry.expires) {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[key] = searchCacheEntry{response: cloneSearchResponse(response), expires: now.Add(s.ttl)}
+}
+
+// cloneSearchResponse copies response deeply enough that appending to,
+// reordering or editing the results of one copy doesn't affect another
+func cloneSearchResponse(response *SearchResponse) *SearchResponse {
+	clone := *response
+	if response.Results != nil {
+		clone.Results = make([]SearchResult, len(response.Results))
+		for i, r := range response.Results {
+			r.Category = append([]int(nil), r.Category...)
+			clone.Results[i] = r
+		}
+	}
+	if response.Indexers != nil {
+		clone.Indexers = append([]IndexerStatus(nil), response.Indexers...)
+	}
+	return &clone
+}
+
+// normalizeQuery lowercases a query and collapses its whitespace
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+// searchCacheKey is the cache key of a results-endpoint search
+func searchCacheKey(indexerID, query string) string {
+	return "search\x00" + strings.ToLower(indexerID) + "\x00" + normalizeQuery(query)
+}
+
+// torznabCacheKey is the cache key of a torznab search. The API key is not
+// part of params by the time this is called.
+func torznabCacheKey(indexerID string, params url.Values) string {
+	normalized := url.Values{}
+	for k, values := range params {
+		k = strings.ToLower(k)
+		for _, v := range values {
+			switch k {
+			case "q":
+				v = normalizeQuery(v)
+			case "cat":
+				cats := strings.Split(v, ",")
+				for i := range cats {
+					cats[i] = strings.TrimSpace(cats[i])
+				}
+				sort.Strings(cats)
+				v = strings.Join(cats, ",")
+			default:
+				v = strings.TrimSpace(v)
+			}
+			normalized.Add(k, v)
+		}
+	}
+	for _, values := range normalized {
+		sort.Strings(values)
+	}
+	// Encode sorts by key
+	return "torznab\x00" + strings.ToLower(indexerID) + "\x00" + normalized.Encode()
+}
+
+// invalidate discards every cached response
+func (s *searchCache) invalidate() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = make(map[string]searchCacheEntry)
+}
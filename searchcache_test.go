@@ -0,0 +1,131 @@
+package jackett_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cehbz/jackett"
+	"github.com/cehbz/jackett/jacketttest"
+)
+
+// newSearchCacheFake serves an indexer with three Ubuntu releases and an
+// empty one
+func newSearchCacheFake(t *testing.T) *jacketttest.Server {
+	server := jacketttest.NewServer()
+	t.Cleanup(server.Close)
+
+	server.AddIndexer(jackett.Indexer{ID: "test-indexer"},
+		jackett.SearchResult{Title: "Ubuntu 22.04 Desktop", Seeders: 100},
+		jackett.SearchResult{Title: "Ubuntu 22.04 Server", Seeders: 50},
+		jackett.SearchResult{Title: "Kubuntu 22.04", Seeders: 10},
+	)
+	server.AddIndexer(jackett.Indexer{ID: "other"})
+	return server
+}
+
+func TestSearchCacheNormalizesQuery(t *testing.T) {
+	server := newSearchCacheFake(t)
+	clock := jacketttest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	client, err := server.NewClient(jackett.WithClock(clock), jackett.WithSearchCache(30*time.Second))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, query := range []string{"ubuntu 22.04", "  Ubuntu   22.04 ", "UBUNTU 22.04"} {
+		response, err := client.Search(query)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(response.Results) != 3 {
+			t.Fatalf("Expected 3 results, got %d", len(response.Results))
+		}
+	}
+	if n := len(server.Requests()); n != 1 {
+		t.Errorf("Expected 1 request for equivalent queries, got %d", n)
+	}
+
+	if _, err := client.SearchWithIndexer("other", "ubuntu 22.04"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := len(server.Requests()); n != 2 {
+		t.Errorf("Expected a separate request for another indexer, got %d requests", n)
+	}
+
+	clock.Advance(30 * time.Second)
+	if _, err := client.Search("ubuntu 22.04"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := len(server.Requests()); n != 3 {
+		t.Errorf("Expected a new request after the TTL, got %d requests", n)
+	}
+}
+
+func TestSearchCacheReturnsIndependentCopies(t *testing.T) {
+	server := newSearchCacheFake(t)
+	client, err := server.NewClient(jackett.WithSearchCache(time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response, err := client.Search("ubuntu")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	title := response.Results[0].Title
+	response.Results[0].Title = "changed"
+	response.Results = response.Results[:1]
+
+	response, err = client.Search("ubuntu")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(response.Results) != 3 || response.Results[0].Title != title {
+		t.Errorf("Expected cached response to be unaffected by mutation, got %d results starting with %q", len(response.Results), response.Results[0].Title)
+	}
+}
+
+func TestSearchCacheSkipsHooks(t *testing.T) {
+	server := newSearchCacheFake(t)
+	client, err := server.NewClient(jackett.WithSearchCache(time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	keepAll := func(*jackett.SearchResult) bool { return true }
+	for i := 0; i < 2; i++ {
+		if _, err := client.SearchWithHook("all", "ubuntu", keepAll); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if n := len(server.Requests()); n != 2 {
+		t.Errorf("Expected hooked searches to bypass the cache, got %d requests", n)
+	}
+}
+
+func TestSearchCacheTorznab(t *testing.T) {
+	server := newSearchCacheFake(t)
+	client, err := server.NewClient(jackett.WithSearchCache(time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	first := url.Values{"q": {"Ubuntu"}, "cat": {"5000,2000"}}
+	second := url.Values{"q": {" ubuntu"}, "cat": {"2000, 5000"}, "t": {"search"}}
+	for _, params := range []url.Values{first, second} {
+		if _, err := client.TorznabSearch(context.Background(), "test-indexer", params); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if n := len(server.Requests()); n != 1 {
+		t.Errorf("Expected 1 request for equivalent torznab queries, got %d", n)
+	}
+
+	if _, err := client.TorznabSearch(context.Background(), "test-indexer", url.Values{"q": {"ubuntu"}, "cat": {"2000"}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := len(server.Requests()); n != 2 {
+		t.Errorf("Expected a separate request for different categories, got %d requests", n)
+	}
+}
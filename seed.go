@@ -0,0 +1,97 @@
+package jackett
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// MinimumSeedDuration returns MinimumSeedTime, which Jackett reports in
+// seconds, as a duration; zero if the tracker sets none
+func (r SearchResult) MinimumSeedDuration() time.Duration {
+	if r.MinimumSeedTime == nil || *r.MinimumSeedTime <= 0 {
+		return 0
+	}
+	return time.Duration(*r.MinimumSeedTime) * time.Second
+}
+
+// SeedObligation describes what a tracker requires after downloading a
+// release. Like Sonarr and Radarr, it treats the obligation as met once
+// either the ratio or the seed time is reached.
+type SeedObligation struct {
+	// MinimumRatio is the ratio of counted upload to counted download to
+	// reach; zero if none
+	MinimumRatio float64
+	// MinimumSeedTime is how long to seed; zero if none
+	MinimumSeedTime time.Duration
+	// DownloadVolumeFactor and UploadVolumeFactor scale the traffic the
+	// tracker counts: a download factor of 0 is freeleech, an upload factor
+	// of 2 is double upload
+	DownloadVolumeFactor float64
+	UploadVolumeFactor   float64
+}
+
+// SeedObligation returns the tracker's seeding requirements for r
+func (r SearchResult) SeedObligation() SeedObligation {
+	o := SeedObligation{
+		MinimumSeedTime:      r.MinimumSeedDuration(),
+		DownloadVolumeFactor: r.DownloadVolumeFactor,
+		UploadVolumeFactor:   r.UploadVolumeFactor,
+	}
+	if r.MinimumRatio != nil && *r.MinimumRatio > 0 {
+		o.MinimumRatio = *r.MinimumRatio
+	}
+	return o
+}
+
+// None reports whether the tracker requires nothing
+func (o SeedObligation) None() bool {
+	return o.MinimumRatio == 0 && o.MinimumSeedTime == 0
+}
+
+// RequiredUpload returns how many bytes must actually be uploaded to reach
+// MinimumRatio after downloading size bytes, accounting for the volume
+// factors: zero if there is no ratio to reach or the download is freeleech,
+// and -1 if uploads don't count, so that only seeding time can meet the
+// obligation.
+func (o SeedObligation) RequiredUpload(size int64) int64 {
+	if o.MinimumRatio == 0 || o.DownloadVolumeFactor == 0 {
+		return 0
+	}
+	if o.UploadVolumeFactor <= 0 {
+		return -1
+	}
+	return int64(math.Ceil(o.MinimumRatio * float64(size) * o.DownloadVolumeFactor / o.UploadVolumeFactor))
+}
+
+// Met reports whether a release downloaded and uploaded the given number of
+// bytes and seeded for the given time has fulfilled the obligation
+func (o SeedObligation) Met(downloaded, uploaded int64, seeded time.Duration) bool {
+	if o.None() {
+		return true
+	}
+	if o.MinimumSeedTime > 0 && seeded >= o.MinimumSeedTime {
+		return true
+	}
+	if o.MinimumRatio == 0 {
+		return false
+	}
+	counted := float64(downloaded) * o.DownloadVolumeFactor
+	return counted == 0 || float64(uploaded)*o.UploadVolumeFactor/counted >= o.MinimumRatio
+}
+
+// String describes the obligation, e.g. "ratio 1.00 or 72h0m0s seeding"
+func (o SeedObligation) String() string {
+	var parts []string
+	if o.MinimumRatio > 0 {
+		parts = append(parts, fmt.Sprintf("ratio %.2f", o.MinimumRatio))
+	}
+	if o.MinimumSeedTime > 0 {
+		parts = append(parts, fmt.Sprintf("%s seeding", o.MinimumSeedTime))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, " or ")
+}
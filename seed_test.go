@@ -0,0 +1,70 @@
+package jackett
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSeedObligation(t *testing.T) {
+	ratio, seconds := 1.0, int64(72*3600)
+	r := SearchResult{MinimumRatio: &ratio, MinimumSeedTime: &seconds, DownloadVolumeFactor: 1, UploadVolumeFactor: 1}
+
+	if got := r.MinimumSeedDuration(); got != 72*time.Hour {
+		t.Errorf("Expected 72h, got %v", got)
+	}
+	o := r.SeedObligation()
+	if o.None() || o.MinimumRatio != 1 || o.MinimumSeedTime != 72*time.Hour {
+		t.Errorf("Unexpected obligation %+v", o)
+	}
+	if got := o.String(); got != "ratio 1.00 or 72h0m0s seeding" {
+		t.Errorf("Unexpected description %q", got)
+	}
+
+	tests := []struct {
+		name       string
+		downloaded int64
+		uploaded   int64
+		seeded     time.Duration
+		want       bool
+	}{
+		{"neither", 100, 50, time.Hour, false},
+		{"ratio reached", 100, 100, time.Hour, true},
+		{"seed time reached", 100, 0, 72 * time.Hour, true},
+		{"nothing downloaded", 0, 0, 0, true},
+	}
+	for _, tt := range tests {
+		if got := o.Met(tt.downloaded, tt.uploaded, tt.seeded); got != tt.want {
+			t.Errorf("%s: Met = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+
+	if got := o.RequiredUpload(1000); got != 1000 {
+		t.Errorf("Expected 1000 bytes to upload, got %d", got)
+	}
+	o.DownloadVolumeFactor, o.UploadVolumeFactor = 0.5, 2
+	if got := o.RequiredUpload(1000); got != 250 {
+		t.Errorf("Expected half leech and double upload to need 250 bytes, got %d", got)
+	}
+	if !o.Met(100, 25, 0) {
+		t.Error("Expected the volume factors to count towards the ratio")
+	}
+	o.DownloadVolumeFactor = 0
+	if got := o.RequiredUpload(1000); got != 0 || !o.Met(100, 0, 0) {
+		t.Errorf("Expected freeleech to need no upload, got %d", got)
+	}
+	o.DownloadVolumeFactor, o.UploadVolumeFactor = 1, 0
+	if got := o.RequiredUpload(1000); got != -1 || o.Met(100, 1000, 0) {
+		t.Errorf("Expected uncounted uploads never to reach the ratio, got %d", got)
+	}
+}
+
+func TestSeedObligationNone(t *testing.T) {
+	zero := 0.0
+	o := SearchResult{MinimumRatio: &zero, DownloadVolumeFactor: 1, UploadVolumeFactor: 1}.SeedObligation()
+	if !o.None() || o.String() != "none" || !o.Met(100, 0, 0) || o.RequiredUpload(100) != 0 {
+		t.Errorf("Expected no obligation, got %+v", o)
+	}
+	if got := (SearchResult{}).MinimumSeedDuration(); got != 0 {
+		t.Errorf("Expected no seed time, got %v", got)
+	}
+}
@@ -0,0 +1,186 @@
+package jackett
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// SeenStore remembers which releases were already handled, such as the
+// matches reported by a Watcher or the downloads of a client created with
+// WithDownloadSeenStore. Persistent stores prevent duplicate grabs
+// after a restart or crash. Keys are opaque strings without newlines;
+// implementations must be safe for concurrent use. SQLSeenStore keeps them in
+// SQLite; stores backed by other databases such as bbolt only need these two
+// methods.
+type SeenStore interface {
+	// Seen reports whether key was marked
+	Seen(key string) (bool, error)
+	// Mark records keys as seen
+	Mark(keys ...string) error
+}
+
+// MemorySeenStore is a SeenStore that forgets everything when the process
+// exits
+type MemorySeenStore struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// NewMemorySeenStore returns an empty in-memory store
+func NewMemorySeenStore() *MemorySeenStore {
+	return &MemorySeenStore{seen: make(map[string]bool)}
+}
+
+// Seen reports whether key was marked
+func (s *MemorySeenStore) Seen(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seen[key], nil
+}
+
+// Mark records keys as seen
+func (s *MemorySeenStore) Mark(keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		s.seen[key] = true
+	}
+	return nil
+}
+
+// FileSeenStore is a SeenStore persisted in a text file with one key per
+// line. Marks are appended and synced to disk before Mark returns, so they
+// survive a crash; a line torn by a crash mid-write is ignored on load.
+type FileSeenStore struct {
+	mem  *MemorySeenStore
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenFileSeenStore loads the store at path, creating the file if needed.
+// Close it when done.
+func OpenFileSeenStore(path string) (*FileSeenStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open seen store: %w", err)
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read seen store: %w", err)
+	}
+
+	mem := NewMemorySeenStore()
+	lines := strings.Split(string(data), "\n")
+	for _, key := range lines[:len(lines)-1] {
+		if key != "" {
+			mem.seen[key] = true
+		}
+	}
+	// The last line is torn if it lacks its newline; end it so the next
+	// mark starts on a line of its own
+	if lines[len(lines)-1] != "" {
+		if _, err := file.WriteString("\n"); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to repair seen store: %w", err)
+		}
+	}
+	return &FileSeenStore{mem: mem, file: file}, nil
+}
+
+// Seen reports whether key was marked
+func (s *FileSeenStore) Seen(key string) (bool, error) {
+	return s.mem.Seen(key)
+}
+
+// Mark appends keys to the file and syncs it
+func (s *FileSeenStore) Mark(keys ...string) error {
+	var b strings.Builder
+	for _, key := range keys {
+		if strings.ContainsAny(key, "\r\n") {
+			return fmt.Errorf("invalid seen store key %q", key)
+		}
+		b.WriteString(key)
+		b.WriteByte('\n')
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return os.ErrClosed
+	}
+	if _, err := s.file.WriteString(b.String()); err != nil {
+		return fmt.Errorf("failed to write seen store: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync seen store: %w", err)
+	}
+	return s.mem.Mark(keys...)
+}
+
+// Close closes the file
+func (s *FileSeenStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// SQLSeenStore is a SeenStore in a SQLite database, in a table named
+// jackett_seen. The database/sql driver is the caller's choice, e.g.
+// modernc.org/sqlite or github.com/mattn/go-sqlite3, so that this package
+// needs none.
+type SQLSeenStore struct {
+	db *sql.DB
+}
+
+// NewSQLSeenStore returns a store in db, creating its table if needed. The
+// caller keeps ownership of db and closes it when done.
+func NewSQLSeenStore(db *sql.DB) (*SQLSeenStore, error) {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS jackett_seen (release_key TEXT PRIMARY KEY)`); err != nil {
+		return nil, fmt.Errorf("failed to create seen store: %w", err)
+	}
+	return &SQLSeenStore{db: db}, nil
+}
+
+// Seen reports whether key was marked
+func (s *SQLSeenStore) Seen(key string) (bool, error) {
+	var one int
+	err := s.db.QueryRow(`SELECT 1 FROM jackett_seen WHERE release_key = ?`, key).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read seen store: %w", err)
+	}
+	return true, nil
+}
+
+// Mark records keys in one transaction, so that either all or none are
+// marked
+func (s *SQLSeenStore) Mark(keys ...string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to write seen store: %w", err)
+	}
+	defer tx.Rollback()
+	for _, key := range keys {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO jackett_seen (release_key) VALUES (?)`, key); err != nil {
+			return fmt.Errorf("failed to write seen store: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to write seen store: %w", err)
+	}
+	return nil
+}
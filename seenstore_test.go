@@ -0,0 +1,198 @@
+package jackett
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFileSeenStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seen")
+	store, err := OpenFileSeenStore(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := store.Mark("guid:1", "hash:abc"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if seen, _ := store.Seen("hash:abc"); !seen {
+		t.Error("Expected a marked key to be seen")
+	}
+	if err := store.Mark("bad\nkey"); err == nil {
+		t.Error("Expected an error for a key with a newline")
+	}
+	store.Close()
+
+	// Simulate a crash in the middle of appending a key
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("guid:tor")
+	f.Close()
+
+	store, err = OpenFileSeenStore(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer store.Close()
+	for key, want := range map[string]bool{"guid:1": true, "hash:abc": true, "guid:tor": false, "guid:2": false} {
+		if seen, _ := store.Seen(key); seen != want {
+			t.Errorf("Seen(%q) = %v, expected %v", key, seen, want)
+		}
+	}
+
+	if err := store.Mark("guid:2"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "guid:1\nhash:abc\nguid:tor\nguid:2\n" {
+		t.Errorf("Unexpected file contents %q", data)
+	}
+}
+
+func TestWatcherSeenStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seen")
+	searcher := &feedSearcher{}
+	searcher.set(SearchResult{Title: "Show.S01E01", GUID: "a/1", InfoHash: "0123456789abcdef0123456789abcdef01234567"})
+
+	poll := func() []WatchMatch {
+		store, err := OpenFileSeenStore(path)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer store.Close()
+		watcher := &Watcher{Searcher: searcher, Store: store, Queries: []WatchQuery{{Name: "show", Query: "show"}}}
+		return watcher.Poll(context.Background())
+	}
+
+	if matches := poll(); len(matches) != 1 {
+		t.Fatalf("Expected 1 match, got %+v", matches)
+	}
+	// The same release from another indexer, after a restart
+	searcher.set(SearchResult{Title: "Show.S01E01", GUID: "b/7", InfoHash: "0123456789ABCDEF0123456789ABCDEF01234567"})
+	if matches := poll(); len(matches) != 0 {
+		t.Errorf("Expected the release to be remembered across restarts, got %+v", matches)
+	}
+}
+
+// seenDriver is a database/sql driver understanding just the statements of
+// SQLSeenStore, standing in for SQLite. Connections to the same name share
+// a table.
+type seenDriver struct {
+	mu     sync.Mutex
+	tables map[string]map[string]bool
+}
+
+var testSeenDriver = &seenDriver{tables: make(map[string]map[string]bool)}
+
+func init() {
+	sql.Register("jackett-seen-test", testSeenDriver)
+}
+
+func (d *seenDriver) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.tables[name] == nil {
+		d.tables[name] = make(map[string]bool)
+	}
+	return &seenConn{d: d, table: d.tables[name]}, nil
+}
+
+type seenConn struct {
+	d     *seenDriver
+	table map[string]bool
+}
+
+func (c *seenConn) Prepare(query string) (driver.Stmt, error) {
+	return &seenStmt{c: c, query: query}, nil
+}
+func (c *seenConn) Close() error              { return nil }
+func (c *seenConn) Begin() (driver.Tx, error) { return seenTx{}, nil }
+
+type seenTx struct{}
+
+func (seenTx) Commit() error   { return nil }
+func (seenTx) Rollback() error { return nil }
+
+type seenStmt struct {
+	c     *seenConn
+	query string
+}
+
+func (s *seenStmt) Close() error { return nil }
+func (s *seenStmt) NumInput() int {
+	return strings.Count(s.query, "?")
+}
+
+func (s *seenStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.c.d.mu.Lock()
+	defer s.c.d.mu.Unlock()
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS jackett_seen"):
+	case strings.HasPrefix(s.query, "INSERT OR IGNORE INTO jackett_seen"):
+		s.c.table[args[0].(string)] = true
+	default:
+		return nil, errors.New("unsupported statement")
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *seenStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.c.d.mu.Lock()
+	defer s.c.d.mu.Unlock()
+	if !strings.HasPrefix(s.query, "SELECT 1 FROM jackett_seen WHERE release_key = ?") {
+		return nil, errors.New("unsupported statement")
+	}
+	return &seenRows{found: s.c.table[args[0].(string)]}, nil
+}
+
+type seenRows struct {
+	found bool
+}
+
+func (r *seenRows) Columns() []string { return []string{"1"} }
+func (r *seenRows) Close() error      { return nil }
+func (r *seenRows) Next(dest []driver.Value) error {
+	if !r.found {
+		return io.EOF
+	}
+	r.found = false
+	dest[0] = int64(1)
+	return nil
+}
+
+func TestSQLSeenStore(t *testing.T) {
+	open := func() *SQLSeenStore {
+		db, err := sql.Open("jackett-seen-test", t.Name())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		store, err := NewSQLSeenStore(db)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return store
+	}
+
+	store := open()
+	if err := store.Mark("guid:1", "hash:abc"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := store.Mark("guid:1"); err != nil {
+		t.Fatalf("Expected marking a key twice to succeed, got %v", err)
+	}
+
+	// After a restart
+	store = open()
+	for key, want := range map[string]bool{"guid:1": true, "hash:abc": true, "guid:2": false} {
+		if seen, err := store.Seen(key); err != nil || seen != want {
+			t.Errorf("Seen(%q) = %v, %v, expected %v", key, seen, err, want)
+		}
+	}
+}
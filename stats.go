@@ -0,0 +1,147 @@
+package jackett
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// IndexerStats aggregates the searches sent to one indexer over the lifetime
+// of a StatsCollector
+type IndexerStats struct {
+	ID       string
+	Requests int64
+	Errors   int64
+	Results  int64
+	// TotalLatency is summed over the TimedRequests whose latency could be
+	// attributed to this indexer alone. Aggregate searches across all
+	// indexers count towards Requests but not TimedRequests.
+	TotalLatency  time.Duration
+	TimedRequests int64
+}
+
+// ErrorRate is the fraction of requests that failed
+func (s IndexerStats) ErrorRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Requests)
+}
+
+// AverageLatency is the mean latency of the timed requests
+func (s IndexerStats) AverageLatency() time.Duration {
+	if s.TimedRequests == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(s.TimedRequests)
+}
+
+// AverageResults is the mean number of results per successful request
+func (s IndexerStats) AverageResults() float64 {
+	succeeded := s.Requests - s.Errors
+	if succeeded == 0 {
+		return 0
+	}
+	return float64(s.Results) / float64(succeeded)
+}
+
+// StatsCollector records per-indexer search statistics. Attach one to a
+// client with WithStatsCollector; a collector may be shared between clients.
+// It is safe for concurrent use.
+type StatsCollector struct {
+	mu    sync.Mutex
+	stats map[string]*IndexerStats
+}
+
+// NewStatsCollector returns an empty StatsCollector
+func NewStatsCollector() *StatsCollector {
+	return &StatsCollector{stats: make(map[string]*IndexerStats)}
+}
+
+// WithStatsCollector records per-indexer statistics for every search into s
+func WithStatsCollector(s *StatsCollector) Option {
+	return func(c *Client) {
+		c.stats = s
+	}
+}
+
+// Record adds one request to an indexer's statistics. A negative latency
+// means the latency is unknown.
+func (s *StatsCollector) Record(indexerID string, latency time.Duration, results int, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.stats[indexerID]
+	if !ok {
+		entry = &IndexerStats{ID: indexerID}
+		s.stats[indexerID] = entry
+	}
+
+	entry.Requests++
+	if failed {
+		entry.Errors++
+	} else {
+		entry.Results += int64(results)
+	}
+	if latency >= 0 {
+		entry.TotalLatency += latency
+		entry.TimedRequests++
+	}
+}
+
+// Snapshot returns a copy of the statistics of every indexer seen so far,
+// ordered by indexer ID
+func (s *StatsCollector) Snapshot() []IndexerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := make([]IndexerStats, 0, len(s.stats))
+	for _, entry := range s.stats {
+		snapshot = append(snapshot, *entry)
+	}
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].ID < snapshot[j].ID })
+	return snapshot
+}
+
+// Reset discards all recorded statistics
+func (s *StatsCollector) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats = make(map[string]*IndexerStats)
+}
+
+// IndexerStats returns the per-indexer statistics recorded so far, or nil if
+// the client has no StatsCollector
+func (c *Client) IndexerStats() []IndexerStats {
+	if c.stats == nil {
+		return nil
+	}
+	return c.stats.Snapshot()
+}
+
+// recordSearch feeds the outcome of a search that started at start into the
+// client's StatsCollector. Searches of "all" are attributed to each indexer
+// using the statuses Jackett reports; a failed aggregate search cannot be
+// attributed and is not recorded.
+func (c *Client) recordSearch(indexerID string, start time.Time, response *SearchResponse, err error) {
+	if c.stats == nil {
+		return
+	}
+
+	if indexerID != "all" {
+		var results int
+		if response != nil {
+			results = len(response.Results)
+		}
+		c.stats.Record(indexerID, c.clock.Now().Sub(start), results, err != nil)
+		return
+	}
+
+	if err != nil || response == nil {
+		return
+	}
+	for _, status := range response.Indexers {
+		c.stats.Record(status.ID, -1, int(status.Results), status.Status == IndexerStatusError)
+	}
+}
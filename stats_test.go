@@ -0,0 +1,88 @@
+package jackett
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestStatsCollector_Record(t *testing.T) {
+	stats := NewStatsCollector()
+	stats.Record("b", 100*time.Millisecond, 10, false)
+	stats.Record("b", 300*time.Millisecond, 0, true)
+	stats.Record("b", -1, 20, false)
+	stats.Record("a", 50*time.Millisecond, 1, false)
+
+	snapshot := stats.Snapshot()
+	if len(snapshot) != 2 || snapshot[0].ID != "a" || snapshot[1].ID != "b" {
+		t.Fatalf("Expected stats for 'a' and 'b' in order, got %+v", snapshot)
+	}
+
+	b := snapshot[1]
+	if b.Requests != 3 || b.Errors != 1 || b.Results != 30 {
+		t.Errorf("Expected 3 requests, 1 error, 30 results, got %+v", b)
+	}
+	if rate := b.ErrorRate(); rate < 0.33 || rate > 0.34 {
+		t.Errorf("Expected error rate 1/3, got %f", rate)
+	}
+	if avg := b.AverageLatency(); avg != 200*time.Millisecond {
+		t.Errorf("Expected average latency 200ms, got %v", avg)
+	}
+	if avg := b.AverageResults(); avg != 15 {
+		t.Errorf("Expected 15 results per successful request, got %f", avg)
+	}
+
+	stats.Reset()
+	if len(stats.Snapshot()) != 0 {
+		t.Error("Expected no stats after Reset")
+	}
+}
+
+func TestIndexerStats_Searches(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2.0/indexers/all/results":  {statusCode: http.StatusOK, responseBody: hookSearchJSON},
+		"/api/v2.0/indexers/bad/results":  {statusCode: http.StatusInternalServerError, responseBody: "boom"},
+		"/api/v2.0/indexers/good/results": {statusCode: http.StatusOK, responseBody: `{"Results": [{"Title": "One"}, {"Title": "Two"}], "Indexers": []}`},
+	}
+	query := url.Values{"apikey": []string{"test-api-key"}, "Query": []string{"test"}}
+	expectedRequests := []expectedRequest{
+		{method: "GET", url: "/api/v2.0/indexers/all/results", query: query},
+		{method: "GET", url: "/api/v2.0/indexers/bad/results", query: query},
+		{method: "GET", url: "/api/v2.0/indexers/good/results", query: query},
+	}
+
+	client, _, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if client.IndexerStats() != nil {
+		t.Error("Expected nil stats without a collector")
+	}
+	WithStatsCollector(NewStatsCollector())(client)
+
+	if _, err := client.Search("test"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.SearchWithIndexer("bad", "test"); err == nil {
+		t.Fatal("Expected error, got none")
+	}
+	if _, err := client.SearchWithIndexer("good", "test"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	stats := client.IndexerStats()
+	if len(stats) != 3 {
+		t.Fatalf("Expected stats for 3 indexers, got %+v", stats)
+	}
+	a, bad, good := stats[0], stats[1], stats[2]
+	if a.ID != "a" || a.Requests != 1 || a.Results != 3 || a.TimedRequests != 0 {
+		t.Errorf("Expected untimed aggregate request with 3 results for 'a', got %+v", a)
+	}
+	if bad.Errors != 1 || bad.ErrorRate() != 1 {
+		t.Errorf("Expected 'bad' to record one error, got %+v", bad)
+	}
+	if good.Results != 2 || good.TimedRequests != 1 {
+		t.Errorf("Expected 'good' to record 2 results with latency, got %+v", good)
+	}
+}
@@ -0,0 +1,158 @@
+package jackett
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TextfileExporter periodically tests every indexer and writes per-indexer
+// SLI metrics in the Prometheus text format to a file that node_exporter's
+// textfile collector picks up
+type TextfileExporter struct {
+	// Client is the Jackett client whose indexers are probed
+	Client *Client
+	// Dir is the node_exporter textfile collector directory
+	Dir string
+	// Filename defaults to "jackett.prom"
+	Filename string
+	// Interval between probes, 5 minutes by default
+	Interval time.Duration
+	// Concurrency of indexer tests, 4 by default
+	Concurrency int
+
+	mu      sync.Mutex
+	samples map[string]*indexerSamples
+}
+
+// indexerSamples accumulates probe outcomes for one indexer
+type indexerSamples struct {
+	name     string
+	tests    int64
+	failures int64
+	up       bool
+	latency  time.Duration
+}
+
+// Run probes and writes metrics every Interval until ctx is cancelled or the
+// client is closed. The first probe happens immediately.
+func (e *TextfileExporter) Run(ctx context.Context) error {
+	interval := e.Interval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	ticker := e.Client.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.WriteOnce(ctx); err != nil && ctx.Err() == nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-e.Client.Done():
+			return ErrClientClosed
+		case <-ticker.C():
+		}
+	}
+}
+
+// WriteOnce probes every indexer once and rewrites the metrics file
+func (e *TextfileExporter) WriteOnce(ctx context.Context) error {
+	concurrency := e.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	report, err := e.Client.TestAllIndexers(ctx, concurrency)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	if e.samples == nil {
+		e.samples = make(map[string]*indexerSamples)
+	}
+	for _, h := range report {
+		s, ok := e.samples[h.ID]
+		if !ok {
+			s = &indexerSamples{}
+			e.samples[h.ID] = s
+		}
+		s.name = h.Name
+		s.tests++
+		if !h.OK {
+			s.failures++
+		}
+		s.up = h.OK
+		s.latency = h.Latency
+	}
+	content := e.render()
+	e.mu.Unlock()
+
+	return e.write(content)
+}
+
+// render formats the accumulated samples; e.mu must be held
+func (e *TextfileExporter) render() string {
+	ids := make([]string, 0, len(e.samples))
+	for id := range e.samples {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var b strings.Builder
+	metric := func(name, kind, help string, value func(*indexerSamples) string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		for _, id := range ids {
+			s := e.samples[id]
+			fmt.Fprintf(&b, "%s{indexer=\"%s\",name=\"%s\"} %s\n", name, escapeLabel(id), escapeLabel(s.name), value(s))
+		}
+	}
+
+	metric("jackett_indexer_up", "gauge", "Whether the last test of the indexer succeeded.", func(s *indexerSamples) string {
+		if s.up {
+			return "1"
+		}
+		return "0"
+	})
+	metric("jackett_indexer_tests_total", "counter", "Indexer tests run by this exporter.", func(s *indexerSamples) string {
+		return fmt.Sprint(s.tests)
+	})
+	metric("jackett_indexer_test_failures_total", "counter", "Indexer tests that failed.", func(s *indexerSamples) string {
+		return fmt.Sprint(s.failures)
+	})
+	metric("jackett_indexer_success_ratio", "gauge", "Fraction of indexer tests that succeeded.", func(s *indexerSamples) string {
+		return fmt.Sprintf("%g", float64(s.tests-s.failures)/float64(s.tests))
+	})
+	metric("jackett_indexer_latency_seconds", "gauge", "Duration of the last indexer test.", func(s *indexerSamples) string {
+		return fmt.Sprintf("%g", s.latency.Seconds())
+	})
+
+	return b.String()
+}
+
+// write replaces the metrics file atomically so the collector never reads a
+// partial file
+func (e *TextfileExporter) write(content string) error {
+	filename := e.Filename
+	if filename == "" {
+		filename = "jackett.prom"
+	}
+
+	if err := writeFileAtomic(filepath.Join(e.Dir, filename), []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	return nil
+}
+
+// escapeLabel escapes a Prometheus label value
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
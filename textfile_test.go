@@ -0,0 +1,62 @@
+package jackett
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTextfileExporter_WriteOnce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2.0/indexers/all/results/torznab":
+			w.Write([]byte(allIndexersXML))
+		case "/api/v2.0/indexers/configured-indexer/test":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "test-api-key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	dir := t.TempDir()
+	exporter := &TextfileExporter{Client: client, Dir: dir}
+	for i := 0; i < 2; i++ {
+		if err := exporter.WriteOnce(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "jackett.prom"))
+	if err != nil {
+		t.Fatalf("Expected metrics file, got %v", err)
+	}
+	metrics := string(data)
+
+	for _, want := range []string{
+		"# TYPE jackett_indexer_up gauge",
+		`jackett_indexer_up{indexer="configured-indexer",name="Configured Indexer"} 1`,
+		`jackett_indexer_up{indexer="unconfigured-indexer",name="Unconfigured Indexer"} 0`,
+		`jackett_indexer_tests_total{indexer="configured-indexer",name="Configured Indexer"} 2`,
+		`jackett_indexer_test_failures_total{indexer="unconfigured-indexer",name="Unconfigured Indexer"} 2`,
+		`jackett_indexer_success_ratio{indexer="configured-indexer",name="Configured Indexer"} 1`,
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", want, metrics)
+		}
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected only the metrics file in the directory, got %d entries", len(entries))
+	}
+}
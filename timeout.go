@@ -0,0 +1,65 @@
+package jackett
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultRequestTimeout bounds each request whose context has no deadline,
+// unless changed with WithDefaultTimeout. It is generous since aggregate
+// searches over many slow trackers can take minutes.
+const DefaultRequestTimeout = 5 * time.Minute
+
+// requestTimeoutKey is the context key of a timeout set by
+// ContextWithRequestTimeout
+type requestTimeoutKey struct{}
+
+// WithDefaultTimeout bounds each request to Jackett or a tracker whose
+// context has no deadline by d, so a wedged server can't hang a caller that
+// didn't set one. Zero or less disables the default.
+func WithDefaultTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.defaultTimeout = d
+	}
+}
+
+// ContextWithRequestTimeout returns a copy of ctx that bounds each request
+// of calls using it by d, whether or not ctx has a deadline and in place of
+// the client's default timeout. Unlike context.WithTimeout the time starts
+// anew with every request, so one context can serve a long-running loop.
+// Zero or less disables the default timeout for these calls.
+func ContextWithRequestTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, requestTimeoutKey{}, d)
+}
+
+// withRequestTimeout returns req bound by its per-call timeout, or the
+// default timeout if its context has no deadline
+func (c *Client) withRequestTimeout(req *http.Request) (*http.Request, context.CancelFunc) {
+	ctx := req.Context()
+	timeout, ok := ctx.Value(requestTimeoutKey{}).(time.Duration)
+	if !ok {
+		if _, hasDeadline := ctx.Deadline(); hasDeadline {
+			return req, func() {}
+		}
+		timeout = c.defaultTimeout
+	}
+	if timeout <= 0 {
+		return req, func() {}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return req.WithContext(ctx), cancel
+}
+
+// cancelOnClose releases a request's timeout once its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,74 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDefaultTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		if r.URL.Path == "/dl" {
+			w.Write([]byte(testTorrent))
+			return
+		}
+		w.Write([]byte(allIndexersXML))
+	}))
+	defer server.Close()
+
+	client, _ := NewClient(server.URL, "key")
+	if client.defaultTimeout != DefaultRequestTimeout {
+		t.Errorf("Expected the default timeout %v, got %v", DefaultRequestTimeout, client.defaultTimeout)
+	}
+
+	client, _ = NewClientWithOptions(server.URL, "key", WithDefaultTimeout(20*time.Millisecond))
+	if _, err := client.GetIndexers(); !errors.Is(err, ErrServerUnavailable) {
+		t.Errorf("Expected the default timeout to cut off the request, got %v", err)
+	}
+	if _, err := client.DownloadTorrent(server.URL + "/dl"); err == nil {
+		t.Error("Expected the default timeout to cut off the download")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.GetIndexersContext(ctx); err != nil {
+		t.Errorf("Expected the caller's deadline to replace the default, got %v", err)
+	}
+
+	if _, err := client.GetIndexersContext(ContextWithRequestTimeout(context.Background(), 0)); err != nil {
+		t.Errorf("Expected the per-call override to disable the timeout, got %v", err)
+	}
+}
+
+func TestContextWithRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		w.Write([]byte(allIndexersXML))
+	}))
+	defer server.Close()
+
+	client, _ := NewClientWithOptions(server.URL, "key", WithDefaultTimeout(0))
+
+	// The per-call timeout applies even under a caller's generous deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = ContextWithRequestTimeout(ctx, 20*time.Millisecond)
+	if _, err := client.GetIndexersContext(ctx); !errors.Is(err, ErrServerUnavailable) {
+		t.Errorf("Expected the per-call timeout to cut off the request, got %v", err)
+	}
+	if _, err := client.GetIndexersContext(context.Background()); err != nil {
+		t.Errorf("Expected no timeout by default, got %v", err)
+	}
+}
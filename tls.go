@@ -0,0 +1,96 @@
+package jackett
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+)
+
+// WithTLSConfig sets the TLS configuration used to connect to Jackett, e.g.
+// to trust a self-signed certificate (RootCAs), present a client certificate
+// to an mTLS proxy (Certificates) or raise MinVersion. cfg is cloned. It
+// applies only to connections to Jackett's host: downloads from trackers and
+// other hosts keep the default configuration. The http.Client and its
+// transport are copied, not modified; the transport must be an
+// *http.Transport, otherwise NewClientWithOptions fails.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Client) {
+		c.tlsConfig = cfg.Clone()
+	}
+}
+
+// WithInsecureSkipVerify disables verification of Jackett's TLS certificate,
+// leaving the connection open to interception. Certificates of other hosts,
+// such as trackers serving downloads, are still verified. Prefer
+// WithTLSConfig with the certificate in RootCAs. A warning is logged when the
+// client is created, through the WithLogger logger or slog's default one.
+func WithInsecureSkipVerify() Option {
+	return func(c *Client) {
+		c.insecureTLS = true
+	}
+}
+
+// applyTLS routes connections to Jackett's host through a copy of the
+// client's transport carrying the TLS options, once all options are applied
+func (c *Client) applyTLS() {
+	if c.tlsConfig == nil && !c.insecureTLS {
+		return
+	}
+	hc := *c.client
+	var base *http.Transport
+	switch t := hc.Transport.(type) {
+	case nil:
+		base = http.DefaultTransport.(*http.Transport)
+	case *http.Transport:
+		base = t
+	default:
+		c.optionErr = fmt.Errorf("jackett: TLS options need an *http.Transport, got %T", t)
+		return
+	}
+
+	jackett := base.Clone()
+	if c.tlsConfig != nil {
+		jackett.TLSClientConfig = c.tlsConfig.Clone()
+	}
+	if c.insecureTLS {
+		if jackett.TLSClientConfig == nil {
+			jackett.TLSClientConfig = &tls.Config{}
+		}
+		jackett.TLSClientConfig.InsecureSkipVerify = true
+	}
+
+	baseURL, _ := url.Parse(c.baseURL)
+	hc.Transport = &jackettTLSTransport{jackett: jackett, other: base, host: baseURL.Host}
+	c.client = &hc
+}
+
+// jackettTLSTransport sends HTTPS requests for Jackett's host through a
+// transport with Jackett's TLS options, and all others through the default
+// one, so that neither relaxed verification nor client certificates extend
+// to trackers or other third parties
+type jackettTLSTransport struct {
+	jackett *http.Transport
+	other   http.RoundTripper
+	host    string
+}
+
+func (t *jackettTLSTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == "https" && req.URL.Host == t.host {
+		return t.jackett.RoundTrip(req)
+	}
+	return t.other.RoundTrip(req)
+}
+
+// warnInsecureTLS logs that certificate verification is disabled
+func (c *Client) warnInsecureTLS() {
+	if !c.insecureTLS {
+		return
+	}
+	logger := c.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Warn("jackett TLS certificate verification disabled", slog.String("url", c.baseURL))
+}
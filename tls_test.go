@@ -0,0 +1,95 @@
+package jackett
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithTLSConfig(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(allIndexersXML))
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	plain, _ := NewClient(server.URL, "key")
+	if _, err := plain.GetIndexers(); err == nil {
+		t.Fatal("Expected the self-signed certificate to be rejected")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	client, err := NewClientWithOptions(server.URL, "key", WithTLSConfig(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.GetIndexers(); err != nil {
+		t.Errorf("Expected the trusted certificate to be accepted, got %v", err)
+	}
+	if cfg := plain.client.Transport.(*http.Transport).TLSClientConfig; cfg != nil && cfg.RootCAs != nil {
+		t.Error("Expected other clients' transports to be left alone")
+	}
+}
+
+func TestWithInsecureSkipVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(allIndexersXML))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	client, err := NewClientWithOptions(server.URL, "key", WithInsecureSkipVerify(), WithLogger(logger))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.GetIndexers(); err != nil {
+		t.Errorf("Expected verification to be skipped, got %v", err)
+	}
+	if !strings.Contains(logs.String(), "level=WARN") || !strings.Contains(logs.String(), "verification disabled") {
+		t.Errorf("Expected a warning, got %q", logs.String())
+	}
+}
+
+func TestTLSOptionsNeedHTTPTransport(t *testing.T) {
+	custom := &http.Client{Transport: &mockRoundTripper{t: t}}
+	_, err := NewClientWithOptions("https://localhost", "key", WithHTTPClient(custom), WithTLSConfig(&tls.Config{}))
+	if err == nil || !strings.Contains(err.Error(), "*http.Transport") {
+		t.Errorf("Expected an error for a custom RoundTripper, got %v", err)
+	}
+}
+
+func TestTLSOptionsOnlyApplyToJackett(t *testing.T) {
+	jackett := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(allIndexersXML))
+	}))
+	defer jackett.Close()
+	tracker := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("d4:infod4:name4:testee"))
+	}))
+	tracker.Config.ErrorLog = log.New(io.Discard, "", 0)
+	tracker.StartTLS()
+	defer tracker.Close()
+
+	client, err := NewClientWithOptions(jackett.URL, "key", WithInsecureSkipVerify(), WithTimeout(5*time.Second), WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.GetIndexers(); err != nil {
+		t.Errorf("Expected Jackett's certificate to be accepted, got %v", err)
+	}
+	if _, err := client.DownloadTorrentContext(context.Background(), tracker.URL+"/download/1", io.Discard); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("Expected the tracker's certificate to be verified, got %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package torrent
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// maxDepth bounds nesting so hostile input can't exhaust the stack
+const maxDepth = 64
+
+// Decode decodes a single bencoded value. Integers decode to int64, strings
+// to string, lists to []interface{} and dictionaries to
+// map[string]interface{}. Trailing data after the value is an error.
+func Decode(data []byte) (interface{}, error) {
+	d := &decoder{data: data}
+	v, err := d.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("torrent: trailing data at offset %d", d.pos)
+	}
+	return v, nil
+}
+
+// decoder is a cursor over bencoded data
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *decoder) value(depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("torrent: nesting too deep at offset %d", d.pos)
+	}
+	if d.pos >= len(d.data) {
+		return nil, fmt.Errorf("torrent: unexpected end of data")
+	}
+
+	switch c := d.data[d.pos]; {
+	case c == 'i':
+		return d.integer()
+	case c >= '0' && c <= '9':
+		return d.string()
+	case c == 'l':
+		d.pos++
+		var list []interface{}
+		for {
+			if d.pos >= len(d.data) {
+				return nil, fmt.Errorf("torrent: unterminated list")
+			}
+			if d.data[d.pos] == 'e' {
+				d.pos++
+				return list, nil
+			}
+			v, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+	case c == 'd':
+		dict := make(map[string]interface{})
+		err := d.dict(depth, func(key string, start int) error {
+			v, err := d.value(depth + 1)
+			if err != nil {
+				return err
+			}
+			dict[key] = v
+			return nil
+		})
+		return dict, err
+	default:
+		return nil, fmt.Errorf("torrent: unexpected %q at offset %d", c, d.pos)
+	}
+}
+
+// dict walks a dictionary, calling fn for each key with the decoder
+// positioned at the start of its value. fn must consume the value.
+func (d *decoder) dict(depth int, fn func(key string, start int) error) error {
+	if d.pos >= len(d.data) || d.data[d.pos] != 'd' {
+		return fmt.Errorf("torrent: expected dictionary at offset %d", d.pos)
+	}
+	d.pos++
+	for {
+		if d.pos >= len(d.data) {
+			return fmt.Errorf("torrent: unterminated dictionary")
+		}
+		if d.data[d.pos] == 'e' {
+			d.pos++
+			return nil
+		}
+		key, err := d.string()
+		if err != nil {
+			return err
+		}
+		if err := fn(key, d.pos); err != nil {
+			return err
+		}
+	}
+}
+
+func (d *decoder) integer() (int64, error) {
+	start := d.pos
+	end := d.indexFrom(d.pos+1, 'e')
+	if end < 0 {
+		return 0, fmt.Errorf("torrent: unterminated integer at offset %d", start)
+	}
+	digits := string(d.data[start+1 : end])
+	if digits == "" || digits == "-0" || (len(digits) > 1 && digits[0] == '0') || (len(digits) > 2 && digits[:2] == "-0") {
+		return 0, fmt.Errorf("torrent: invalid integer %q at offset %d", digits, start)
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("torrent: invalid integer %q at offset %d", digits, start)
+	}
+	d.pos = end + 1
+	return n, nil
+}
+
+func (d *decoder) string() (string, error) {
+	start := d.pos
+	colon := d.indexFrom(d.pos, ':')
+	if colon < 0 {
+		return "", fmt.Errorf("torrent: invalid string at offset %d", start)
+	}
+	length, err := strconv.Atoi(string(d.data[start:colon]))
+	if err != nil || length < 0 {
+		return "", fmt.Errorf("torrent: invalid string length at offset %d", start)
+	}
+	if length > len(d.data)-colon-1 {
+		return "", fmt.Errorf("torrent: string at offset %d overruns data", start)
+	}
+	d.pos = colon + 1 + length
+	return string(d.data[colon+1 : d.pos]), nil
+}
+
+func (d *decoder) indexFrom(from int, b byte) int {
+	for i := from; i < len(d.data); i++ {
+		if d.data[i] == b {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,44 @@
+package torrent
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	v, err := Decode([]byte("d3:bari-7e3:fool4:spami0eee"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"bar": int64(-7),
+		"foo": []interface{}{"spam", int64(0)},
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("Expected %v, got %v", expected, v)
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"i03e",
+		"i-0e",
+		"ie",
+		"i12",
+		"5:abc",
+		"-1:a",
+		"l",
+		"d3:foo",
+		"x",
+		"i1ei2e",
+		strings.Repeat("l", maxDepth+2) + strings.Repeat("e", maxDepth+2),
+	}
+	for _, data := range tests {
+		if _, err := Decode([]byte(data)); err == nil {
+			t.Errorf("Expected error decoding %q, got none", data)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+// Package torrent decodes .torrent files so that downloads returned by Jackett
+// can be inspected and validated without a full BitTorrent library.
+package torrent
@@ -0,0 +1,151 @@
+package torrent
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// File is one file of a torrent. Path is relative to the torrent's Name
+// directory for multi-file torrents and equal to Name for single-file ones.
+type File struct {
+	Path   string
+	Length int64
+}
+
+// Metainfo is the metadata of a .torrent file
+type Metainfo struct {
+	// InfoHash is the hex-encoded SHA-1 of the info dictionary (BitTorrent v1)
+	InfoHash string
+	// InfoHashV2 is the hex-encoded SHA-256 of the info dictionary, set only
+	// for v2 and hybrid torrents
+	InfoHashV2   string
+	Name         string
+	PieceLength  int64
+	Files        []File
+	TotalSize    int64
+	Private      bool
+	Announce     string
+	AnnounceList [][]string
+	Comment      string
+	CreatedBy    string
+}
+
+// Parse decodes the contents of a .torrent file
+func Parse(data []byte) (*Metainfo, error) {
+	d := &decoder{data: data}
+
+	var info map[string]interface{}
+	var rawInfo []byte
+	top := make(map[string]interface{})
+	err := d.dict(0, func(key string, start int) error {
+		v, err := d.value(1)
+		if err != nil {
+			return err
+		}
+		top[key] = v
+		if key == "info" {
+			info, _ = v.(map[string]interface{})
+			rawInfo = data[start:d.pos]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(data) {
+		return nil, fmt.Errorf("torrent: trailing data at offset %d", d.pos)
+	}
+	if info == nil {
+		return nil, fmt.Errorf("torrent: missing info dictionary")
+	}
+
+	sum := sha1.Sum(rawInfo)
+	m := &Metainfo{InfoHash: hex.EncodeToString(sum[:])}
+	if version, _ := info["meta version"].(int64); version == 2 {
+		sum := sha256.Sum256(rawInfo)
+		m.InfoHashV2 = hex.EncodeToString(sum[:])
+	}
+
+	m.Name, _ = info["name"].(string)
+	m.PieceLength, _ = info["piece length"].(int64)
+	private, _ := info["private"].(int64)
+	m.Private = private == 1
+	m.Announce, _ = top["announce"].(string)
+	m.Comment, _ = top["comment"].(string)
+	m.CreatedBy, _ = top["created by"].(string)
+	m.AnnounceList = parseAnnounceList(top["announce-list"])
+
+	if m.Name == "" {
+		return nil, fmt.Errorf("torrent: info has no name")
+	}
+
+	if length, ok := info["length"].(int64); ok {
+		m.Files = []File{{Path: m.Name, Length: length}}
+	} else if files, ok := info["files"].([]interface{}); ok {
+		for i, f := range files {
+			file, err := parseFile(f)
+			if err != nil {
+				return nil, fmt.Errorf("torrent: file %d: %w", i, err)
+			}
+			m.Files = append(m.Files, file)
+		}
+	} else {
+		return nil, fmt.Errorf("torrent: info has neither length nor files")
+	}
+
+	for _, f := range m.Files {
+		if f.Length < 0 {
+			return nil, fmt.Errorf("torrent: negative length for %s", f.Path)
+		}
+		m.TotalSize += f.Length
+	}
+
+	return m, nil
+}
+
+// parseFile decodes one entry of a multi-file torrent's files list
+func parseFile(v interface{}) (File, error) {
+	dict, ok := v.(map[string]interface{})
+	if !ok {
+		return File{}, fmt.Errorf("not a dictionary")
+	}
+	length, ok := dict["length"].(int64)
+	if !ok {
+		return File{}, fmt.Errorf("missing length")
+	}
+	parts, _ := dict["path"].([]interface{})
+	var path []string
+	for _, p := range parts {
+		s, ok := p.(string)
+		if !ok {
+			return File{}, fmt.Errorf("invalid path")
+		}
+		path = append(path, s)
+	}
+	if len(path) == 0 {
+		return File{}, fmt.Errorf("missing path")
+	}
+	return File{Path: strings.Join(path, "/"), Length: length}, nil
+}
+
+// parseAnnounceList decodes the tiers of the announce-list extension
+func parseAnnounceList(v interface{}) [][]string {
+	tiers, _ := v.([]interface{})
+	var list [][]string
+	for _, t := range tiers {
+		urls, _ := t.([]interface{})
+		var tier []string
+		for _, u := range urls {
+			if s, ok := u.(string); ok {
+				tier = append(tier, s)
+			}
+		}
+		if len(tier) > 0 {
+			list = append(list, tier)
+		}
+	}
+	return list
+}
@@ -0,0 +1,72 @@
+package torrent
+
+import (
+	"reflect"
+	"testing"
+)
+
+const (
+	multiFileInfo = "d5:filesld6:lengthi100e4:pathl3:sub5:a.txteed6:lengthi250e4:pathl5:b.txteee4:name4:test12:piece lengthi16384e6:pieces20:aaaaaaaaaaaaaaaaaaaa7:privatei1ee"
+	multiFile     = "d8:announce23:http://tracker/announce13:announce-listll23:http://tracker/announceel22:http://backup/announceee7:comment5:hello4:info" + multiFileInfo + "e"
+
+	singleFileInfo = "d6:lengthi42e4:name5:x.iso12:piece lengthi16384e6:pieces20:bbbbbbbbbbbbbbbbbbbbe"
+	singleFile     = "d4:info" + singleFileInfo + "e"
+)
+
+func TestParse_MultiFile(t *testing.T) {
+	m, err := Parse([]byte(multiFile))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if m.InfoHash != "b7ae755a2f831b292d00ab184af9151704e07bb1" {
+		t.Errorf("Expected info hash b7ae755a2f831b292d00ab184af9151704e07bb1, got %s", m.InfoHash)
+	}
+	if m.InfoHashV2 != "" {
+		t.Errorf("Expected no v2 info hash, got %s", m.InfoHashV2)
+	}
+	if m.Name != "test" || m.PieceLength != 16384 || !m.Private {
+		t.Errorf("Expected private torrent 'test' with 16 KiB pieces, got %+v", m)
+	}
+	expectedFiles := []File{{Path: "sub/a.txt", Length: 100}, {Path: "b.txt", Length: 250}}
+	if !reflect.DeepEqual(m.Files, expectedFiles) {
+		t.Errorf("Expected files %v, got %v", expectedFiles, m.Files)
+	}
+	if m.TotalSize != 350 {
+		t.Errorf("Expected total size 350, got %d", m.TotalSize)
+	}
+	if m.Announce != "http://tracker/announce" || len(m.AnnounceList) != 2 || m.Comment != "hello" {
+		t.Errorf("Expected announce and comment metadata, got %+v", m)
+	}
+}
+
+func TestParse_SingleFile(t *testing.T) {
+	m, err := Parse([]byte(singleFile))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if m.InfoHash != "be5e82747593ae842886a0ed7bbad08002c1a1f9" {
+		t.Errorf("Expected info hash be5e82747593ae842886a0ed7bbad08002c1a1f9, got %s", m.InfoHash)
+	}
+	if len(m.Files) != 1 || m.Files[0].Path != "x.iso" || m.TotalSize != 42 {
+		t.Errorf("Expected single file x.iso of 42 bytes, got %+v", m.Files)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"not bencode":  "<html>not a torrent</html>",
+		"no info":      "d8:announce3:urle",
+		"no files":     "d4:infod4:name1:xee",
+		"trailing":     singleFile + "x",
+		"truncated":    singleFile[:20],
+		"not dict":     "li1ee",
+		"bad filelist": "d4:infod5:filesl1:xe4:name1:xee",
+	}
+	for name, data := range tests {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("%s: expected error, got none", name)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package jackett
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// torznabFeed is the RSS document returned by torznab searches
+type torznabFeed struct {
+	XMLName xml.Name `xml:"rss"`
+	Channel struct {
+		Items []torznabItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type torznabItem struct {
+	Title       string `xml:"title"`
+	GUID        string `xml:"guid"`
+	Link        string `xml:"link"`
+	Comments    string `xml:"comments"`
+	PubDate     string `xml:"pubDate"`
+	Size        int64  `xml:"size"`
+	Description string `xml:"description"`
+	Categories  []int  `xml:"category"`
+	Indexer     struct {
+		ID   string `xml:"id,attr"`
+		Name string `xml:",chardata"`
+	} `xml:"jackettindexer"`
+	Enclosure struct {
+		URL    string `xml:"url,attr"`
+		Length int64  `xml:"length,attr"`
+		Type   string `xml:"type,attr"`
+	} `xml:"enclosure"`
+	Attrs []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value,attr"`
+	} `xml:"attr"`
+}
+
+// torznabErrorBody is the payload of a torznab error response
+type torznabErrorBody struct {
+	XMLName     xml.Name `xml:"error"`
+	Code        string   `xml:"code,attr"`
+	Description string   `xml:"description,attr"`
+}
+
+// TorznabSearch runs a raw torznab query against an indexer ("all" for every
+// configured indexer). params holds the torznab parameters such as t, q,
+// imdbid, season, cat, limit and offset; the API key is added automatically
+// and imdbid may be given with or without its "tt" prefix.
+func (c *Client) TorznabSearch(ctx context.Context, indexerID string, params url.Values) (*SearchResponse, error) {
+	query := url.Values{}
+	for k, v := range params {
+		query[k] = v
+	}
+	query.Del("apikey")
+	if query.Get("t") == "" {
+		query.Set("t", "search")
+	}
+	normalizeIMDbParam(query)
+
+	start := c.clock.Now()
+	response, err := c.torznabResponse(ctx, indexerID, query)
+	c.recordHistory(ctx, "torznab", indexerID, query.Get("q"), query, start, response, err)
+	return response, err
+}
+
+// torznabResponse obtains the response to a torznab search from the cache,
+// a concurrent identical search or Jackett. Searches with a per-call API key
+// always go to Jackett.
+func (c *Client) torznabResponse(ctx context.Context, indexerID string, query url.Values) (*SearchResponse, error) {
+	owned := url.Values{}
+	for k, v := range query {
+		owned[k] = v
+	}
+	if hasAPIKeyOverride(ctx) {
+		return c.fetchTorznab(ctx, indexerID, owned)
+	}
+
+	key := torznabCacheKey(indexerID, query)
+	if response, ok := c.searchCache.get(key, c.clock.Now()); ok {
+		return response, nil
+	}
+	return c.coalesceSearch(key, func() (*SearchResponse, error) {
+		response, err := c.fetchTorznab(ctx, indexerID, owned)
+		if err == nil {
+			c.searchCache.put(key, response, c.clock.Now())
+		}
+		return response, err
+	})
+}
+
+// fetchTorznab requests a torznab search; query must not be shared
+func (c *Client) fetchTorznab(ctx context.Context, indexerID string, query url.Values) (*SearchResponse, error) {
+	if err := c.waitForRateLimit(ctx, indexerID); err != nil {
+		return nil, fmt.Errorf("torznab search error: %w", err)
+	}
+	query.Set("apikey", c.apiKeyFor(ctx))
+
+	start := c.clock.Now()
+	endpoint := fmt.Sprintf("/api/v2.0/indexers/%s/results/torznab/api", url.PathEscape(indexerID))
+	respData, err := c.doRequest(ctx, "GET", endpoint, query, nil)
+	if err != nil {
+		err = fmt.Errorf("torznab search error: %w", err)
+		c.recordSearch(indexerID, start, nil, err)
+		c.audit("torznab", indexerID, redactedQuery(query), start, 0, err)
+		return nil, err
+	}
+
+	results, err := parseTorznabFeed(respData)
+	if err != nil {
+		c.recordSearch(indexerID, start, nil, err)
+		c.audit("torznab", indexerID, redactedQuery(query), start, 0, err)
+		return nil, err
+	}
+
+	response := &SearchResponse{Results: results}
+	if c.maxResults > 0 && len(results) > c.maxResults {
+		response.Results = results[:c.maxResults]
+		response.Truncated = true
+	}
+	c.recordSearch(indexerID, start, response, nil)
+	c.audit("torznab", indexerID, redactedQuery(query), start, len(response.Results), nil)
+	c.postProcess(response)
+	return response, nil
+}
+
+// parseTorznabFeed converts a torznab RSS document into search results
+func parseTorznabFeed(data []byte) ([]SearchResult, error) {
+	if torznabErr, ok := parseTorznabError(data); ok {
+		return nil, torznabErr
+	}
+
+	var feed torznabFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("failed to decode torznab response: %w", err)
+	}
+
+	results := make([]SearchResult, 0, len(feed.Channel.Items))
+	for _, item := range feed.Channel.Items {
+		results = append(results, item.toSearchResult())
+	}
+	return results, nil
+}
+
+func (item torznabItem) toSearchResult() SearchResult {
+	r := SearchResult{
+		Title:                item.Title,
+		GUID:                 item.GUID,
+		Link:                 item.Link,
+		Details:              item.Comments,
+		PublishDate:          item.PubDate,
+		Size:                 item.Size,
+		Tracker:              strings.TrimSpace(item.Indexer.Name),
+		TrackerId:            item.Indexer.ID,
+		DownloadVolumeFactor: 1,
+		UploadVolumeFactor:   1,
+		ContentType:          item.Enclosure.Type,
+	}
+	r.PublishDate = rssDate(item.PubDate)
+	if r.Size == 0 {
+		r.Size = item.Enclosure.Length
+	}
+	if r.Link == "" {
+		r.Link = item.Enclosure.URL
+	}
+	if item.Description != "" {
+		description := item.Description
+		r.Description = &description
+	}
+
+	seen := make(map[int]bool)
+	addCategory := func(id int) {
+		if !seen[id] {
+			seen[id] = true
+			r.Category = append(r.Category, id)
+		}
+	}
+	for _, id := range item.Categories {
+		addCategory(id)
+	}
+
+	intPtr := func(s string) *int {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil
+		}
+		return &n
+	}
+	strPtr := func(s string) *string { return &s }
+
+	for _, attr := range item.Attrs {
+		v := attr.Value
+		switch strings.ToLower(attr.Name) {
+		case "seeders":
+			r.Seeders, _ = strconv.Atoi(v)
+		case "peers":
+			r.Peers, _ = strconv.Atoi(v)
+		case "size":
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				r.Size = n
+			}
+		case "category":
+			if id, err := strconv.Atoi(v); err == nil {
+				addCategory(id)
+			}
+		case "hydraindexername":
+			// NZBHydra2 names the source indexer in an attribute
+			if r.Tracker == "" {
+				r.Tracker = v
+			}
+		case "usenetdate":
+			r.UsenetDate = rssDate(v)
+		case "infohash":
+			r.InfoHash = v
+		case "magneturl":
+			r.MagnetURI = v
+		case "imdb", "imdbid":
+			if id, err := ParseIMDbID(v); err == nil {
+				r.Imdb = &id
+			}
+		case "tmdbid":
+			r.TMDb = intPtr(v)
+		case "tvdbid":
+			r.TVDBId = intPtr(v)
+		case "tvmazeid":
+			r.TVMazeId = intPtr(v)
+		case "rageid":
+			r.RageID = intPtr(v)
+		case "traktid":
+			r.TraktId = intPtr(v)
+		case "doubanid":
+			r.DoubanId = intPtr(v)
+		case "year":
+			r.Year = intPtr(v)
+		case "grabs":
+			r.Grabs = intPtr(v)
+		case "files":
+			r.Files = intPtr(v)
+		case "downloadvolumefactor":
+			r.DownloadVolumeFactor, _ = strconv.ParseFloat(v, 64)
+		case "uploadvolumefactor":
+			r.UploadVolumeFactor, _ = strconv.ParseFloat(v, 64)
+		case "minimumratio":
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				r.MinimumRatio = &f
+			}
+		case "minimumseedtime":
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				r.MinimumSeedTime = &n
+			}
+		case "genre":
+			genres := strings.Split(v, ",")
+			for i := range genres {
+				genres[i] = strings.TrimSpace(genres[i])
+			}
+			r.Genres = &genres
+		case "poster", "coverurl":
+			r.Poster = strPtr(v)
+		case "author":
+			r.Author = strPtr(v)
+		case "booktitle":
+			r.BookTitle = strPtr(v)
+		case "publisher":
+			r.Publisher = strPtr(v)
+		case "artist":
+			r.Artist = strPtr(v)
+		case "album":
+			r.Album = strPtr(v)
+		case "label":
+			r.Label = strPtr(v)
+		case "track":
+			r.Track = strPtr(v)
+		}
+	}
+
+	return r
+}
+
+// rssDateLayouts are the RFC 822 forms feeds use for dates: with or
+// without seconds, with a numeric or named zone, and with one- or two-digit
+// days. RFC 3339 is accepted too.
+var rssDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	"Mon, 2 Jan 2006 15:04 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04 -0700",
+	time.RFC3339Nano,
+}
+
+// rssDate converts an RSS date to RFC 3339, or returns "" if it is in none
+// of rssDateLayouts, so that date fields only ever hold RFC 3339
+func rssDate(s string) string {
+	s = strings.TrimSpace(s)
+	for _, layout := range rssDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC().Format(time.RFC3339)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+package jackett
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const torznabFeedXML = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom" xmlns:torznab="http://torznab.com/schemas/2015/feed">
+  <channel>
+    <title>Test Indexer</title>
+    <item>
+      <title>Show.Name.S01E02.1080p.WEB.x264</title>
+      <guid>https://tracker.example/details/1</guid>
+      <jackettindexer id="test-indexer">Test Indexer</jackettindexer>
+      <type>private</type>
+      <comments>https://tracker.example/details/1</comments>
+      <pubDate>Mon, 01 Jan 2024 12:00:00 +0000</pubDate>
+      <size>1073741824</size>
+      <grabs>12</grabs>
+      <description>A description</description>
+      <link>http://localhost:9117/dl/test-indexer/?jackett_apikey=key&amp;path=abc</link>
+      <category>5000</category>
+      <category>5040</category>
+      <enclosure url="http://localhost:9117/dl/test-indexer/?jackett_apikey=key&amp;path=abc" length="1073741824" type="application/x-bittorrent" />
+      <torznab:attr name="category" value="5000" />
+      <torznab:attr name="category" value="100001" />
+      <torznab:attr name="seeders" value="42" />
+      <torznab:attr name="peers" value="50" />
+      <torznab:attr name="infohash" value="0123456789abcdef0123456789abcdef01234567" />
+      <torznab:attr name="magneturl" value="magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567" />
+      <torznab:attr name="imdbid" value="tt0944947" />
+      <torznab:attr name="tvdbid" value="121361" />
+      <torznab:attr name="downloadvolumefactor" value="0" />
+      <torznab:attr name="uploadvolumefactor" value="1" />
+      <torznab:attr name="minimumratio" value="1" />
+      <torznab:attr name="minimumseedtime" value="172800" />
+    </item>
+  </channel>
+</rss>`
+
+func TestParseTorznabFeed(t *testing.T) {
+	results, err := parseTorznabFeed([]byte(torznabFeedXML))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+
+	r := results[0]
+	if r.Title != "Show.Name.S01E02.1080p.WEB.x264" || r.Tracker != "Test Indexer" || r.TrackerId != "test-indexer" {
+		t.Errorf("Unexpected identity fields: %+v", r)
+	}
+	if r.Seeders != 42 || r.Peers != 50 || r.Size != 1073741824 {
+		t.Errorf("Unexpected stats: seeders %d, peers %d, size %d", r.Seeders, r.Peers, r.Size)
+	}
+	if r.PublishDate != "2024-01-01T12:00:00Z" {
+		t.Errorf("Expected RFC 3339 publish date, got '%s'", r.PublishDate)
+	}
+	if len(r.Category) != 3 {
+		t.Errorf("Expected 3 distinct categories, got %v", r.Category)
+	}
+	if r.Imdb == nil || *r.Imdb != 944947 {
+		t.Errorf("Expected IMDb 944947, got %v", r.Imdb)
+	}
+	if r.DownloadVolumeFactor != 0 || r.MinimumSeedTime == nil || *r.MinimumSeedTime != 172800 {
+		t.Errorf("Unexpected tracker rules: dvf %v, seed time %v", r.DownloadVolumeFactor, r.MinimumSeedTime)
+	}
+	if r.Description == nil || *r.Description != "A description" {
+		t.Errorf("Expected description, got %v", r.Description)
+	}
+}
+
+func TestParseTorznabFeed_Error(t *testing.T) {
+	_, err := parseTorznabFeed([]byte(invalidAPIKeyXML))
+	if err == nil || !strings.Contains(err.Error(), "Invalid API Key") {
+		t.Errorf("Expected torznab error, got %v", err)
+	}
+}
+
+func TestTorznabSearch(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2.0/indexers/test-indexer/results/torznab/api": {statusCode: http.StatusOK, responseBody: torznabFeedXML},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "GET", url: "/api/v2.0/indexers/test-indexer/results/torznab/api", query: url.Values{
+			"apikey": []string{"test-api-key"},
+			"t":      []string{"tvsearch"},
+			"q":      []string{"show name"},
+			"season": []string{"1"},
+		}},
+	}
+
+	client, mockTransport, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response, err := client.TorznabSearch(context.Background(), "test-indexer", url.Values{
+		"t": []string{"tvsearch"}, "q": []string{"show name"}, "season": []string{"1"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(response.Results) != 1 {
+		t.Errorf("Expected 1 result, got %d", len(response.Results))
+	}
+
+	if mockTransport.requestIndex != len(mockTransport.expectedRequests) {
+		t.Errorf("Not all expected requests were made")
+	}
+}
+
+func TestRSSDate(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Mon, 01 Jan 2024 12:00:00 +0000", "2024-01-01T12:00:00Z"},
+		{"Mon, 01 Jan 2024 12:00:00 GMT", "2024-01-01T12:00:00Z"},
+		{"Mon, 1 Jan 2024 13:00 +0100", "2024-01-01T12:00:00Z"},
+		{"1 Jan 2024 12:00:00 +0000", "2024-01-01T12:00:00Z"},
+		{"2024-01-01T13:00:00+01:00", "2024-01-01T12:00:00Z"},
+		{"yesterday", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := rssDate(tt.in); got != tt.want {
+			t.Errorf("rssDate(%q) = %q, expected %q", tt.in, got, tt.want)
+		}
+	}
+}
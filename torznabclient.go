@@ -0,0 +1,174 @@
+package jackett
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// TorznabClient searches any torznab-compatible endpoint, such as
+// NZBHydra2's or a standalone indexer's, behind the same Searcher and
+// TorrentDownloader interfaces as Client. Unlike Client it only needs the
+// torznab XML API, not Jackett's JSON endpoints. It is safe for concurrent
+// use.
+type TorznabClient struct {
+	client *http.Client
+	apiURL string
+	apiKey string
+	// MaxDownloadSize limits torrent downloads like WithMaxDownloadSize
+	MaxDownloadSize int64
+}
+
+var (
+	_ Searcher          = (*TorznabClient)(nil)
+	_ TorrentDownloader = (*TorznabClient)(nil)
+)
+
+// NewTorznabClient initializes a client for the torznab API at apiURL, the
+// URL queried with ?t=search, e.g. "http://localhost:5076/torznab/api" for
+// NZBHydra2. Use DetectBackend to find it from a server's base URL.
+func NewTorznabClient(apiURL, apiKey string, httpClient ...*http.Client) (*TorznabClient, error) {
+	apiURL, _, err := normalizeBaseURL(apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	client := newDefaultHTTPClient()
+	if len(httpClient) > 0 && httpClient[0] != nil {
+		client = httpClient[0]
+	}
+	return &TorznabClient{
+		client:          client,
+		apiURL:          apiURL,
+		apiKey:          apiKey,
+		MaxDownloadSize: defaultMaxDownloadSize,
+	}, nil
+}
+
+// Search searches every indexer behind the endpoint
+func (t *TorznabClient) Search(query string) (*SearchResponse, error) {
+	return t.SearchWithIndexer("all", query)
+}
+
+// SearchWithIndexer searches one indexer of an aggregator, or "all". The
+// indexer is selected with NZBHydra2's indexers parameter, which plain
+// torznab endpoints ignore.
+func (t *TorznabClient) SearchWithIndexer(indexerID, query string) (*SearchResponse, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	return t.TorznabSearch(context.Background(), indexerID, params)
+}
+
+// TorznabSearch runs a raw torznab query; see Client.TorznabSearch
+func (t *TorznabClient) TorznabSearch(ctx context.Context, indexerID string, params url.Values) (*SearchResponse, error) {
+	query := url.Values{}
+	for k, v := range params {
+		query[k] = v
+	}
+	if query.Get("t") == "" {
+		query.Set("t", "search")
+	}
+	normalizeIMDbParam(query)
+	if indexerID != "" && indexerID != "all" {
+		query.Set("indexers", indexerID)
+	}
+
+	data, err := t.get(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("torznab search error: %w", err)
+	}
+	results, err := parseTorznabFeed(data)
+	if err != nil {
+		return nil, err
+	}
+	return &SearchResponse{Results: results}, nil
+}
+
+// GetCaps retrieves the capabilities and categories of the endpoint
+func (t *TorznabClient) GetCaps(ctx context.Context) (*Caps, []Category, error) {
+	data, err := t.get(ctx, url.Values{"t": {"caps"}})
+	if err != nil {
+		return nil, nil, fmt.Errorf("get caps error: %w", err)
+	}
+	if torznabErr, ok := parseTorznabError(data); ok {
+		return nil, nil, fmt.Errorf("get caps error: %w", torznabErr)
+	}
+
+	var tc TorznabCaps
+	if err := xml.Unmarshal(data, &tc); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode caps: %w", err)
+	}
+	caps, categories := convertCaps(tc)
+	return caps, categories, nil
+}
+
+// DownloadTorrent downloads the .torrent file behind a result link
+func (t *TorznabClient) DownloadTorrent(link string) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := t.DownloadTorrentContext(context.Background(), link, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DownloadTorrentContext streams the .torrent file behind a result link to
+// w. Aggregators embed their API key in result links, so none is added.
+func (t *TorznabClient) DownloadTorrentContext(ctx context.Context, link string, w io.Writer) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", link, nil)
+	if err != nil {
+		return 0, fmt.Errorf("invalid download link: %w", err)
+	}
+
+	resp, err := stopAtMagnet(t.client, nil).Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("download error: %w: %w", ErrServerUnavailable, err)
+	}
+	defer resp.Body.Close()
+	return copyTorrent(resp, w, t.MaxDownloadSize)
+}
+
+// get sends a torznab request with the API key added to query
+func (t *TorznabClient) get(ctx context.Context, query url.Values) ([]byte, error) {
+	apiURL, err := url.Parse(t.apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse API URL: %w", err)
+	}
+	query.Set("apikey", t.apiKey)
+	apiURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	return fetchXML(t.client, req)
+}
+
+// fetchXML sends req and returns the body of a 2xx response. Torznab errors
+// are returned as *TorznabError, whatever the status code.
+func fetchXML(hc *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := hc.Do(req)
+	if err != nil {
+		if req.Context().Err() != nil {
+			return nil, fmt.Errorf("request failed: %w", err)
+		}
+		return nil, fmt.Errorf("request failed: %w: %w", ErrServerUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		if torznabErr, ok := parseTorznabError(body); ok {
+			return nil, torznabErr
+		}
+		return nil, &statusError{code: resp.StatusCode, body: string(body), path: req.URL.Path}
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return data, nil
+}
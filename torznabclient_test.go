@@ -0,0 +1,137 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const testCapsXML = `<?xml version="1.0" encoding="UTF-8"?>
+<caps>
+  <server title="Jackett" />
+  <limits default="50" max="100" />
+  <searching>
+    <search available="yes" supportedParams="q" />
+    <tv-search available="yes" supportedParams="q,season,ep" />
+  </searching>
+  <categories>
+    <category id="5000" name="TV">
+      <subcat id="5040" name="TV/HD" />
+    </category>
+  </categories>
+</caps>`
+
+func newHydraTestServer(t *testing.T, got *url.Values) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/torznab/api" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("apikey") != "key" {
+			w.Write([]byte(invalidAPIKeyXML))
+			return
+		}
+		if got != nil {
+			*got = r.URL.Query()
+		}
+		switch r.URL.Query().Get("t") {
+		case "caps":
+			w.Write([]byte(testCapsXML))
+		default:
+			w.Write([]byte(torznabFeedXML))
+		}
+	}))
+}
+
+func TestTorznabClientSearch(t *testing.T) {
+	var got url.Values
+	server := newHydraTestServer(t, &got)
+	defer server.Close()
+
+	client, err := NewTorznabClient(server.URL+"/torznab/api", "key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response, err := client.Search("show")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(response.Results) != 1 || response.Results[0].Seeders != 42 {
+		t.Errorf("Unexpected results %+v", response.Results)
+	}
+	if got.Get("t") != "search" || got.Get("q") != "show" || got.Has("indexers") {
+		t.Errorf("Unexpected parameters %v", got)
+	}
+
+	if _, err := client.SearchWithIndexer("Tracker A", "show"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got.Get("indexers") != "Tracker A" {
+		t.Errorf("Expected the indexer to be selected, got %v", got)
+	}
+
+	params := url.Values{"t": {"tvsearch"}, "tvdbid": {"121361"}, "apikey": {"other"}}
+	if _, err := client.TorznabSearch(context.Background(), "all", params); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got.Get("t") != "tvsearch" || got.Get("tvdbid") != "121361" {
+		t.Errorf("Unexpected parameters %v", got)
+	}
+	if params.Get("apikey") != "other" {
+		t.Error("Expected the caller's parameters not to be modified")
+	}
+
+	caps, categories, err := client.GetCaps(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if caps.Searching.TVSearch == nil || len(categories) != 1 || categories[0].ID != 5000 {
+		t.Errorf("Unexpected caps %+v, %+v", caps, categories)
+	}
+
+	bad, _ := NewTorznabClient(server.URL+"/torznab/api", "wrong")
+	if _, err := bad.Search("show"); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey, got %v", err)
+	}
+}
+
+func TestTorznabFeedHydraIndexer(t *testing.T) {
+	feed := `<rss><channel><item><title>Show</title>` +
+		`<torznab:attr name="hydraIndexerName" value="Tracker A" /></item></channel></rss>`
+	results, err := parseTorznabFeed([]byte(feed))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if results[0].Tracker != "Tracker A" {
+		t.Errorf("Expected the NZBHydra2 indexer name, got %q", results[0].Tracker)
+	}
+}
+
+func TestTorznabClientDownloadTorrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/getnzb/1":
+			w.Write([]byte(testTorrent))
+		case "/getnzb/2":
+			http.Redirect(w, r, "magnet:?xt=urn:btih:abc", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := NewTorznabClient(server.URL+"/torznab/api", "key")
+	data, err := client.DownloadTorrent(server.URL + "/getnzb/1")
+	if err != nil || string(data) != testTorrent {
+		t.Fatalf("Unexpected download %q, %v", data, err)
+	}
+
+	var magnet *MagnetRedirectError
+	if _, err := client.DownloadTorrent(server.URL + "/getnzb/2"); !errors.As(err, &magnet) {
+		t.Errorf("Expected a MagnetRedirectError, got %v", err)
+	}
+}
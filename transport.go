@@ -0,0 +1,91 @@
+package jackett
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportConfig tunes the connection pool and network timeouts of the HTTP
+// transport a client builds for itself
+type TransportConfig struct {
+	// MaxIdleConns limits idle connections across all hosts
+	MaxIdleConns int
+	// MaxIdleConnsPerHost limits idle connections kept per host. Nearly all
+	// of a client's requests go to one Jackett instance, so this should be
+	// at least the number of concurrent requests to avoid opening (and
+	// leaving in TIME_WAIT) a new connection for most of them.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits all connections per host; zero means no limit
+	MaxConnsPerHost int
+	// IdleConnTimeout closes connections idle for longer
+	IdleConnTimeout time.Duration
+	// DialTimeout bounds establishing a TCP connection
+	DialTimeout time.Duration
+	// KeepAlive is the TCP keep-alive interval; negative disables keep-alives
+	KeepAlive time.Duration
+	// TLSHandshakeTimeout bounds the TLS handshake
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout bounds the wait for response headers after the
+	// request is written; zero means no limit. Aggregate searches can take
+	// a long time before Jackett answers, so keep this generous.
+	ResponseHeaderTimeout time.Duration
+	// HTTP2 attempts HTTP/2 for HTTPS connections
+	HTTP2 bool
+	// TLSConfig, if set, is cloned into the transport (see WithTLSConfig)
+	TLSConfig *tls.Config
+}
+
+// DefaultTransportConfig returns the transport settings used when no
+// http.Client is supplied to NewClient
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   32,
+		IdleConnTimeout:       90 * time.Second,
+		DialTimeout:           10 * time.Second,
+		KeepAlive:             30 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 2 * time.Minute,
+		HTTP2:                 true,
+	}
+}
+
+// NewTransport builds an http.Transport with these settings, honoring the
+// proxy environment variables like http.DefaultTransport does
+func (cfg TransportConfig) NewTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   cfg.DialTimeout,
+		KeepAlive: cfg.KeepAlive,
+	}
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:   cfg.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: cfg.ResponseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+		ForceAttemptHTTP2:     cfg.HTTP2,
+		TLSClientConfig:       cfg.TLSConfig.Clone(),
+	}
+}
+
+// newDefaultHTTPClient returns the http.Client used when none is supplied
+func newDefaultHTTPClient() *http.Client {
+	return &http.Client{Transport: DefaultTransportConfig().NewTransport()}
+}
+
+// WithTransportConfig replaces the client's transport with one built from
+// cfg. Other settings of the http.Client, such as its timeout and redirect
+// policy, are kept; the http.Client itself is copied, not modified.
+func WithTransportConfig(cfg TransportConfig) Option {
+	return func(c *Client) {
+		hc := *c.client
+		hc.Transport = cfg.NewTransport()
+		c.client = &hc
+	}
+}
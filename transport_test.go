@@ -0,0 +1,56 @@
+package jackett
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewClientDefaultTransport(t *testing.T) {
+	client, err := NewClient("http://localhost:9117", "test-api-key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if client.client == http.DefaultClient {
+		t.Fatal("Expected a dedicated http.Client instead of http.DefaultClient")
+	}
+	transport, ok := client.client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected *http.Transport, got %T", client.client.Transport)
+	}
+	if transport.MaxIdleConnsPerHost != DefaultTransportConfig().MaxIdleConnsPerHost {
+		t.Errorf("Expected MaxIdleConnsPerHost %d, got %d", DefaultTransportConfig().MaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	}
+	if transport.Proxy == nil {
+		t.Error("Expected proxy settings to be taken from the environment")
+	}
+}
+
+func TestWithTransportConfig(t *testing.T) {
+	custom := &http.Client{Timeout: time.Minute}
+	cfg := DefaultTransportConfig()
+	cfg.MaxIdleConnsPerHost = 4
+	cfg.MaxConnsPerHost = 8
+	cfg.HTTP2 = false
+
+	client, err := NewClientWithOptions("http://localhost:9117", "test-api-key",
+		WithHTTPClient(custom),
+		WithTransportConfig(cfg),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if client.client.Timeout != time.Minute {
+		t.Errorf("Expected the http.Client timeout to be kept, got %v", client.client.Timeout)
+	}
+	if custom.Transport != nil {
+		t.Error("Expected the supplied http.Client to be left unmodified")
+	}
+	transport := client.client.Transport.(*http.Transport)
+	if transport.MaxIdleConnsPerHost != 4 || transport.MaxConnsPerHost != 8 || transport.ForceAttemptHTTP2 {
+		t.Errorf("Expected the configured transport, got MaxIdleConnsPerHost=%d MaxConnsPerHost=%d HTTP2=%v",
+			transport.MaxIdleConnsPerHost, transport.MaxConnsPerHost, transport.ForceAttemptHTTP2)
+	}
+}
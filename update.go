@@ -0,0 +1,213 @@
+package jackett
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultReleasesURL is where Jackett itself looks for updates
+const defaultReleasesURL = "https://api.github.com/repos/Jackett/Jackett/releases"
+
+// Release describes a published Jackett release
+type Release struct {
+	Version     string    `json:"version"`
+	Name        string    `json:"name"`
+	Notes       string    `json:"notes"`
+	URL         string    `json:"url"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// UpdateChangelog describes the releases newer than the running instance
+type UpdateChangelog struct {
+	CurrentVersion  string    `json:"current_version"`
+	LatestVersion   string    `json:"latest_version"`
+	UpdateAvailable bool      `json:"update_available"`
+	Releases        []Release `json:"releases"` // newest first
+}
+
+// UpdateStatus summarizes whether a Jackett instance is current
+type UpdateStatus struct {
+	CurrentVersion  string `json:"current_version"`
+	LatestVersion   string `json:"latest_version"`
+	UpdateAvailable bool   `json:"update_available"`
+	// UpdatesDisabled reports that Jackett's own automatic updates are
+	// turned off in the server config
+	UpdatesDisabled bool `json:"updates_disabled"`
+}
+
+// githubRelease is the subset of the GitHub release payload used here
+type githubRelease struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Body        string    `json:"body"`
+	HTMLURL     string    `json:"html_url"`
+	Prerelease  bool      `json:"prerelease"`
+	Draft       bool      `json:"draft"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// WithReleasesURL overrides the GitHub releases feed consulted for updates
+func WithReleasesURL(releasesURL string) Option {
+	return func(c *Client) {
+		c.releasesURL = releasesURL
+	}
+}
+
+// GetUpdateChangelog compares the running Jackett version with the published
+// releases and returns the release notes of every newer release. Pre-releases
+// are only considered if the server is configured to update to them.
+func (c *Client) GetUpdateChangelog() (*UpdateChangelog, error) {
+	return c.GetUpdateChangelogContext(context.Background())
+}
+
+// GetUpdateChangelogContext is like GetUpdateChangelog but honors ctx for cancellation
+func (c *Client) GetUpdateChangelogContext(ctx context.Context) (*UpdateChangelog, error) {
+	config, err := c.GetServerConfigContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.updateChangelog(ctx, config)
+}
+
+// updateChangelog builds the changelog for the server described by config
+func (c *Client) updateChangelog(ctx context.Context, config map[string]interface{}) (*UpdateChangelog, error) {
+	current, _ := config["app_version"].(string)
+	currentVersion, _ := ParseVersion(current)
+	prerelease, _ := config["prerelease"].(bool)
+
+	releases, err := c.fetchReleases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get update changelog error: %w", err)
+	}
+
+	changelog := &UpdateChangelog{CurrentVersion: current}
+	for _, r := range releases {
+		if r.Draft || (r.Prerelease && !prerelease) {
+			continue
+		}
+		version := strings.TrimPrefix(r.TagName, "v")
+		parsed, err := ParseVersion(version)
+		if err != nil || parsed.Compare(currentVersion) <= 0 {
+			continue
+		}
+		changelog.Releases = append(changelog.Releases, Release{
+			Version:     version,
+			Name:        r.Name,
+			Notes:       r.Body,
+			URL:         r.HTMLURL,
+			Prerelease:  r.Prerelease,
+			PublishedAt: r.PublishedAt,
+		})
+	}
+
+	if len(changelog.Releases) > 0 {
+		changelog.LatestVersion = changelog.Releases[0].Version
+		changelog.UpdateAvailable = true
+	} else {
+		changelog.LatestVersion = current
+	}
+
+	return changelog, nil
+}
+
+func (c *Client) fetchReleases(ctx context.Context) ([]githubRelease, error) {
+	releasesURL := c.releasesURL
+	if releasesURL == "" {
+		releasesURL = defaultReleasesURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", releasesURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	c.setUserAgent(req)
+
+	done, err := c.lifecycle.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	resp, err := c.dumping(c.client).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &statusError{code: resp.StatusCode, body: string(body)}
+	}
+
+	var releases []githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to decode releases: %w", err)
+	}
+
+	// GitHub lists releases newest first, but don't rely on it
+	sortReleases(releases)
+	return releases, nil
+}
+
+func sortReleases(releases []githubRelease) {
+	sort.SliceStable(releases, func(i, j int) bool {
+		a, _ := ParseVersion(releases[i].TagName)
+		b, _ := ParseVersion(releases[j].TagName)
+		return a.Compare(b) > 0
+	})
+}
+
+// CheckForUpdates reports whether a newer Jackett release is available for
+// the running instance
+func (c *Client) CheckForUpdates() (*UpdateStatus, error) {
+	return c.CheckForUpdatesContext(context.Background())
+}
+
+// CheckForUpdatesContext is like CheckForUpdates but honors ctx for cancellation
+func (c *Client) CheckForUpdatesContext(ctx context.Context) (*UpdateStatus, error) {
+	config, err := c.GetServerConfigContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changelog, err := c.updateChangelog(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	disabled, _ := config["updatedisabled"].(bool)
+	return &UpdateStatus{
+		CurrentVersion:  changelog.CurrentVersion,
+		LatestVersion:   changelog.LatestVersion,
+		UpdateAvailable: changelog.UpdateAvailable,
+		UpdatesDisabled: disabled,
+	}, nil
+}
+
+// TriggerUpdate asks Jackett to download and install the latest release. Jackett
+// restarts itself once the update is applied, so the instance is briefly
+// unavailable afterwards.
+func (c *Client) TriggerUpdate() error {
+	return c.TriggerUpdateContext(context.Background())
+}
+
+// TriggerUpdateContext is like TriggerUpdate but honors ctx for cancellation
+func (c *Client) TriggerUpdateContext(ctx context.Context) error {
+	params := url.Values{}
+	params.Set("apikey", c.apiKeyFor(ctx))
+
+	if _, err := c.doAdmin(ctx, "POST", "/api/v2.0/server/update", params, nil); err != nil {
+		return fmt.Errorf("trigger update error: %w", err)
+	}
+
+	return nil
+}
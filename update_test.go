@@ -0,0 +1,117 @@
+package jackett_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/cehbz/jackett"
+	"github.com/cehbz/jackett/jacketttest"
+)
+
+const releasesJSON = `[
+  {"tag_name": "v0.22.120", "name": "v0.22.120", "body": "Prerelease notes", "html_url": "https://example.com/120", "prerelease": true},
+  {"tag_name": "v0.22.110", "name": "v0.22.110", "body": "Fixed tracker X", "html_url": "https://example.com/110"},
+  {"tag_name": "v0.22.100", "name": "v0.22.100", "body": "Current", "html_url": "https://example.com/100"},
+  {"tag_name": "v0.22.115", "name": "v0.22.115", "body": "Added tracker Y", "html_url": "https://example.com/115"},
+  {"tag_name": "v0.22.90", "name": "v0.22.90", "body": "Old", "html_url": "https://example.com/90"}
+]`
+
+// newUpdateFake serves the given server config and releasesJSON as the
+// release list
+func newUpdateFake(t *testing.T, config map[string]interface{}) *jacketttest.Server {
+	server := jacketttest.NewServer()
+	t.Cleanup(server.Close)
+	server.SetServerConfig(config)
+	server.Handle("/releases", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(releasesJSON))
+	})
+	return server
+}
+
+func TestGetUpdateChangelog(t *testing.T) {
+	server := newUpdateFake(t, map[string]interface{}{"app_version": "0.22.100", "prerelease": false})
+	client, err := server.NewClient(jackett.WithReleasesURL(server.URL + "/releases"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	changelog, err := client.GetUpdateChangelog()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !changelog.UpdateAvailable {
+		t.Error("Expected an update to be available")
+	}
+	if changelog.LatestVersion != "0.22.115" {
+		t.Errorf("Expected latest version '0.22.115', got '%s'", changelog.LatestVersion)
+	}
+	if len(changelog.Releases) != 2 {
+		t.Fatalf("Expected 2 newer releases, got %d", len(changelog.Releases))
+	}
+	if changelog.Releases[1].Notes != "Fixed tracker X" {
+		t.Errorf("Expected notes 'Fixed tracker X', got '%s'", changelog.Releases[1].Notes)
+	}
+}
+
+func TestGetUpdateChangelog_Prerelease(t *testing.T) {
+	server := newUpdateFake(t, map[string]interface{}{"app_version": "0.22.120", "prerelease": true})
+	client, err := server.NewClient(jackett.WithReleasesURL(server.URL + "/releases"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	changelog, err := client.GetUpdateChangelog()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if changelog.UpdateAvailable {
+		t.Error("Expected no update to be available")
+	}
+	if changelog.LatestVersion != "0.22.120" {
+		t.Errorf("Expected latest version '0.22.120', got '%s'", changelog.LatestVersion)
+	}
+}
+
+func TestCheckForUpdates(t *testing.T) {
+	server := newUpdateFake(t, map[string]interface{}{"app_version": "0.22.100", "prerelease": false, "updatedisabled": true})
+	client, err := server.NewClient(jackett.WithReleasesURL(server.URL + "/releases"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	status, err := client.CheckForUpdates()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !status.UpdateAvailable || status.LatestVersion != "0.22.115" {
+		t.Errorf("Expected update to 0.22.115, got %+v", status)
+	}
+	if !status.UpdatesDisabled {
+		t.Error("Expected updates to be reported as disabled")
+	}
+}
+
+func TestTriggerUpdate(t *testing.T) {
+	server := jacketttest.NewServer()
+	defer server.Close()
+	server.Handle("POST /api/v2.0/server/update", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := client.TriggerUpdate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 1 || requests[0].Method != "POST" || requests[0].Path != "/api/v2.0/server/update" {
+		t.Errorf("Expected one update request, got %+v", requests)
+	}
+}
@@ -0,0 +1,92 @@
+package jackett
+
+import (
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/cehbz/jackett/torrent"
+)
+
+// InfoHashMismatchError reports a download whose info hash differs from the
+// one the indexer advertised for the result
+type InfoHashMismatchError struct {
+	Expected string
+	Actual   string
+}
+
+func (e *InfoHashMismatchError) Error() string {
+	return fmt.Sprintf("info hash mismatch: expected %s, got %s", e.Expected, e.Actual)
+}
+
+// WithInfoHashVerification makes SearchResult.Fetch verify every download
+// against the result's advertised info hash (see SearchResult.Verify)
+func WithInfoHashVerification() Option {
+	return func(c *Client) {
+		c.verifyInfoHash = true
+	}
+}
+
+// Verify checks a downloaded release against r.InfoHash. It returns an
+// *InfoHashMismatchError if the .torrent file or magnet URI carries a
+// different info hash, and an error if a .torrent file can't be decoded.
+// Results without an advertised info hash and NZB files can't be verified
+// and always pass.
+func (r SearchResult) Verify(release *DownloadedRelease) error {
+	expected := normalizeInfoHash(r.InfoHash)
+	if expected == "" || release.IsNZB() {
+		return nil
+	}
+
+	var actual string
+	if release.IsMagnet() {
+		actual = magnetInfoHash(release.MagnetURI)
+		if actual == "" {
+			return nil
+		}
+	} else {
+		meta, err := torrent.Parse(release.Torrent)
+		if err != nil {
+			return fmt.Errorf("verify %q error: %w", r.Title, err)
+		}
+		actual = meta.InfoHash
+	}
+
+	if actual != expected {
+		return &InfoHashMismatchError{Expected: expected, Actual: actual}
+	}
+	return nil
+}
+
+// normalizeInfoHash converts a v1 info hash in hex or base32 form to
+// lowercase hex, returning "" for anything else
+func normalizeInfoHash(hash string) string {
+	hash = strings.TrimSpace(hash)
+	switch len(hash) {
+	case 40:
+		if _, err := hex.DecodeString(hash); err == nil {
+			return strings.ToLower(hash)
+		}
+	case 32:
+		if b, err := base32.StdEncoding.DecodeString(strings.ToUpper(hash)); err == nil {
+			return hex.EncodeToString(b)
+		}
+	}
+	return ""
+}
+
+// magnetInfoHash extracts the normalized v1 info hash of a magnet URI
+func magnetInfoHash(magnetURI string) string {
+	u, err := url.Parse(magnetURI)
+	if err != nil {
+		return ""
+	}
+	for _, xt := range u.Query()["xt"] {
+		if strings.HasPrefix(strings.ToLower(xt), "urn:btih:") {
+			return normalizeInfoHash(xt[len("urn:btih:"):])
+		}
+	}
+	return ""
+}
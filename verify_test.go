@@ -0,0 +1,69 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const (
+	testTorrent         = "d4:infod6:lengthi42e4:name5:x.iso12:piece lengthi16384e6:pieces20:bbbbbbbbbbbbbbbbbbbbee"
+	testTorrentInfoHash = "be5e82747593ae842886a0ed7bbad08002c1a1f9"
+)
+
+func TestSearchResult_Verify(t *testing.T) {
+	torrentRelease := &DownloadedRelease{Torrent: []byte(testTorrent)}
+
+	tests := []struct {
+		name     string
+		infoHash string
+		release  *DownloadedRelease
+		mismatch bool
+	}{
+		{"torrent match", testTorrentInfoHash, torrentRelease, false},
+		{"uppercase hash", "BE5E82747593AE842886A0ED7BBAD08002C1A1F9", torrentRelease, false},
+		{"torrent mismatch", "0123456789abcdef0123456789abcdef01234567", torrentRelease, true},
+		{"no advertised hash", "", torrentRelease, false},
+		{"base32 magnet match", testTorrentInfoHash, &DownloadedRelease{MagnetURI: "magnet:?xt=urn:btih:XZPIE5DVSOXIIKEGUDWXXOWQQABMDIPZ"}, false},
+		{"magnet mismatch", testTorrentInfoHash, &DownloadedRelease{MagnetURI: testMagnetURI}, true},
+	}
+	for _, tt := range tests {
+		err := SearchResult{Title: tt.name, InfoHash: tt.infoHash}.Verify(tt.release)
+		var mismatch *InfoHashMismatchError
+		if got := errors.As(err, &mismatch); got != tt.mismatch {
+			t.Errorf("%s: expected mismatch %v, got %v", tt.name, tt.mismatch, err)
+		}
+		if !tt.mismatch && err != nil {
+			t.Errorf("%s: expected no error, got %v", tt.name, err)
+		}
+	}
+
+	if err := (SearchResult{InfoHash: testTorrentInfoHash}).Verify(&DownloadedRelease{Torrent: []byte("<html>")}); err == nil {
+		t.Error("Expected error for undecodable torrent, got none")
+	}
+}
+
+func TestSearchResult_Fetch_Verification(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testTorrent))
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithOptions(server.URL, "test-api-key", WithInfoHashVerification())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	good := SearchResult{Title: "good", Link: server.URL + "/dl/good", InfoHash: testTorrentInfoHash}
+	if _, err := good.Fetch(context.Background(), client); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	bad := SearchResult{Title: "bad", Link: server.URL + "/dl/bad", InfoHash: "0123456789abcdef0123456789abcdef01234567"}
+	var mismatch *InfoHashMismatchError
+	if _, err := bad.Fetch(context.Background(), client); !errors.As(err, &mismatch) {
+		t.Errorf("Expected InfoHashMismatchError, got %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedFeature is returned when an operation needs a newer Jackett
+// release than the server is running
+var ErrUnsupportedFeature = errors.New("jackett: feature not supported by server version")
+
+// Version is a Jackett release version such as 0.22.1234
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion parses a dotted Jackett version. A leading "v" and any
+// pre-release or build suffix ("-beta", "+abc") are ignored, as is a fourth
+// revision component, which Jackett always reports as zero.
+func ParseVersion(s string) (Version, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(trimmed, "-+"); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+
+	parts := strings.Split(trimmed, ".")
+	if trimmed == "" || len(parts) > 4 {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+
+	var nums [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q", s)
+		}
+		if i < len(nums) {
+			nums[i] = n
+		}
+	}
+
+	return Version{Major: nums[0], Minor: nums[1], Patch: nums[2]}, nil
+}
+
+// String formats v as major.minor.patch
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or 1 as v is older than, equal to, or newer than o
+func (v Version) Compare(o Version) int {
+	switch {
+	case v.Major != o.Major:
+		return compareInts(v.Major, o.Major)
+	case v.Minor != o.Minor:
+		return compareInts(v.Minor, o.Minor)
+	default:
+		return compareInts(v.Patch, o.Patch)
+	}
+}
+
+// AtLeast reports whether v is the same as or newer than o
+func (v Version) AtLeast(o Version) bool {
+	return v.Compare(o) >= 0
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Feature names a server capability that only exists on some Jackett releases
+type Feature string
+
+const (
+	// FeatureAPIV2 is the /api/v2.0 API used throughout this package
+	FeatureAPIV2 Feature = "api_v2"
+	// FeatureFlareSolverr is the FlareSolverr URL in the server config
+	FeatureFlareSolverr Feature = "flaresolverr"
+	// FeatureFlareSolverrMaxTimeout is the configurable FlareSolverr timeout
+	FeatureFlareSolverrMaxTimeout Feature = "flaresolverr_maxtimeout"
+)
+
+// featureVersions maps each feature to the first release that supports it
+var featureVersions = map[Feature]Version{
+	FeatureAPIV2:                  {Major: 0, Minor: 8},
+	FeatureFlareSolverr:           {Major: 0, Minor: 17},
+	FeatureFlareSolverrMaxTimeout: {Major: 0, Minor: 18},
+}
+
+// Supports reports whether a server running v provides feature. Unknown
+// features are reported as unsupported.
+func (v Version) Supports(feature Feature) bool {
+	min, ok := featureVersions[feature]
+	return ok && v.AtLeast(min)
+}
+
+// ServerVersion retrieves and parses the version of the running Jackett instance
+func (c *Client) ServerVersion(ctx context.Context) (Version, error) {
+	config, err := c.GetServerConfigContext(ctx)
+	if err != nil {
+		return Version{}, err
+	}
+	return serverVersion(config)
+}
+
+// SupportsFeature reports whether the Jackett instance provides feature
+func (c *Client) SupportsFeature(feature Feature) (bool, error) {
+	return c.SupportsFeatureContext(context.Background(), feature)
+}
+
+// SupportsFeatureContext is like SupportsFeature but honors ctx for cancellation
+func (c *Client) SupportsFeatureContext(ctx context.Context, feature Feature) (bool, error) {
+	if _, ok := featureVersions[feature]; !ok {
+		return false, fmt.Errorf("unknown feature %q", feature)
+	}
+
+	version, err := c.ServerVersion(ctx)
+	if err != nil {
+		return false, err
+	}
+	return version.Supports(feature), nil
+}
+
+// serverVersion parses app_version out of a server config
+func serverVersion(config map[string]interface{}) (Version, error) {
+	raw, _ := config["app_version"].(string)
+	version, err := ParseVersion(raw)
+	if err != nil {
+		return Version{}, fmt.Errorf("server version error: %w", err)
+	}
+	return version, nil
+}
+
+// requireFeature fails with ErrUnsupportedFeature if the server described by
+// config is too old for feature. Servers reporting an unparseable version,
+// such as development builds, are given the benefit of the doubt.
+func requireFeature(config map[string]interface{}, feature Feature) error {
+	version, err := serverVersion(config)
+	if err != nil || version.Supports(feature) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s requires Jackett %s, server is %s", ErrUnsupportedFeature, feature, featureVersions[feature], version)
+}
@@ -0,0 +1,104 @@
+package jackett
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+	}{
+		{"0.22.1234", Version{0, 22, 1234}},
+		{"v0.22.1234", Version{0, 22, 1234}},
+		{"0.22.1234.0", Version{0, 22, 1234}},
+		{"0.23", Version{0, 23, 0}},
+		{"0.22.5-beta", Version{0, 22, 5}},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.in)
+		if err != nil {
+			t.Errorf("ParseVersion(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseVersion(%q) = %v, expected %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "dev", "0.x.1", "1.2.3.4.5"} {
+		if _, err := ParseVersion(in); err == nil {
+			t.Errorf("Expected error parsing %q, got none", in)
+		}
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"0.22.100", "0.22.100", 0},
+		{"0.22.99", "0.22.100", -1},
+		{"0.23", "0.22.999", 1},
+		{"0.22.100.0", "0.22.100", 0},
+	}
+	for _, tt := range tests {
+		a, _ := ParseVersion(tt.a)
+		b, _ := ParseVersion(tt.b)
+		if got := a.Compare(b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, expected %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSupportsFeature(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2.0/server/config": {statusCode: http.StatusOK, responseBody: `{"app_version": "0.17.500"}`},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "GET", url: "/api/v2.0/server/config"},
+		{method: "GET", url: "/api/v2.0/server/config"},
+	}
+
+	client, _, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if ok, err := client.SupportsFeature(FeatureFlareSolverr); err != nil || !ok {
+		t.Errorf("Expected FlareSolverr to be supported, got %v (%v)", ok, err)
+	}
+	if ok, err := client.SupportsFeature(FeatureFlareSolverrMaxTimeout); err != nil || ok {
+		t.Errorf("Expected FlareSolverr max timeout to be unsupported, got %v (%v)", ok, err)
+	}
+	if _, err := client.SupportsFeature("bogus"); err == nil {
+		t.Error("Expected error for unknown feature, got none")
+	}
+}
+
+func TestSetFlareSolverr_UnsupportedVersion(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2.0/server/config": {statusCode: http.StatusOK, responseBody: `{"app_version": "0.16.100"}`},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "GET", url: "/api/v2.0/server/config"},
+	}
+
+	client, mockTransport, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	err = client.SetFlareSolverr("http://flaresolverr:8191", time.Minute)
+	if !errors.Is(err, ErrUnsupportedFeature) {
+		t.Fatalf("Expected ErrUnsupportedFeature, got %v", err)
+	}
+
+	if mockTransport.requestIndex != len(mockTransport.expectedRequests) {
+		t.Errorf("Expected no config to be posted")
+	}
+}
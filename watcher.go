@@ -0,0 +1,339 @@
+package jackett
+
+import (
+	"context"
+	"math/rand"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// WatchQuery is a named search polled by a Watcher
+type WatchQuery struct {
+	// Name identifies the query in matches
+	Name string
+	// Query is the search term; an empty query with no Params polls the
+	// indexers' latest releases
+	Query string
+	// IndexerID restricts the query to one indexer; empty means "all"
+	IndexerID string
+	// Params holds further torznab parameters, such as t, cat or imdbid
+	Params url.Values
+	// Filter, if set, drops the results it returns false for; see And, Or
+	// and Not for combining predicates
+	Filter Predicate
+	// Profile, if set, drops results that the Watcher's ledger assesses as
+	// GrabRejected or GrabSkip under it
+	Profile *QualityProfile
+}
+
+// WatchMatch is a release newly found by a watched query
+type WatchMatch struct {
+	// Query is the Name of the matching query
+	Query  string
+	Result SearchResult
+	Time   time.Time
+	// Decision is the Profile's verdict, GrabNew without a Profile
+	Decision GrabDecision
+}
+
+// Watcher polls named queries and reports releases it hasn't seen before,
+// the building block of autodl-style tools. Releases are recognized by GUID
+// and info hash, or by link if they have neither, and are reported at most
+// once even if several queries or indexers return them. A failing query
+// doesn't stop the others.
+type Watcher struct {
+	// Searcher runs the queries, usually a *Client
+	Searcher Searcher
+	// Queries are polled in order
+	Queries []WatchQuery
+	// Interval between polls, 15 minutes by default
+	Interval time.Duration
+	// Jitter randomizes each interval by up to this fraction in either
+	// direction (0.1 means ±10%), so that many watchers don't poll in
+	// lockstep
+	Jitter float64
+	// SkipExisting marks the results of the first poll as seen without
+	// reporting them, so only releases appearing later are matched. With a
+	// persistent Store it is usually only wanted on the very first run.
+	SkipExisting bool
+	// Store remembers reported releases, in memory by default. A persistent
+	// store such as a FileSeenStore prevents duplicate matches after a
+	// restart. Run marks a release only once its match was delivered, so a
+	// crash in between reports it again rather than losing it.
+	Store SeenStore
+	// Ledger is consulted for queries with a Profile, an in-memory ledger
+	// by default. Matches of those queries are recorded in it once
+	// delivered, so later releases of the episode are assessed as upgrades
+	// or skipped.
+	Ledger *GrabLedger
+	// Clock defaults to the Searcher's clock if it is a *Client
+	Clock Clock
+	// OnMatch, if set, is called for each match from the polling goroutine
+	OnMatch func(WatchMatch)
+	// Matches, if set, receives each match. Sends block, so the channel must
+	// be drained or buffered.
+	Matches chan<- WatchMatch
+	// OnError, if set, is called for each failed query. Queries answered
+	// only partially report a *PartialError but still produce matches.
+	OnError func(query string, err error)
+
+	mu     sync.Mutex
+	store  SeenStore
+	ledger *GrabLedger
+	polled bool
+}
+
+// Run polls every Interval until ctx is cancelled or the Searcher, if a
+// *Client, is closed. The first poll happens immediately.
+func (w *Watcher) Run(ctx context.Context) error {
+	var done <-chan struct{}
+	if c, ok := w.Searcher.(*Client); ok {
+		done = c.Done()
+	}
+
+	for {
+		for _, match := range w.pending(ctx) {
+			if err := w.emit(ctx, done, match); err != nil {
+				return err
+			}
+			w.mark(match)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-done:
+			return ErrClientClosed
+		case <-w.clock().After(w.nextInterval()):
+		}
+	}
+}
+
+// emit delivers match to the callback and the channel
+func (w *Watcher) emit(ctx context.Context, done <-chan struct{}, match WatchMatch) error {
+	if w.OnMatch != nil {
+		w.OnMatch(match)
+	}
+	if w.Matches == nil {
+		return nil
+	}
+	select {
+	case w.Matches <- match:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+		return ErrClientClosed
+	}
+}
+
+// Poll runs every query once and returns the matches not seen before, in
+// query order, marking them as seen. It does not call OnMatch or send on
+// Matches; Run does.
+func (w *Watcher) Poll(ctx context.Context) []WatchMatch {
+	var matches []WatchMatch
+	for _, match := range w.pending(ctx) {
+		if w.mark(match) {
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+// pending runs every query once and returns the matches not seen before, in
+// query order, without marking them. The results of a first poll with
+// SkipExisting are marked instead of returned.
+func (w *Watcher) pending(ctx context.Context) []WatchMatch {
+	var matches []WatchMatch
+	reported := make(map[string]bool)
+	for _, q := range w.Queries {
+		if ctx.Err() != nil {
+			break
+		}
+		matches = append(matches, w.poll(ctx, q, reported)...)
+	}
+
+	w.mu.Lock()
+	skip := !w.polled && w.SkipExisting
+	w.polled = true
+	w.mu.Unlock()
+	if skip {
+		for _, match := range matches {
+			w.mark(match)
+		}
+		return nil
+	}
+	return matches
+}
+
+// mark records match's release as seen, and in the ledger if its query has
+// a Profile, reporting whether it was
+func (w *Watcher) mark(match WatchMatch) bool {
+	w.mu.Lock()
+	err := w.seenStore().Mark(releaseKeys(match.Result)...)
+	for _, q := range w.Queries {
+		if err == nil && q.Name == match.Query && q.Profile != nil {
+			w.grabLedger().Record(match.Result)
+			break
+		}
+	}
+	w.mu.Unlock()
+	if err != nil {
+		if w.OnError != nil {
+			w.OnError(match.Query, err)
+		}
+		return false
+	}
+	return true
+}
+
+// poll runs one query and returns its results that are neither seen nor in
+// reported, the keys of the releases already matched by this poll, adding
+// theirs
+func (w *Watcher) poll(ctx context.Context, q WatchQuery, reported map[string]bool) []WatchMatch {
+	params := url.Values{}
+	for k, v := range q.Params {
+		params[k] = v
+	}
+	if q.Query != "" {
+		params.Set("q", q.Query)
+	}
+	indexerID := q.IndexerID
+	if indexerID == "" {
+		indexerID = "all"
+	}
+
+	response, err := w.Searcher.TorznabSearch(ctx, indexerID, params)
+	if err != nil && w.OnError != nil && ctx.Err() == nil {
+		w.OnError(q.Name, err)
+	}
+	if response == nil {
+		return nil
+	}
+	now := w.clock().Now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	store := w.seenStore()
+
+	var matches []WatchMatch
+	for _, r := range response.Results {
+		keys := releaseKeys(r)
+		seen, err := anySeen(store, keys)
+		if err != nil {
+			// Skipping is safer than risking a duplicate grab
+			if w.OnError != nil {
+				w.OnError(q.Name, err)
+			}
+			continue
+		}
+		if len(keys) == 0 || seen || anyReported(reported, keys) {
+			continue
+		}
+		if q.Filter != nil && !q.Filter(r) {
+			continue
+		}
+		decision := GrabNew
+		if q.Profile != nil {
+			decision = w.grabLedger().Assess(r, *q.Profile)
+			if decision == GrabRejected || decision == GrabSkip {
+				continue
+			}
+		}
+		for _, key := range keys {
+			reported[key] = true
+		}
+		matches = append(matches, WatchMatch{Query: q.Name, Result: r, Time: now, Decision: decision})
+	}
+	return matches
+}
+
+// Seen reports whether the watcher has already reported result. Store
+// errors count as not seen.
+func (w *Watcher) Seen(result SearchResult) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	seen, _ := anySeen(w.seenStore(), releaseKeys(result))
+	return seen
+}
+
+// seenStore returns Store, or an in-memory store; w.mu must be held
+func (w *Watcher) seenStore() SeenStore {
+	if w.Store != nil {
+		return w.Store
+	}
+	if w.store == nil {
+		w.store = NewMemorySeenStore()
+	}
+	return w.store
+}
+
+// grabLedger returns Ledger, or an in-memory ledger; w.mu must be held
+func (w *Watcher) grabLedger() *GrabLedger {
+	if w.Ledger != nil {
+		return w.Ledger
+	}
+	if w.ledger == nil {
+		w.ledger = NewGrabLedger()
+	}
+	return w.ledger
+}
+
+// anyReported reports whether any of keys is in reported
+func anyReported(reported map[string]bool, keys []string) bool {
+	for _, key := range keys {
+		if reported[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// anySeen reports whether any of keys was marked
+func anySeen(store SeenStore, keys []string) (bool, error) {
+	for _, key := range keys {
+		seen, err := store.Seen(key)
+		if err != nil || seen {
+			return seen, err
+		}
+	}
+	return false, nil
+}
+
+// nextInterval returns the jittered delay until the next poll
+func (w *Watcher) nextInterval() time.Duration {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+	if w.Jitter > 0 {
+		interval += time.Duration((rand.Float64()*2 - 1) * w.Jitter * float64(interval))
+	}
+	return interval
+}
+
+func (w *Watcher) clock() Clock {
+	if w.Clock != nil {
+		return w.Clock
+	}
+	if c, ok := w.Searcher.(*Client); ok {
+		return c.clock
+	}
+	return systemClock{}
+}
+
+// releaseKeys identifies a release across searches and indexers by GUID
+// and info hash, or by link if it has neither
+func releaseKeys(r SearchResult) []string {
+	var keys []string
+	if r.GUID != "" {
+		keys = append(keys, "guid:"+r.GUID)
+	}
+	if hash := normalizeInfoHash(r.InfoHash); hash != "" {
+		keys = append(keys, "hash:"+hash)
+	}
+	if len(keys) == 0 && r.Link != "" {
+		keys = append(keys, "link:"+r.Link)
+	}
+	return keys
+}
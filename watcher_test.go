@@ -0,0 +1,207 @@
+package jackett
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// feedSearcher answers torznab searches from a settable result list
+type feedSearcher struct {
+	mu      sync.Mutex
+	results []SearchResult
+	err     error
+	params  []url.Values
+}
+
+func (f *feedSearcher) Search(query string) (*SearchResponse, error) {
+	return f.TorznabSearch(context.Background(), "all", url.Values{"q": {query}})
+}
+
+func (f *feedSearcher) SearchWithIndexer(indexerID, query string) (*SearchResponse, error) {
+	return f.TorznabSearch(context.Background(), indexerID, url.Values{"q": {query}})
+}
+
+func (f *feedSearcher) TorznabSearch(ctx context.Context, indexerID string, params url.Values) (*SearchResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.params = append(f.params, params)
+	if f.err != nil {
+		return nil, f.err
+	}
+	var results []SearchResult
+	for _, r := range f.results {
+		if strings.Contains(strings.ToLower(r.Title), strings.ToLower(params.Get("q"))) {
+			results = append(results, r)
+		}
+	}
+	return &SearchResponse{Results: results}, nil
+}
+
+func (f *feedSearcher) set(results ...SearchResult) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results = results
+}
+
+func TestWatcherPoll(t *testing.T) {
+	searcher := &feedSearcher{}
+	searcher.set(
+		SearchResult{Title: "Show.S01E01.720p", GUID: "1"},
+		SearchResult{Title: "Show.S01E01.480p", GUID: "2"},
+		SearchResult{Title: "Other.Show.S01E01.1080p", GUID: "3", Seeders: 0},
+	)
+	ledger := NewGrabLedger()
+	ledger.Record(SearchResult{Title: "Show.S01E02.1080p"})
+
+	watcher := &Watcher{
+		Searcher: searcher,
+		Ledger:   ledger,
+		Queries: []WatchQuery{
+			{Name: "show", Query: "show", Params: url.Values{"cat": {"5000"}}, Profile: &QualityProfile{Min: Quality720p, Cutoff: Quality1080p}},
+			{Name: "other", Query: "other", Filter: func(r SearchResult) bool { return r.Seeders > 0 }},
+		},
+	}
+
+	matches := watcher.Poll(context.Background())
+	if len(matches) != 2 || matches[0].Result.GUID != "1" || matches[1].Result.GUID != "3" {
+		t.Fatalf("Expected the 720p and 1080p releases, got %+v", matches)
+	}
+	if matches[0].Query != "show" || matches[0].Decision != GrabNew {
+		t.Errorf("Unexpected match %+v", matches[0])
+	}
+	if p := searcher.params[0]; p.Get("q") != "show" || p.Get("cat") != "5000" {
+		t.Errorf("Unexpected search parameters %v", p)
+	}
+	if !watcher.Seen(SearchResult{GUID: "1"}) || watcher.Seen(SearchResult{GUID: "2"}) {
+		t.Error("Expected only matched releases to be seen")
+	}
+
+	searcher.set(
+		SearchResult{Title: "Show.S01E01.720p", GUID: "1"},
+		SearchResult{Title: "Show.S01E02.720p", GUID: "4"},
+		SearchResult{Title: "Show.S01E03.1080p", GUID: "5"},
+		SearchResult{Title: "Other.Show.S01E02", GUID: "6", Seeders: 3},
+	)
+	matches = watcher.Poll(context.Background())
+	if len(matches) != 2 || matches[0].Result.GUID != "5" || matches[1].Result.GUID != "6" {
+		t.Errorf("Expected only new releases not yet grabbed, got %+v", matches)
+	}
+}
+
+func TestWatcherProfileWithoutLedger(t *testing.T) {
+	searcher := &feedSearcher{}
+	searcher.set(SearchResult{Title: "Show.S01E01.720p", GUID: "1"})
+	watcher := &Watcher{
+		Searcher: searcher,
+		Queries:  []WatchQuery{{Name: "show", Query: "show", Profile: &QualityProfile{Min: Quality720p}}},
+	}
+
+	if matches := watcher.Poll(context.Background()); len(matches) != 1 {
+		t.Fatalf("Expected the 720p release, got %+v", matches)
+	}
+
+	searcher.set(
+		SearchResult{Title: "Show.S01E01.720p.REPACK", GUID: "2"},
+		SearchResult{Title: "Show.S01E01.1080p", GUID: "3"},
+	)
+	matches := watcher.Poll(context.Background())
+	if len(matches) != 1 || matches[0].Result.GUID != "3" || matches[0].Decision != GrabUpgrade {
+		t.Errorf("Expected only the 1080p upgrade, got %+v", matches)
+	}
+}
+
+func TestWatcherMarksAfterDelivery(t *testing.T) {
+	searcher := &feedSearcher{}
+	release := SearchResult{Title: "Show.S01E01", GUID: "a/1"}
+	searcher.set(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watcher := &Watcher{
+		Searcher: searcher,
+		Queries:  []WatchQuery{{Name: "show", Query: "show"}},
+		// Nobody receives, so the match is never delivered
+		Matches: make(chan WatchMatch),
+		OnMatch: func(WatchMatch) { cancel() },
+	}
+
+	if err := watcher.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if watcher.Seen(release) {
+		t.Error("Expected an undelivered match not to be marked as seen")
+	}
+	if matches := watcher.Poll(context.Background()); len(matches) != 1 {
+		t.Errorf("Expected the undelivered release to match again, got %+v", matches)
+	}
+}
+
+func TestWatcherSkipExisting(t *testing.T) {
+	searcher := &feedSearcher{}
+	searcher.set(SearchResult{Title: "Show.S01E01", InfoHash: "0123456789ABCDEF0123456789ABCDEF01234567"})
+
+	var failed []string
+	watcher := &Watcher{
+		Searcher:     searcher,
+		Queries:      []WatchQuery{{Name: "show", Query: "show"}},
+		SkipExisting: true,
+		OnError:      func(query string, err error) { failed = append(failed, query) },
+	}
+	if matches := watcher.Poll(context.Background()); len(matches) != 0 {
+		t.Errorf("Expected the first poll to report nothing, got %+v", matches)
+	}
+
+	searcher.set(
+		SearchResult{Title: "Show.S01E01", InfoHash: "0123456789abcdef0123456789abcdef01234567"},
+		SearchResult{Title: "Show.S01E02", Link: "http://tracker/2"},
+	)
+	matches := watcher.Poll(context.Background())
+	if len(matches) != 1 || matches[0].Result.Title != "Show.S01E02" {
+		t.Errorf("Expected only the new release, got %+v", matches)
+	}
+
+	searcher.err = errors.New("boom")
+	if matches := watcher.Poll(context.Background()); len(matches) != 0 || len(failed) != 1 || failed[0] != "show" {
+		t.Errorf("Expected the failure to be reported, got %+v, %v", matches, failed)
+	}
+}
+
+func TestWatcherRun(t *testing.T) {
+	searcher := &feedSearcher{}
+	searcher.set(SearchResult{Title: "Show.S01E01", GUID: "1"})
+
+	var callbacks sync.WaitGroup
+	callbacks.Add(2)
+	matches := make(chan WatchMatch)
+	watcher := &Watcher{
+		Searcher: searcher,
+		Queries:  []WatchQuery{{Name: "show", Query: "show"}},
+		Interval: 10 * time.Millisecond,
+		Jitter:   0.5,
+		OnMatch:  func(WatchMatch) { callbacks.Done() },
+		Matches:  matches,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- watcher.Run(ctx) }()
+
+	if m := <-matches; m.Result.GUID != "1" {
+		t.Errorf("Unexpected match %+v", m)
+	}
+	searcher.set(SearchResult{Title: "Show.S01E01", GUID: "1"}, SearchResult{Title: "Show.S01E02", GUID: "2"})
+	if m := <-matches; m.Result.GUID != "2" {
+		t.Errorf("Unexpected match %+v", m)
+	}
+	callbacks.Wait()
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
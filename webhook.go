@@ -0,0 +1,176 @@
+package jackett
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"text/template"
+	"time"
+)
+
+// WebhookFormat selects the payload a Webhook posts
+type WebhookFormat int
+
+const (
+	// WebhookJSON posts the match as a WebhookPayload
+	WebhookJSON WebhookFormat = iota
+	// WebhookDiscord posts a Discord webhook message
+	WebhookDiscord
+	// WebhookSlack posts a Slack incoming webhook message
+	WebhookSlack
+)
+
+// SignatureHeader carries the HMAC-SHA256 of a webhook body, hex-encoded
+// with a "sha256=" prefix, when the Webhook has a Secret
+const SignatureHeader = "X-Jackett-Signature-256"
+
+// Webhook is an endpoint notified of watch matches
+type Webhook struct {
+	URL    string
+	Format WebhookFormat
+	// Template, if set, is a text/template rendering the body from the
+	// WatchMatch instead of Format. Its json function encodes a value as
+	// JSON, e.g. {"title": {{json .Result.Title}}}.
+	Template string
+	// Secret, if set, signs each body in the SignatureHeader
+	Secret string
+	// IncludeCredentials posts the result's links as Jackett returned them.
+	// By default API keys and tracker passkeys are removed from them (see
+	// SearchResult.Links) before the result is rendered, since webhooks
+	// often post to third parties such as Discord or Slack.
+	IncludeCredentials bool
+}
+
+// WebhookPayload is the body of WebhookJSON notifications
+type WebhookPayload struct {
+	Query  string       `json:"query"`
+	Time   time.Time    `json:"time"`
+	Result SearchResult `json:"result"`
+}
+
+// WebhookNotifier posts watch matches as JSON to webhooks, so that watcher
+// users don't need a separate notification layer. Use its OnMatch as the
+// Watcher's OnMatch.
+type WebhookNotifier struct {
+	Webhooks []Webhook
+	// HTTPClient defaults to one with a 30 second timeout
+	HTTPClient *http.Client
+	// OnError, if set, is called with the failures of OnMatch
+	OnError func(WatchMatch, error)
+}
+
+// OnMatch notifies every webhook of match, reporting failures to OnError
+func (n *WebhookNotifier) OnMatch(match WatchMatch) {
+	if err := n.Notify(context.Background(), match); err != nil && n.OnError != nil {
+		n.OnError(match, err)
+	}
+}
+
+// Notify posts match to every webhook. A failing webhook doesn't keep the
+// others from being notified; the failures are joined in the error. Errors
+// name webhooks by index, as their URLs often embed access tokens.
+func (n *WebhookNotifier) Notify(ctx context.Context, match WatchMatch) error {
+	var errs []error
+	for i, hook := range n.Webhooks {
+		if err := n.post(ctx, hook, match); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// post sends one notification
+func (n *WebhookNotifier) post(ctx context.Context, hook Webhook, match WatchMatch) error {
+	body, err := hook.render(match)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if hook.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(hook.Secret))
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	hc := n.HTTPClient
+	if hc == nil {
+		hc = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		// The *url.Error would quote the URL
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return fmt.Errorf("unexpected response code: %d, response: %s", resp.StatusCode, data)
+	}
+	return nil
+}
+
+// render builds the body posted for match
+func (hook Webhook) render(match WatchMatch) ([]byte, error) {
+	if !hook.IncludeCredentials {
+		match.Result = match.Result.withoutCredentials()
+	}
+	if hook.Template != "" {
+		tmpl, err := template.New("webhook").Funcs(template.FuncMap{
+			"json": func(v interface{}) (string, error) {
+				data, err := json.Marshal(v)
+				return string(data), err
+			},
+		}).Parse(hook.Template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, match); err != nil {
+			return nil, fmt.Errorf("failed to render template: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	switch hook.Format {
+	case WebhookDiscord:
+		return json.Marshal(map[string]string{"content": matchSummary(match)})
+	case WebhookSlack:
+		return json.Marshal(map[string]string{"text": matchSummary(match)})
+	}
+	return json.Marshal(WebhookPayload{Query: match.Query, Time: match.Time, Result: match.Result})
+}
+
+// matchSummary describes a match in one line for chat webhooks
+func matchSummary(match WatchMatch) string {
+	r := match.Result
+	summary := fmt.Sprintf("%s: %s (%s", match.Query, r.Title, FormatSize(r.Size))
+	if r.Tracker != "" {
+		summary += ", " + r.Tracker
+	}
+	if !r.IsUsenet() {
+		summary += fmt.Sprintf(", %d seeders", r.Seeders)
+	}
+	summary += ")"
+	if r.Details != "" {
+		summary += " " + r.Details
+	}
+	return summary
+}
@@ -0,0 +1,146 @@
+package jackett
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	bodies := make(map[string]string)
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies[r.URL.Path] = string(body)
+		if r.URL.Path == "/signed" {
+			signature = r.Header.Get(SignatureHeader)
+		}
+	}))
+	defer server.Close()
+
+	match := WatchMatch{
+		Query:  "my show",
+		Time:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Result: SearchResult{Title: "Show.S01E01", Size: 1536 << 20, Seeders: 7, Tracker: "Tracker A", Details: "https://tracker/1"},
+	}
+	notifier := &WebhookNotifier{Webhooks: []Webhook{
+		{URL: server.URL + "/generic"},
+		{URL: server.URL + "/discord", Format: WebhookDiscord},
+		{URL: server.URL + "/slack", Format: WebhookSlack},
+		{URL: server.URL + "/signed", Template: `{"title": {{json .Result.Title}}, "query": {{json .Query}}}`, Secret: "s3cret"},
+	}}
+	if err := notifier.Notify(context.Background(), match); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var payload WebhookPayload
+	if err := json.Unmarshal([]byte(bodies["/generic"]), &payload); err != nil {
+		t.Fatalf("Expected a JSON payload, got %v", err)
+	}
+	if payload.Query != "my show" || payload.Result.Title != "Show.S01E01" || !payload.Time.Equal(match.Time) {
+		t.Errorf("Unexpected payload %+v", payload)
+	}
+
+	summary := "my show: Show.S01E01 (1.5 GB, Tracker A, 7 seeders) https://tracker/1"
+	if bodies["/discord"] != `{"content":"`+summary+`"}` {
+		t.Errorf("Unexpected Discord body %s", bodies["/discord"])
+	}
+	if bodies["/slack"] != `{"text":"`+summary+`"}` {
+		t.Errorf("Unexpected Slack body %s", bodies["/slack"])
+	}
+
+	if bodies["/signed"] != `{"title": "Show.S01E01", "query": "my show"}` {
+		t.Errorf("Unexpected templated body %s", bodies["/signed"])
+	}
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(bodies["/signed"]))
+	if signature != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+		t.Errorf("Unexpected signature %q", signature)
+	}
+}
+
+func TestWebhookCredentials(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+
+	blackhole := "http://localhost:9117/bh/a/?jackett_apikey=secretkey&path=x"
+	match := WatchMatch{Query: "show", Result: SearchResult{
+		Title:         "Show.S01E01",
+		Link:          "http://localhost:9117/dl/a/?jackett_apikey=secretkey&path=x",
+		GUID:          "http://localhost:9117/dl/a/?jackett_apikey=secretkey&path=x",
+		BlackholeLink: &blackhole,
+		MagnetURI:     "magnet:?xt=urn:btih:abc&tr=https%3A%2F%2Ft.example%2Fannounce.php%3Fpasskey%3Dfeedface1234",
+	}}
+	notifier := &WebhookNotifier{Webhooks: []Webhook{
+		{URL: server.URL},
+		{URL: server.URL, Template: `{{.Result.Link}}`},
+		{URL: server.URL, IncludeCredentials: true},
+	}}
+	if err := notifier.Notify(context.Background(), match); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, body := range bodies[:2] {
+		if strings.Contains(body, "secretkey") || strings.Contains(body, "feedface1234") {
+			t.Errorf("Expected credentials to be stripped, got %s", body)
+		}
+	}
+	if !strings.Contains(bodies[1], "/dl/a/?path=x") {
+		t.Errorf("Expected the stripped link in the template, got %s", bodies[1])
+	}
+	if !strings.Contains(bodies[2], "secretkey") {
+		t.Errorf("Expected IncludeCredentials to post the full result, got %s", bodies[2])
+	}
+	if !strings.Contains(match.Result.Link, "secretkey") {
+		t.Error("Expected the match itself to be left alone")
+	}
+}
+
+func TestWebhookNotifierErrors(t *testing.T) {
+	var delivered int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/broken") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		delivered++
+	}))
+	defer server.Close()
+
+	var failures []error
+	notifier := &WebhookNotifier{
+		Webhooks: []Webhook{
+			{URL: server.URL + "/broken/secret-token"},
+			{URL: server.URL + "/ok"},
+			{URL: server.URL + "/ok", Template: "{{"},
+		},
+		OnError: func(m WatchMatch, err error) { failures = append(failures, err) },
+	}
+	notifier.OnMatch(WatchMatch{Query: "q", Result: SearchResult{Title: "Show"}})
+
+	if delivered != 1 {
+		t.Errorf("Expected the working webhook to be notified, got %d deliveries", delivered)
+	}
+	if len(failures) != 1 {
+		t.Fatalf("Expected one joined error, got %v", failures)
+	}
+	msg := failures[0].Error()
+	if !strings.Contains(msg, "webhook 0") || !strings.Contains(msg, "webhook 2: invalid template") {
+		t.Errorf("Unexpected error %q", msg)
+	}
+	if strings.Contains(msg, "secret-token") {
+		t.Errorf("Expected the webhook URL to be kept out of errors, got %q", msg)
+	}
+}